@@ -24,6 +24,8 @@ func application() *cobra.Command {
 		cli.NewDeleteCommand(applicationDeleteCmd, applicationDeleteRun, nil, withAllCommandModifiers()...),
 		applicationKey(),
 		applicationVariable(),
+		applicationQuarantine(),
+		applicationVulnerabilityPolicy(),
 		cli.NewCommand(applicationExportCmd, applicationExportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(applicationImportCmd, applicationImportRun, nil, withAllCommandModifiers()...),
 	})
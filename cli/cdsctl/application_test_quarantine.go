@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var applicationQuarantineCmd = cli.Command{
+	Name:  "quarantine",
+	Short: "Manage CDS application quarantined tests",
+	Long: `Failures of quarantined tests are reported by the JUnit action but do not fail the job.
+A quarantined test is identified by its name and optionally its testsuite.`,
+}
+
+func applicationQuarantine() *cobra.Command {
+	return cli.NewCommand(applicationQuarantineCmd, nil, []*cobra.Command{
+		cli.NewListCommand(applicationQuarantineListCmd, applicationQuarantineListRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(applicationQuarantineAddCmd, applicationQuarantineAddRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(applicationQuarantineDeleteCmd, applicationQuarantineDeleteRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var applicationQuarantineListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS application quarantined tests",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+}
+
+func applicationQuarantineListRun(v cli.Values) (cli.ListResult, error) {
+	qs, err := client.ApplicationTestQuarantineList(v.GetString(_ProjectKey), v.GetString(_ApplicationName))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(qs), nil
+}
+
+var applicationQuarantineAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Add a test in the quarantine of an application",
+	Long: `Add a test in the quarantine of an application:
+
+	# Quarantine the test TestFoo of all testsuites for a week
+	$ cdsctl application quarantine add MYPROJECT myapp TestFoo --reason "timeout on CI" --expire 168h
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+	Args: []cli.Arg{
+		{Name: "test-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "test-suite",
+			Usage: "Name of the testsuite of the test, all testsuites by default",
+		},
+		{
+			Name:  "reason",
+			Usage: "Why the test is quarantined",
+		},
+		{
+			Name:  "owner",
+			Usage: "Owner of the quarantined test, current user by default",
+		},
+		{
+			Name:  "expire",
+			Usage: "Expiry of the quarantine as a duration (ie. 168h) or a date (ie. 2006-01-02), no expiry by default",
+		},
+	},
+}
+
+func applicationQuarantineAddRun(v cli.Values) error {
+	q := &sdk.TestQuarantine{
+		TestSuite: v.GetString("test-suite"),
+		Name:      v.GetString("test-name"),
+		Reason:    v.GetString("reason"),
+		Owner:     v.GetString("owner"),
+	}
+
+	if expire := v.GetString("expire"); expire != "" {
+		var t time.Time
+		if d, err := time.ParseDuration(expire); err == nil {
+			t = time.Now().Add(d)
+		} else if t, err = time.Parse("2006-01-02", expire); err != nil {
+			return fmt.Errorf("invalid expire value %s: it must be a duration or a date", expire)
+		}
+		q.Expire = &t
+	}
+
+	if err := client.ApplicationTestQuarantineAdd(v.GetString(_ProjectKey), v.GetString(_ApplicationName), q); err != nil {
+		return err
+	}
+	fmt.Printf("Test %s quarantined with id %d in application %s\n", q.Name, q.ID, v.GetString(_ApplicationName))
+	return nil
+}
+
+var applicationQuarantineDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Remove a test from the quarantine of an application",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func applicationQuarantineDeleteRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v.GetString("id"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid id %s: %v", v.GetString("id"), err)
+	}
+	return client.ApplicationTestQuarantineDelete(v.GetString(_ProjectKey), v.GetString(_ApplicationName), id)
+}
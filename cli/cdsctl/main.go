@@ -33,6 +33,7 @@ func main() {
 		group(),
 		health(),
 		project(),
+		sbom(),
 		worker(),
 		workflow(),
 		update(),
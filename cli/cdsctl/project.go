@@ -30,6 +30,8 @@ func projectCommands() []*cobra.Command {
 		projectVariable(),
 		projectIntegration(),
 		projectRepositoryManager(),
+		projectVulnerabilityPolicy(),
+		projectFreeze(),
 	}
 }
 
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var projectFreezeCmd = cli.Command{
+	Name:  "freeze",
+	Short: "Manage CDS project freeze windows",
+	Long: `During a freeze window, the pipelines that deploy on an environment of the project do not start:
+they are held until the end of the window (action "hold") or they fail (action "fail").
+A window is either recurring, starting at each occurrence of a cron expression for a duration,
+or a range of dates. Without environment, a window applies to all the environments of the project.
+
+The members of the override group of a window can still start the workflows manually.`,
+}
+
+func projectFreeze() *cobra.Command {
+	return cli.NewCommand(projectFreezeCmd, nil, []*cobra.Command{
+		cli.NewListCommand(projectFreezeListCmd, projectFreezeListRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectFreezeAddCmd, projectFreezeAddRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectFreezeUpdateCmd, projectFreezeUpdateRun, nil, withAllCommandModifiers()...),
+		cli.NewDeleteCommand(projectFreezeDeleteCmd, projectFreezeDeleteRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var projectFreezeListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS project freeze windows",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectFreezeListRun(v cli.Values) (cli.ListResult, error) {
+	fs, err := client.ProjectFreezeWindowList(v.GetString(_ProjectKey))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(fs), nil
+}
+
+var projectFreezeFlags = []cli.Flag{
+	{
+		Name:  "environment",
+		Usage: "Name of the environment of the window, all the environments of the project by default",
+	},
+	{
+		Name:  "cron",
+		Usage: "Cron expression of the starts of a recurring window (ie. \"0 14 * * 5\")",
+	},
+	{
+		Name:  "duration",
+		Usage: "Duration of a recurring window (ie. 60h)",
+	},
+	{
+		Name:  "from",
+		Usage: "Start of a range, as a date (ie. 2006-01-02) or a time (ie. 2006-01-02T15:04)",
+	},
+	{
+		Name:  "to",
+		Usage: "End of a range, as a date (ie. 2006-01-02, the day is included) or a time (ie. 2006-01-02T15:04)",
+	},
+	{
+		Name:  "timezone",
+		Usage: "Timezone of the cron expression and of the range, UTC by default",
+	},
+	{
+		Name:  "action",
+		Usage: "hold or fail, hold by default",
+	},
+	{
+		Name:  "override-group",
+		Usage: "Name of the group whose members can start the workflows during the window",
+	},
+	{
+		Name:  "reason",
+		Usage: "Why the deployments are frozen",
+	},
+}
+
+var projectFreezeAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Add a freeze window in a project",
+	Long: `Add a freeze window in a project:
+
+	# No production deployments on Friday afternoon
+	$ cdsctl project freeze add MYPROJECT friday --environment production --cron "0 14 * * 5" --duration 10h --timezone Europe/Paris --reason "Friday afternoon"
+
+	# End-of-year freeze, the release managers can still deploy
+	$ cdsctl project freeze add MYPROJECT end-of-year --from 2019-12-20 --to 2020-01-03 --action fail --override-group release-managers
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+	Flags: projectFreezeFlags,
+}
+
+func projectFreezeAddRun(v cli.Values) error {
+	f := &sdk.FreezeWindow{Name: v.GetString("name")}
+	if err := projectFreezeFromFlags(v, f); err != nil {
+		return err
+	}
+	if err := client.ProjectFreezeWindowCreate(v.GetString(_ProjectKey), f); err != nil {
+		return err
+	}
+	fmt.Printf("Freeze window %s added in project %s\n", f.Name, v.GetString(_ProjectKey))
+	return nil
+}
+
+var projectFreezeUpdateCmd = cli.Command{
+	Name:  "update",
+	Short: "Update a freeze window of a project, only the given flags are changed",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+	Flags: projectFreezeFlags,
+}
+
+func projectFreezeUpdateRun(v cli.Values) error {
+	fs, err := client.ProjectFreezeWindowList(v.GetString(_ProjectKey))
+	if err != nil {
+		return err
+	}
+	var f *sdk.FreezeWindow
+	for i := range fs {
+		if fs[i].Name == v.GetString("name") {
+			f = &fs[i]
+		}
+	}
+	if f == nil {
+		return fmt.Errorf("freeze window %s not found in project %s", v.GetString("name"), v.GetString(_ProjectKey))
+	}
+
+	// A recurring window becomes a range, and conversely
+	if v.GetString("cron") != "" || v.GetString("duration") != "" {
+		f.From, f.To = nil, nil
+	}
+	if v.GetString("from") != "" || v.GetString("to") != "" {
+		f.Cron, f.Duration = "", ""
+	}
+	if err := projectFreezeFromFlags(v, f); err != nil {
+		return err
+	}
+	if err := client.ProjectFreezeWindowUpdate(v.GetString(_ProjectKey), v.GetString("name"), f); err != nil {
+		return err
+	}
+	fmt.Printf("Freeze window %s updated in project %s\n", f.Name, v.GetString(_ProjectKey))
+	return nil
+}
+
+// projectFreezeFromFlags sets the fields of a freeze window from the flags that are given
+func projectFreezeFromFlags(v cli.Values, f *sdk.FreezeWindow) error {
+	set := func(flag string, field *string) {
+		if value := v.GetString(flag); value != "" {
+			*field = value
+		}
+	}
+	set("environment", &f.EnvironmentName)
+	set("cron", &f.Cron)
+	set("duration", &f.Duration)
+	set("timezone", &f.Timezone)
+	set("action", &f.Action)
+	set("override-group", &f.OverrideGroup)
+	set("reason", &f.Reason)
+
+	loc := time.UTC
+	if f.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(f.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %s: %v", f.Timezone, err)
+		}
+	}
+	if from := v.GetString("from"); from != "" {
+		t, _, err := parseFreezeTime(from, loc)
+		if err != nil {
+			return err
+		}
+		f.From = &t
+	}
+	if to := v.GetString("to"); to != "" {
+		t, isDay, err := parseFreezeTime(to, loc)
+		if err != nil {
+			return err
+		}
+		if isDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		f.To = &t
+	}
+	return nil
+}
+
+// parseFreezeTime parses a date or a time, and returns true for a date
+func parseFreezeTime(s string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02T15:04", s, loc); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid date %s: it must be a date (ie. 2006-01-02) or a time (ie. 2006-01-02T15:04)", s)
+}
+
+var projectFreezeDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a freeze window of a project",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "name"},
+	},
+}
+
+func projectFreezeDeleteRun(v cli.Values) error {
+	return client.ProjectFreezeWindowDelete(v.GetString(_ProjectKey), v.GetString("name"))
+}
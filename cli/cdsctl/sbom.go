@@ -0,0 +1,62 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var sbomCmd = cli.Command{
+	Name:  "sbom",
+	Short: "Manage CDS software bill of materials",
+}
+
+func sbom() *cobra.Command {
+	return cli.NewCommand(sbomCmd, nil, []*cobra.Command{
+		cli.NewListCommand(sbomSearchCmd, sbomSearchRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var sbomSearchCmd = cli.Command{
+	Name:  "search",
+	Short: "Search the workflow runs that shipped a component",
+	Long: `Search in the software bill of materials uploaded by the SBOM builtin action the applications and workflow runs that contain a component.
+
+	# Search the runs that shipped log4j-core 2.14.1
+	$ cdsctl sbom search --name log4j-core --version 2.14.1
+
+	# Search by package url
+	$ cdsctl sbom search --purl pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1
+`,
+	Flags: []cli.Flag{
+		{
+			Name:  "name",
+			Usage: "Name of the component",
+		},
+		{
+			Name:  "version",
+			Usage: "Version of the component",
+		},
+		{
+			Name:  "purl",
+			Usage: "Package url of the component",
+		},
+	},
+}
+
+func sbomSearchRun(v cli.Values) (cli.ListResult, error) {
+	filter := sdk.SBOMComponentFilter{
+		Name:    v.GetString("name"),
+		Version: v.GetString("version"),
+		PURL:    v.GetString("purl"),
+	}
+	if err := filter.IsValid(); err != nil {
+		return nil, err
+	}
+	res, err := client.SBOMComponentUsages(filter)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(res), nil
+}
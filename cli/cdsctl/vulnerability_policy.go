@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+const vulnerabilityPolicyLong = `A vulnerability policy is evaluated each time a vulnerability report is uploaded by a job.
+When the policy is not respected, the job fails (action "fail") or the pipelines after the
+current one are not triggered (action "block_triggers").
+
+The policy of an application takes precedence over the policy of its project.
+
+Example of policy file:
+
+	action: fail
+	only_default_branch: false
+	# fail if there are new vulnerabilities with given severity or higher
+	fail_on_new: high
+	# maximum number of vulnerabilities by severity
+	max_by_severity:
+	  critical: 0
+	  high: 10
+	# vulnerabilities excluded from the evaluation
+	ignores:
+	- id: CVE-2019-0001
+	  component: github.com/foo/bar
+	  justification: not exploitable in our context
+	  expire: 2020-01-01T00:00:00Z
+`
+
+var projectVulnerabilityPolicyCmd = cli.Command{
+	Name:  "vulnerability-policy",
+	Short: "Manage CDS project vulnerability policy",
+	Long:  vulnerabilityPolicyLong,
+}
+
+func projectVulnerabilityPolicy() *cobra.Command {
+	ctx := []cli.Arg{{Name: _ProjectKey}}
+	return cli.NewCommand(projectVulnerabilityPolicyCmd, nil, vulnerabilityPolicyCommands(ctx))
+}
+
+var applicationVulnerabilityPolicyCmd = cli.Command{
+	Name:  "vulnerability-policy",
+	Short: "Manage CDS application vulnerability policy",
+	Long:  vulnerabilityPolicyLong,
+}
+
+func applicationVulnerabilityPolicy() *cobra.Command {
+	ctx := []cli.Arg{{Name: _ProjectKey}, {Name: _ApplicationName}}
+	return cli.NewCommand(applicationVulnerabilityPolicyCmd, nil, vulnerabilityPolicyCommands(ctx))
+}
+
+func vulnerabilityPolicyCommands(ctx []cli.Arg) []*cobra.Command {
+	showCmd := cli.Command{
+		Name:  "show",
+		Short: "Show the vulnerability policy",
+		Ctx:   ctx,
+	}
+	exportCmd := cli.Command{
+		Name:  "export",
+		Short: "Export the vulnerability policy as a yaml file",
+		Ctx:   ctx,
+	}
+	importCmd := cli.Command{
+		Name:  "import",
+		Short: "Import the vulnerability policy from a yaml file",
+		Ctx:   ctx,
+		Args: []cli.Arg{
+			{Name: "file"},
+		},
+	}
+	deleteCmd := cli.Command{
+		Name:  "delete",
+		Short: "Delete the vulnerability policy",
+		Ctx:   ctx,
+	}
+
+	return []*cobra.Command{
+		cli.NewGetCommand(showCmd, vulnerabilityPolicyShowRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(exportCmd, vulnerabilityPolicyExportRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(importCmd, vulnerabilityPolicyImportRun, nil, withAllCommandModifiers()...),
+		cli.NewDeleteCommand(deleteCmd, vulnerabilityPolicyDeleteRun, nil, withAllCommandModifiers()...),
+	}
+}
+
+func vulnerabilityPolicyShowRun(v cli.Values) (interface{}, error) {
+	return client.VulnerabilityPolicyGet(v.GetString(_ProjectKey), v.GetString(_ApplicationName))
+}
+
+func vulnerabilityPolicyExportRun(v cli.Values) error {
+	p, err := client.VulnerabilityPolicyGet(v.GetString(_ProjectKey), v.GetString(_ApplicationName))
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("unable to marshal: %v", err)
+	}
+
+	fmt.Println(string(b))
+	return nil
+}
+
+func vulnerabilityPolicyImportRun(v cli.Values) error {
+	b, err := ioutil.ReadFile(v.GetString("file"))
+	if err != nil {
+		return fmt.Errorf("unable to read file %s: %v", v.GetString("file"), err)
+	}
+
+	p := new(sdk.VulnerabilityPolicy)
+	if err := yaml.Unmarshal(b, p); err != nil {
+		return fmt.Errorf("unable to load file: %v", err)
+	}
+
+	return client.VulnerabilityPolicySet(v.GetString(_ProjectKey), v.GetString(_ApplicationName), p)
+}
+
+func vulnerabilityPolicyDeleteRun(v cli.Values) error {
+	return client.VulnerabilityPolicyDelete(v.GetString(_ProjectKey), v.GetString(_ApplicationName))
+}
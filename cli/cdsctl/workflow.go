@@ -21,6 +21,7 @@ func workflow() *cobra.Command {
 		cli.NewCommand(templateApplyCmd("applyTemplate"), templateApplyRun, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(workflowListCmd, workflowListRun, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(workflowHistoryCmd, workflowHistoryRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowQueueCmd, workflowQueueRun, nil, withAllCommandModifiers()...),
 		cli.NewGetCommand(workflowShowCmd, workflowShowRun, nil, withAllCommandModifiers()...),
 		cli.NewGetCommand(workflowStatusCmd, workflowStatusRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowRunManualCmd, workflowRunManualRun, nil, withAllCommandModifiers()...),
@@ -32,6 +33,8 @@ func workflow() *cobra.Command {
 		cli.NewCommand(workflowFavoriteCmd, workflowFavoriteRun, nil, withAllCommandModifiers()...),
 		cli.NewGetCommand(workflowTransformAsCodeCmd, workflowTransformAsCodeRun, nil, withAllCommandModifiers()...),
 		workflowArtifact(),
+		workflowTests(),
+		workflowHook(),
 		workflowLog(),
 		workflowAdvanced(),
 	})
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowHookCmd = cli.Command{
+	Name:  "hook",
+	Short: "Manage CDS workflow hooks and their executions",
+}
+
+func workflowHook() *cobra.Command {
+	return cli.NewCommand(workflowHookCmd, nil, []*cobra.Command{
+		cli.NewListCommand(workflowHookListCmd, workflowHookListRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowHookExecutionsCmd, workflowHookExecutionsRun, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(workflowHookExecutionCmd, workflowHookExecutionRun, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(workflowHookReplayCmd, workflowHookReplayRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var workflowHookListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the hooks of a CDS workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+}
+
+type workflowHookDisplay struct {
+	UUID  string `cli:"uuid,key"`
+	Model string `cli:"model"`
+	Node  string `cli:"node"`
+}
+
+func workflowHookListRun(v cli.Values) (cli.ListResult, error) {
+	wf, err := client.WorkflowGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName))
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := []workflowHookDisplay{}
+	for _, n := range wf.WorkflowData.Array() {
+		for _, h := range n.Hooks {
+			hooks = append(hooks, workflowHookDisplay{UUID: h.UUID, Model: h.HookModelName, Node: n.Name})
+		}
+	}
+	return cli.AsListResult(hooks), nil
+}
+
+var workflowHookExecutionsCmd = cli.Command{
+	Name:  "executions",
+	Short: "List the last executions of a hook of a CDS workflow",
+	Long: `List the last executions of a hook with the number of the workflow run they triggered.
+
+	# Get the UUID of the hooks of a workflow
+	$ cdsctl workflow hook list MYPROJECT myworkflow
+	$ cdsctl workflow hook executions MYPROJECT myworkflow 3ab1f1a0-1c1c-4a5b-9f8e-5e4e5d5c5b5a
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "uuid"},
+	},
+}
+
+func workflowHookExecutionsRun(v cli.Values) (cli.ListResult, error) {
+	execs, err := client.WorkflowHookExecutions(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetString("uuid"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(execs), nil
+}
+
+var workflowHookExecutionCmd = cli.Command{
+	Name:  "execution",
+	Short: "Display an execution of a hook of a CDS workflow, with the data it received and the payload of the workflow run",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "uuid"},
+		{Name: "timestamp"},
+	},
+}
+
+type workflowHookExecutionDisplay struct {
+	sdk.TaskExecution
+	Replayed int64  `json:"-" cli:"replay_of"`
+	Received string `json:"-" cli:"received"`
+	Data     string `json:"-" cli:"payload"`
+}
+
+func workflowHookExecutionRun(v cli.Values) (interface{}, error) {
+	timestamp, err := v.GetInt64("timestamp")
+	if err != nil {
+		return nil, err
+	}
+	e, err := client.WorkflowHookExecution(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetString("uuid"), timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return newWorkflowHookExecutionDisplay(*e), nil
+}
+
+var workflowHookReplayCmd = cli.Command{
+	Name:  "replay",
+	Short: "Replay an execution of a hook of a CDS workflow",
+	Long: `Replay an execution of a hook: the workflow is triggered again with the payload of the execution, or with the given payload.
+
+	# Replay an execution with another version
+	$ cdsctl workflow hook replay MYPROJECT myworkflow 3ab1f1a0-1c1c-4a5b-9f8e-5e4e5d5c5b5a 1546300800000000000 -d '{"version": "1.2.4"}'
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "uuid"},
+		{Name: "timestamp"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:      "data",
+			ShortHand: "d",
+			Usage:     "Replay the execution with this payload, a JSON object of strings",
+			IsValid: func(s string) bool {
+				if strings.TrimSpace(s) == "" {
+					return true
+				}
+				data := map[string]string{}
+				return json.Unmarshal([]byte(s), &data) == nil
+			},
+		},
+	},
+}
+
+func workflowHookReplayRun(v cli.Values) (interface{}, error) {
+	timestamp, err := v.GetInt64("timestamp")
+	if err != nil {
+		return nil, err
+	}
+
+	var payload map[string]string
+	if strings.TrimSpace(v.GetString("data")) != "" {
+		if err := json.Unmarshal([]byte(v.GetString("data")), &payload); err != nil {
+			return nil, fmt.Errorf("Error payload isn't a valid json object of strings")
+		}
+	}
+
+	e, err := client.WorkflowHookExecutionReplay(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetString("uuid"), timestamp, payload)
+	if err != nil {
+		return nil, err
+	}
+	return newWorkflowHookExecutionDisplay(*e), nil
+}
+
+func newWorkflowHookExecutionDisplay(e sdk.TaskExecution) workflowHookExecutionDisplay {
+	d := workflowHookExecutionDisplay{TaskExecution: e}
+
+	payload := e.Payload
+	if e.Replay != nil {
+		d.Replayed = e.Replay.Timestamp
+		if payload == nil {
+			payload = e.Replay.Payload
+		}
+	}
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = k + "=" + payload[k]
+	}
+	d.Data = strings.Join(lines, "\n")
+
+	switch {
+	case e.WebHook != nil:
+		d.Received = string(e.WebHook.RequestBody)
+		if d.Received == "" {
+			d.Received = e.WebHook.RequestURL
+		}
+	case e.Kafka != nil:
+		d.Received = string(e.Kafka.Message)
+	case e.RabbitMQ != nil:
+		d.Received = string(e.RabbitMQ.Message)
+	case e.NATS != nil:
+		d.Received = e.NATS.Subject + ": " + string(e.NATS.Message)
+	case e.MQTT != nil:
+		d.Received = e.MQTT.Topic + ": " + string(e.MQTT.Message)
+	case e.GerritEvent != nil:
+		d.Received = string(e.GerritEvent.Message)
+	case e.ScheduledTask != nil:
+		d.Received = e.ScheduledTask.DateScheduledExecution
+	}
+	return d
+}
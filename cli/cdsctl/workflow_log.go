@@ -2,8 +2,9 @@ package main
 
 import (
 	"fmt"
-	"io/ioutil"
+	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -32,6 +33,12 @@ var workflowLogCmd = cli.Command{
 	$ cdsctl workflow logs download KEY WF 1 --pattern="MyJob"
 	# this will download file WF-1.0-pipeline.myPipeline-stage.MyStage-job.MyJob-status.Success-step.0.log
 
+	# search a string in the logs of the 10 latest runs
+	$ cdsctl workflow logs search KEY WF "connection refused"
+
+	# display the sections and the errors of the logs of the latest run
+	$ cdsctl workflow logs summary KEY WF
+
 `,
 }
 
@@ -39,6 +46,8 @@ func workflowLog() *cobra.Command {
 	return cli.NewCommand(workflowLogCmd, nil, []*cobra.Command{
 		cli.NewCommand(workflowLogListCmd, workflowLogListRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowLogDownloadCmd, workflowLogDownloadRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowLogSearchCmd, workflowLogSearchRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowLogSummaryCmd, workflowLogSummaryRun, nil, withAllCommandModifiers()...),
 	})
 }
 
@@ -236,19 +245,22 @@ func workflowLogDownloadRun(v cli.Values) error {
 			continue
 		}
 
-		buildState, err := client.WorkflowNodeRunJobStep(v.GetString(_ProjectKey),
+		f, err := os.OpenFile(log.getFilename(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		if err := client.WorkflowNodeRunJobStepLog(v.GetString(_ProjectKey),
 			v.GetString(_WorkflowName),
 			runNumber,
 			log.runID,
 			log.jobID,
 			log.stepOrder,
-		)
-		if err != nil {
+			f,
+		); err != nil {
+			f.Close()
 			return err
 		}
-
-		d1 := []byte(buildState.StepLogs.Val)
-		if err := ioutil.WriteFile(log.getFilename(), d1, 0644); err != nil {
+		if err := f.Close(); err != nil {
 			return err
 		}
 		fmt.Printf("file %s created\n", log.getFilename())
@@ -260,3 +272,171 @@ func workflowLogDownloadRun(v cli.Values) error {
 	}
 	return nil
 }
+
+var workflowLogSearchCmd = cli.Command{
+	Name:  "search",
+	Short: "Search in logs of the latest runs of a workflow",
+	Long: `Search a string or a regular expression in the step logs of the latest runs of a workflow.
+
+	# search a string in the logs of the 10 latest runs
+	$ cdsctl workflow logs search KEY WF "connection refused"
+
+	# search a regular expression in the logs of the 50 latest runs, with 5 lines of context
+	$ cdsctl workflow logs search KEY WF "panic: .*" --regex --runs 50 --context 5
+
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "query"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "regex",
+			Usage: "The query is a regular expression",
+			Type:  cli.FlagBool,
+		},
+		{
+			Name:    "runs",
+			Usage:   "Number of latest runs to search in",
+			Default: strconv.Itoa(sdk.LogSearchDefaultRuns),
+		},
+		{
+			Name:    "context",
+			Usage:   "Number of lines displayed before and after each matching line",
+			Default: strconv.Itoa(sdk.LogSearchDefaultContext),
+		},
+		{
+			Name:    "limit",
+			Usage:   "Maximum number of matching lines",
+			Default: strconv.Itoa(sdk.LogSearchDefaultLimit),
+		},
+	},
+}
+
+func workflowLogSearchRun(v cli.Values) error {
+	runs, err := v.GetInt64("runs")
+	if err != nil {
+		return err
+	}
+	nbLines, err := v.GetInt64("context")
+	if err != nil {
+		return err
+	}
+	limit, err := v.GetInt64("limit")
+	if err != nil {
+		return err
+	}
+
+	res, err := client.WorkflowLogSearch(v.GetString(_ProjectKey), v.GetString(_WorkflowName), sdk.LogSearchRequest{
+		Query:   v.GetString("query"),
+		Regex:   v.GetBool("regex"),
+		Runs:    runs,
+		Context: int(nbLines),
+		Limit:   int(limit),
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, h := range res.Hits {
+		if i > 0 && nbLines > 0 {
+			fmt.Println("--")
+		}
+		prefix := fmt.Sprintf("#%d %s/%s/%s", h.RunNumber, h.NodeName, h.JobName, h.StepName)
+		for j, l := range h.Before {
+			fmt.Printf("%s-%d-%s\n", prefix, h.Line-len(h.Before)+j, l)
+		}
+		fmt.Printf("%s:%d:%s\n", prefix, h.Line, h.Content)
+		for j, l := range h.After {
+			fmt.Printf("%s-%d-%s\n", prefix, h.Line+1+j, l)
+		}
+	}
+	if res.Partial {
+		fmt.Fprintln(os.Stderr, "Results are partial: some archived logs were not searched, search in less runs to search all of them")
+	}
+	return nil
+}
+
+var workflowLogSummaryCmd = cli.Command{
+	Name:  "summary",
+	Short: "Display the sections and the errors of the logs of a workflow run",
+	Long: `Display for each step of a workflow run the duration of the sections of its log, then its warning and error lines.
+
+Sections, warnings and errors are delimited in logs by lines starting with the markers ::cds-section::<name>,
+::cds-endsection::, ::cds-warning::<message> and ::cds-error::<message>.
+
+	# display the summary of the logs of the latest run
+	$ cdsctl workflow logs summary KEY WF
+
+	# display the summary of the logs of one job, for run number 1
+	$ cdsctl workflow logs summary KEY WF 1 --pattern="MyJob"
+
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	OptionalArgs: []cli.Arg{
+		{
+			Name: "run-number",
+			IsValid: func(s string) bool {
+				match, _ := regexp.MatchString(`[0-9]?`, s)
+				return match
+			},
+			Weight: 1,
+		},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "pattern",
+			Usage: "Filter on log filename",
+		},
+	},
+}
+
+func workflowLogSummaryRun(v cli.Values) error {
+	runNumber, err := workflowLogSearchNumber(v)
+	if err != nil {
+		return err
+	}
+
+	wr, err := client.WorkflowRunGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber)
+	if err != nil {
+		return err
+	}
+
+	var reg *regexp.Regexp
+	if v.GetString("pattern") != "" {
+		reg, err = regexp.Compile(v.GetString("pattern"))
+		if err != nil {
+			return fmt.Errorf("Invalid pattern %s: %v", v.GetString("pattern"), err)
+		}
+	}
+
+	for _, log := range workflowLogProcess(wr) {
+		if reg != nil && !reg.MatchString(log.getFilename()) {
+			continue
+		}
+
+		state, err := client.WorkflowNodeRunJobStep(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber, log.runID, log.jobID, log.stepOrder)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(log.getFilename())
+		for _, s := range state.Sections {
+			fmt.Printf("  %-40s %10s  lines %d-%d", s.Name, s.Duration, s.FirstLine, s.LastLine)
+			if s.Warnings > 0 || s.Errors > 0 {
+				fmt.Printf("  (%d warnings, %d errors)", s.Warnings, s.Errors)
+			}
+			fmt.Println()
+		}
+		for _, p := range state.Problems {
+			fmt.Printf("  %s:%d: %s\n", p.Level, p.Number, p.Text)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"time"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowQueueCmd = cli.Command{
+	Name:  "queue",
+	Short: "Display the position in the queue of the waiting jobs of a CDS workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+}
+
+func workflowQueueRun(v cli.Values) (cli.ListResult, error) {
+	jobs, err := client.QueueWorkflowNodeJobRun(sdk.StatusWaiting)
+	if err != nil {
+		return nil, err
+	}
+
+	type jobDisplay struct {
+		Position      int    `cli:"position"`
+		ID            int64  `cli:"id,key"`
+		Run           string `cli:"run"`
+		Job           string `cli:"job"`
+		Priority      int    `cli:"priority"`
+		Queued        string `cli:"queued"`
+		EstimatedWait string `cli:"estimated_wait"`
+		BookedBy      string `cli:"booked_by"`
+	}
+
+	res := []jobDisplay{}
+	for _, j := range jobs {
+		if p, _ := j.Header.Get(sdk.ProjectKeyHeader); p != v.GetString(_ProjectKey) {
+			continue
+		}
+		if w, _ := j.Header.Get(sdk.WorkflowHeader); w != v.GetString(_WorkflowName) {
+			continue
+		}
+		run, _ := j.Header.Get(sdk.WorkflowRunHeader)
+		res = append(res, jobDisplay{
+			Position:      j.QueuePosition,
+			ID:            j.ID,
+			Run:           run,
+			Job:           j.Job.Action.Name,
+			Priority:      j.Priority,
+			Queued:        (time.Duration(j.QueuedSeconds) * time.Second).String(),
+			EstimatedWait: (time.Duration(j.EstimatedWaitSeconds) * time.Second).String(),
+			BookedBy:      j.BookedBy.Name,
+		})
+	}
+	return cli.AsListResult(res), nil
+}
@@ -85,6 +85,17 @@ var workflowRunManualCmd = cli.Command{
 			Usage:     "Synchronise your pipelines with your last editions. Must be used with flag run-number",
 			Type:      cli.FlagBool,
 		},
+		{
+			Name:  "priority",
+			Usage: fmt.Sprintf("Priority of the jobs in the queue, between %d and %d", sdk.WorkflowNodeJobRunPriorityMin, sdk.WorkflowNodeJobRunPriorityMax),
+			IsValid: func(s string) bool {
+				if s == "" {
+					return true
+				}
+				p, err := strconv.Atoi(s)
+				return err == nil && p >= sdk.WorkflowNodeJobRunPriorityMin && p <= sdk.WorkflowNodeJobRunPriorityMax
+			},
+		},
 	},
 }
 
@@ -129,6 +140,14 @@ func workflowRunManualRun(v cli.Values) error {
 		}
 	}
 
+	if v.GetString("priority") != "" {
+		p, err := strconv.Atoi(v.GetString("priority"))
+		if err != nil {
+			return fmt.Errorf("priority invalid: not a integer")
+		}
+		manual.Priority = &p
+	}
+
 	var runNumber, fromNodeID int64
 
 	if v.GetString("run-number") != "" {
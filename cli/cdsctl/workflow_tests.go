@@ -0,0 +1,110 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowTestsCmd = cli.Command{
+	Name:  "tests",
+	Short: "Manage CDS workflow tests history",
+}
+
+func workflowTests() *cobra.Command {
+	return cli.NewCommand(workflowTestsCmd, nil, []*cobra.Command{
+		cli.NewListCommand(workflowTestsListCmd, workflowTestsListRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowTestsHistoryCmd, workflowTestsHistoryRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var workflowTestsListCmd = cli.Command{
+	Name:  "list",
+	Short: "List the tests of the last runs of a CDS workflow",
+	Long: `List the test cases of the last runs of a CDS workflow with their number of failures and their average duration.
+
+A test case is flaky if it succeeded and failed on the same commit.
+
+	# List the tests that failed in the last 20 runs on the master branch
+	$ cdsctl workflow tests list MYPROJECT myworkflow --branch master --runs 20 --failed
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "branch",
+			Usage: "Only look at the runs of this branch",
+		},
+		{
+			Name:    "runs",
+			Usage:   "Number of last runs to look at",
+			Default: "20",
+		},
+		{
+			Name:  "failed",
+			Usage: "Only list the tests that failed at least once",
+			Type:  cli.FlagBool,
+		},
+		{
+			Name:  "flaky",
+			Usage: "Only list the flaky tests",
+			Type:  cli.FlagBool,
+		},
+	},
+}
+
+func workflowTestsListRun(v cli.Values) (cli.ListResult, error) {
+	runs, err := v.GetInt64("runs")
+	if err != nil {
+		return nil, err
+	}
+	tests, err := client.WorkflowTests(v.GetString(_ProjectKey), v.GetString(_WorkflowName), sdk.WorkflowTestCaseFilter{
+		Branch:     v.GetString("branch"),
+		Runs:       runs,
+		OnlyFailed: v.GetBool("failed"),
+		OnlyFlaky:  v.GetBool("flaky"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(tests), nil
+}
+
+var workflowTestsHistoryCmd = cli.Command{
+	Name:  "history",
+	Short: "Display the results of a test in the last runs of a CDS workflow",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "test-suite"},
+		{Name: "test-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "branch",
+			Usage: "Only look at the runs of this branch",
+		},
+		{
+			Name:    "limit",
+			Usage:   "Number of results to display",
+			Default: "20",
+		},
+	},
+}
+
+func workflowTestsHistoryRun(v cli.Values) (cli.ListResult, error) {
+	limit, err := v.GetInt64("limit")
+	if err != nil {
+		return nil, err
+	}
+	history, err := client.WorkflowTestHistory(v.GetString(_ProjectKey), v.GetString(_WorkflowName), v.GetString("test-suite"), v.GetString("test-name"), v.GetString("branch"), limit)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(history), nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -35,6 +36,23 @@ func SendVulnerabilityReport(workerHTTPPort int32, report sdk.VulnerabilityWorke
 		return fmt.Errorf("cannot send report to worker /vulnerability: HTTP %d", resp.StatusCode)
 	}
 
+	// The worker returns the evaluation of the vulnerability policy, older workers return an empty body
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read body /vulnerability: %v", err)
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil
+	}
+	var evaluation *sdk.VulnerabilityPolicyEvaluation
+	if err := json.Unmarshal(b, &evaluation); err != nil {
+		return fmt.Errorf("cannot read vulnerability policy evaluation: %v", err)
+	}
+	if evaluation.FailsNodeRun() {
+		return fmt.Errorf("vulnerability policy not respected: %s", strings.Join(evaluation.Reasons, ", "))
+	}
+
 	return nil
 }
 
@@ -132,6 +132,7 @@ type Configuration struct {
 			SecretAccessKey     string `toml:"secretAccessKey" json:"-" comment:"A static AWS Secret Access Key"`
 			SessionToken        string `toml:"sessionToken" json:"-" comment:"A static AWS session token"`
 		} `toml:"awss3" json:"awss3"`
+		CacheSizeLimit int64 `toml:"cacheSizeLimit" default:"10240" comment:"Max size in Mo of the worker caches of a project in a storage. Least recently used caches are deleted when the limit is reached. 0 for no limit" json:"cacheSizeLimit"`
 	} `toml:"artifact" comment:"Either filesystem local storage or Openstack Swift Storage are supported" json:"artifact"`
 	Events struct {
 		Kafka struct {
@@ -187,8 +188,10 @@ type Configuration struct {
 		URL         string `toml:"url" comment:"Example: http://localhost:9000" json:"url"`
 	} `toml:"graylog" json:"graylog" comment:"###########################\n Graylog Search. \n When CDS API generates errors, you can fetch them with cdsctl. \n Examples: \n $ cdsctl admin errors get <error-id> \n $ cdsctl admin errors get 55f6e977-d39b-11e8-8513-0242ac110007 \n##########################"`
 	Log struct {
-		StepMaxSize    int64 `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
-		ServiceMaxSize int64 `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
+		StepMaxSize    int64  `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
+		ServiceMaxSize int64  `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
+		Archive        bool   `toml:"archive" default:"false" comment:"Move the logs of terminated runs, and the logs reaching stepMaxSize instead of truncating them, to the artifact storage" json:"archive"`
+		SearchIndex    string `toml:"searchIndex" default:"postgres" comment:"Index used to search in step logs: postgres or elasticsearch" json:"searchIndex"`
 	} `toml:"log" json:"log" comment:"###########################\n Log settings.\n##########################"`
 }
 
@@ -492,6 +495,14 @@ func (a *API) mustDBWithCtx(ctx context.Context) *gorp.DbMap {
 	return db
 }
 
+// logStorage returns the storage where step logs are moved, nil if logs stay in database.
+func (a *API) logStorage() objectstore.Driver {
+	if !a.Config.Log.Archive {
+		return nil
+	}
+	return a.SharedStorage
+}
+
 // Serve will start the http api server
 func (a *API) Serve(ctx context.Context) error {
 	log.Info("Starting CDS API Server %s", sdk.VERSION)
@@ -851,6 +862,14 @@ func (a *API) Serve(ctx context.Context) error {
 		func(ctx context.Context) {
 			workflow.Initialize(ctx, a.DBConnectionFactory.GetDBMap, a.Cache, a.Config.URL.UI, a.Config.DefaultOS, a.Config.DefaultArch)
 		}, a.PanicDump())
+	sdk.GoRoutine(ctx, "api.releaseFrozenNodeRunsRoutine",
+		func(ctx context.Context) {
+			a.releaseFrozenNodeRunsRoutine(ctx)
+		}, a.PanicDump())
+	sdk.GoRoutine(ctx, "api.scheduleQueueRoutine",
+		func(ctx context.Context) {
+			a.scheduleQueueRoutine(ctx)
+		}, a.PanicDump())
 	sdk.GoRoutine(ctx, "PushInElasticSearch",
 		func(ctx context.Context) {
 			event.PushInElasticSearch(ctx, a.mustDB(), a.Cache)
@@ -861,8 +880,22 @@ func (a *API) Serve(ctx context.Context) error {
 		}, a.PanicDump())
 	sdk.GoRoutine(ctx, "Purge",
 		func(ctx context.Context) {
-			purge.Initialize(ctx, a.Cache, a.DBConnectionFactory.GetDBMap, a.Metrics.WorkflowRunsMarkToDelete, a.Metrics.WorkflowRunsDeleted)
+			purge.Initialize(ctx, a.Cache, a.DBConnectionFactory.GetDBMap, a.SharedStorage, a.Metrics.WorkflowRunsMarkToDelete, a.Metrics.WorkflowRunsDeleted)
 		}, a.PanicDump())
+	if a.Config.Log.SearchIndex != sdk.LogSearchIndexElasticsearch {
+		sdk.GoRoutine(ctx, "workflow.CreateLogSearchIndex",
+			func(ctx context.Context) {
+				if err := workflow.CreateLogSearchIndex(a.mustDB(), a.Cache); err != nil {
+					log.Error("workflow.CreateLogSearchIndex> %v", err)
+				}
+			}, a.PanicDump())
+	}
+	if a.Config.Log.Archive {
+		sdk.GoRoutine(ctx, "workflow.ArchiveStepLogs",
+			func(ctx context.Context) {
+				workflow.ArchiveStepLogs(ctx, a.DBConnectionFactory.GetDBMap, a.SharedStorage)
+			}, a.PanicDump())
+	}
 
 	s := &http.Server{
 		Addr:           fmt.Sprintf("%s:%d", a.Config.HTTP.Addr, a.Config.HTTP.Port),
@@ -887,7 +920,7 @@ func (a *API) Serve(ctx context.Context) error {
 
 	go func() {
 		//TLS is disabled for the moment. We need to serve TLS on HTTP too
-		if err := grpcInit(a.DBConnectionFactory, a.Config.GRPC.Addr, a.Config.GRPC.Port, false, "", "", a.Config.Log.StepMaxSize); err != nil {
+		if err := grpcInit(a.DBConnectionFactory, a.Config.GRPC.Addr, a.Config.GRPC.Port, false, "", "", a.Config.Log.StepMaxSize, a.logStorage()); err != nil {
 			log.Error("Cannot start GRPC server: %v", err)
 		}
 	}()
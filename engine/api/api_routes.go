@@ -93,6 +93,8 @@ func (api *API) InitRouter() {
 
 	// Hooks
 	r.Handle("/hook/{uuid}/workflow/{workflowID}/vcsevent/{vcsServer}", r.GET(api.getHookPollingVCSEvents))
+	r.Handle("/hook/{uuid}/changes", r.GET(api.getHookChangedFilesHandler, NeedService()))
+	r.Handle("/hook/{uuid}/branch", r.GET(api.getHookRepositoryBranchHandler, NeedService()))
 
 	// Integration
 	r.Handle("/integration/models", r.GET(api.getIntegrationModelsHandler), r.POST(api.postIntegrationModelHandler, NeedAdmin(true)))
@@ -142,6 +144,9 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/all/keys", r.GET(api.getAllKeysProjectHandler))
 	r.Handle("/project/{permProjectKey}/keys", r.GET(api.getKeysInProjectHandler), r.POST(api.addKeyInProjectHandler))
 	r.Handle("/project/{permProjectKey}/keys/{name}", r.DELETE(api.deleteKeyInProjectHandler))
+	r.Handle("/project/{permProjectKey}/freeze", r.GET(api.getFreezeWindowsHandler), r.POST(api.postFreezeWindowHandler))
+	r.Handle("/project/{permProjectKey}/freeze/{name}", r.PUT(api.putFreezeWindowHandler), r.DELETE(api.deleteFreezeWindowHandler))
+	r.Handle("/project/{permProjectKey}/vulnerability/policy", r.GET(api.getVulnerabilityPolicyHandler), r.PUT(api.putVulnerabilityPolicyHandler), r.DELETE(api.deleteVulnerabilityPolicyHandler))
 	// Import Application
 	r.Handle("/project/{permProjectKey}/import/application", r.POST(api.postApplicationImportHandler))
 	// Export Application
@@ -161,7 +166,10 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/variable/audit", r.GET(api.getVariablesAuditInApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/variable/{name}", r.GET(api.getVariableInApplicationHandler), r.POST(api.addVariableInApplicationHandler), r.PUT(api.updateVariableInApplicationHandler), r.DELETE(api.deleteVariableFromApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/variable/{name}/audit", r.GET(api.getVariableAuditInApplicationHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/vulnerability/policy", r.GET(api.getVulnerabilityPolicyHandler), r.PUT(api.putVulnerabilityPolicyHandler), r.DELETE(api.deleteVulnerabilityPolicyHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/vulnerability/{id}", r.POST(api.postVulnerabilityHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/tests/quarantine", r.GET(api.getApplicationTestQuarantinesHandler), r.POST(api.postApplicationTestQuarantineHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/tests/quarantine/{id}", r.DELETE(api.deleteApplicationTestQuarantineHandler))
 	// Application deployment
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/deployment/config/{integration}", r.POST(api.postApplicationDeploymentStrategyConfigHandler, AllowProvider(true)), r.GET(api.getApplicationDeploymentStrategyConfigHandler), r.DELETE(api.deleteApplicationDeploymentStrategyConfigHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/deployment/config", r.GET(api.getApplicationDeploymentStrategiesConfigHandler))
@@ -205,6 +213,9 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/groups", r.POST(api.postWorkflowGroupHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/groups/{groupName}", r.PUT(api.putWorkflowGroupHandler), r.DELETE(api.deleteWorkflowGroupHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/hooks/{uuid}", r.GET(api.getWorkflowHookHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/hooks/{uuid}/executions", r.GET(api.getWorkflowHookExecutionsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/hooks/{uuid}/executions/{timestamp}", r.GET(api.getWorkflowHookExecutionHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/hooks/{uuid}/executions/{timestamp}/replay", r.POSTEXECUTE(api.postWorkflowHookExecutionReplayHandler))
 	r.Handle("/project/{key}/workflow/{permWorkflowName}/node/{nodeID}/hook/model", r.GET(api.getWorkflowHookModelsHandler))
 	r.Handle("/project/{key}/workflow/{permWorkflowName}/node/{nodeID}/outgoinghook/model", r.GET(api.getWorkflowOutgoingHookModelsHandler))
 
@@ -230,6 +241,9 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs", r.GET(api.getWorkflowRunsHandler, EnableTracing()), r.POSTEXECUTE(api.postWorkflowRunHandler, AllowServices(true), EnableTracing()))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/branch/{branch}", r.DELETE(api.deleteWorkflowRunsBranchHandler, NeedService()))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/latest", r.GET(api.getLatestWorkflowRunHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests", r.GET(api.getWorkflowTestsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests/history", r.GET(api.getWorkflowTestHistoryHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/logs/search", r.POST(api.postWorkflowLogSearchHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/tags", r.GET(api.getWorkflowRunTagsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/num", r.GET(api.getWorkflowRunNumHandler), r.POST(api.postWorkflowRunNumHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}", r.GET(api.getWorkflowRunHandler, AllowServices(true), EnableTracing()), r.DELETE(api.deleteWorkflowRunHandler))
@@ -244,8 +258,11 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/info", r.GET(api.getWorkflowNodeRunJobSpawnInfosHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/log/service", r.GET(api.getWorkflowNodeRunJobServiceLogsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/step/{stepOrder}", r.GET(api.getWorkflowNodeRunJobStepHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobId}/step/{stepOrder}/log", r.GET(api.getWorkflowNodeRunJobStepLogHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/node/{nodeID}/triggers/condition", r.GET(api.getWorkflowTriggerConditionHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/release", r.POST(api.releaseApplicationWorkflowHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/sbom", r.GET(api.getWorkflowNodeRunSBOMsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/sbom/{sbomID}/download", r.GET(api.getWorkflowNodeRunSBOMDocumentHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/hooks/{hookRunID}/callback", r.POST(api.postWorkflowJobHookCallbackHandler, AllowServices(true)))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/hooks/{hookRunID}/details", r.GET(api.getWorkflowJobHookDetailsHandler, NeedService()))
 
@@ -277,10 +294,12 @@ func (api *API) InitRouter() {
 	// Cache
 	r.Handle("/project/{permProjectKey}/storage/{integrationName}/cache/{tag}", r.POSTEXECUTE(api.postPushCacheHandler, NeedWorker()), r.GET(api.getPullCacheHandler, NeedWorker()))
 	r.Handle("/project/{permProjectKey}/storage/{integrationName}/cache/{tag}/url", r.POSTEXECUTE(api.postPushCacheWithTempURLHandler, NeedWorker()), r.GET(api.getPullCacheWithTempURLHandler, NeedWorker()))
+	r.Handle("/project/{permProjectKey}/storage/{integrationName}/cache/{tag}/url/callback", r.POSTEXECUTE(api.postPushCacheWithTempURLCallbackHandler, NeedWorker()))
 
 	//Workflow queue
 	r.Handle("/queue/workflows", r.GET(api.getWorkflowJobQueueHandler, EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/count", r.GET(api.countWorkflowJobQueueHandler, EnableTracing(), MaintenanceAware()))
+	r.Handle("/queue/workflows/capacity", r.GET(api.getHatcheryCapacitiesHandler, NeedHatchery(), NeedAdmin(true)), r.POST(api.postHatcheryCapacityHandler, NeedHatchery(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{id}/take", r.POST(api.postTakeWorkflowJobHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{id}/book", r.POST(api.postBookWorkflowJobHandler, NeedHatchery(), EnableTracing(), MaintenanceAware()), r.DELETE(api.deleteBookWorkflowJobHandler, NeedHatchery(), EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{id}/infos", r.GET(api.getWorkflowJobHandler, NeedWorker(), NeedHatchery(), EnableTracing(), MaintenanceAware()))
@@ -291,10 +310,15 @@ func (api *API) InitRouter() {
 	r.Handle("/queue/workflows/log/service", r.POSTEXECUTE(r.Asynchronous(api.postWorkflowJobServiceLogsHandler, 1), NeedHatchery(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{permID}/coverage", r.POSTEXECUTE(api.postWorkflowJobCoverageResultsHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{permID}/test", r.POSTEXECUTE(api.postWorkflowJobTestsResultsHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
+	r.Handle("/queue/workflows/{permID}/test/quarantine", r.GET(api.getWorkflowJobTestQuarantinesHandler, NeedWorker()))
+	r.Handle("/queue/workflows/{permID}/sbom", r.POSTEXECUTE(api.postWorkflowJobSBOMHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{permID}/tag", r.POSTEXECUTE(api.postWorkflowJobTagsHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{permID}/variable", r.POSTEXECUTE(api.postWorkflowJobVariableHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
 	r.Handle("/queue/workflows/{permID}/step", r.POSTEXECUTE(api.postWorkflowJobStepStatusHandler, NeedWorker(), EnableTracing(), MaintenanceAware()))
 
+	// SBOM
+	r.Handle("/sbom/component", r.GET(api.getSBOMComponentUsagesHandler))
+
 	r.Handle("/variable/type", r.GET(api.getVariableTypeHandler))
 	r.Handle("/parameter/type", r.GET(api.getParameterTypeHandler))
 	r.Handle("/notification/type", r.GET(api.getUserNotificationTypeHandler))
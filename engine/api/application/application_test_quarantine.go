@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadTestQuarantines returns the quarantined tests of an application.
+func LoadTestQuarantines(ctx context.Context, db gorp.SqlExecutor, appID int64) ([]sdk.TestQuarantine, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM application_test_quarantine
+	WHERE application_id = $1
+	ORDER BY test_suite, name`).Args(appID)
+	var qs []sdk.TestQuarantine
+	if err := gorpmapping.GetAll(ctx, db, query, &qs); err != nil {
+		return nil, sdk.WrapError(err, "cannot load test quarantines for application %d", appID)
+	}
+	return qs, nil
+}
+
+// LoadActiveTestQuarantines returns the quarantined tests of an application that are not expired.
+func LoadActiveTestQuarantines(ctx context.Context, db gorp.SqlExecutor, appID int64) ([]sdk.TestQuarantine, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM application_test_quarantine
+	WHERE application_id = $1 AND (expire IS NULL OR expire > $2)
+	ORDER BY test_suite, name`).Args(appID, time.Now())
+	var qs []sdk.TestQuarantine
+	if err := gorpmapping.GetAll(ctx, db, query, &qs); err != nil {
+		return nil, sdk.WrapError(err, "cannot load active test quarantines for application %d", appID)
+	}
+	return qs, nil
+}
+
+// LoadTestQuarantine returns a quarantined test of an application.
+func LoadTestQuarantine(ctx context.Context, db gorp.SqlExecutor, appID, id int64) (*sdk.TestQuarantine, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM application_test_quarantine
+	WHERE application_id = $1 AND id = $2`).Args(appID, id)
+	var q sdk.TestQuarantine
+	found, err := gorpmapping.Get(ctx, db, query, &q)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load test quarantine %d", id)
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &q, nil
+}
+
+// InsertTestQuarantine adds a test in the quarantine of an application.
+func InsertTestQuarantine(db gorp.SqlExecutor, q *sdk.TestQuarantine) error {
+	q.Created = time.Now()
+	return sdk.WrapError(gorpmapping.Insert(db, q), "unable to insert test quarantine for %s", q.Name)
+}
+
+// DeleteTestQuarantine removes a test from the quarantine of an application.
+func DeleteTestQuarantine(db gorp.SqlExecutor, q *sdk.TestQuarantine) error {
+	return sdk.WrapError(gorpmapping.Delete(db, q), "unable to delete test quarantine %d", q.ID)
+}
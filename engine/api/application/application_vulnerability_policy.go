@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadVulnerabilityPolicy returns the vulnerability policy of an application, or the policy of the whole
+// project if appID is 0.
+func LoadVulnerabilityPolicy(ctx context.Context, db gorp.SqlExecutor, projectID, appID int64) (*sdk.VulnerabilityPolicy, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM vulnerability_policy
+	WHERE project_id = $1 AND COALESCE(application_id, 0) = $2`).Args(projectID, appID)
+	var p sdk.VulnerabilityPolicy
+	found, err := gorpmapping.Get(ctx, db, query, &p)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load vulnerability policy")
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &p, nil
+}
+
+// LoadEffectiveVulnerabilityPolicy returns the vulnerability policy of an application if it exists, or else
+// the policy of its project, nil if there is no policy.
+func LoadEffectiveVulnerabilityPolicy(ctx context.Context, db gorp.SqlExecutor, projectID, appID int64) (*sdk.VulnerabilityPolicy, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM vulnerability_policy
+	WHERE project_id = $1 AND (application_id = $2 OR application_id IS NULL)
+	ORDER BY application_id NULLS LAST
+	LIMIT 1`).Args(projectID, appID)
+	var p sdk.VulnerabilityPolicy
+	found, err := gorpmapping.Get(ctx, db, query, &p)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load vulnerability policy for application %d", appID)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// UpsertVulnerabilityPolicy replaces the vulnerability policy of a project or an application.
+func UpsertVulnerabilityPolicy(ctx context.Context, db gorp.SqlExecutor, p *sdk.VulnerabilityPolicy) error {
+	var appID int64
+	if p.ApplicationID != nil {
+		appID = *p.ApplicationID
+	}
+	old, err := LoadVulnerabilityPolicy(ctx, db, p.ProjectID, appID)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return err
+	}
+	if old != nil {
+		p.ID = old.ID
+		p.Created = old.Created
+		return sdk.WrapError(gorpmapping.Update(db, p), "unable to update vulnerability policy")
+	}
+	p.Created = time.Now()
+	return sdk.WrapError(gorpmapping.Insert(db, p), "unable to insert vulnerability policy")
+}
+
+// DeleteVulnerabilityPolicy removes a vulnerability policy.
+func DeleteVulnerabilityPolicy(db gorp.SqlExecutor, p *sdk.VulnerabilityPolicy) error {
+	return sdk.WrapError(gorpmapping.Delete(db, p), "unable to delete vulnerability policy %d", p.ID)
+}
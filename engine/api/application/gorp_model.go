@@ -22,6 +22,8 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(dbApplicationVariableAudit{}, "application_variable_audit", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbApplicationKey{}, "application_key", false))
 	gorpmapping.Register(gorpmapping.New(dbApplicationVulnerability{}, "application_vulnerability", true, "id"))
+	gorpmapping.Register(gorpmapping.New(sdk.TestQuarantine{}, "application_test_quarantine", true, "id"))
+	gorpmapping.Register(gorpmapping.New(sdk.VulnerabilityPolicy{}, "vulnerability_policy", true, "id"))
 }
 
 type sqlApplicationJSON struct {
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getApplicationTestQuarantinesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+
+		app, err := application.LoadByName(api.mustDB(), api.Cache, key, appName)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load application")
+		}
+
+		qs, err := application.LoadTestQuarantines(ctx, api.mustDB(), app.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, qs, http.StatusOK)
+	}
+}
+
+func (api *API) postApplicationTestQuarantineHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+
+		var q sdk.TestQuarantine
+		if err := service.UnmarshalBody(r, &q); err != nil {
+			return sdk.WrapError(err, "unable to read body")
+		}
+		if err := q.IsValid(); err != nil {
+			return err
+		}
+
+		app, err := application.LoadByName(api.mustDB(), api.Cache, key, appName)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load application")
+		}
+
+		q.ID = 0
+		q.ApplicationID = app.ID
+		if q.Owner == "" {
+			q.Owner = deprecatedGetUser(ctx).Username
+		}
+
+		if err := application.InsertTestQuarantine(api.mustDB(), &q); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, q, http.StatusOK)
+	}
+}
+
+func (api *API) deleteApplicationTestQuarantineHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		app, err := application.LoadByName(api.mustDB(), api.Cache, key, appName)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load application")
+		}
+
+		q, err := application.LoadTestQuarantine(ctx, api.mustDB(), app.ID, id)
+		if err != nil {
+			return err
+		}
+
+		if err := application.DeleteTestQuarantine(api.mustDB(), q); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowJobTestQuarantinesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "permID")
+		if err != nil {
+			return sdk.WrapError(err, "invalid node job run ID")
+		}
+
+		nodeRunJob, err := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node run job")
+		}
+
+		nr, err := workflow.LoadNodeRunByID(api.mustDB(), nodeRunJob.WorkflowNodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load node run")
+		}
+
+		qs := []sdk.TestQuarantine{}
+		if nr.ApplicationID != 0 {
+			qs, err = application.LoadActiveTestQuarantines(ctx, api.mustDB(), nr.ApplicationID)
+			if err != nil {
+				return err
+			}
+		}
+		return service.WriteJSON(w, qs, http.StatusOK)
+	}
+}
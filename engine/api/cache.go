@@ -2,20 +2,30 @@ package api
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"time"
 
+	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workercache"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
 func (api *API) postPushCacheHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
 		tag := vars["tag"]
+		digest := r.FormValue("digest")
 
 		// check tag name pattern
 		regexp := sdk.NamePatternRegex
@@ -28,21 +38,65 @@ func (api *API) postPushCacheHandler() service.Handler {
 		}
 		defer r.Body.Close()
 
-		cacheObject := sdk.Cache{
-			Name:    "cache.tar",
-			Project: vars[permProjectKey],
-			Tag:     tag,
+		storageDriver, err := api.getStorageDriver(vars[permProjectKey], vars["integrationName"])
+		if err != nil {
+			return err
 		}
 
-		storageDriver, err := api.getStorageDriver(vars[permProjectKey], vars["integrationName"])
+		// Workers that don't compute the digest of the archive store it with its tag
+		if digest == "" {
+			cacheObject := sdk.Cache{
+				Name:    "cache.tar",
+				Project: vars[permProjectKey],
+				Tag:     tag,
+			}
+			if _, err := storageDriver.Store(&cacheObject, r.Body); err != nil {
+				return sdk.WrapError(err, "postPushCacheHandler>Cannot store cache")
+			}
+			return nil
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, vars[permProjectKey], deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", vars[permProjectKey])
+		}
+
+		entry := sdk.CacheEntry{
+			ProjectID:       proj.ID,
+			IntegrationName: vars["integrationName"],
+			Tag:             tag,
+			Digest:          digest,
+		}
+
+		// An identical archive is already stored, only the tag has to reference it
+		existing, err := workercache.LoadByDigest(ctx, api.mustDB(), proj.ID, entry.IntegrationName, digest)
 		if err != nil {
 			return err
 		}
+		if existing != nil {
+			entry.Size = existing.Size
+		} else {
+			blob := sdk.NewCacheBlob(proj.Key, digest)
+			h := sha256.New()
+			counter := new(byteCounter)
+			body := ioutil.NopCloser(io.TeeReader(r.Body, io.MultiWriter(h, counter)))
+			if _, err := storageDriver.Store(&blob, body); err != nil {
+				return sdk.WrapError(err, "cannot store cache")
+			}
+			if hex.EncodeToString(h.Sum(nil)) != digest {
+				if err := storageDriver.Delete(&blob); err != nil {
+					log.Error("postPushCacheHandler> unable to delete cache blob %s: %v", digest, err)
+				}
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "digest of the cache archive doesn't match %s", digest)
+			}
+			entry.Size = int64(*counter)
+		}
 
-		if _, err := storageDriver.Store(&cacheObject, r.Body); err != nil {
-			return sdk.WrapError(err, "postPushCacheHandler>Cannot store cache")
+		if err := api.upsertCacheEntry(ctx, storageDriver, proj.Key, &entry); err != nil {
+			return err
 		}
 
+		api.evictCacheEntries(ctx, storageDriver, proj.ID, proj.Key, entry.IntegrationName, entry.Tag)
 		return nil
 	}
 }
@@ -58,20 +112,26 @@ func (api *API) getPullCacheHandler() service.Handler {
 			return sdk.ErrInvalidName
 		}
 
-		cacheObject := sdk.Cache{
-			Project: vars[permProjectKey],
-			Name:    "cache.tar",
-			Tag:     tag,
+		storageDriver, err := api.getStorageDriver(vars[permProjectKey], vars["integrationName"])
+		if err != nil {
+			return err
 		}
 
-		storageDriver, err := api.getStorageDriver(vars[permProjectKey], vars["integrationName"])
+		cacheObject, err := api.lookupCache(ctx, vars[permProjectKey], vars["integrationName"], tag, r.URL.Query()["restore"])
 		if err != nil {
 			return err
 		}
+		w.Header().Add(sdk.CacheKeyHeader, cacheObject.Tag)
+
+		// Archive content addressed are stored with their digest
+		if cacheObject.Digest != "" {
+			blob := sdk.NewCacheBlob(vars[permProjectKey], cacheObject.Digest)
+			cacheObject = &blob
+		}
 
 		s, temporaryURLSupported := storageDriver.(objectstore.DriverWithRedirect)
 		if storageDriver.TemporaryURLSupported() && temporaryURLSupported { // with temp URL
-			fURL, _, err := s.FetchURL(&cacheObject)
+			fURL, _, err := s.FetchURL(cacheObject)
 			if err != nil {
 				return sdk.WrapError(err, "Cannot fetch cache object")
 			}
@@ -81,7 +141,7 @@ func (api *API) getPullCacheHandler() service.Handler {
 			return nil
 		}
 
-		ioread, err := storageDriver.Fetch(&cacheObject)
+		ioread, err := storageDriver.Fetch(cacheObject)
 		if err != nil {
 			return sdk.WrapError(sdk.ErrNotFound, "getPullCacheHandler> Cannot fetch artifact cache.tar : %v", err)
 		}
@@ -101,6 +161,11 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
 		tag := vars["tag"]
+		digest := r.FormValue("digest")
+		size, err := FormInt(r, "size")
+		if err != nil {
+			return err
+		}
 
 		// check tag name pattern
 		regexp := sdk.NamePatternRegex
@@ -124,6 +189,37 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 			Tag:     tag,
 		}
 
+		// The archive is uploaded to its content address, it is referenced with its tag by the callback, once checked
+		if digest != "" {
+			proj, err := project.Load(api.mustDB(), api.Cache, vars[permProjectKey], deprecatedGetUser(ctx))
+			if err != nil {
+				return sdk.WrapError(err, "cannot load project %s", vars[permProjectKey])
+			}
+
+			// An identical archive is already stored, only the tag has to reference it: no upload url is returned
+			existing, err := workercache.LoadByDigest(ctx, api.mustDB(), proj.ID, vars["integrationName"], digest)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				entry := sdk.CacheEntry{
+					ProjectID:       proj.ID,
+					IntegrationName: vars["integrationName"],
+					Tag:             tag,
+					Digest:          digest,
+					Size:            existing.Size,
+				}
+				if err := api.upsertCacheEntry(ctx, storageDriver, proj.Key, &entry); err != nil {
+					return err
+				}
+				api.evictCacheEntries(ctx, storageDriver, proj.ID, proj.Key, entry.IntegrationName, entry.Tag)
+				return service.WriteJSON(w, cacheObject, http.StatusOK)
+			}
+
+			api.Cache.SetWithTTL(cacheUploadKey(proj.Key, vars["integrationName"], tag, digest), size, cacheUploadTTL)
+			cacheObject = sdk.NewCacheBlob(proj.Key, digest)
+		}
+
 		url, key, errO := store.StoreURL(&cacheObject, "application/tar")
 		if errO != nil {
 			return sdk.WrapError(errO, "postPushCacheWithTempURLHandler>Cannot store cache")
@@ -135,6 +231,84 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 	}
 }
 
+// cacheUploadTTL is the delay in seconds given to a worker to upload a cache archive with a temporary url
+const cacheUploadTTL = 3600
+
+func cacheUploadKey(projectKey, integrationName, tag, digest string) string {
+	return cache.Key("workercache", "uploads", projectKey, integrationName, tag, digest)
+}
+
+func (api *API) postPushCacheWithTempURLCallbackHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		tag := vars["tag"]
+		digest := r.FormValue("digest")
+		size, err := FormInt(r, "size")
+		if err != nil {
+			return err
+		}
+
+		// check tag name pattern
+		regexp := sdk.NamePatternRegex
+		if !regexp.MatchString(tag) {
+			return sdk.ErrInvalidName
+		}
+
+		storageDriver, err := api.getStorageDriver(vars[permProjectKey], vars["integrationName"])
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, vars[permProjectKey], deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", vars[permProjectKey])
+		}
+
+		key := cacheUploadKey(proj.Key, vars["integrationName"], tag, digest)
+		var expectedSize int
+		if !api.Cache.Get(key, &expectedSize) {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no upload of cache %s with digest %s", tag, digest)
+		}
+		if size != expectedSize {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "size of the cache archive doesn't match %d", expectedSize)
+		}
+
+		// The uploaded archive is checked before being referenced, it is deleted if it is not the expected one
+		blob := sdk.NewCacheBlob(proj.Key, digest)
+		f, err := storageDriver.Fetch(&blob)
+		if err != nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "cache archive %s not uploaded: %v", digest, err)
+		}
+		h := sha256.New()
+		counter := new(byteCounter)
+		_, err = io.Copy(io.MultiWriter(h, counter), f)
+		_ = f.Close()
+		if err != nil {
+			return sdk.WrapError(err, "cannot read cache archive %s", digest)
+		}
+		if hex.EncodeToString(h.Sum(nil)) != digest || int64(*counter) != int64(expectedSize) {
+			if err := storageDriver.Delete(&blob); err != nil {
+				log.Error("postPushCacheWithTempURLCallbackHandler> unable to delete cache blob %s: %v", digest, err)
+			}
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "digest or size of the cache archive doesn't match %s", digest)
+		}
+		api.Cache.Delete(key)
+
+		entry := sdk.CacheEntry{
+			ProjectID:       proj.ID,
+			IntegrationName: vars["integrationName"],
+			Tag:             tag,
+			Digest:          digest,
+			Size:            int64(*counter),
+		}
+		if err := api.upsertCacheEntry(ctx, storageDriver, proj.Key, &entry); err != nil {
+			return err
+		}
+		api.evictCacheEntries(ctx, storageDriver, proj.ID, proj.Key, entry.IntegrationName, entry.Tag)
+		return nil
+	}
+}
+
 func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -156,13 +330,16 @@ func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 			return sdk.WrapError(sdk.ErrNotImplemented, "getPullCacheWithTempURLHandler> cast error")
 		}
 
-		cacheObject := sdk.Cache{
-			Name:    "cache.tar",
-			Project: vars[permProjectKey],
-			Tag:     tag,
+		cacheObject, err := api.lookupCache(ctx, vars[permProjectKey], vars["integrationName"], tag, r.URL.Query()["restore"])
+		if err != nil {
+			return err
+		}
+		fetched := *cacheObject
+		if cacheObject.Digest != "" {
+			fetched = sdk.NewCacheBlob(vars[permProjectKey], cacheObject.Digest)
 		}
 
-		url, key, errF := store.FetchURL(&cacheObject)
+		url, key, errF := store.FetchURL(&fetched)
 		if errF != nil {
 			return sdk.WrapError(errF, "getPullCacheWithTempURLHandler> Cannot get tmp URL")
 		}
@@ -172,3 +349,136 @@ func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 		return service.WriteJSON(w, cacheObject, http.StatusOK)
 	}
 }
+
+// lookupCache returns the cache with the given tag, or else the latest cache matching the first possible restore key.
+// Caches pushed without digest are not referenced in database, they can only be retrieved with their exact tag.
+func (api *API) lookupCache(ctx context.Context, projectKey, integrationName, tag string, restoreKeys []string) (*sdk.Cache, error) {
+	legacy := &sdk.Cache{
+		Project: projectKey,
+		Name:    "cache.tar",
+		Tag:     tag,
+	}
+
+	proj, err := project.Load(api.mustDB(), api.Cache, projectKey, nil)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load project %s", projectKey)
+	}
+
+	entry, err := workercache.LoadByTag(ctx, api.mustDB(), proj.ID, integrationName, tag)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; entry == nil && i < len(restoreKeys); i++ {
+		if restoreKeys[i] == "" {
+			continue
+		}
+		entry, err = workercache.LoadLatestByTagPrefix(ctx, api.mustDB(), proj.ID, integrationName, restoreKeys[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if entry == nil {
+		return legacy, nil
+	}
+
+	if err := workercache.UpdateLastAccess(api.mustDB(), entry.ID); err != nil {
+		log.Error("lookupCache> %v", err)
+	}
+
+	return &sdk.Cache{
+		Project:         projectKey,
+		Name:            "cache.tar",
+		Tag:             entry.Tag,
+		Digest:          entry.Digest,
+		IntegrationName: integrationName,
+	}, nil
+}
+
+// upsertCacheEntry references the archive of the entry with its tag. The archive previously referenced by the tag
+// is deleted if it is not used anymore.
+func (api *API) upsertCacheEntry(ctx context.Context, storageDriver objectstore.Driver, projectKey string, entry *sdk.CacheEntry) error {
+	old, err := workercache.LoadByTag(ctx, api.mustDB(), entry.ProjectID, entry.IntegrationName, entry.Tag)
+	if err != nil {
+		return err
+	}
+	if old == nil {
+		return workercache.Insert(api.mustDB(), entry)
+	}
+
+	entry.ID = old.ID
+	entry.Created = time.Now()
+	entry.LastAccess = entry.Created
+	if err := workercache.Update(api.mustDB(), entry); err != nil {
+		return err
+	}
+	if old.Digest != entry.Digest {
+		deleteUnusedCacheBlob(api.mustDB(), storageDriver, projectKey, old)
+	}
+	return nil
+}
+
+// evictCacheEntries deletes the least recently used caches of a project storage until
+// its size is under the limit. Size of an archive referenced by several tags is counted once.
+func (api *API) evictCacheEntries(ctx context.Context, storageDriver objectstore.Driver, projectID int64, projectKey, integrationName, keepTag string) {
+	if api.Config.Artifact.CacheSizeLimit <= 0 {
+		return
+	}
+	limit := api.Config.Artifact.CacheSizeLimit * 1024 * 1024
+
+	entries, err := workercache.LoadAllByProject(ctx, api.mustDB(), projectID, integrationName)
+	if err != nil {
+		log.Error("evictCacheEntries> %v", err)
+		return
+	}
+
+	var total int64
+	digests := map[string]struct{}{}
+	for _, e := range entries {
+		if _, ok := digests[e.Digest]; !ok {
+			digests[e.Digest] = struct{}{}
+			total += e.Size
+		}
+	}
+
+	// entries are sorted by last access desc
+	for i := len(entries) - 1; i >= 0 && total > limit; i-- {
+		e := entries[i]
+		if e.Tag == keepTag {
+			continue
+		}
+		if err := workercache.Delete(api.mustDB(), &e); err != nil {
+			log.Error("evictCacheEntries> %v", err)
+			return
+		}
+		log.Info("evictCacheEntries> cache %s of project %s deleted", e.Tag, projectKey)
+		if deleteUnusedCacheBlob(api.mustDB(), storageDriver, projectKey, &e) {
+			total -= e.Size
+		}
+	}
+}
+
+// deleteUnusedCacheBlob deletes the archive referenced by given entry if no other entry references it
+func deleteUnusedCacheBlob(db gorp.SqlExecutor, storageDriver objectstore.Driver, projectKey string, e *sdk.CacheEntry) bool {
+	n, err := workercache.CountByDigest(db, e.ProjectID, e.IntegrationName, e.Digest)
+	if err != nil {
+		log.Error("deleteUnusedCacheBlob> %v", err)
+		return false
+	}
+	if n > 0 {
+		return false
+	}
+	blob := sdk.NewCacheBlob(projectKey, e.Digest)
+	if err := storageDriver.Delete(&blob); err != nil {
+		log.Error("deleteUnusedCacheBlob> unable to delete cache blob %s: %v", e.Digest, err)
+		return false
+	}
+	return true
+}
+
+// byteCounter counts the bytes written to it
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
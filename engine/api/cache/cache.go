@@ -43,6 +43,9 @@ type Store interface {
 	SetScan(key string, members ...interface{}) error
 	Lock(key string, expiration time.Duration, retryWaitDurationMillisecond int, retryCount int) bool
 	Unlock(key string)
+	SetNX(key string, value interface{}, ttl int) bool
+	UpdateTTLIfValue(key string, value interface{}, ttl int) bool
+	DeleteIfValue(key string, value interface{}) bool
 }
 
 //New init a cache
@@ -432,3 +432,55 @@ func (s *RedisStore) Lock(key string, expiration time.Duration, retrywdMilliseco
 func (s *RedisStore) Unlock(key string) {
 	s.Delete(key)
 }
+
+var (
+	updateTTLIfValueScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("EXPIRE", KEYS[1], ARGV[2]) else return 0 end`)
+	deleteIfValueScript    = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`)
+)
+
+//SetNX sets a value with a ttl only if the key does not exist, it returns true if the value was set
+func (s *RedisStore) SetNX(key string, value interface{}, ttl int) bool {
+	if s.Client == nil {
+		log.Error("redis> cannot get redis client")
+		return false
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		log.Warning("redis> error caching %s: %s", key, err)
+		return false
+	}
+	res, err := s.Client.SetNX(key, string(b), time.Duration(ttl)*time.Second).Result()
+	if err != nil {
+		log.Error("redis>SetNX> set error %s: %v", key, err)
+		return false
+	}
+	return res
+}
+
+//UpdateTTLIfValue atomically updates the ttl of a key only if it holds the given value, it returns true if the ttl was updated
+func (s *RedisStore) UpdateTTLIfValue(key string, value interface{}, ttl int) bool {
+	return s.runIfValue(updateTTLIfValueScript, key, value, ttl)
+}
+
+//DeleteIfValue atomically deletes a key only if it holds the given value, it returns true if the key was deleted
+func (s *RedisStore) DeleteIfValue(key string, value interface{}) bool {
+	return s.runIfValue(deleteIfValueScript, key, value)
+}
+
+func (s *RedisStore) runIfValue(script *redis.Script, key string, value interface{}, args ...interface{}) bool {
+	if s.Client == nil {
+		log.Error("redis> cannot get redis client")
+		return false
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		log.Warning("redis> error marshaling %s: %s", key, err)
+		return false
+	}
+	res, err := script.Run(s.Client, []string{key}, append([]interface{}{string(b)}, args...)...).Int64()
+	if err != nil {
+		log.Error("redis> script error %s: %v", key, err)
+		return false
+	}
+	return res == 1
+}
@@ -27,6 +27,9 @@ const (
 
 	// StringDataRightTruncation is raisedalue is too long for varchar.
 	StringDataRightTruncation = "22001"
+
+	// InvalidRegularExpressionPGCode is the pg code when a regular expression can't be compiled
+	InvalidRegularExpressionPGCode = "2201B"
 )
 
 // NewQuery returns a new query from given string request.
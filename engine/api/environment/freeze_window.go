@@ -0,0 +1,83 @@
+package environment
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// LoadFreezeWindows returns the freeze windows of a project.
+func LoadFreezeWindows(ctx context.Context, db gorp.SqlExecutor, projectID int64) ([]sdk.FreezeWindow, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM freeze_window
+	WHERE project_id = $1
+	ORDER BY name`).Args(projectID)
+	var fs []sdk.FreezeWindow
+	if err := gorpmapping.GetAll(ctx, db, query, &fs); err != nil {
+		return nil, sdk.WrapError(err, "cannot load freeze windows for project %d", projectID)
+	}
+	return fs, nil
+}
+
+// LoadFreezeWindowsForEnvironment returns the freeze windows of a project that apply to an environment.
+func LoadFreezeWindowsForEnvironment(ctx context.Context, db gorp.SqlExecutor, projectID, envID int64) ([]sdk.FreezeWindow, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM freeze_window
+	WHERE project_id = $1 AND (environment_id IS NULL OR environment_id = $2)
+	ORDER BY name`).Args(projectID, envID)
+	var fs []sdk.FreezeWindow
+	if err := gorpmapping.GetAll(ctx, db, query, &fs); err != nil {
+		return nil, sdk.WrapError(err, "cannot load freeze windows for environment %d", envID)
+	}
+	return fs, nil
+}
+
+// LoadFreezeWindow returns a freeze window of a project by its name.
+func LoadFreezeWindow(ctx context.Context, db gorp.SqlExecutor, projectID int64, name string) (*sdk.FreezeWindow, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM freeze_window
+	WHERE project_id = $1 AND name = $2`).Args(projectID, name)
+	var f sdk.FreezeWindow
+	found, err := gorpmapping.Get(ctx, db, query, &f)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load freeze window %s", name)
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &f, nil
+}
+
+// ActiveFreezeWindow returns the freeze window active at given time that ends the latest, and false if there is none.
+func ActiveFreezeWindow(fs []sdk.FreezeWindow, t time.Time) (*sdk.FreezeWindow, time.Time, bool) {
+	var active *sdk.FreezeWindow
+	var until time.Time
+	for i := range fs {
+		end, ok := fs[i].ActiveUntil(t)
+		if ok && end.After(until) {
+			active = &fs[i]
+			until = end
+		}
+	}
+	return active, until, active != nil
+}
+
+// InsertFreezeWindow adds a freeze window in a project.
+func InsertFreezeWindow(db gorp.SqlExecutor, f *sdk.FreezeWindow) error {
+	f.Created = time.Now()
+	return sdk.WrapError(gorpmapping.Insert(db, f), "unable to insert freeze window %s", f.Name)
+}
+
+// UpdateFreezeWindow updates a freeze window.
+func UpdateFreezeWindow(db gorp.SqlExecutor, f *sdk.FreezeWindow) error {
+	return sdk.WrapError(gorpmapping.Update(db, f), "unable to update freeze window %s", f.Name)
+}
+
+// DeleteFreezeWindow removes a freeze window from a project.
+func DeleteFreezeWindow(db gorp.SqlExecutor, f *sdk.FreezeWindow) error {
+	return sdk.WrapError(gorpmapping.Delete(db, f), "unable to delete freeze window %s", f.Name)
+}
@@ -55,21 +55,22 @@ func PublishWorkflowNodeRun(db gorp.SqlExecutor, nr sdk.WorkflowNodeRun, w sdk.W
 	}
 
 	e := sdk.EventRunWorkflowNode{
-		ID:             nr.ID,
-		Number:         nr.Number,
-		SubNumber:      nr.SubNumber,
-		Status:         nr.Status,
-		Start:          nr.Start.Unix(),
-		Manual:         nr.Manual,
-		HookEvent:      nr.HookEvent,
-		Payload:        nr.Payload,
-		SourceNodeRuns: nr.SourceNodeRuns,
-		Hash:           nr.VCSHash,
-		BranchName:     nr.VCSBranch,
-		NodeID:         nr.WorkflowNodeID,
-		RunID:          nr.WorkflowRunID,
-		StagesSummary:  make([]sdk.StageSummary, len(nr.Stages)),
-		HookUUID:       nr.UUID,
+		ID:               nr.ID,
+		Number:           nr.Number,
+		SubNumber:        nr.SubNumber,
+		Status:           nr.Status,
+		Start:            nr.Start.Unix(),
+		Manual:           nr.Manual,
+		HookEvent:        nr.HookEvent,
+		Payload:          nr.Payload,
+		SourceNodeRuns:   nr.SourceNodeRuns,
+		Hash:             nr.VCSHash,
+		BranchName:       nr.VCSBranch,
+		NodeID:           nr.WorkflowNodeID,
+		RunID:            nr.WorkflowRunID,
+		StagesSummary:    make([]sdk.StageSummary, len(nr.Stages)),
+		HookUUID:         nr.UUID,
+		TestsQuarantined: nr.TestsQuarantined,
 	}
 
 	if nr.Callback != nil {
@@ -156,9 +157,10 @@ func PublishWorkflowNodeRun(db gorp.SqlExecutor, nr sdk.WorkflowNodeRun, w sdk.W
 // PublishWorkflowNodeJobRun publish a WorkflowNodeJobRun
 func PublishWorkflowNodeJobRun(db gorp.SqlExecutor, pkey, wname string, jr sdk.WorkflowNodeJobRun) {
 	e := sdk.EventRunWorkflowJob{
-		ID:     jr.ID,
-		Status: jr.Status,
-		Start:  jr.Start.Unix(),
+		ID:       jr.ID,
+		Status:   jr.Status,
+		Start:    jr.Start.Unix(),
+		BookedBy: jr.BookedBy.ID,
 	}
 
 	if sdk.StatusIsTerminated(jr.Status) {
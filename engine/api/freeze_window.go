@@ -0,0 +1,229 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) getFreezeWindowsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		fs, err := environment.LoadFreezeWindows(ctx, api.mustDB(), proj.ID)
+		if err != nil {
+			return err
+		}
+		envs, err := environment.LoadAllNames(api.mustDB(), proj.ID)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load environments of project %s", key)
+		}
+		for i := range fs {
+			if fs[i].EnvironmentID == nil {
+				continue
+			}
+			for _, env := range envs {
+				if env.ID == *fs[i].EnvironmentID {
+					fs[i].EnvironmentName = env.Name
+				}
+			}
+		}
+		return service.WriteJSON(w, fs, http.StatusOK)
+	}
+}
+
+func (api *API) postFreezeWindowHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		var f sdk.FreezeWindow
+		if err := service.UnmarshalBody(r, &f); err != nil {
+			return sdk.WrapError(err, "unable to read body")
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		f.ID = 0
+		if err := api.prepareFreezeWindow(ctx, proj, &f, ""); err != nil {
+			return err
+		}
+
+		if _, err := environment.LoadFreezeWindow(ctx, api.mustDB(), proj.ID, f.Name); err == nil {
+			return sdk.NewErrorFrom(sdk.ErrAlreadyExist, "freeze window %s already exists", f.Name)
+		} else if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+
+		if err := environment.InsertFreezeWindow(api.mustDB(), &f); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, f, http.StatusOK)
+	}
+}
+
+func (api *API) putFreezeWindowHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		name := vars["name"]
+
+		var f sdk.FreezeWindow
+		if err := service.UnmarshalBody(r, &f); err != nil {
+			return sdk.WrapError(err, "unable to read body")
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		old, err := environment.LoadFreezeWindow(ctx, api.mustDB(), proj.ID, name)
+		if err != nil {
+			return err
+		}
+
+		f.ID = old.ID
+		f.Created = old.Created
+		if err := api.prepareFreezeWindow(ctx, proj, &f, old.OverrideGroup); err != nil {
+			return err
+		}
+
+		if err := environment.UpdateFreezeWindow(api.mustDB(), &f); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, f, http.StatusOK)
+	}
+}
+
+func (api *API) deleteFreezeWindowHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		name := vars["name"]
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+
+		f, err := environment.LoadFreezeWindow(ctx, api.mustDB(), proj.ID, name)
+		if err != nil {
+			return err
+		}
+
+		if err := environment.DeleteFreezeWindow(api.mustDB(), f); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// prepareFreezeWindow sets the project and the environment of a freeze window, and checks it.
+// As its members can deploy during the window, only an administrator can change the override group.
+func (api *API) prepareFreezeWindow(ctx context.Context, proj *sdk.Project, f *sdk.FreezeWindow, oldOverrideGroup string) error {
+	if f.OverrideGroup != oldOverrideGroup && !deprecatedGetUser(ctx).Admin {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "only an administrator can change the override group of a freeze window")
+	}
+
+	f.ProjectID = proj.ID
+	if f.Action == "" {
+		f.Action = sdk.FreezeWindowActionHold
+	}
+	if f.Timezone == "" {
+		f.Timezone = "UTC"
+	}
+	if err := f.IsValid(); err != nil {
+		return err
+	}
+
+	f.EnvironmentID = nil
+	if f.EnvironmentName != "" {
+		env, err := environment.LoadEnvironmentByName(api.mustDB(), proj.Key, f.EnvironmentName)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load environment %s", f.EnvironmentName)
+		}
+		f.EnvironmentID = &env.ID
+	}
+	return nil
+}
+
+// releaseFrozenNodeRunsRoutine starts every minute the node runs held by a freeze window that is over
+func (api *API) releaseFrozenNodeRunsRoutine(c context.Context) {
+	tick := time.NewTicker(time.Minute)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting releaseFrozenNodeRunsRoutine: %v", c.Err())
+			}
+			return
+		case <-tick.C:
+			if err := api.releaseFrozenNodeRuns(c); err != nil {
+				log.Warning("releaseFrozenNodeRunsRoutine> %v", err)
+			}
+		}
+	}
+}
+
+// releaseFrozenNodeRuns starts the node runs held by a freeze window that is over
+func (api *API) releaseFrozenNodeRuns(ctx context.Context) error {
+	ids, err := workflow.LoadFrozenNodeRunIDs(api.mustDB())
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := api.releaseFrozenNodeRun(ctx, id); err != nil {
+			log.Warning("releaseFrozenNodeRuns> unable to release node run %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+func (api *API) releaseFrozenNodeRun(ctx context.Context, nodeRunID int64) error {
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	proj, err := project.LoadProjectByNodeRunID(ctx, tx, api.Cache, nodeRunID, nil, project.LoadOptions.WithVariables)
+	if err != nil {
+		return sdk.WrapError(err, "unable to load project")
+	}
+
+	report, released, err := workflow.ReleaseFrozenNodeRun(ctx, tx, api.Cache, proj, nodeRunID)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	go workflow.SendEvent(api.mustDB(), proj.Key, report)
+	return nil
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_postFreezeWindowHandlerOverrideGroup(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	admin, adminPass := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, admin)
+	u, pass := assets.InsertLambdaUser(db, &proj.ProjectGroups[0].Group)
+
+	from, to := time.Now(), time.Now().Add(time.Hour)
+	f := sdk.FreezeWindow{
+		Name:          "release",
+		From:          &from,
+		To:            &to,
+		OverrideGroup: proj.ProjectGroups[0].Group.Name,
+	}
+	vars := map[string]string{"permProjectKey": proj.Key}
+	uri := router.GetRoute("POST", api.postFreezeWindowHandler, vars)
+	test.NotEmpty(t, uri)
+
+	// A project writer can't set the override group
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, f)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	f.OverrideGroup = ""
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, f)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// But an administrator can
+	vars["name"] = f.Name
+	uri = router.GetRoute("PUT", api.putFreezeWindowHandler, vars)
+	test.NotEmpty(t, uri)
+	f.OverrideGroup = proj.ProjectGroups[0].Group.Name
+	req = assets.NewAuthentifiedRequest(t, admin, adminPass, "PUT", uri, f)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// A project writer can still update the other fields of the window
+	f.Reason = "release in progress"
+	req = assets.NewAuthentifiedRequest(t, u, pass, "PUT", uri, f)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	f.OverrideGroup = ""
+	req = assets.NewAuthentifiedRequest(t, u, pass, "PUT", uri, f)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	res, err := environment.LoadFreezeWindow(context.TODO(), db, proj.ID, f.Name)
+	require.NoError(t, err)
+	assert.Equal(t, proj.ProjectGroups[0].Group.Name, res.OverrideGroup)
+	assert.Equal(t, "release in progress", res.Reason)
+}
+
+func Test_releaseFrozenNodeRuns(t *testing.T) {
+	api, db, _, end := newTestAPI(t)
+	defer end()
+
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+	}
+	require.NoError(t, pipeline.InsertPipeline(db, api.Cache, proj, &pip, u))
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	require.NoError(t, pipeline.InsertStage(db, s))
+	require.NoError(t, pipeline.InsertJob(db, &sdk.Job{
+		Enabled:         true,
+		PipelineStageID: s.ID,
+		Action:          sdk.Action{Enabled: true, Name: "deploy"},
+	}, s.ID, &pip))
+
+	env := sdk.Environment{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "production",
+	}
+	require.NoError(t, environment.InsertEnvironment(db, &env))
+
+	proj, _ = project.LoadByID(db, api.Cache, proj.ID, u, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments, project.LoadOptions.WithGroups)
+
+	w := sdk.Workflow{
+		Name:       "test_freeze",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: &sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "deploy",
+				Ref:  "deploy",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID:    pip.ID,
+					EnvironmentID: env.ID,
+				},
+			},
+		},
+	}
+	require.NoError(t, workflow.Insert(db, api.Cache, &w, proj, u))
+	w1, err := workflow.Load(context.TODO(), db, api.Cache, proj, w.Name, u, workflow.LoadOptions{DeepPipeline: true})
+	require.NoError(t, err)
+
+	from, to := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	f := sdk.FreezeWindow{
+		ProjectID:     proj.ID,
+		EnvironmentID: &env.ID,
+		Name:          "release",
+		From:          &from,
+		To:            &to,
+		Timezone:      "UTC",
+		Action:        sdk.FreezeWindowActionHold,
+	}
+	require.NoError(t, environment.InsertFreezeWindow(db, &f))
+
+	wr, err := workflow.CreateRun(db, w1, nil, u)
+	require.NoError(t, err)
+	wr.Workflow = *w1
+	_, err = workflow.StartWorkflowRun(context.TODO(), db, api.Cache, proj, wr, &sdk.WorkflowRunPostHandlerOption{
+		Manual: &sdk.WorkflowNodeRunManual{User: *u},
+	}, u, nil)
+	require.NoError(t, err)
+	nodeRunID := wr.WorkflowNodeRuns[w1.WorkflowData.Node.ID][0].ID
+
+	// The node run stays held while the window is active
+	require.NoError(t, api.releaseFrozenNodeRuns(context.TODO()))
+	ids, err := workflow.LoadFrozenNodeRunIDs(db)
+	require.NoError(t, err)
+	assert.Contains(t, ids, nodeRunID)
+
+	// The node run is started once the window is over
+	require.NoError(t, environment.DeleteFreezeWindow(db, &f))
+	require.NoError(t, api.releaseFrozenNodeRuns(context.TODO()))
+	ids, err = workflow.LoadFrozenNodeRunIDs(db)
+	require.NoError(t, err)
+	assert.NotContains(t, ids, nodeRunID)
+
+	jobIDs, err := workflow.LoadNodeJobRunIDByNodeRunID(db, nodeRunID)
+	require.NoError(t, err)
+	assert.Len(t, jobIDs, 1)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/api/grpc"
+	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -22,6 +23,7 @@ type grpcHandlers struct {
 	dbConnectionFactory *database.DBConnectionFactory
 	store               cache.Store
 	stepMaxLogSize      int64
+	logStorage          objectstore.Driver
 }
 
 //SendLog is the WorkflowQueueServer implementation
@@ -40,7 +42,7 @@ func (h *grpcHandlers) SendLog(stream grpc.WorkflowQueue_SendLogServer) error {
 
 		db := h.dbConnectionFactory.GetDBMap()
 
-		if err := workflow.AddLog(db, nil, in, h.stepMaxLogSize); err != nil {
+		if err := workflow.AddLog(db, h.logStorage, nil, in, h.stepMaxLogSize); err != nil {
 			return sdk.WrapError(err, "Unable to insert log ")
 		}
 	}
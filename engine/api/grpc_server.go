@@ -12,12 +12,13 @@ import (
 	"github.com/ovh/cds/engine/api/auth"
 	"github.com/ovh/cds/engine/api/database"
 	cdsgrpc "github.com/ovh/cds/engine/api/grpc"
+	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
 // grpcInit initialize all GRPC services
-func grpcInit(dbConnectionFactory *database.DBConnectionFactory, addr string, port int, tls bool, certFile, keyFile string, stepMaxLogSize int64) error {
+func grpcInit(dbConnectionFactory *database.DBConnectionFactory, addr string, port int, tls bool, certFile, keyFile string, stepMaxLogSize int64, logStorage objectstore.Driver) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", addr, port))
 	if err != nil {
 		return err
@@ -28,6 +29,7 @@ func grpcInit(dbConnectionFactory *database.DBConnectionFactory, addr string, po
 	grpcHandlers := &grpcHandlers{
 		dbConnectionFactory: dbConnectionFactory,
 		stepMaxLogSize:      stepMaxLogSize,
+		logStorage:          logStorage,
 	}
 
 	opts := []grpc.ServerOption{
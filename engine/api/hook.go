@@ -95,7 +95,98 @@ func (api *API) getHookPollingVCSEvents() service.Handler {
 		}
 
 		w.Header().Add("X-CDS-Poll-Interval", fmt.Sprintf("%.0f", pollingDelay.Seconds()))
+		if c, ok := client.(sdk.VCSRateLimitedClient); ok {
+			if rateLimit := c.RateLimitStatus(); rateLimit != nil {
+				// the rate limit applies to the token of the project, the pollers which use the tokens of the same user share it
+				rateLimit.Owner = vcsServer.Username
+				rateLimit.SetHeader(w.Header())
+			}
+		}
 
 		return service.WriteJSON(w, repoEvents, http.StatusOK)
 	}
 }
+
+// getHookChangedFilesHandler returns the files changed between two refs of the repository of a hook
+func (api *API) getHookChangedFilesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		uuid := vars["uuid"]
+		base := r.FormValue("base")
+		head := r.FormValue("head")
+		if base == "" || head == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "base and head are mandatory")
+		}
+
+		h, err := workflow.LoadHookByUUID(api.mustDB(), uuid)
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, h.Config[sdk.HookConfigProject].Value, nil)
+		if err != nil {
+			return err
+		}
+
+		vcsServer := repositoriesmanager.GetProjectVCSServer(proj, h.Config[sdk.HookConfigVCSServer].Value)
+		client, err := repositoriesmanager.AuthorizedClient(ctx, api.mustDB(), api.Cache, proj.Key, vcsServer)
+		if err != nil {
+			return err
+		}
+
+		files, err := client.ChangedFilesBetweenRefs(ctx, h.Config[sdk.HookConfigRepoFullName].Value, base, head)
+		if err != nil {
+			return sdk.WrapError(err, "unable to get changed files between %s and %s", base, head)
+		}
+
+		return service.WriteJSON(w, files, http.StatusOK)
+	}
+}
+
+// getHookRepositoryBranchHandler returns a branch of the repository of a hook, or its default branch
+func (api *API) getHookRepositoryBranchHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		uuid := vars["uuid"]
+		branch := r.FormValue("branch")
+
+		h, err := workflow.LoadHookByUUID(api.mustDB(), uuid)
+		if err != nil {
+			return err
+		}
+		if h.Config[sdk.HookConfigVCSServer].Value == "" || h.Config[sdk.HookConfigRepoFullName].Value == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "hook %s is not linked to a repository", uuid)
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, h.Config[sdk.HookConfigProject].Value, nil)
+		if err != nil {
+			return err
+		}
+
+		vcsServer := repositoriesmanager.GetProjectVCSServer(proj, h.Config[sdk.HookConfigVCSServer].Value)
+		client, err := repositoriesmanager.AuthorizedClient(ctx, api.mustDB(), api.Cache, proj.Key, vcsServer)
+		if err != nil {
+			return err
+		}
+
+		repo := h.Config[sdk.HookConfigRepoFullName].Value
+		if branch != "" {
+			b, err := client.Branch(ctx, repo, branch)
+			if err != nil {
+				return sdk.WrapError(err, "unable to get branch %s of repository %s", branch, repo)
+			}
+			return service.WriteJSON(w, b, http.StatusOK)
+		}
+
+		branches, err := client.Branches(ctx, repo)
+		if err != nil {
+			return sdk.WrapError(err, "unable to get branches of repository %s", repo)
+		}
+		for _, b := range branches {
+			if b.Default {
+				return service.WriteJSON(w, b, http.StatusOK)
+			}
+		}
+		return sdk.NewErrorFrom(sdk.ErrNotFound, "no default branch found for repository %s", repo)
+	}
+}
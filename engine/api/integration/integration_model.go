@@ -12,6 +12,8 @@ var (
 	BuiltinModels = []sdk.IntegrationModel{
 		sdk.KafkaIntegration,
 		sdk.RabbitMQIntegration,
+		sdk.NATSIntegration,
+		sdk.MQTTIntegration,
 		sdk.OpenstackIntegration,
 		sdk.AWSIntegration,
 	}
@@ -9,6 +9,7 @@ import (
 	"go.opencensus.io/stats"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -17,7 +18,7 @@ import (
 )
 
 //Initialize starts goroutines for workflows
-func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMap, workflowRunsMarkToDelete, workflowRunsDeleted *stats.Int64Measure) {
+func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMap, storage objectstore.Driver, workflowRunsMarkToDelete, workflowRunsDeleted *stats.Int64Measure) {
 	tickPurge := time.NewTicker(15 * time.Minute)
 	defer tickPurge.Stop()
 
@@ -30,7 +31,7 @@ func Initialize(ctx context.Context, store cache.Store, DBFunc func() *gorp.DbMa
 			}
 		case <-tickPurge.C:
 			log.Debug("purge> Deleting all workflow run marked to delete...")
-			if err := deleteWorkflowRunsHistory(ctx, DBFunc(), workflowRunsDeleted); err != nil {
+			if err := deleteWorkflowRunsHistory(ctx, DBFunc(), storage, workflowRunsDeleted); err != nil {
 				log.Warning("purge> Error on deleteWorkflowRunsHistory : %v", err)
 			}
 
@@ -144,13 +145,19 @@ func workflows(ctx context.Context, db *gorp.DbMap, store cache.Store, workflowR
 }
 
 // deleteWorkflowRunsHistory is useful to delete all the workflow run marked with to delete flag in db
-func deleteWorkflowRunsHistory(ctx context.Context, db gorp.SqlExecutor, workflowRunsDeleted *stats.Int64Measure) error {
+func deleteWorkflowRunsHistory(ctx context.Context, db gorp.SqlExecutor, storage objectstore.Driver, workflowRunsDeleted *stats.Int64Measure) error {
 	var ids []int64
 	if _, err := db.Select(&ids, "SELECT id FROM workflow_run WHERE to_delete = true ORDER BY id ASC LIMIT 2000"); err != nil {
 		return err
 	}
 
 	for _, id := range ids {
+		if storage != nil {
+			if err := workflow.DeleteStepLogParts(db, storage, id); err != nil {
+				log.Error("deleteWorkflowRunsHistory> unable to delete logs of workflow run %d: %v", id, err)
+			}
+		}
+
 		res, err := db.Exec("DELETE FROM workflow_run WHERE workflow_run.id = $1", id)
 		if err != nil {
 			log.Error("deleteWorkflowRunsHistory> unable to delete workflow run %d: %v", id, err)
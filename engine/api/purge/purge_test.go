@@ -12,6 +12,6 @@ func Test_deleteWorkflowRunsHistory(t *testing.T) {
 	db, _, end := test.SetupPG(t, bootstrap.InitiliazeDB)
 	defer end()
 
-	err := deleteWorkflowRunsHistory(context.Background(), db, nil)
+	err := deleteWorkflowRunsHistory(context.Background(), db, nil, nil)
 	test.NoError(t, err)
 }
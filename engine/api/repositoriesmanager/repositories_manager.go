@@ -65,6 +65,7 @@ type vcsClient struct {
 	srvs       []sdk.Service
 	cache      *gocache.Cache
 	db         gorp.SqlExecutor
+	rateLimit  *sdk.VCSRateLimit // rate limit of the vcs server given by the vcs service
 }
 
 func (c *vcsClient) Cache() *gocache.Cache {
@@ -255,6 +256,9 @@ func (c *vcsClient) doJSONRequest(ctx context.Context, method, path string, in i
 	if err != nil {
 		return code, sdk.WithStack(err)
 	}
+	if rateLimit := sdk.VCSRateLimitFromHeader(headers); rateLimit != nil {
+		c.rateLimit = rateLimit
+	}
 	err = c.checkAccessToken(ctx, headers)
 
 	return code, sdk.WithStack(err)
@@ -410,6 +414,17 @@ func (c *vcsClient) CommitsBetweenRefs(ctx context.Context, fullname, base, head
 	return commits, nil
 }
 
+func (c *vcsClient) ChangedFilesBetweenRefs(ctx context.Context, fullname, base, head string) ([]string, error) {
+	var files []string
+	path := fmt.Sprintf("/vcs/%s/repos/%s/changes?base=%s&head=%s", c.name, fullname, url.QueryEscape(base), url.QueryEscape(head))
+	if code, err := c.doJSONRequest(ctx, "GET", path, nil, &files); err != nil {
+		if code != http.StatusNotFound {
+			return nil, sdk.WrapError(err, "unable to find changed files on repository %s from %s", fullname, c.name)
+		}
+	}
+	return files, nil
+}
+
 func (c *vcsClient) Commit(ctx context.Context, fullname, hash string) (sdk.VCSCommit, error) {
 	commit := sdk.VCSCommit{}
 	path := fmt.Sprintf("/vcs/%s/repos/%s/commits/%s", c.name, fullname, hash)
@@ -610,6 +625,11 @@ func (c *vcsClient) GetAccessToken(_ context.Context) string {
 	return ""
 }
 
+// RateLimitStatus returns the last rate limit of the vcs server given by the vcs service
+func (c *vcsClient) RateLimitStatus() *sdk.VCSRateLimit {
+	return c.rateLimit
+}
+
 // WebhooksInfos is a set of info about webhooks
 type WebhooksInfos struct {
 	WebhooksSupported  bool     `json:"webhooks_supported"`
@@ -0,0 +1,116 @@
+package sbom
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// Insert stores a software bill of materials of a node run with its document and components.
+func Insert(db gorp.SqlExecutor, s *sdk.WorkflowNodeRunSBOM) error {
+	s.Created = time.Now()
+	if err := gorpmapping.Insert(db, s); err != nil {
+		return sdk.WrapError(err, "unable to insert sbom %s", s.Name)
+	}
+
+	if _, err := db.Exec("UPDATE workflow_node_run_sbom SET document = $1 WHERE id = $2", string(s.Document), s.ID); err != nil {
+		return sdk.WrapError(err, "unable to store document of sbom %s", s.Name)
+	}
+
+	for i := range s.Components {
+		s.Components[i].SBOMID = s.ID
+		if err := gorpmapping.Insert(db, &s.Components[i]); err != nil {
+			return sdk.WrapError(err, "unable to insert component %s of sbom %s", s.Components[i].Name, s.Name)
+		}
+	}
+	return nil
+}
+
+// LoadByNodeRunID returns the software bill of materials of a node run without their documents.
+func LoadByNodeRunID(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) ([]sdk.WorkflowNodeRunSBOM, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM workflow_node_run_sbom
+	WHERE workflow_node_run_id = $1
+	ORDER BY id`).Args(nodeRunID)
+
+	var res []sdk.WorkflowNodeRunSBOM
+	if err := gorpmapping.GetAll(ctx, db, query, &res); err != nil {
+		return nil, sdk.WrapError(err, "unable to load sbom for node run %d", nodeRunID)
+	}
+
+	for i := range res {
+		cs, err := loadComponents(ctx, db, res[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		res[i].Components = cs
+	}
+	return res, nil
+}
+
+// LoadDocument returns the software bill of materials of a node run with its document.
+func LoadDocument(ctx context.Context, db gorp.SqlExecutor, nodeRunID, id int64) (*sdk.WorkflowNodeRunSBOM, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM workflow_node_run_sbom
+	WHERE workflow_node_run_id = $1 AND id = $2`).Args(nodeRunID, id)
+
+	var s sdk.WorkflowNodeRunSBOM
+	found, err := gorpmapping.Get(ctx, db, query, &s)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load sbom %d", id)
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+
+	var doc sql.NullString
+	if err := db.QueryRow("SELECT document FROM workflow_node_run_sbom WHERE id = $1", id).Scan(&doc); err != nil {
+		return nil, sdk.WrapError(err, "unable to load document of sbom %d", id)
+	}
+	if doc.Valid {
+		s.Document = []byte(doc.String)
+	}
+	return &s, nil
+}
+
+func loadComponents(ctx context.Context, db gorp.SqlExecutor, sbomID int64) ([]sdk.SBOMComponent, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM sbom_component
+	WHERE sbom_id = $1
+	ORDER BY name, version`).Args(sbomID)
+
+	var res []sdk.SBOMComponent
+	if err := gorpmapping.GetAll(ctx, db, query, &res); err != nil {
+		return nil, sdk.WrapError(err, "unable to load components of sbom %d", sbomID)
+	}
+	return res, nil
+}
+
+// LoadComponentUsages returns the node runs of given projects that shipped a component, the most recent first.
+func LoadComponentUsages(ctx context.Context, db gorp.SqlExecutor, projectIDs []int64, filter sdk.SBOMComponentFilter) ([]sdk.SBOMComponentUsage, error) {
+	query := `
+	SELECT project.projectkey AS project_key, application.name AS application_name, workflow.name AS workflow_name,
+		workflow_node_run_sbom.num, workflow_node_run_sbom.branch, workflow_node_run_sbom.created,
+		sbom_component.name, sbom_component.version, sbom_component.license, sbom_component.purl
+	FROM sbom_component
+	JOIN workflow_node_run_sbom ON workflow_node_run_sbom.id = sbom_component.sbom_id
+	JOIN application ON application.id = workflow_node_run_sbom.application_id
+	JOIN workflow ON workflow.id = workflow_node_run_sbom.workflow_id
+	JOIN project ON project.id = workflow.project_id
+	WHERE project.id = ANY(string_to_array($1, ',')::int[])
+	AND ($2 = '' OR sbom_component.name = $2)
+	AND ($3 = '' OR sbom_component.version = $3)
+	AND ($4 = '' OR sbom_component.purl = $4)
+	ORDER BY workflow_node_run_sbom.created DESC, sbom_component.id`
+
+	var res []sdk.SBOMComponentUsage
+	if _, err := db.Select(&res, query, gorpmapping.IDsToQueryString(projectIDs), filter.Name, filter.Version, filter.PURL); err != nil {
+		return nil, sdk.WrapError(err, "unable to load component usages")
+	}
+	return res, nil
+}
@@ -0,0 +1,13 @@
+package sbom
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func init() {
+	gorpmapping.Register(
+		gorpmapping.New(sdk.WorkflowNodeRunSBOM{}, "workflow_node_run_sbom", true, "id"),
+		gorpmapping.New(sdk.SBOMComponent{}, "sbom_component", true, "id"),
+	)
+}
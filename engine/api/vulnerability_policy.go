@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// loadVulnerabilityPolicyTarget returns the project and the application id from the request, the application id
+// is 0 for the policy of the whole project.
+func (api *API) loadVulnerabilityPolicyTarget(ctx context.Context, r *http.Request) (*sdk.Project, int64, error) {
+	vars := mux.Vars(r)
+	key := vars[permProjectKey]
+	appName := vars["applicationName"]
+
+	proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+	if err != nil {
+		return nil, 0, sdk.WrapError(err, "unable to load project %s", key)
+	}
+
+	if appName == "" {
+		return proj, 0, nil
+	}
+
+	app, err := application.LoadByName(api.mustDB(), api.Cache, key, appName)
+	if err != nil {
+		return nil, 0, sdk.WrapError(err, "unable to load application")
+	}
+	return proj, app.ID, nil
+}
+
+func (api *API) getVulnerabilityPolicyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		proj, appID, err := api.loadVulnerabilityPolicyTarget(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		p, err := application.LoadVulnerabilityPolicy(ctx, api.mustDB(), proj.ID, appID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, p, http.StatusOK)
+	}
+}
+
+func (api *API) putVulnerabilityPolicyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var p sdk.VulnerabilityPolicy
+		if err := service.UnmarshalBody(r, &p); err != nil {
+			return sdk.WrapError(err, "unable to read body")
+		}
+		if err := p.IsValid(); err != nil {
+			return err
+		}
+
+		proj, appID, err := api.loadVulnerabilityPolicyTarget(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		p.ID = 0
+		p.ProjectID = proj.ID
+		p.ApplicationID = nil
+		if appID != 0 {
+			p.ApplicationID = &appID
+		}
+
+		if err := application.UpsertVulnerabilityPolicy(ctx, api.mustDB(), &p); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, p, http.StatusOK)
+	}
+}
+
+func (api *API) deleteVulnerabilityPolicyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		proj, appID, err := api.loadVulnerabilityPolicyTarget(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		p, err := application.LoadVulnerabilityPolicy(ctx, api.mustDB(), proj.ID, appID)
+		if err != nil {
+			return err
+		}
+
+		if err := application.DeleteVulnerabilityPolicy(api.mustDB(), p); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
@@ -0,0 +1,97 @@
+package workercache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func get(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.CacheEntry, error) {
+	var e sdk.CacheEntry
+	found, err := gorpmapping.Get(ctx, db, q, &e)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get cache entry")
+	}
+	if !found {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// LoadByTag returns the cache entry with given tag.
+func LoadByTag(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, tag string) (*sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM project_cache
+	WHERE project_id = $1 AND integration_name = $2 AND tag = $3`).Args(projectID, integrationName, tag)
+	return get(ctx, db, query)
+}
+
+// LoadLatestByTagPrefix returns the most recently pushed cache entry which tag starts with given prefix.
+func LoadLatestByTagPrefix(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, prefix string) (*sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM project_cache
+	WHERE project_id = $1 AND integration_name = $2 AND left(tag, length($3)) = $3
+	ORDER BY created DESC
+	LIMIT 1`).Args(projectID, integrationName, prefix)
+	return get(ctx, db, query)
+}
+
+// LoadByDigest returns a cache entry referencing the archive with given digest.
+func LoadByDigest(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, digest string) (*sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM project_cache
+	WHERE project_id = $1 AND integration_name = $2 AND digest = $3
+	LIMIT 1`).Args(projectID, integrationName, digest)
+	return get(ctx, db, query)
+}
+
+// LoadAllByProject returns all cache entries of a project storage, the most recently used first.
+func LoadAllByProject(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName string) ([]sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM project_cache
+	WHERE project_id = $1 AND integration_name = $2
+	ORDER BY last_access DESC`).Args(projectID, integrationName)
+	var es []sdk.CacheEntry
+	if err := gorpmapping.GetAll(ctx, db, query, &es); err != nil {
+		return nil, sdk.WrapError(err, "cannot get cache entries")
+	}
+	return es, nil
+}
+
+// CountByDigest returns the number of cache entries referencing the archive with given digest.
+func CountByDigest(db gorp.SqlExecutor, projectID int64, integrationName, digest string) (int64, error) {
+	n, err := db.SelectInt(`
+	SELECT COUNT(id) FROM project_cache
+	WHERE project_id = $1 AND integration_name = $2 AND digest = $3`, projectID, integrationName, digest)
+	if err != nil {
+		return 0, sdk.WrapError(err, "cannot count cache entries for digest %s", digest)
+	}
+	return n, nil
+}
+
+// Insert a cache entry in database.
+func Insert(db gorp.SqlExecutor, e *sdk.CacheEntry) error {
+	e.Created = time.Now()
+	e.LastAccess = e.Created
+	return sdk.WrapError(gorpmapping.Insert(db, e), "unable to insert cache entry %s", e.Tag)
+}
+
+// Update a cache entry in database.
+func Update(db gorp.SqlExecutor, e *sdk.CacheEntry) error {
+	return sdk.WrapError(gorpmapping.Update(db, e), "unable to update cache entry %s", e.Tag)
+}
+
+// UpdateLastAccess sets the last access date of a cache entry to now.
+func UpdateLastAccess(db gorp.SqlExecutor, id int64) error {
+	_, err := db.Exec("UPDATE project_cache SET last_access = $2 WHERE id = $1", id, time.Now())
+	return sdk.WrapError(err, "unable to update last access of cache entry %d", id)
+}
+
+// Delete a cache entry in database.
+func Delete(db gorp.SqlExecutor, e *sdk.CacheEntry) error {
+	return sdk.WrapError(gorpmapping.Delete(db, e), "unable to delete cache entry %s", e.Tag)
+}
@@ -113,6 +113,7 @@ func (api *API) getWorkflowHandler() service.Handler {
 
 		//We filter project and workflow configurtaion key, because they are always set on insertHooks
 		w1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		w1.HideHookSecrets()
 		return service.WriteJSON(w, w1, http.StatusOK)
 	}
 }
@@ -199,6 +200,7 @@ func (api *API) postWorkflowRollbackHandler() service.Handler {
 
 		event.PublishWorkflowUpdate(key, *wf, *newWf, deprecatedGetUser(ctx))
 
+		newWf.HideHookSecrets()
 		return service.WriteJSON(w, *newWf, http.StatusOK)
 	}
 }
@@ -369,6 +371,7 @@ func (api *API) postWorkflowHandler() service.Handler {
 
 		//We filter project and workflow configurtaion key, because they are always set on insertHooks
 		wf1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		wf1.HideHookSecrets()
 
 		return service.WriteJSON(w, wf1, http.StatusCreated)
 	}
@@ -454,6 +457,7 @@ func (api *API) putWorkflowHandler() service.Handler {
 
 		//We filter project and workflow configuration key, because they are always set on insertHooks
 		wf1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		wf1.HideHookSecrets()
 		return service.WriteJSON(w, wf1, http.StatusOK)
 	}
 }
@@ -147,10 +147,12 @@ func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	var res = struct {
 		Metadata     sql.NullString `db:"metadata"`
 		PurgeTags    sql.NullString `db:"purge_tags"`
+		IncludePaths sql.NullString `db:"include_paths"`
+		ExcludePaths sql.NullString `db:"exclude_paths"`
 		WorkflowData sql.NullString `db:"workflow_data"`
 	}{}
 
-	if err := db.SelectOne(&res, "SELECT metadata, purge_tags, workflow_data FROM workflow WHERE id = $1", w.ID); err != nil {
+	if err := db.SelectOne(&res, "SELECT metadata, purge_tags, include_paths, exclude_paths, workflow_data FROM workflow WHERE id = $1", w.ID); err != nil {
 		return sdk.WrapError(err, "PostGet> Unable to load marshalled workflow")
 	}
 
@@ -166,6 +168,13 @@ func (w *Workflow) PostGet(db gorp.SqlExecutor) error {
 	}
 	w.PurgeTags = purgeTags
 
+	if err := gorpmapping.JSONNullString(res.IncludePaths, &w.IncludePaths); err != nil {
+		return err
+	}
+	if err := gorpmapping.JSONNullString(res.ExcludePaths, &w.ExcludePaths); err != nil {
+		return err
+	}
+
 	data := &sdk.WorkflowData{}
 	if err := gorpmapping.JSONNullString(res.WorkflowData, data); err != nil {
 		return sdk.WrapError(err, "Unable to unmarshall workflow data")
@@ -211,11 +220,20 @@ func (w *Workflow) PostUpdate(db gorp.SqlExecutor) error {
 		return errPt
 	}
 
+	includePaths, errI := gorpmapping.JSONToNullString(w.IncludePaths)
+	if errI != nil {
+		return sdk.WrapError(errI, "Workflow.PostUpdate> Unable to marshall include paths")
+	}
+	excludePaths, errE := gorpmapping.JSONToNullString(w.ExcludePaths)
+	if errE != nil {
+		return sdk.WrapError(errE, "Workflow.PostUpdate> Unable to marshall exclude paths")
+	}
+
 	data, errD := gorpmapping.JSONToNullString(w.WorkflowData)
 	if errD != nil {
 		return sdk.WrapError(errD, "Workflow.PostUpdate> Unable to marshall workflow data")
 	}
-	if _, err := db.Exec("update workflow set purge_tags = $1, workflow_data = $3 where id = $2", pt, w.ID, data); err != nil {
+	if _, err := db.Exec("update workflow set purge_tags = $1, workflow_data = $3, include_paths = $4, exclude_paths = $5 where id = $2", pt, w.ID, data, includePaths, excludePaths); err != nil {
 		return err
 	}
 
@@ -618,6 +636,9 @@ func loadFavorite(db gorp.SqlExecutor, w *sdk.Workflow, u *sdk.User) (bool, erro
 
 // Insert inserts a new workflow
 func Insert(db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, p *sdk.Project, u *sdk.User) error {
+	// Secrets of the hooks sent hidden can't be restored on a new workflow
+	w.RestoreHookSecrets(nil)
+
 	if err := IsValid(context.TODO(), store, db, w, p, u, LoadOptions{}); err != nil {
 		return sdk.WrapError(err, "Unable to validate workflow")
 	}
@@ -881,6 +902,10 @@ func RenameNode(db gorp.SqlExecutor, w *sdk.Workflow) error {
 func Update(ctx context.Context, db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, p *sdk.Project, u *sdk.User, uptOption UpdateOptions) error {
 	ctx, end := observability.Span(ctx, "workflow.Update")
 	defer end()
+
+	// Secrets of the hooks are sent back hidden by the users
+	w.RestoreHookSecrets(uptOption.OldWorkflow)
+
 	if err := IsValid(ctx, store, db, w, p, u, LoadOptions{}); err != nil {
 		return err
 	}
@@ -1163,6 +1188,16 @@ func checkHooks(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.Node) error {
 				}
 			}
 		}
+
+		// A webhook authenticated with a signature or a token can't be called without secret
+		if model.Name == sdk.WebHookModelName {
+			switch auth := h.Config[sdk.WebHookModelConfigAuth].Value; auth {
+			case sdk.WebHookAuthHMAC, sdk.WebHookAuthBearer:
+				if h.Config[sdk.HookConfigWebHookSecret].Value == "" {
+					return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given hook config, '%s' is required by the %s authentication", sdk.HookConfigWebHookSecret, auth)
+				}
+			}
+		}
 	}
 
 	return nil
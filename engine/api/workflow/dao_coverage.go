@@ -87,9 +87,9 @@ func UpdateCoverage(db gorp.SqlExecutor, cov sdk.WorkflowNodeRunCoverage) error
 
 // PostGet is a db hook on workflow_node_run_coverage
 func (c *Coverage) PostGet(s gorp.SqlExecutor) error {
-	var report, trend sql.NullString
-	query := "SELECT report, trend FROM workflow_node_run_coverage WHERE workflow_node_run_id=$1"
-	if err := s.QueryRow(query, c.WorkflowNodeRunID).Scan(&report, &trend); err != nil {
+	var report, trend, packages sql.NullString
+	query := "SELECT report, trend, packages FROM workflow_node_run_coverage WHERE workflow_node_run_id=$1"
+	if err := s.QueryRow(query, c.WorkflowNodeRunID).Scan(&report, &trend, &packages); err != nil {
 		return sdk.WrapError(err, "Unable to get report and trend")
 	}
 
@@ -100,6 +100,10 @@ func (c *Coverage) PostGet(s gorp.SqlExecutor) error {
 	if err := gorpmapping.JSONNullString(trend, &c.Trend); err != nil {
 		return sdk.WrapError(err, "Unable to unmarshal trend")
 	}
+
+	if err := gorpmapping.JSONNullString(packages, &c.Packages); err != nil {
+		return sdk.WrapError(err, "Unable to unmarshal packages")
+	}
 	return nil
 }
 
@@ -119,11 +123,16 @@ func (c *Coverage) PostUpdate(s gorp.SqlExecutor) error {
 		return sdk.WrapError(errT, "workflow.coverage.postupdate> Unable to stringify trend")
 	}
 
+	packagesS, errP := gorpmapping.JSONToNullString(c.Packages)
+	if errP != nil {
+		return sdk.WrapError(errP, "workflow.coverage.postupdate> Unable to stringify packages")
+	}
+
 	query := `
     UPDATE workflow_node_run_coverage 
-    SET report=$1, trend=$2
-    WHERE workflow_node_run_id=$3`
-	if _, err := s.Exec(query, reportS, trendS, c.WorkflowNodeRunID); err != nil {
+    SET report=$1, trend=$2, packages=$3
+    WHERE workflow_node_run_id=$4`
+	if _, err := s.Exec(query, reportS, trendS, packagesS, c.WorkflowNodeRunID); err != nil {
 		return sdk.WrapError(err, "Unable to update report and trend")
 	}
 
@@ -131,7 +140,7 @@ func (c *Coverage) PostUpdate(s gorp.SqlExecutor) error {
 }
 
 // ComputeNewReport compute trends and import new coverage report
-func ComputeNewReport(ctx context.Context, db gorp.SqlExecutor, cache cache.Store, report coverage.Report, wnr *sdk.WorkflowNodeRun, proj *sdk.Project) error {
+func ComputeNewReport(ctx context.Context, db gorp.SqlExecutor, cache cache.Store, report coverage.Report, wnr *sdk.WorkflowNodeRun, proj *sdk.Project) (sdk.WorkflowNodeRunCoverage, error) {
 	covReport := sdk.WorkflowNodeRunCoverage{
 		WorkflowID:        wnr.WorkflowID,
 		WorkflowRunID:     wnr.WorkflowRunID,
@@ -141,13 +150,14 @@ func ComputeNewReport(ctx context.Context, db gorp.SqlExecutor, cache cache.Stor
 		Repository:        wnr.VCSRepository,
 		Branch:            wnr.VCSBranch,
 		Report:            report,
+		Packages:          sdk.CoveragePackages(report),
 		Trend:             sdk.WorkflowNodeRunCoverageTrends{},
 	}
 
 	// Get previous report
 	previousReport, errP := loadPreviousCoverageReport(db, wnr.WorkflowID, wnr.Number, wnr.VCSRepository, wnr.VCSBranch, covReport.ApplicationID)
 	if errP != nil && !sdk.ErrorIs(errP, sdk.ErrNotFound) {
-		return sdk.WrapError(errP, "computeNewReport> Unable to load previous report")
+		return covReport, sdk.WrapError(errP, "computeNewReport> Unable to load previous report")
 	}
 
 	if !sdk.ErrorIs(errP, sdk.ErrNotFound) {
@@ -157,14 +167,14 @@ func ComputeNewReport(ctx context.Context, db gorp.SqlExecutor, cache cache.Stor
 	}
 
 	if err := ComputeLatestDefaultBranchReport(ctx, db, cache, proj, wnr, &covReport); err != nil {
-		return sdk.WrapError(err, "Unable to get default branch coverage report")
+		return covReport, sdk.WrapError(err, "Unable to get default branch coverage report")
 	}
 
 	if err := InsertCoverage(db, covReport); err != nil {
-		return sdk.WrapError(err, "Unable to insert coverage report")
+		return covReport, sdk.WrapError(err, "Unable to insert coverage report")
 	}
 
-	return nil
+	return covReport, nil
 }
 
 // ComputeLatestDefaultBranchReport add the default branch coverage report into  the given report
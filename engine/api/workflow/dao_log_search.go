@@ -0,0 +1,380 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// LogSearchIndex returns the step logs of the latest runs of a workflow that may match a search, and whether some
+// step logs were not searched.
+type LogSearchIndex interface {
+	SearchStepLogs(ctx context.Context, workflowID int64, req sdk.LogSearchRequest) ([]sdk.LogSearchDocument, bool, error)
+}
+
+// NewLogSearchIndex returns the log search index for the given name, the postgres index is used by default. The
+// store is where step logs are archived.
+func NewLogSearchIndex(db gorp.SqlExecutor, store objectstore.Driver, name string) LogSearchIndex {
+	if name == sdk.LogSearchIndexElasticsearch {
+		return elasticsearchLogSearchIndex{db: db}
+	}
+	return postgresLogSearchIndex{db: db, store: store}
+}
+
+// SearchLogs returns the lines of the step logs of the latest runs of a workflow that match the request.
+func SearchLogs(ctx context.Context, index LogSearchIndex, workflowID int64, req sdk.LogSearchRequest) (sdk.LogSearchResult, error) {
+	docs, partial, err := index.SearchStepLogs(ctx, workflowID, req)
+	if err != nil {
+		return sdk.LogSearchResult{}, err
+	}
+
+	res := sdk.LogSearchResult{Hits: []sdk.LogSearchHit{}, Partial: partial}
+	for _, d := range docs {
+		if len(res.Hits) >= req.Limit {
+			break
+		}
+		res.Hits = append(res.Hits, req.Hits(d, req.Limit-len(res.Hits))...)
+	}
+	return res, nil
+}
+
+// LoadLastRunNumbers returns the numbers of the latest runs of a workflow.
+func LoadLastRunNumbers(db gorp.SqlExecutor, workflowID int64, limit int64) ([]int64, error) {
+	var nums []int64
+	if _, err := db.Select(&nums, "SELECT num FROM workflow_run WHERE workflow_id = $1 ORDER BY num DESC LIMIT $2", workflowID, limit); err != nil {
+		return nil, sdk.WrapError(err, "unable to load last run numbers of workflow %d", workflowID)
+	}
+	return nums, nil
+}
+
+const (
+	logSearchIndexName = "idx_workflow_node_run_job_logs_value_trgm"
+	// maximum size of the archived step logs read from the object storage by a search, the next ones are skipped
+	logSearchMaxArchivedSize = 50 * 1024 * 1024
+)
+
+type postgresLogSearchIndex struct {
+	db    gorp.SqlExecutor
+	store objectstore.Driver
+}
+
+// SearchStepLogs uses the trigram index on step logs values to filter the logs of the latest runs.
+// Logs moved to the object storage are read from the storage and matched line by line, until logSearchMaxArchivedSize
+// is read. The search is then partial.
+func (i postgresLogSearchIndex) SearchStepLogs(ctx context.Context, workflowID int64, req sdk.LogSearchRequest) ([]sdk.LogSearchDocument, bool, error) {
+	filter := "workflow_node_run_job_logs.value LIKE $3"
+	arg := "%" + escapeLike(req.Query) + "%"
+	if req.Regex {
+		filter = "workflow_node_run_job_logs.value ~ $3"
+		arg = postgresRegex(req.Query)
+	}
+
+	query := `
+	WITH runs AS (
+		SELECT id, num FROM workflow_run
+		WHERE workflow_id = $1
+		ORDER BY num DESC
+		LIMIT $2
+	)
+	SELECT runs.num, workflow_node_run.id, workflow_node_run.workflow_node_name,
+		workflow_node_run_job_logs.workflow_node_run_job_id, workflow_node_run_job_logs.step_order, workflow_node_run_job_logs.value,
+		workflow_node_run_job_logs.parts, ` + filter + `
+	FROM workflow_node_run_job_logs
+	JOIN workflow_node_run ON workflow_node_run.id = workflow_node_run_job_logs.workflow_node_run_id
+	JOIN runs ON runs.id = workflow_node_run.workflow_run_id
+	WHERE ` + filter + ` OR workflow_node_run_job_logs.parts IS NOT NULL
+	ORDER BY runs.num DESC, workflow_node_run.id, workflow_node_run_job_logs.workflow_node_run_job_id, workflow_node_run_job_logs.step_order`
+
+	rows, err := i.db.Query(query, workflowID, req.Runs, arg)
+	if e, ok := err.(*pq.Error); ok && e.Code == gorpmapping.InvalidRegularExpressionPGCode {
+		return nil, false, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid regular expression: %s", e.Message)
+	}
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "unable to search step logs of workflow %d", workflowID)
+	}
+	defer rows.Close()
+
+	var docs []sdk.LogSearchDocument
+	var archivedSize int64
+	var partial bool
+	for rows.Next() && len(docs) < req.Limit {
+		d := sdk.LogSearchDocument{WorkflowID: workflowID}
+		var sizes pq.Int64Array
+		var matched bool
+		if err := rows.Scan(&d.RunNumber, &d.NodeRunID, &d.NodeName, &d.JobRunID, &d.StepOrder, &d.Value, &sizes, &matched); err != nil {
+			return nil, false, sdk.WithStack(err)
+		}
+		if len(sizes) > 0 {
+			var size int64
+			for _, s := range sizes {
+				size += s
+			}
+			if i.store == nil || archivedSize+size > logSearchMaxArchivedSize {
+				partial = true
+				continue
+			}
+			archivedSize += size
+
+			p := StepLogParts{NodeRunID: d.NodeRunID, JobRunID: d.JobRunID, StepOrder: d.StepOrder, Sizes: sizes}
+			d.Value, err = readStepLog(NewStepLogReader(i.store, p, d.Value, 0))
+			if err != nil {
+				return nil, false, err
+			}
+			matched = len(req.Hits(d, 1)) > 0
+		}
+		if matched {
+			docs = append(docs, d)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, sdk.WithStack(err)
+	}
+
+	if err := loadLogSearchDocumentsNames(i.db, docs); err != nil {
+		return nil, false, err
+	}
+	return docs, partial, nil
+}
+
+func readStepLog(r io.ReadCloser) (string, error) {
+	defer r.Close()
+	btes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", sdk.WrapError(err, "unable to read step log")
+	}
+	return string(btes), nil
+}
+
+// loadLogSearchDocumentsNames sets the job and step names of documents from the stages of their node runs.
+func loadLogSearchDocumentsNames(db gorp.SqlExecutor, docs []sdk.LogSearchDocument) error {
+	ids := make([]int64, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.NodeRunID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := db.Query("SELECT id, stages FROM workflow_node_run WHERE id = ANY($1)", pq.Int64Array(ids))
+	if err != nil {
+		return sdk.WrapError(err, "unable to load node runs stages")
+	}
+	defer rows.Close()
+
+	jobs := map[int64]sdk.WorkflowNodeJobRun{}
+	for rows.Next() {
+		var id int64
+		var stagesDB sql.NullString
+		if err := rows.Scan(&id, &stagesDB); err != nil {
+			return sdk.WithStack(err)
+		}
+		var stages []sdk.Stage
+		if err := gorpmapping.JSONNullString(stagesDB, &stages); err != nil {
+			return sdk.WrapError(err, "unable to unmarshal stages of node run %d", id)
+		}
+		for _, s := range stages {
+			for _, j := range s.RunJobs {
+				jobs[j.ID] = j
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	for i := range docs {
+		j, has := jobs[docs[i].JobRunID]
+		if !has {
+			continue
+		}
+		docs[i].JobName, docs[i].StepName = LogSearchJobStepNames(j, docs[i].StepOrder)
+	}
+	return nil
+}
+
+// LogSearchJobStepNames returns the name of a job and the name of one of its steps.
+func LogSearchJobStepNames(j sdk.WorkflowNodeJobRun, stepOrder int64) (string, string) {
+	var stepName string
+	if stepOrder >= 0 && int(stepOrder) < len(j.Job.Action.Actions) {
+		step := j.Job.Action.Actions[stepOrder]
+		stepName = step.StepName
+		if stepName == "" {
+			stepName = step.Name
+		}
+	}
+	return j.Job.Action.Name, stepName
+}
+
+// postgresRegex returns the postgres regular expression of a RE2 regular expression. Postgres matches the whole
+// step log, the newline-sensitive mode is used to match each line as sdk.LogSearchRequest.Hits does, and the RE2
+// escapes of word boundaries and of beginning and end of text are translated.
+func postgresRegex(expr string) string {
+	flags, expr := sdk.SplitLogSearchRegexFlags(expr)
+	// flags after a "-" are cleared
+	if i := strings.Index(flags, "-"); i >= 0 {
+		flags = flags[:i]
+	}
+	options := "n"
+	if strings.Contains(flags, "i") {
+		options += "i"
+	}
+
+	var b strings.Builder
+	b.WriteString("(?" + options + ")")
+	for i := 0; i < len(expr); i++ {
+		if expr[i] != '\\' || i+1 == len(expr) {
+			b.WriteByte(expr[i])
+			continue
+		}
+		i++
+		switch expr[i] {
+		case 'b':
+			b.WriteString(`\y`)
+		case 'B':
+			b.WriteString(`\Y`)
+		case 'A':
+			b.WriteByte('^')
+		case 'z':
+			b.WriteByte('$')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(expr[i])
+		}
+	}
+	return b.String()
+}
+
+// CreateLogSearchIndex builds the trigram index on step logs values used by the postgres log search. The index is
+// built concurrently, outside of the sql migrations which run in a transaction, once the pg_trgm extension exists.
+func CreateLogSearchIndex(db gorp.SqlExecutor, store cache.Store) error {
+	lockKey := cache.Key("workflow", "logs", "search", "index")
+	if !store.Lock(lockKey, 24*time.Hour, -1, -1) {
+		return nil
+	}
+	defer store.Unlock(lockKey)
+
+	n, err := db.SelectInt("SELECT COUNT(1) FROM pg_extension WHERE extname = 'pg_trgm'")
+	if err != nil {
+		return sdk.WrapError(err, "unable to check pg_trgm extension")
+	}
+	if n == 0 {
+		log.Warning("CreateLogSearchIndex> pg_trgm extension must be created by a superuser to index the step logs")
+		return nil
+	}
+
+	// an index whose build failed is left invalid and must be dropped to be built again
+	var valid []bool
+	if _, err := db.Select(&valid, `
+		SELECT pg_index.indisvalid FROM pg_index
+		JOIN pg_class ON pg_class.oid = pg_index.indexrelid
+		WHERE pg_class.relname = $1`, logSearchIndexName); err != nil {
+		return sdk.WrapError(err, "unable to check index %s", logSearchIndexName)
+	}
+	if len(valid) > 0 && valid[0] {
+		return nil
+	}
+	if len(valid) > 0 {
+		if _, err := db.Exec("DROP INDEX CONCURRENTLY IF EXISTS " + logSearchIndexName); err != nil {
+			return sdk.WrapError(err, "unable to drop invalid index %s", logSearchIndexName)
+		}
+	}
+
+	log.Info("CreateLogSearchIndex> building index %s", logSearchIndexName)
+	if _, err := db.Exec("CREATE INDEX CONCURRENTLY IF NOT EXISTS " + logSearchIndexName + " ON workflow_node_run_job_logs USING GIN (value gin_trgm_ops)"); err != nil {
+		return sdk.WrapError(err, "unable to create index %s", logSearchIndexName)
+	}
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+type elasticsearchLogSearchIndex struct {
+	db gorp.SqlExecutor
+}
+
+// SearchStepLogs asks the elasticsearch service for the step logs of the latest runs.
+func (i elasticsearchLogSearchIndex) SearchStepLogs(ctx context.Context, workflowID int64, req sdk.LogSearchRequest) ([]sdk.LogSearchDocument, bool, error) {
+	nums, err := LoadLastRunNumbers(i.db, workflowID, req.Runs)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(nums) == 0 {
+		return nil, false, nil
+	}
+
+	srvs, err := services.FindByType(i.db, services.TypeElasticsearch)
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "unable to get elasticsearch service")
+	}
+	if len(srvs) == 0 {
+		return nil, false, sdk.NewErrorFrom(sdk.ErrNotFound, "no elasticsearch service found to search in logs")
+	}
+
+	indexReq := sdk.LogSearchIndexRequest{
+		LogSearchRequest: req,
+		WorkflowID:       workflowID,
+		RunNumbers:       nums,
+	}
+	var docs []sdk.LogSearchDocument
+	if _, _, err := services.DoJSONRequest(ctx, srvs, "GET", "/logs", indexReq, &docs); err != nil {
+		return nil, false, sdk.WrapError(err, "unable to search logs")
+	}
+	return docs, false, nil
+}
+
+// PushStepLogInElasticSearch sends the log of a terminated step to the elasticsearch service.
+func PushStepLogInElasticSearch(ctx context.Context, db gorp.SqlExecutor, store objectstore.Driver, nodeRun sdk.WorkflowNodeRun, job sdk.WorkflowNodeJobRun, stepOrder int64) {
+	srvs, err := services.FindByType(db, services.TypeElasticsearch)
+	if err != nil {
+		log.Error("PushStepLogInElasticSearch> Unable to get elasticsearch service: %v", err)
+		return
+	}
+	if len(srvs) == 0 {
+		return
+	}
+
+	r, _, err := LoadStepLogReader(db, store, job.ID, stepOrder, 0)
+	if err != nil {
+		log.Error("PushStepLogInElasticSearch> Unable to load logs of step %d of job %d: %v", stepOrder, job.ID, err)
+		return
+	}
+	defer r.Close()
+	value, err := ioutil.ReadAll(r)
+	if err != nil {
+		log.Error("PushStepLogInElasticSearch> Unable to read logs of step %d of job %d: %v", stepOrder, job.ID, err)
+		return
+	}
+	if len(value) == 0 {
+		return
+	}
+
+	doc := sdk.LogSearchDocument{
+		WorkflowID: nodeRun.WorkflowID,
+		RunNumber:  nodeRun.Number,
+		NodeRunID:  nodeRun.ID,
+		NodeName:   nodeRun.WorkflowNodeName,
+		JobRunID:   job.ID,
+		StepOrder:  stepOrder,
+		Value:      string(value),
+	}
+	doc.JobName, doc.StepName = LogSearchJobStepNames(job, stepOrder)
+
+	if _, code, err := services.DoJSONRequest(ctx, srvs, "POST", "/logs", doc, nil); code >= 400 || err != nil {
+		log.Error("PushStepLogInElasticSearch> Unable to send logs of step %d of job %d to elasticsearch [%d]: %v", stepOrder, job.ID, code, err)
+	}
+}
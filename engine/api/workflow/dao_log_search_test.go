@@ -0,0 +1,141 @@
+package workflow_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSearchLogs(t *testing.T) {
+	db, cache, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	event.Initialize(event.KafkaConfig{}, cache)
+
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+	}
+	require.NoError(t, pipeline.InsertPipeline(db, cache, proj, &pip, u))
+
+	w := sdk.Workflow{
+		Name:       "test_search_logs",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: &sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "node1",
+				Ref:  "node1",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID: pip.ID,
+				},
+			},
+		},
+	}
+	require.NoError(t, workflow.Insert(db, cache, &w, proj, u))
+	w1, err := workflow.Load(context.TODO(), db, cache, proj, w.Name, u, workflow.LoadOptions{DeepPipeline: true})
+	require.NoError(t, err)
+
+	wfr, err := workflow.CreateRun(db, w1, nil, u)
+	require.NoError(t, err)
+	wfr.Workflow = *w1
+	_, err = workflow.StartWorkflowRun(context.TODO(), db, cache, proj, wfr, &sdk.WorkflowRunPostHandlerOption{
+		Manual: &sdk.WorkflowNodeRunManual{User: *u},
+	}, u, nil)
+	require.NoError(t, err)
+
+	nodeRunID, err := db.SelectInt("SELECT id FROM workflow_node_run WHERE workflow_run_id = $1", wfr.ID)
+	require.NoError(t, err)
+	for i, v := range []string{"compiling\nerror: first\ndone", "testing\nError: second\n100% done"} {
+		_, err := db.Exec(`
+			INSERT INTO workflow_node_run_job_logs (workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value)
+			VALUES ($1, $2, $3, $3, $3, $4, $5)`, 1, nodeRunID, time.Now(), i, v)
+		require.NoError(t, err)
+	}
+
+	// the log of the third step has been archived in the object storage
+	dir, err := ioutil.TempDir("", "cds-logs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+	store, err := objectstore.Init(context.Background(), objectstore.Config{
+		Kind:    objectstore.Filesystem,
+		Options: objectstore.ConfigOptions{Filesystem: objectstore.ConfigOptionsFilesystem{Basedir: dir}},
+	})
+	require.NoError(t, err)
+	archived := "deploying\nerror: archived\n"
+	buf := new(bytes.Buffer)
+	gw := gzip.NewWriter(buf)
+	_, err = gw.Write([]byte(archived))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	_, err = store.Store(sdk.StepLogPart{NodeRunID: nodeRunID, JobRunID: 1, StepOrder: 2}, ioutil.NopCloser(buf))
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO workflow_node_run_job_logs (workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value, parts, archived)
+		VALUES ($1, $2, $3, $3, $3, $4, '', $5, true)`, 1, nodeRunID, time.Now(), 2, pq.Int64Array{int64(len(archived))})
+	require.NoError(t, err)
+
+	index := workflow.NewLogSearchIndex(db, store, sdk.LogSearchIndexPostgres)
+	search := func(req sdk.LogSearchRequest) []sdk.LogSearchHit {
+		require.NoError(t, req.IsValid())
+		res, err := workflow.SearchLogs(context.TODO(), index, w1.ID, req)
+		require.NoError(t, err)
+		assert.False(t, res.Partial)
+		return res.Hits
+	}
+
+	// LIKE special characters are escaped
+	hits := search(sdk.LogSearchRequest{Query: "100%"})
+	require.Len(t, hits, 1)
+	assert.Equal(t, int64(1), hits[0].StepOrder)
+	assert.Equal(t, "node1", hits[0].NodeName)
+
+	// regular expressions are matched line by line
+	hits = search(sdk.LogSearchRequest{Query: `^error: \w+$`, Regex: true})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "error: first", hits[0].Content)
+
+	hits = search(sdk.LogSearchRequest{Query: `(?i)^error:`, Regex: true})
+	assert.Len(t, hits, 3)
+
+	// archived logs are searched in the object storage
+	hits = search(sdk.LogSearchRequest{Query: "archived"})
+	require.Len(t, hits, 1)
+	assert.Equal(t, int64(2), hits[0].StepOrder)
+	assert.Equal(t, []string{"deploying"}, hits[0].Before)
+
+	hits = search(sdk.LogSearchRequest{Query: `\bdone\z`, Regex: true})
+	assert.Len(t, hits, 2)
+
+	// without the object storage, the archived logs are not searched and the results are partial
+	res, err := workflow.SearchLogs(context.TODO(), workflow.NewLogSearchIndex(db, nil, sdk.LogSearchIndexPostgres), w1.ID, sdk.LogSearchRequest{Query: "error", Runs: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.True(t, res.Partial)
+	assert.Len(t, res.Hits, 1)
+
+	// a regular expression that postgres can't compile is a wrong request
+	_, err = workflow.SearchLogs(context.TODO(), index, w1.ID, sdk.LogSearchRequest{Query: `a(?i)b`, Regex: true, Runs: 1, Limit: 1})
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest))
+}
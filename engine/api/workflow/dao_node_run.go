@@ -50,7 +50,9 @@ workflow_node_run.uuid,
 workflow_node_run.outgoinghook,
 workflow_node_run.hook_execution_timestamp,
 workflow_node_run.execution_id,
-workflow_node_run.callback
+workflow_node_run.callback,
+workflow_node_run.tests_quarantined,
+workflow_node_run.vulnerability_policy
 `
 
 const nodeRunTestsField string = ", workflow_node_run.tests"
@@ -266,6 +268,7 @@ func fromDBNodeRun(rr NodeRun, opts LoadRunOptions) (*sdk.WorkflowNodeRun, error
 	r.Start = rr.Start
 	r.Done = rr.Done
 	r.LastModified = rr.LastModified
+	r.TestsQuarantined = int(rr.TestsQuarantined.Int64)
 
 	if rr.VCSHash.Valid {
 		r.VCSHash = rr.VCSHash.String
@@ -378,6 +381,12 @@ func fromDBNodeRun(rr NodeRun, opts LoadRunOptions) (*sdk.WorkflowNodeRun, error
 		}
 	}
 
+	if rr.VulnerabilityPolicy.Valid {
+		if err := gorpmapping.JSONNullString(rr.VulnerabilityPolicy, &r.VulnerabilityPolicy); err != nil {
+			return nil, sdk.WrapError(err, "fromDBNodeRun>Error loading node run %d: VulnerabilityPolicy", r.ID)
+		}
+	}
+
 	return r, nil
 }
 
@@ -414,7 +423,16 @@ func makeDBNodeRun(n sdk.WorkflowNodeRun) (*NodeRun, error) {
 	nodeRunDB.HookExecutionTimestamp.Int64 = n.HookExecutionTimeStamp
 	nodeRunDB.UUID.Valid = true
 	nodeRunDB.UUID.String = n.UUID
+	nodeRunDB.TestsQuarantined.Valid = true
+	nodeRunDB.TestsQuarantined.Int64 = int64(n.TestsQuarantined)
 
+	if n.VulnerabilityPolicy != nil {
+		s, err := gorpmapping.JSONToNullString(n.VulnerabilityPolicy)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to get json from vulnerability_policy")
+		}
+		nodeRunDB.VulnerabilityPolicy = s
+	}
 	if n.TriggersRun != nil {
 		s, err := gorpmapping.JSONToNullString(n.TriggersRun)
 		if err != nil {
@@ -535,6 +553,18 @@ func UpdateNodeRun(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) error {
 	return nil
 }
 
+// UpdateNodeRunVulnerabilityPolicy stores the evaluation of the vulnerability policy of a node run
+func UpdateNodeRunVulnerabilityPolicy(db gorp.SqlExecutor, id int64, e *sdk.VulnerabilityPolicyEvaluation) error {
+	s, err := gorpmapping.JSONToNullString(e)
+	if err != nil {
+		return sdk.WrapError(err, "unable to get json from vulnerability policy evaluation")
+	}
+	if _, err := db.Exec("UPDATE workflow_node_run SET vulnerability_policy = $1 WHERE id = $2", s, id); err != nil {
+		return sdk.WrapError(err, "unable to update vulnerability policy of node run %d", id)
+	}
+	return nil
+}
+
 // GetNodeRunBuildCommits gets commits for given node run and return current vcs info
 func GetNodeRunBuildCommits(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, wf *sdk.Workflow, wNodeName string, number int64, nodeRun *sdk.WorkflowNodeRun, app *sdk.Application, env *sdk.Environment) ([]sdk.VCSCommit, sdk.BuildNumberAndHash, error) {
 	var cur sdk.BuildNumberAndHash
@@ -96,7 +96,7 @@ func LoadNodeJobRunQueue(ctx context.Context, db gorp.SqlExecutor, store cache.S
 	and workflow_node_run_job.status = ANY(string_to_array($3, ','))
 	AND contains_service IN ($4, $5)
 	AND (model_type is NULL OR model_type = '' OR model_type = ANY(string_to_array($6, ',')))
-	ORDER BY workflow_node_run_job.queued ASC
+	ORDER BY workflow_node_run_job.priority DESC, workflow_node_run_job.queued ASC
 	`).Args(
 		*filter.Since,                       // $1
 		*filter.Until,                       // $2
@@ -175,7 +175,7 @@ func LoadNodeJobRunQueueByGroups(ctx context.Context, db gorp.SqlExecutor, store
 		OR 
 		model_type = '' OR model_type = ANY(string_to_array($6, ','))
 	)
-	ORDER BY workflow_node_run_job.queued ASC
+	ORDER BY workflow_node_run_job.priority DESC, workflow_node_run_job.queued ASC
 	`).Args(
 		*filter.Since,                       // $1
 		*filter.Until,                       // $2
@@ -215,6 +215,10 @@ func loadNodeJobRunQueue(ctx context.Context, db gorp.SqlExecutor, store cache.S
 		jobs = append(jobs, jr)
 	}
 
+	if err := sortNodeJobRunQueue(ctx, db, store, jobs); err != nil {
+		log.Error("LoadNodeJobRunQueue> unable to sort queue: %v", err)
+	}
+
 	return jobs, nil
 }
 
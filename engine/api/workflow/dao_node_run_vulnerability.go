@@ -3,7 +3,6 @@ package workflow
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"github.com/go-gorp/gorp"
 
@@ -16,8 +15,9 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-// HandleVulnerabilityReport calculate vulnerability trend and save report
-func HandleVulnerabilityReport(ctx context.Context, db gorp.SqlExecutor, cache cache.Store, proj *sdk.Project, nr *sdk.WorkflowNodeRun, workerReport sdk.VulnerabilityWorkerReport) error {
+// HandleVulnerabilityReport calculate vulnerability trend, save report and returns the evaluation of the vulnerability
+// policy of the application, nil if there is no policy to evaluate.
+func HandleVulnerabilityReport(ctx context.Context, db gorp.SqlExecutor, cache cache.Store, proj *sdk.Project, nr *sdk.WorkflowNodeRun, workerReport sdk.VulnerabilityWorkerReport) (*sdk.VulnerabilityPolicyEvaluation, error) {
 	var defaultBranch string
 	// Get default branch
 	if nr.VCSServer != "" {
@@ -25,12 +25,12 @@ func HandleVulnerabilityReport(ctx context.Context, db gorp.SqlExecutor, cache c
 		projectVCSServer := repositoriesmanager.GetProjectVCSServer(proj, nr.VCSServer)
 		client, erra := repositoriesmanager.AuthorizedClient(ctx, db, cache, proj.Key, projectVCSServer)
 		if erra != nil {
-			return sdk.WrapError(sdk.ErrNoReposManagerClientAuth, "HandleVulnerabilityReport> Cannot get repo client %s : %v", nr.VCSServer, erra)
+			return nil, sdk.WrapError(sdk.ErrNoReposManagerClientAuth, "HandleVulnerabilityReport> Cannot get repo client %s : %v", nr.VCSServer, erra)
 		}
 
 		b, errB := repositoriesmanager.DefaultBranch(ctx, client, nr.VCSRepository)
 		if errB != nil {
-			return sdk.WrapError(errB, "HandleVulnerabilityReport> Unable to get default branch")
+			return nil, sdk.WrapError(errB, "HandleVulnerabilityReport> Unable to get default branch")
 		}
 		defaultBranch = b.DisplayID
 	}
@@ -38,12 +38,19 @@ func HandleVulnerabilityReport(ctx context.Context, db gorp.SqlExecutor, cache c
 	// Get report on the current node run if exist
 	currentNodeRunReport, err := loadVulnerabilityReport(db, nr.ID)
 	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
-		return sdk.WrapError(err, "Unable to load vulnerability report")
+		return nil, sdk.WrapError(err, "Unable to load vulnerability report")
+	}
+
+	// Evaluate the policy before the vulnerabilities of the application are updated from the default branch
+	vulns := append(append([]sdk.Vulnerability{}, currentNodeRunReport.Report.Vulnerabilities...), workerReport.Vulnerabilities...)
+	evaluation, errE := evaluateVulnerabilityPolicy(ctx, db, proj, nr, vulns, defaultBranch)
+	if errE != nil {
+		return nil, sdk.WrapError(errE, "Unable to evaluate vulnerability policy")
 	}
 
 	if err != nil && sdk.ErrorIs(err, sdk.ErrNotFound) {
 		if err := createNewVulnerabilityReport(db, cache, proj, nr, workerReport, defaultBranch); err != nil {
-			return sdk.WrapError(err, "Unable to create no vulnerability report")
+			return nil, sdk.WrapError(err, "Unable to create no vulnerability report")
 		}
 	}
 
@@ -64,14 +71,14 @@ func HandleVulnerabilityReport(ctx context.Context, db gorp.SqlExecutor, cache c
 	// Update report
 	dbReport := dbNodeRunVulenrabilitiesReport(currentNodeRunReport)
 	if err := dbReport.PostInsert(db); err != nil {
-		return sdk.WrapError(err, "Unable to insert report")
+		return nil, sdk.WrapError(err, "Unable to insert report")
 	}
 
 	// If we are on default branch, save report on application
 	if defaultBranch != "" && defaultBranch == nr.VCSBranch {
 		// Save vulnerabilities
 		if err := application.InsertVulnerabilities(db, currentNodeRunReport.Report.Vulnerabilities, nr.ApplicationID, workerReport.Type); err != nil {
-			return sdk.WrapError(err, "Unable to insert vulnerability")
+			return nil, sdk.WrapError(err, "Unable to insert vulnerability")
 		}
 
 		// push metrics
@@ -84,7 +91,32 @@ func HandleVulnerabilityReport(ctx context.Context, db gorp.SqlExecutor, cache c
 		}
 	}
 
-	return nil
+	return evaluation, nil
+}
+
+// evaluateVulnerabilityPolicy evaluates the vulnerability policy of the application of the node run and stores the
+// result on the node run. Vulnerabilities are new if they are not in the vulnerabilities of the application, that
+// come from its default branch.
+func evaluateVulnerabilityPolicy(ctx context.Context, db gorp.SqlExecutor, proj *sdk.Project, nr *sdk.WorkflowNodeRun, vulns []sdk.Vulnerability, defaultBranch string) (*sdk.VulnerabilityPolicyEvaluation, error) {
+	policy, err := application.LoadEffectiveVulnerabilityPolicy(ctx, db, proj.ID, nr.ApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil || (policy.OnlyDefaultBranch && (defaultBranch == "" || defaultBranch != nr.VCSBranch)) {
+		return nil, nil
+	}
+
+	appVulns, err := application.LoadVulnerabilities(db, nr.ApplicationID)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil, err
+	}
+
+	e := policy.Evaluate(vulns, appVulns)
+	nr.VulnerabilityPolicy = &e
+	if err := UpdateNodeRunVulnerabilityPolicy(db, nr.ID, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
 }
 
 func createNewVulnerabilityReport(db gorp.SqlExecutor, cache cache.Store, proj *sdk.Project, nr *sdk.WorkflowNodeRun, workerReport sdk.VulnerabilityWorkerReport, defaultBranch string) error {
@@ -148,17 +180,17 @@ func syncVunerabilitiesWithApplication(db gorp.SqlExecutor, nodeRunReport sdk.Wo
 	// create map
 	m := make(map[string]sdk.Vulnerability, len(nodeRunReport.Report.Vulnerabilities))
 	for _, v := range nodeRunReport.Report.Vulnerabilities {
-		m[fmt.Sprintf("%s-%s-%s", v.Component, v.Version, v.CVE)] = v
+		m[v.Key()] = v
 	}
 
 	for _, v := range appVuln {
 		if v.Ignored {
-			mVuln, ok := m[fmt.Sprintf("%s-%s-%s", v.Component, v.Version, v.CVE)]
+			mVuln, ok := m[v.Key()]
 			if !ok {
 				continue
 			}
 			mVuln.Ignored = true
-			m[fmt.Sprintf("%s-%s-%s", v.Component, v.Version, v.CVE)] = mVuln
+			m[v.Key()] = mVuln
 		}
 	}
 
@@ -969,6 +969,7 @@ func stopRunsBlocked(db *gorp.DbMap) error {
 		FROM workflow_run
 		WHERE (workflow_run.status = $1 or workflow_run.status = $2 or workflow_run.status = $3)
 		AND now() - workflow_run.last_execution > interval '1 day'
+		AND workflow_run.id NOT IN (SELECT workflow_run_id FROM workflow_node_run_freeze)
 		LIMIT 30`
 	ids := []struct {
 		ID int64 `db:"id"`
@@ -1817,3 +1817,46 @@ vcs_ssh_key: proj-blabla
 	}
 
 }
+
+func TestInsertWorkflowWithAuthenticatedWebHookWithoutSecret(t *testing.T) {
+	db, cache, end := test.SetupPG(t)
+	defer end()
+	test.NoError(t, workflow.CreateBuiltinWorkflowHookModels(db))
+	webHookModel, err := workflow.LoadHookModelByName(db, sdk.WebHookModelName)
+	test.NoError(t, err)
+
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, cache, proj, &pip, u))
+	proj, _ = project.LoadByID(db, cache, proj.ID, u, project.LoadOptions.WithPipelines, project.LoadOptions.WithGroups)
+
+	for _, auth := range []string{sdk.WebHookAuthHMAC, sdk.WebHookAuthBearer} {
+		w := sdk.Workflow{
+			Name:       "test_" + auth,
+			ProjectID:  proj.ID,
+			ProjectKey: proj.Key,
+			WorkflowData: &sdk.WorkflowData{
+				Node: sdk.Node{
+					Type:    sdk.NodeTypePipeline,
+					Context: &sdk.NodeContext{PipelineID: pip.ID},
+					Hooks: []sdk.NodeHook{{
+						HookModelID: webHookModel.ID,
+						Config: sdk.WorkflowNodeHookConfig{
+							sdk.WebHookModelConfigAuth:  {Value: auth, Configurable: true},
+							sdk.HookConfigWebHookSecret: {Value: "", Configurable: true, Type: sdk.HookConfigTypePassword},
+						},
+					}},
+				},
+			},
+		}
+		err := workflow.Insert(db, cache, &w, proj, u)
+		assert.True(t, sdk.ErrorIs(err, sdk.ErrWrongRequest), "a %s webhook without secret must be rejected: %v", auth, err)
+	}
+}
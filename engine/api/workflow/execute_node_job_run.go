@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/go-gorp/gorp"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
@@ -308,6 +310,8 @@ func TakeNodeJobRun(ctx context.Context, dbFunc func() *gorp.DbMap, db gorp.SqlE
 		return nil, nil, sdk.WrapError(err, "Cannot update node job run %d", jobID)
 	}
 
+	recordNodeJobRunTaken(store)
+
 	return job, report, nil
 }
 
@@ -558,6 +562,8 @@ func BookNodeJobRun(store cache.Store, id int64, hatchery *sdk.Service) (*sdk.Se
 		return nil, nil
 	}
 	if h.ID == hatchery.ID {
+		// the job may have been booked by the scheduler, extend the booking for spawning
+		store.SetWithTTL(k, hatchery, 120)
 		return nil, nil
 	}
 	return &h, sdk.WrapError(sdk.ErrJobAlreadyBooked, "BookNodeJobRun> job %d already booked by %s (%d)", id, h.Name, h.ID)
@@ -575,8 +581,9 @@ func FreeNodeJobRun(store cache.Store, id int64) error {
 	return sdk.WrapError(sdk.ErrJobNotBooked, "BookNodeJobRun> job %d already released", id)
 }
 
-//AddLog adds a build log
-func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, maxLogSize int64) error {
+//AddLog adds a build log. If a storage is given, the log in database is moved to
+//the storage when it reaches the max size instead of being truncated.
+func AddLog(db gorp.SqlExecutor, store objectstore.Driver, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, maxLogSize int64) error {
 	if job != nil {
 		logs.PipelineBuildJobID = job.ID
 		logs.PipelineBuildID = job.WorkflowNodeRunID
@@ -588,13 +595,44 @@ func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, max
 		return sdk.WrapError(err, "cannot check if log exists")
 	}
 
+	if maxLogSize == 0 {
+		maxLogSize = DefaultMaxLogSize
+	}
+	if exists && store != nil && size+int64(len(logs.Val)) > maxLogSize {
+		existingLogs, err := LoadStepLogs(db, logs.PipelineBuildJobID, logs.StepOrder)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load existing logs")
+		}
+		if err := storeStepLogPart(db, store, existingLogs, false); err != nil {
+			return err
+		}
+		size = 0
+	}
+
 	// ignore the log if max size already reached
 	if maxReached := truncateLogs(maxLogSize, size, logs); maxReached {
 		return nil
 	}
 
+	// The time and the markers of the lines are stored beside the value of the log, only the lines
+	// of the chunk are appended to the stored ones
+	lines, err := loadStepLogLinesState(db, logs.PipelineBuildJobID, logs.StepOrder)
+	if err != nil {
+		return err
+	}
+	received := time.Now()
+	if logs.LastModified != nil {
+		if t, err := ptypes.Timestamp(logs.LastModified); err == nil {
+			received = t
+		}
+	}
+	lines.Append(received, logs.Val)
+
 	if !exists {
-		return sdk.WrapError(insertLog(db, logs), "cannot insert log")
+		if err := insertLog(db, logs); err != nil {
+			return sdk.WrapError(err, "cannot insert log")
+		}
+		return appendStepLogLines(db, logs.PipelineBuildJobID, logs.StepOrder, lines)
 	}
 
 	existingLogs, err := LoadStepLogs(db, logs.PipelineBuildJobID, logs.StepOrder)
@@ -608,7 +646,10 @@ func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, max
 	existingLogs.LastModified = logs.LastModified
 	existingLogs.Done = logs.Done
 
-	return sdk.WrapError(updateLog(db, existingLogs), "cannot update log")
+	if err := updateLog(db, existingLogs); err != nil {
+		return sdk.WrapError(err, "cannot update log")
+	}
+	return appendStepLogLines(db, logs.PipelineBuildJobID, logs.StepOrder, lines)
 }
 
 //AddServiceLog adds a service log
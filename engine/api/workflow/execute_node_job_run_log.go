@@ -7,6 +7,7 @@ import (
 	"github.com/go-gorp/gorp"
 	"github.com/golang/protobuf/ptypes"
 
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -59,6 +60,65 @@ func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error)
 	return logs, nil
 }
 
+// LoadStepLogLines loads the structured data of the lines of a step log.
+func LoadStepLogLines(db gorp.SqlExecutor, id int64, order int64) (sdk.LogLines, error) {
+	var lines sdk.LogLines
+	var s sql.NullString
+	query := `
+		SELECT lines
+		FROM workflow_node_run_job_logs
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2`
+	if err := db.QueryRow(query, id, order).Scan(&s); err != nil {
+		if err == sql.ErrNoRows {
+			return lines, nil
+		}
+		return lines, sdk.WrapError(err, "unable to load lines of log of step %d of job %d", order, id)
+	}
+	if err := gorpmapping.JSONNullString(s, &lines); err != nil {
+		return lines, sdk.WrapError(err, "unable to read lines of log of step %d of job %d", order, id)
+	}
+	return lines, nil
+}
+
+// loadStepLogLinesState loads the structured data of a step log without its lines, as needed to parse its next chunk.
+func loadStepLogLinesState(db gorp.SqlExecutor, id int64, order int64) (sdk.LogLines, error) {
+	var lines sdk.LogLines
+	var s sql.NullString
+	query := `
+		SELECT lines - 'lines'
+		FROM workflow_node_run_job_logs
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2`
+	if err := db.QueryRow(query, id, order).Scan(&s); err != nil {
+		if err == sql.ErrNoRows {
+			return lines, nil
+		}
+		return lines, sdk.WrapError(err, "unable to load lines of log of step %d of job %d", order, id)
+	}
+	if err := gorpmapping.JSONNullString(s, &lines); err != nil {
+		return lines, sdk.WrapError(err, "unable to read lines of log of step %d of job %d", order, id)
+	}
+	return lines, nil
+}
+
+// appendStepLogLines updates the structured data of a step log, the given lines are appended to the stored ones.
+func appendStepLogLines(db gorp.SqlExecutor, id int64, order int64, lines sdk.LogLines) error {
+	if lines.Lines == nil {
+		lines.Lines = []sdk.LogLine{}
+	}
+	s, err := gorpmapping.JSONToNullString(lines)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	query := `
+		UPDATE workflow_node_run_job_logs
+		SET lines = $1::jsonb || jsonb_build_object('lines', COALESCE(lines->'lines', '[]'::jsonb) || ($1::jsonb->'lines'))
+		WHERE workflow_node_run_job_id = $2 AND step_order = $3`
+	if _, err := db.Exec(query, s, id, order); err != nil {
+		return sdk.WrapError(err, "unable to update lines of log of step %d of job %d", order, id)
+	}
+	return nil
+}
+
 //LoadLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job)
 func LoadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
 	query := `
@@ -128,6 +188,7 @@ func insertLog(db gorp.SqlExecutor, logs *sdk.Log) error {
 	return db.QueryRow(query, logs.PipelineBuildJobID, logs.PipelineBuildID, s, m, d, logs.StepOrder, logs.Val).Scan(&logs.Id)
 }
 
+// updateLog updates a log, its new value will be archived again.
 func updateLog(db gorp.SqlExecutor, logs *sdk.Log) error {
 	if logs.Start == nil {
 		logs.Start, _ = ptypes.TimestampProto(time.Now())
@@ -147,7 +208,8 @@ func updateLog(db gorp.SqlExecutor, logs *sdk.Log) error {
 			last_modified = $4,
 			done = $5,
 			step_order = $6,
-			value = $7
+			value = $7,
+			archived = false
 		where id = $8`
 
 	s, errs := ptypes.Timestamp(logs.Start)
@@ -0,0 +1,318 @@
+package workflow
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// StepLogParts are the sizes of the parts of a step log moved to the object storage.
+type StepLogParts struct {
+	NodeRunID int64
+	JobRunID  int64
+	StepOrder int64
+	Sizes     []int64
+}
+
+// Size returns the size of the log moved to the object storage.
+func (p StepLogParts) Size() int64 {
+	var size int64
+	for _, s := range p.Sizes {
+		size += s
+	}
+	return size
+}
+
+func (p StepLogParts) part(i int) sdk.StepLogPart {
+	return sdk.StepLogPart{NodeRunID: p.NodeRunID, JobRunID: p.JobRunID, StepOrder: p.StepOrder, Index: i}
+}
+
+// LoadStepLogParts loads the parts of a step log moved to the object storage.
+func LoadStepLogParts(db gorp.SqlExecutor, id int64, order int64) (StepLogParts, error) {
+	p := StepLogParts{JobRunID: id, StepOrder: order}
+	var sizes pq.Int64Array
+	query := `
+		SELECT workflow_node_run_id, parts
+		FROM workflow_node_run_job_logs
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2`
+	if err := db.QueryRow(query, id, order).Scan(&p.NodeRunID, &sizes); err != nil {
+		if err == sql.ErrNoRows {
+			return p, nil
+		}
+		return p, sdk.WrapError(err, "unable to load parts of log of step %d of job %d", order, id)
+	}
+	p.Sizes = sizes
+	return p, nil
+}
+
+// storeStepLogPart compresses a part of a step log in the object storage, then saves its size in the database.
+func storeStepLogPart(db gorp.SqlExecutor, store objectstore.Driver, logs *sdk.Log, archived bool) error {
+	p, err := LoadStepLogParts(db, logs.PipelineBuildJobID, logs.StepOrder)
+	if err != nil {
+		return err
+	}
+
+	if logs.Val != "" {
+		pr, pw := io.Pipe()
+		go func() {
+			gw := gzip.NewWriter(pw)
+			if _, err := io.WriteString(gw, logs.Val); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			pw.CloseWithError(gw.Close())
+		}()
+		if _, err := store.Store(p.part(len(p.Sizes)), pr); err != nil {
+			pr.CloseWithError(err) // nolint
+			return sdk.WrapError(err, "unable to store part %d of log of step %d of job %d", len(p.Sizes), logs.StepOrder, logs.PipelineBuildJobID)
+		}
+		p.Sizes = append(p.Sizes, int64(len(logs.Val)))
+	}
+
+	query := `
+		UPDATE workflow_node_run_job_logs SET value = '', parts = $1, archived = $2
+		WHERE workflow_node_run_job_id = $3 AND step_order = $4`
+	if _, err := db.Exec(query, pq.Int64Array(p.Sizes), archived, logs.PipelineBuildJobID, logs.StepOrder); err != nil {
+		return sdk.WrapError(err, "unable to update parts of log of step %d of job %d", logs.StepOrder, logs.PipelineBuildJobID)
+	}
+	logs.Val = ""
+	return nil
+}
+
+// ArchiveStepLogs moves periodically the logs of terminated node runs to the object storage.
+func ArchiveStepLogs(c context.Context, DBFunc func() *gorp.DbMap, store objectstore.Driver) {
+	tick := time.NewTicker(time.Minute)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting workflow.ArchiveStepLogs: %v", c.Err())
+				return
+			}
+		case <-tick.C:
+			// all the logs to archive are handled by batches before waiting for the next tick
+			for {
+				n, err := archiveStepLogs(DBFunc(), store, 1000)
+				if err != nil {
+					log.Warning("workflow.ArchiveStepLogs> %v", err)
+					break
+				}
+				if n < 1000 || c.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// archiveStepLogs archives the oldest logs of terminated node runs, it returns the number of archived logs.
+func archiveStepLogs(db *gorp.DbMap, store objectstore.Driver, limit int) (int, error) {
+	var ids []int64
+	query := `
+		SELECT workflow_node_run_job_logs.id
+		FROM workflow_node_run_job_logs
+		JOIN workflow_node_run ON workflow_node_run.id = workflow_node_run_job_logs.workflow_node_run_id
+		WHERE workflow_node_run_job_logs.archived = false AND workflow_node_run.status NOT IN ($1, $2)
+		ORDER BY workflow_node_run_job_logs.id
+		LIMIT $3`
+	if _, err := db.Select(&ids, query, sdk.StatusWaiting.String(), sdk.StatusBuilding.String(), limit); err != nil {
+		return 0, sdk.WrapError(err, "unable to load logs to archive")
+	}
+
+	var n int
+	for _, id := range ids {
+		if err := archiveStepLog(db, store, id); err != nil {
+			log.Error("workflow.archiveStepLog> unable to archive log %d: %v", id, err)
+			continue
+		}
+		n++
+	}
+	return n, nil
+}
+
+func archiveStepLog(db *gorp.DbMap, store objectstore.Driver, id int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	logs := &sdk.Log{Id: id}
+	query := `
+		SELECT workflow_node_run_job_id, step_order, value
+		FROM workflow_node_run_job_logs
+		WHERE id = $1 AND archived = false
+		FOR UPDATE SKIP LOCKED`
+	if err := tx.QueryRow(query, id).Scan(&logs.PipelineBuildJobID, &logs.StepOrder, &logs.Val); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return sdk.WithStack(err)
+	}
+
+	if err := storeStepLogPart(tx, store, logs, true); err != nil {
+		return err
+	}
+	return sdk.WithStack(tx.Commit())
+}
+
+// DeleteStepLogParts deletes from the object storage the parts of the step logs of a workflow run.
+func DeleteStepLogParts(db gorp.SqlExecutor, store objectstore.Driver, workflowRunID int64) error {
+	query := `
+		SELECT workflow_node_run_job_logs.workflow_node_run_id, workflow_node_run_job_logs.workflow_node_run_job_id,
+			workflow_node_run_job_logs.step_order, workflow_node_run_job_logs.parts
+		FROM workflow_node_run_job_logs
+		JOIN workflow_node_run ON workflow_node_run.id = workflow_node_run_job_logs.workflow_node_run_id
+		WHERE workflow_node_run.workflow_run_id = $1 AND workflow_node_run_job_logs.parts IS NOT NULL`
+	rows, err := db.Query(query, workflowRunID)
+	if err != nil {
+		return sdk.WrapError(err, "unable to load log parts of workflow run %d", workflowRunID)
+	}
+	defer rows.Close()
+
+	var logs []StepLogParts
+	for rows.Next() {
+		var p StepLogParts
+		var sizes pq.Int64Array
+		if err := rows.Scan(&p.NodeRunID, &p.JobRunID, &p.StepOrder, &sizes); err != nil {
+			return sdk.WithStack(err)
+		}
+		p.Sizes = sizes
+		logs = append(logs, p)
+	}
+	if err := rows.Err(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	for _, p := range logs {
+		for i := range p.Sizes {
+			if err := store.Delete(p.part(i)); err != nil {
+				log.Error("DeleteStepLogParts> unable to delete part %d of log of step %d of job %d: %v", i, p.StepOrder, p.JobRunID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadStepLogReader returns a reader on a step log from the given offset, and the size of the whole log.
+func LoadStepLogReader(db gorp.SqlExecutor, store objectstore.Driver, id int64, order int64, offset int64) (io.ReadCloser, int64, error) {
+	logs, err := LoadStepLogs(db, id, order)
+	if err != nil {
+		return nil, 0, sdk.WrapError(err, "cannot load log of step %d of job %d", order, id)
+	}
+	if logs == nil {
+		return ioutil.NopCloser(strings.NewReader("")), 0, nil
+	}
+	p, err := LoadStepLogParts(db, id, order)
+	if err != nil {
+		return nil, 0, err
+	}
+	return NewStepLogReader(store, p, logs.Val, offset), p.Size() + int64(len(logs.Val)), nil
+}
+
+// NewStepLogReader reads a step log from the given offset, first in its parts in the object storage then
+// in the value still in the database.
+func NewStepLogReader(store objectstore.Driver, p StepLogParts, value string, offset int64) io.ReadCloser {
+	r := &stepLogReader{store: store, logParts: p, value: value}
+	for r.next < len(p.Sizes) && offset >= p.Sizes[r.next] {
+		offset -= p.Sizes[r.next]
+		r.next++
+	}
+	if r.next == len(p.Sizes) {
+		if offset > int64(len(r.value)) {
+			offset = int64(len(r.value))
+		}
+		r.value = r.value[offset:]
+		offset = 0
+	}
+	r.skip = offset
+	return r
+}
+
+type stepLogReader struct {
+	store    objectstore.Driver
+	logParts StepLogParts
+	next     int
+	value    string
+	skip     int64
+	current  io.Reader
+	closers  []io.Closer
+}
+
+func (r *stepLogReader) open() error {
+	if r.next == len(r.logParts.Sizes) {
+		r.current = strings.NewReader(r.value)
+		r.value = ""
+		r.next++
+		return nil
+	}
+
+	f, err := r.store.Fetch(r.logParts.part(r.next))
+	if err != nil {
+		return sdk.WrapError(err, "unable to fetch part %d of log of step %d of job %d", r.next, r.logParts.StepOrder, r.logParts.JobRunID)
+	}
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return sdk.WrapError(err, "unable to read part %d of log of step %d of job %d", r.next, r.logParts.StepOrder, r.logParts.JobRunID)
+	}
+	r.closers = []io.Closer{gr, f}
+	r.current = gr
+	r.next++
+
+	if r.skip > 0 {
+		if _, err := io.CopyN(ioutil.Discard, gr, r.skip); err != nil {
+			return sdk.WithStack(err)
+		}
+		r.skip = 0
+	}
+	return nil
+}
+
+func (r *stepLogReader) Read(b []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.next > len(r.logParts.Sizes) {
+				return 0, io.EOF
+			}
+			if err := r.open(); err != nil {
+				return 0, err
+			}
+		}
+		n, err := r.current.Read(b)
+		if err == io.EOF {
+			r.closeCurrent()
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *stepLogReader) closeCurrent() {
+	for _, c := range r.closers {
+		c.Close() // nolint
+	}
+	r.closers = nil
+	r.current = nil
+}
+
+func (r *stepLogReader) Close() error {
+	r.closeCurrent()
+	return nil
+}
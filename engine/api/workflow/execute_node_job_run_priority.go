@@ -0,0 +1,230 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// an idle period longer than this delay between two taken jobs is not used to estimate the waiting time
+	queueStatsMaxInterval = 5 * time.Minute
+	// ttl in seconds of the queue statistics
+	queueStatsTTL = 3600
+	// ttl in seconds of the computed queue order, shared by all the queue loads
+	queueOrderTTL = 5
+)
+
+var (
+	keyQueueStats = cache.Key("queue", "stats")
+	keyQueueOrder = cache.Key("queue", "order")
+)
+
+// queueStats is used to estimate the waiting time of jobs in the queue
+type queueStats struct {
+	LastTake    time.Time `json:"last_take"`
+	AvgInterval float64   `json:"avg_interval"` // moving average of the seconds between two taken jobs
+}
+
+// queuedJob is a light representation of a waiting or building job used to compute the queue order
+type queuedJob struct {
+	ID         int64          `db:"id"`
+	ProjectID  int64          `db:"project_id"`
+	Priority   int            `db:"priority"`
+	Queued     time.Time      `db:"queued"`
+	Status     string         `db:"status"`
+	ExecGroups sql.NullString `db:"exec_groups"`
+	GroupIDs   []int64        `db:"-"`
+}
+
+// computeNodeJobRunPriority returns the priority of the jobs of a node run, it is
+// the one given for a manual run or else the default_priority metadata of the workflow
+func computeNodeJobRunPriority(wr *sdk.WorkflowRun, run *sdk.WorkflowNodeRun) int {
+	var priority int
+	if run.Manual != nil && run.Manual.Priority != nil {
+		priority = *run.Manual.Priority
+	} else if defaultPriority, ok := wr.Workflow.Metadata["default_priority"]; ok && defaultPriority != "" {
+		p, err := strconv.Atoi(defaultPriority)
+		if err != nil {
+			log.Warning("computeNodeJobRunPriority> invalid default_priority %s on workflow %s: %v", defaultPriority, wr.Workflow.Name, err)
+		}
+		priority = p
+	}
+
+	if priority < sdk.WorkflowNodeJobRunPriorityMin {
+		return sdk.WorkflowNodeJobRunPriorityMin
+	}
+	if priority > sdk.WorkflowNodeJobRunPriorityMax {
+		return sdk.WorkflowNodeJobRunPriorityMax
+	}
+	return priority
+}
+
+// recordNodeJobRunTaken updates the statistics used to estimate the waiting time of queued jobs
+func recordNodeJobRunTaken(store cache.Store) {
+	var stats queueStats
+	store.Get(keyQueueStats, &stats)
+
+	now := time.Now()
+	if !stats.LastTake.IsZero() {
+		interval := now.Sub(stats.LastTake)
+		if interval > queueStatsMaxInterval {
+			interval = queueStatsMaxInterval
+		}
+		if stats.AvgInterval == 0 {
+			stats.AvgInterval = interval.Seconds()
+		} else {
+			stats.AvgInterval = 0.9*stats.AvgInterval + 0.1*interval.Seconds()
+		}
+	}
+	stats.LastTake = now
+	store.SetWithTTL(keyQueueStats, stats, queueStatsTTL)
+}
+
+// sortNodeJobRunQueue sorts the given jobs according to their position in the whole queue,
+// and sets their position and estimated waiting time. Jobs that are not waiting are kept at the end.
+func sortNodeJobRunQueue(ctx context.Context, db gorp.SqlExecutor, store cache.Store, jobs []sdk.WorkflowNodeJobRun) error {
+	_, end := observability.Span(ctx, "workflow.sortNodeJobRunQueue")
+	defer end()
+
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	positions, err := loadQueueOrder(db, store)
+	if err != nil {
+		return err
+	}
+
+	var stats queueStats
+	store.Get(keyQueueStats, &stats)
+
+	for i := range jobs {
+		if jobs[i].Status != sdk.StatusWaiting.String() {
+			continue
+		}
+		jobs[i].QueuePosition = positions[jobs[i].ID]
+		jobs[i].EstimatedWaitSeconds = int64(float64(jobs[i].QueuePosition) * stats.AvgInterval)
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		pi, pj := jobs[i].QueuePosition, jobs[j].QueuePosition
+		if pi == 0 || pj == 0 {
+			return pj == 0 && pi != 0
+		}
+		return pi < pj
+	})
+	return nil
+}
+
+// loadQueueOrder returns the position of each waiting job in the whole queue. The order is computed
+// from the waiting and building jobs, then kept in cache for a few seconds to be shared by all the queue loads.
+func loadQueueOrder(db gorp.SqlExecutor, store cache.Store) (map[int64]int, error) {
+	var positions map[int64]int
+	if store.Get(keyQueueOrder, &positions) {
+		return positions, nil
+	}
+
+	var queued []queuedJob
+	if _, err := db.Select(&queued, `
+	SELECT id, project_id, priority, queued, status, exec_groups
+	FROM workflow_node_run_job
+	WHERE status IN ($1, $2)`, sdk.StatusWaiting.String(), sdk.StatusBuilding.String()); err != nil {
+		return nil, sdk.WrapError(err, "unable to load queued jobs")
+	}
+
+	var waiting, building []queuedJob
+	for i := range queued {
+		var groups []sdk.Group
+		if err := gorpmapping.JSONNullString(queued[i].ExecGroups, &groups); err != nil {
+			return nil, sdk.WrapError(err, "unable to read the executable groups of job %d", queued[i].ID)
+		}
+		for _, g := range groups {
+			if g.Name != sdk.SharedInfraGroupName {
+				queued[i].GroupIDs = append(queued[i].GroupIDs, g.ID)
+			}
+		}
+		if queued[i].Status == sdk.StatusWaiting.String() {
+			waiting = append(waiting, queued[i])
+		} else {
+			building = append(building, queued[i])
+		}
+	}
+
+	positions = make(map[int64]int, len(waiting))
+	for i, id := range fairShareOrder(waiting, building) {
+		positions[id] = i + 1
+	}
+	store.SetWithTTL(keyQueueOrder, positions, queueOrderTTL)
+	return positions, nil
+}
+
+// fairShareKeys returns the keys of the shares a job counts in: its project and each group
+// allowed to run it, so that a group can't take the whole queue by spreading its jobs over several projects.
+func fairShareKeys(j queuedJob) []string {
+	keys := make([]string, 0, len(j.GroupIDs)+1)
+	keys = append(keys, "project:"+strconv.FormatInt(j.ProjectID, 10))
+	for _, id := range j.GroupIDs {
+		keys = append(keys, "group:"+strconv.FormatInt(id, 10))
+	}
+	return keys
+}
+
+// fairShareOrder returns the ids of the given waiting jobs in the order they should be taken.
+// Jobs with the highest priority come first. For a same priority, the queue is shared between
+// projects and groups: the share of a job is the number of jobs, running or queued before it,
+// of its most loaded project or group, and the jobs with the smallest share come first.
+// Jobs of a same project keep their arrival order.
+func fairShareOrder(waiting []queuedJob, building []queuedJob) []int64 {
+	sorted := make([]queuedJob, len(waiting))
+	copy(sorted, waiting)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].Queued.Before(sorted[j].Queued)
+	})
+
+	count := make(map[string]int)
+	for _, j := range building {
+		for _, k := range fairShareKeys(j) {
+			count[k]++
+		}
+	}
+
+	shares := make(map[int64]int, len(sorted))
+	for _, j := range sorted {
+		keys := fairShareKeys(j)
+		for _, k := range keys {
+			if count[k] > shares[j.ID] {
+				shares[j.ID] = count[k]
+			}
+		}
+		for _, k := range keys {
+			count[k]++
+		}
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return shares[sorted[i].ID] < shares[sorted[j].ID]
+	})
+
+	ids := make([]int64, len(sorted))
+	for i := range sorted {
+		ids[i] = sorted[i].ID
+	}
+	return ids
+}
@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_fairShareOrder(t *testing.T) {
+	now := time.Now()
+	jobs := []queuedJob{
+		{ID: 1, ProjectID: 1, Queued: now},
+		{ID: 2, ProjectID: 1, Queued: now.Add(1 * time.Second)},
+		{ID: 3, ProjectID: 1, Queued: now.Add(2 * time.Second)},
+		{ID: 4, ProjectID: 2, Queued: now.Add(3 * time.Second)},
+		{ID: 5, ProjectID: 2, Queued: now.Add(4 * time.Second)},
+		{ID: 6, ProjectID: 3, Queued: now.Add(5 * time.Second)},
+	}
+
+	assert.Equal(t, []int64{1, 4, 6, 2, 5, 3}, fairShareOrder(jobs, nil))
+
+	// project 1 already has two running jobs
+	assert.Equal(t, []int64{4, 6, 5, 1, 2, 3}, fairShareOrder(jobs, []queuedJob{{ID: 7, ProjectID: 1}, {ID: 8, ProjectID: 1}}))
+
+	// priority takes precedence over fair share, the prioritized job still counts in the share of its project
+	jobs[2].Priority = 1
+	assert.Equal(t, []int64{3, 4, 6, 1, 5, 2}, fairShareOrder(jobs, nil))
+
+	// the projects 1 and 2 belong to the same group, which shares the queue with the group of the project 3
+	jobs = []queuedJob{
+		{ID: 1, ProjectID: 1, GroupIDs: []int64{10}, Queued: now},
+		{ID: 2, ProjectID: 2, GroupIDs: []int64{10}, Queued: now.Add(1 * time.Second)},
+		{ID: 3, ProjectID: 1, GroupIDs: []int64{10}, Queued: now.Add(2 * time.Second)},
+		{ID: 4, ProjectID: 3, GroupIDs: []int64{20}, Queued: now.Add(3 * time.Second)},
+		{ID: 5, ProjectID: 3, GroupIDs: []int64{20}, Queued: now.Add(4 * time.Second)},
+	}
+	assert.Equal(t, []int64{1, 4, 2, 5, 3}, fairShareOrder(jobs, nil))
+
+	// a job running for the group 10 in another project counts in the share of the group
+	assert.Equal(t, []int64{4, 1, 5, 2, 3}, fairShareOrder(jobs, []queuedJob{{ID: 6, ProjectID: 4, GroupIDs: []int64{10}}}))
+}
+
+func Test_sortNodeJobRunQueue(t *testing.T) {
+	store := newSchedulerStore()
+	store.SetWithTTL(keyQueueStats, queueStats{AvgInterval: 10}, queueStatsTTL)
+
+	// the order computed by a previous load is used without querying the database
+	store.SetWithTTL(keyQueueOrder, map[int64]int{1: 3, 2: 1, 3: 2}, queueOrderTTL)
+	jobs := []sdk.WorkflowNodeJobRun{
+		{ID: 4, Status: sdk.StatusBuilding.String()},
+		{ID: 1, Status: sdk.StatusWaiting.String()},
+		{ID: 2, Status: sdk.StatusWaiting.String()},
+	}
+	require.NoError(t, sortNodeJobRunQueue(context.TODO(), nil, store, jobs))
+	assert.Equal(t, int64(2), jobs[0].ID)
+	assert.Equal(t, 1, jobs[0].QueuePosition)
+	assert.Equal(t, int64(10), jobs[0].EstimatedWaitSeconds)
+	assert.Equal(t, int64(1), jobs[1].ID)
+	assert.Equal(t, 3, jobs[1].QueuePosition)
+	assert.Equal(t, int64(4), jobs[2].ID)
+	assert.Equal(t, 0, jobs[2].QueuePosition)
+}
+
+func Test_computeNodeJobRunPriority(t *testing.T) {
+	wr := &sdk.WorkflowRun{Workflow: sdk.Workflow{Metadata: sdk.Metadata{"default_priority": "3"}}}
+	run := &sdk.WorkflowNodeRun{}
+	assert.Equal(t, 3, computeNodeJobRunPriority(wr, run))
+
+	p := -2
+	run.Manual = &sdk.WorkflowNodeRunManual{Priority: &p}
+	assert.Equal(t, -2, computeNodeJobRunPriority(wr, run))
+
+	p = 100
+	assert.Equal(t, sdk.WorkflowNodeJobRunPriorityMax, computeNodeJobRunPriority(wr, run))
+
+	wr.Workflow.Metadata["default_priority"] = "high"
+	assert.Equal(t, 0, computeNodeJobRunPriority(wr, &sdk.WorkflowNodeRun{}))
+}
@@ -0,0 +1,224 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// a declared capacity is ignored if the hatchery didn't refresh it since this delay
+	hatcheryCapacityExpiration = 2 * time.Minute
+	// ttl in seconds of a booking made by the scheduler
+	scheduledBookingTTL = 30
+	// ttl in seconds of the list of hatcheries that released a job
+	releasedBookingTTL = 600
+	// a schedule of the queue taking longer than this delay doesn't prevent another one
+	scheduleQueueLockExpiration = 30 * time.Second
+)
+
+var (
+	hatcheryCapacityRootKey = cache.Key("hatchery", "capacity")
+	keyScheduleQueueLock    = cache.Key("queue", "schedule", "lock")
+)
+
+func keyReleasedBookJob(id int64) string {
+	return cache.Key("book", "job", "released", strconv.FormatInt(id, 10))
+}
+
+// UpsertHatcheryCapacity stores the capacity declared by a hatchery
+func UpsertHatcheryCapacity(store cache.Store, c sdk.HatcheryCapacity) {
+	c.LastUpdate = time.Now()
+	store.SetAdd(hatcheryCapacityRootKey, strconv.FormatInt(c.HatcheryID, 10), c)
+}
+
+// LoadHatcheryCapacities returns all capacities declared by hatcheries, expired ones are removed
+func LoadHatcheryCapacities(store cache.Store) ([]sdk.HatcheryCapacity, error) {
+	nb := store.SetCard(hatcheryCapacityRootKey)
+	capacities := make([]*sdk.HatcheryCapacity, nb)
+	for i := 0; i < nb; i++ {
+		capacities[i] = &sdk.HatcheryCapacity{}
+	}
+	if err := store.SetScan(hatcheryCapacityRootKey, sdk.InterfaceSlice(capacities)...); err != nil {
+		return nil, sdk.WrapError(err, "unable to load hatchery capacities")
+	}
+
+	res := make([]sdk.HatcheryCapacity, 0, nb)
+	for _, c := range capacities {
+		if c.HatcheryID == 0 {
+			continue
+		}
+		if time.Since(c.LastUpdate) > hatcheryCapacityExpiration {
+			store.SetRemove(hatcheryCapacityRootKey, strconv.FormatInt(c.HatcheryID, 10), c)
+			continue
+		}
+		res = append(res, *c)
+	}
+	return res, nil
+}
+
+// ReleaseNodeJobRun frees a job booked by a hatchery. The hatchery will not be chosen again by the scheduler for this job.
+func ReleaseNodeJobRun(store cache.Store, id int64, hatchery *sdk.Service) error {
+	if hatchery != nil {
+		k := keyReleasedBookJob(id)
+		if !store.Lock(cache.Key(k, "lock"), 5*time.Second, 100, 50) {
+			return sdk.WithStack(fmt.Errorf("unable to lock the hatcheries that released the job %d", id))
+		}
+		var released []int64
+		store.Get(k, &released)
+		released = append(released, hatchery.ID)
+		store.SetWithTTL(k, released, releasedBookingTTL)
+		store.Unlock(cache.Key(k, "lock"))
+	}
+	return FreeNodeJobRun(store, id)
+}
+
+// ScheduleQueue assigns the waiting jobs of the whole queue to the hatcheries that declared their capacity.
+// An event is published for each assigned job, so the chosen hatchery can start it without polling the queue.
+// Only one API instance schedules the queue at a time.
+func ScheduleQueue(ctx context.Context, db gorp.SqlExecutor, store cache.Store) error {
+	if store.SetCard(hatcheryCapacityRootKey) == 0 {
+		return nil
+	}
+
+	if !store.Lock(keyScheduleQueueLock, scheduleQueueLockExpiration, -1, 1) {
+		return nil
+	}
+	defer store.Unlock(keyScheduleQueueLock)
+
+	jobs, err := LoadNodeJobRunQueue(ctx, db, store, NewQueueFilter())
+	if err != nil {
+		return sdk.WrapError(err, "unable to load queue")
+	}
+
+	assigned, err := ScheduleNodeJobRuns(store, jobs)
+	if err != nil {
+		return err
+	}
+
+	for _, j := range assigned {
+		projectKey, workflowName, err := loadNodeJobRunWorkflowName(db, j.ID)
+		if err != nil {
+			log.Warning("ScheduleQueue> unable to publish the assignment of job %d: %v", j.ID, err)
+			continue
+		}
+		event.PublishWorkflowNodeJobRun(db, projectKey, workflowName, j)
+	}
+	return nil
+}
+
+// ScheduleNodeJobRuns assigns the given jobs to the hatcheries that declared their capacity.
+// For each job not already booked, the best hatchery able to run it is chosen according to
+// its priority, its cost weight and its free capacity. The job is then booked for this hatchery
+// for a short time so other hatcheries will skip it. The newly assigned jobs are returned.
+func ScheduleNodeJobRuns(store cache.Store, jobs []sdk.WorkflowNodeJobRun) ([]sdk.WorkflowNodeJobRun, error) {
+	capacities, err := LoadHatcheryCapacities(store)
+	if err != nil {
+		return nil, err
+	}
+	if len(capacities) == 0 {
+		return nil, nil
+	}
+
+	// Jobs already booked consume the capacity of their hatchery
+	for i := range jobs {
+		if jobs[i].BookedBy.ID == 0 {
+			continue
+		}
+		for j := range capacities {
+			if capacities[j].HatcheryID == jobs[i].BookedBy.ID {
+				capacities[j].Booked++
+				break
+			}
+		}
+	}
+
+	// Keep the queue order, first jobs are scheduled first
+	var assigned []sdk.WorkflowNodeJobRun
+	for i := range jobs {
+		j := &jobs[i]
+		if j.BookedBy.ID != 0 {
+			continue
+		}
+
+		var released []int64
+		store.Get(keyReleasedBookJob(j.ID), &released)
+
+		best := -1
+		for k := range capacities {
+			c := capacities[k]
+			if c.Free() <= 0 || !c.CanRun(*j) || sdk.IsInInt64Array(c.HatcheryID, released) {
+				continue
+			}
+			if best == -1 || c.IsBetterThan(capacities[best]) {
+				best = k
+			}
+		}
+		if best == -1 {
+			continue
+		}
+
+		srv := &sdk.Service{ID: capacities[best].HatcheryID, Name: capacities[best].HatcheryName, Type: services.TypeHatchery}
+		if !bookScheduledNodeJobRun(store, j.ID, srv) {
+			// booked in the meantime by someone else
+			getBookedBy(store, j)
+			continue
+		}
+		log.Debug("ScheduleNodeJobRuns> job %d assigned to hatchery %s", j.ID, srv.Name)
+		capacities[best].Booked++
+		j.BookedBy = *srv
+		assigned = append(assigned, *j)
+	}
+
+	return assigned, nil
+}
+
+// loadNodeJobRunWorkflowName returns the project key and the workflow name of a job, used to publish its events
+func loadNodeJobRunWorkflowName(db gorp.SqlExecutor, id int64) (string, string, error) {
+	var res struct {
+		ProjectKey   string `db:"project_key"`
+		WorkflowName string `db:"workflow_name"`
+	}
+	if err := db.SelectOne(&res, `
+	SELECT project.projectkey AS project_key, workflow.name AS workflow_name
+	FROM workflow_node_run_job
+	JOIN workflow_node_run ON workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+	JOIN workflow_run ON workflow_run.id = workflow_node_run.workflow_run_id
+	JOIN workflow ON workflow.id = workflow_run.workflow_id
+	JOIN project ON project.id = workflow.project_id
+	WHERE workflow_node_run_job.id = $1`, id); err != nil {
+		return "", "", sdk.WrapError(err, "unable to load the workflow of job %d", id)
+	}
+	return res.ProjectKey, res.WorkflowName, nil
+}
+
+func bookScheduledNodeJobRun(store cache.Store, id int64, hatchery *sdk.Service) bool {
+	k := keyBookJob(id)
+	if !store.Lock(cache.Key(k, "lock"), 5*time.Second, -1, -1) {
+		return false
+	}
+	defer store.Unlock(cache.Key(k, "lock"))
+
+	h := sdk.Service{}
+	if store.Get(k, &h) {
+		return false
+	}
+	store.SetWithTTL(k, hatchery, scheduledBookingTTL)
+	return true
+}
+
+func getBookedBy(store cache.Store, j *sdk.WorkflowNodeJobRun) {
+	h := sdk.Service{}
+	if store.Get(keyBookJob(j.ID), &h) {
+		j.BookedBy = h
+	}
+}
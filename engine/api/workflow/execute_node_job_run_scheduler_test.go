@@ -0,0 +1,142 @@
+package workflow
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// schedulerStore is an in memory store for the capacities and the bookings, without expiration
+type schedulerStore struct {
+	cache.Store
+	values map[string]string
+	sets   map[string]map[string]string
+}
+
+func newSchedulerStore() *schedulerStore {
+	return &schedulerStore{values: map[string]string{}, sets: map[string]map[string]string{}}
+}
+
+func (s *schedulerStore) Get(key string, value interface{}) bool {
+	v, has := s.values[key]
+	return has && json.Unmarshal([]byte(v), value) == nil
+}
+
+func (s *schedulerStore) SetWithTTL(key string, value interface{}, ttl int) {
+	b, _ := json.Marshal(value)
+	s.values[key] = string(b)
+}
+
+func (s *schedulerStore) Delete(key string) {
+	delete(s.values, key)
+}
+
+func (s *schedulerStore) Lock(key string, expiration time.Duration, retryWaitDurationMillisecond int, retryCount int) bool {
+	if _, has := s.values[key]; has {
+		return false
+	}
+	s.values[key] = "true"
+	return true
+}
+
+func (s *schedulerStore) Unlock(key string) {
+	delete(s.values, key)
+}
+
+func (s *schedulerStore) SetAdd(rootKey string, memberKey string, member interface{}) {
+	if s.sets[rootKey] == nil {
+		s.sets[rootKey] = map[string]string{}
+	}
+	b, _ := json.Marshal(member)
+	s.sets[rootKey][memberKey] = string(b)
+}
+
+func (s *schedulerStore) SetRemove(rootKey string, memberKey string, member interface{}) {
+	delete(s.sets[rootKey], memberKey)
+}
+
+func (s *schedulerStore) SetCard(key string) int {
+	return len(s.sets[key])
+}
+
+func (s *schedulerStore) SetScan(key string, members ...interface{}) error {
+	keys := make([]string, 0, len(s.sets[key]))
+	for k := range s.sets[key] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		if err := json.Unmarshal([]byte(s.sets[key][k]), members[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestScheduleNodeJobRuns(t *testing.T) {
+	store := newSchedulerStore()
+
+	group := []sdk.Group{{ID: 7}}
+	jobs := []sdk.WorkflowNodeJobRun{{ID: 10}, {ID: 11, ExecGroups: group}, {ID: 12, ExecGroups: group}, {ID: 13, ExecGroups: []sdk.Group{{ID: 5}}}}
+
+	// Without declared capacity, no job is assigned
+	res, err := ScheduleNodeJobRuns(store, jobs)
+	require.NoError(t, err)
+	assert.Empty(t, res)
+	for i := range jobs {
+		assert.Zero(t, jobs[i].BookedBy.ID)
+	}
+
+	UpsertHatcheryCapacity(store, sdk.HatcheryCapacity{HatcheryID: 1, HatcheryName: "docker-1", MaxWorker: 1, IsSharedInfra: true, CostWeight: 2})
+	UpsertHatcheryCapacity(store, sdk.HatcheryCapacity{HatcheryID: 2, HatcheryName: "docker-2", MaxWorker: 2, IsSharedInfra: true, CostWeight: 1})
+	UpsertHatcheryCapacity(store, sdk.HatcheryCapacity{HatcheryID: 3, HatcheryName: "group-5", MaxWorker: 1, GroupID: 5, Priority: 1})
+
+	// The job 10 is already booked by the first hatchery
+	store.SetWithTTL(keyBookJob(10), sdk.Service{ID: 1, Name: "docker-1"}, 120)
+	jobs[0].BookedBy = sdk.Service{ID: 1, Name: "docker-1"}
+
+	res, err = ScheduleNodeJobRuns(store, jobs)
+	require.NoError(t, err)
+	bookedBy := func(jobs []sdk.WorkflowNodeJobRun) []int64 {
+		ids := make([]int64, len(jobs))
+		for i := range jobs {
+			ids[i] = jobs[i].BookedBy.ID
+		}
+		return ids
+	}
+	// The cheapest hatchery takes the jobs 11 and 12, the job 13 goes to the hatchery of its group which has the highest priority
+	assert.Equal(t, []int64{1, 2, 2, 3}, bookedBy(jobs))
+	// Only the newly assigned jobs are returned
+	assert.Equal(t, []int64{2, 2, 3}, bookedBy(res))
+	assert.Equal(t, int64(11), res[0].ID)
+
+	h := sdk.Service{}
+	require.True(t, store.Get(keyBookJob(11), &h))
+	assert.Equal(t, int64(2), h.ID)
+
+	// A hatchery that released a job is not chosen again for it
+	require.NoError(t, ReleaseNodeJobRun(store, 11, &sdk.Service{ID: 2}))
+	require.NoError(t, ReleaseNodeJobRun(store, 12, &sdk.Service{ID: 2}))
+	var released []int64
+	require.True(t, store.Get(keyReleasedBookJob(11), &released))
+	assert.Equal(t, []int64{2}, released)
+	for k := range store.values {
+		assert.False(t, strings.HasSuffix(k, ":lock"), "lock %s must be released", k)
+	}
+
+	jobs = []sdk.WorkflowNodeJobRun{{ID: 11, ExecGroups: group}, {ID: 12, ExecGroups: group}}
+	res, err = ScheduleNodeJobRuns(store, jobs)
+	require.NoError(t, err)
+	// The job 10 has left the queue, the first hatchery takes the job 11 and is then full
+	assert.Equal(t, []int64{1, 0}, bookedBy(jobs))
+	require.Len(t, res, 1)
+	assert.Equal(t, sdk.Service{ID: 1, Name: "docker-1", Type: "hatchery"}, res[0].BookedBy)
+}
@@ -275,6 +275,7 @@ func execute(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *
 			where workflow.id = $1
 			and workflow_node_run.workflow_node_name = $2
 			and workflow_node_run.status = $3
+			and workflow_node_run.id not in (select workflow_node_run_id from workflow_node_run_freeze)
 			order by workflow_node_run.start asc
 			limit 1`
 			waitingRunID, errID := db.SelectInt(mutexQuery, updatedWorkflowRun.WorkflowID, nodeName, string(sdk.StatusWaiting))
@@ -355,6 +356,8 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 	}
 	next()
 
+	priority := computeNodeJobRunPriority(wr, run)
+
 	skippedOrDisabledJobs := 0
 	failedJobs := 0
 	//Browse the jobs
@@ -404,6 +407,7 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 			},
 			Header:          run.Header,
 			ContainsService: containsService,
+			Priority:        priority,
 		}
 		if wm != nil {
 			wjob.ModelType = wm.Type
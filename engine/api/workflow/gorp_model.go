@@ -44,6 +44,8 @@ type NodeRun struct {
 	PipelineParameters     sql.NullString `db:"pipeline_parameters"`
 	BuildParameters        sql.NullString `db:"build_parameters"`
 	Tests                  sql.NullString `db:"tests"`
+	TestsQuarantined       sql.NullInt64  `db:"tests_quarantined"`
+	VulnerabilityPolicy    sql.NullString `db:"vulnerability_policy"`
 	Commits                sql.NullString `db:"commits"`
 	Stages                 sql.NullString `db:"stages"`
 	TriggersRun            sql.NullString `db:"triggers_run"`
@@ -79,6 +81,7 @@ type JobRun struct {
 	ContainsService           bool           `db:"contains_service"`
 	ModelType                 sql.NullString `db:"model_type"`
 	Header                    sql.NullString `db:"header"`
+	Priority                  int            `db:"priority"`
 }
 
 // ToJobRun transform the JobRun with data of the provided sdk.WorkflowNodeJobRun
@@ -102,6 +105,7 @@ func (j *JobRun) ToJobRun(jr *sdk.WorkflowNodeJobRun) (err error) {
 	j.Model = jr.Model
 	j.ModelType = sql.NullString{Valid: true, String: string(jr.ModelType)}
 	j.ContainsService = jr.ContainsService
+	j.Priority = jr.Priority
 	j.ExecGroups, err = gorpmapping.JSONToNullString(jr.ExecGroups)
 	if err != nil {
 		return sdk.WrapError(err, "column exec_groups")
@@ -131,6 +135,7 @@ func (j JobRun) WorkflowNodeRunJob() (sdk.WorkflowNodeJobRun, error) {
 		Done:              j.Done,
 		BookedBy:          j.BookedBy,
 		ContainsService:   j.ContainsService,
+		Priority:          j.Priority,
 	}
 	if err := gorpmapping.JSONNullString(j.Job, &jr.Job); err != nil {
 		return jr, sdk.WrapError(err, "column job")
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/fsamin/go-dump"
 	"github.com/go-gorp/gorp"
@@ -71,6 +72,29 @@ func hookUnregistration(ctx context.Context, db gorp.SqlExecutor, store cache.St
 	return nil
 }
 
+// setHookPathFilters copies the include and exclude paths of the workflow in the configuration of a repository hook.
+// The hooks service uses them to skip the pushes that do not change any matching file.
+func setHookPathFilters(h *sdk.NodeHook, wf *sdk.Workflow) {
+	if h.Config == nil {
+		h.Config = sdk.WorkflowNodeHookConfig{}
+	}
+	filters := map[string][]string{
+		sdk.HookConfigIncludePaths: wf.IncludePaths,
+		sdk.HookConfigExcludePaths: wf.ExcludePaths,
+	}
+	for k, paths := range filters {
+		if len(paths) == 0 {
+			delete(h.Config, k)
+			continue
+		}
+		h.Config[k] = sdk.WorkflowNodeHookConfigValue{
+			Value:        strings.Join(paths, ";"),
+			Configurable: false,
+			Type:         sdk.HookConfigTypeString,
+		}
+	}
+}
+
 func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Store, p *sdk.Project, wf *sdk.Workflow, oldWorkflow *sdk.Workflow) error {
 	var oldHooks map[string]*sdk.NodeHook
 	var oldHooksByRef map[string]sdk.NodeHook
@@ -95,6 +119,10 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 	hookToUpdate := make(map[string]sdk.NodeHook)
 	for i := range wf.WorkflowData.Node.Hooks {
 		h := &wf.WorkflowData.Node.Hooks[i]
+		if h.HookModelName == sdk.RepositoryWebHookModelName || h.HookModelName == sdk.GitPollerModelName {
+			// Set before the comparison with the previous hook, so the hook is updated when the workflow path filters change
+			setHookPathFilters(h, wf)
+		}
 		if h.UUID == "" && h.Ref == "" {
 			nodeName := wf.WorkflowData.Node.Name
 			if len(nodeName) > 45 {
@@ -148,6 +176,16 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 				Value:        wf.Applications[wf.WorkflowData.Node.Context.ApplicationID].RepositoryFullname,
 				Configurable: false,
 			}
+		} else if h.HookModelName == sdk.SchedulerModelName && wf.WorkflowData.Node.IsLinkedToRepo(wf) {
+			// The scheduler needs the repository to run only if it changed since the last scheduled run
+			h.Config[sdk.HookConfigVCSServer] = sdk.WorkflowNodeHookConfigValue{
+				Value:        wf.Applications[wf.WorkflowData.Node.Context.ApplicationID].VCSServer,
+				Configurable: false,
+			}
+			h.Config[sdk.HookConfigRepoFullName] = sdk.WorkflowNodeHookConfigValue{
+				Value:        wf.Applications[wf.WorkflowData.Node.Context.ApplicationID].RepositoryFullname,
+				Configurable: false,
+			}
 		}
 
 		if err := updateSchedulerPayload(ctx, db, store, p, wf, h); err != nil {
@@ -270,10 +308,28 @@ func createVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 	if !webHookInfo.WebhooksSupported || webHookInfo.WebhooksDisabled {
 		return sdk.WrapError(sdk.ErrForbidden, "createVCSConfiguration> hook creation are forbidden")
 	}
+	// The secret is used by the hooks service to verify the signature of the events sent by the repository manager
+	secret := h.Config[sdk.HookConfigWebHookSecret].Value
+	if secret == "" {
+		var err error
+		secret, err = sdk.GenerateHash()
+		if err != nil {
+			return err
+		}
+		h.Config[sdk.HookConfigWebHookSecret] = sdk.WorkflowNodeHookConfigValue{
+			Value:        secret,
+			Configurable: false,
+			Type:         sdk.HookConfigTypePassword,
+		}
+	}
 	vcsHook := sdk.VCSHook{
 		Method:   "POST",
 		URL:      h.Config["webHookURL"].Value,
 		Workflow: true,
+		Secret:   secret,
+	}
+	if events := h.Config[sdk.HookConfigEventFilter].Value; events != "" {
+		vcsHook.Events = strings.Split(events, ";")
 	}
 	if err := client.CreateHook(ctx, h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "Cannot create hook on repository: %+v", vcsHook)
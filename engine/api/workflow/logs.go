@@ -8,6 +8,8 @@ import (
 const (
 	DefaultMaxLogSize = 15728640 // 15MB
 	maxLogMarker      = "... truncated\n"
+	// TruncatedLogHeader starts the end of a log moved to the object storage when the whole log is too large
+	TruncatedLogHeader = "... beginning of the log truncated, download it to get the whole log\n"
 )
 
 func truncateLogs(maxSize, existingSize int64, logs *sdk.Log) bool {
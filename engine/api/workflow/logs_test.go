@@ -1,10 +1,17 @@
 package workflow
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -39,3 +46,41 @@ func Test_truncateStepLogs(t *testing.T) {
 
 	assert.Equal(t, true, truncateServiceLogs(15, 20, logs))
 }
+
+func Test_stepLogReader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-logs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := objectstore.Init(context.Background(), objectstore.Config{
+		Kind:    objectstore.Filesystem,
+		Options: objectstore.ConfigOptions{Filesystem: objectstore.ConfigOptionsFilesystem{Basedir: dir}},
+	})
+	require.NoError(t, err)
+
+	p := StepLogParts{NodeRunID: 1, JobRunID: 2, StepOrder: 3}
+	for _, v := range []string{"first\n", "second\n"} {
+		buf := new(bytes.Buffer)
+		gw := gzip.NewWriter(buf)
+		_, err := gw.Write([]byte(v))
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+		_, err = store.Store(p.part(len(p.Sizes)), ioutil.NopCloser(buf))
+		require.NoError(t, err)
+		p.Sizes = append(p.Sizes, int64(len(v)))
+	}
+
+	read := func(offset int64) string {
+		r := NewStepLogReader(store, p, "third\n", offset)
+		defer r.Close()
+		btes, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		return string(btes)
+	}
+	assert.Equal(t, int64(13), p.Size())
+	assert.Equal(t, "first\nsecond\nthird\n", read(0))
+	assert.Equal(t, "st\nsecond\nthird\n", read(3))
+	assert.Equal(t, "second\nthird\n", read(6))
+	assert.Equal(t, "ird\n", read(15))
+	assert.Equal(t, "", read(100))
+}
@@ -0,0 +1,176 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// checkFreezeWindows checks the freeze windows of the environment of a node before its run starts.
+// During a window, the node run fails or is held until the end of the window (it returns true),
+// unless the workflow was manually started by a member of the override group of the window.
+func checkFreezeWindows(ctx context.Context, db gorp.SqlExecutor, proj *sdk.Project, wr *sdk.WorkflowRun, n *sdk.Node, run *sdk.WorkflowNodeRun, manual *sdk.WorkflowNodeRunManual) (bool, error) {
+	if n.Context == nil || n.Context.EnvironmentID == 0 {
+		return false, nil
+	}
+
+	fs, err := environment.LoadFreezeWindowsForEnvironment(ctx, db, proj.ID, n.Context.EnvironmentID)
+	if err != nil {
+		return false, err
+	}
+	f, until, active := environment.ActiveFreezeWindow(fs, time.Now())
+	if !active {
+		return false, nil
+	}
+
+	// The override applies to the nodes triggered after a manual run of the root node
+	if manual == nil {
+		if root := wr.RootRun(); root != nil {
+			manual = root.Manual
+		}
+	}
+	if manual != nil && f.OverrideGroup != "" {
+		allowed, err := isFreezeWindowOverrider(db, f.OverrideGroup, manual.User.ID)
+		if err != nil {
+			return false, err
+		}
+		if allowed {
+			AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+				ID:   sdk.MsgWorkflowNodeFreezeOverride.ID,
+				Args: []interface{}{n.Name, f.Name, manual.User.Username, f.OverrideGroup},
+			})
+			return false, nil
+		}
+	}
+
+	if f.Action == sdk.FreezeWindowActionFail {
+		AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowNodeFreezeFail.ID,
+			Args: []interface{}{n.Name, f.Name, f.Reason},
+		})
+		run.Status = sdk.StatusFail.String()
+		run.Done = time.Now()
+		return false, nil
+	}
+
+	AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeFreezeHold.ID,
+		Args: []interface{}{n.Name, until.Format(time.RFC3339), f.Name, f.Reason},
+	})
+	return true, nil
+}
+
+func isFreezeWindowOverrider(db gorp.SqlExecutor, groupName string, userID int64) (bool, error) {
+	g, err := group.LoadGroup(db, groupName)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrGroupNotFound) {
+			return false, nil
+		}
+		return false, sdk.WrapError(err, "unable to load override group %s", groupName)
+	}
+	return group.CheckUserInGroup(db, g.ID, userID)
+}
+
+func insertNodeRunFreeze(db gorp.SqlExecutor, run *sdk.WorkflowNodeRun, envID int64) error {
+	query := `INSERT INTO workflow_node_run_freeze (workflow_node_run_id, workflow_run_id, environment_id, created) VALUES ($1, $2, $3, $4)`
+	if _, err := db.Exec(query, run.ID, run.WorkflowRunID, envID, time.Now()); err != nil {
+		return sdk.WrapError(err, "unable to hold node run %d", run.ID)
+	}
+	return nil
+}
+
+func deleteNodeRunFreeze(db gorp.SqlExecutor, nodeRunID int64) error {
+	if _, err := db.Exec(`DELETE FROM workflow_node_run_freeze WHERE workflow_node_run_id = $1`, nodeRunID); err != nil {
+		return sdk.WrapError(err, "unable to release node run %d", nodeRunID)
+	}
+	return nil
+}
+
+// LoadFrozenNodeRunIDs returns the ids of the node runs held by a freeze window, oldest first.
+func LoadFrozenNodeRunIDs(db gorp.SqlExecutor) ([]int64, error) {
+	var ids []int64
+	if _, err := db.Select(&ids, `SELECT workflow_node_run_id FROM workflow_node_run_freeze ORDER BY created`); err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "unable to load frozen node runs")
+	}
+	return ids, nil
+}
+
+// ReleaseFrozenNodeRun executes a node run held by a freeze window when no window is active anymore on its environment.
+// It returns false if the node run is still held.
+func ReleaseFrozenNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, nodeRunID int64) (*ProcessorReport, bool, error) {
+	var envID int64
+	if err := db.SelectOne(&envID, `SELECT environment_id FROM workflow_node_run_freeze WHERE workflow_node_run_id = $1 FOR UPDATE SKIP LOCKED`, nodeRunID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, sdk.WrapError(err, "unable to load frozen node run %d", nodeRunID)
+	}
+
+	nr, err := LoadNodeRunByID(db, nodeRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	// The node run was stopped during the freeze
+	if nr.Status != sdk.StatusWaiting.String() {
+		return nil, true, deleteNodeRunFreeze(db, nodeRunID)
+	}
+
+	fs, err := environment.LoadFreezeWindowsForEnvironment(ctx, db, proj.ID, envID)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, _, active := environment.ActiveFreezeWindow(fs, time.Now()); active {
+		return nil, false, nil
+	}
+
+	wr, err := LoadRunByID(db, nr.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "unable to load workflow run %d", nr.WorkflowRunID)
+	}
+	n := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID)
+	if n == nil {
+		return nil, false, sdk.WrapError(sdk.ErrWorkflowNodeNotFound, "unable to find node %d", nr.WorkflowNodeID)
+	}
+
+	if err := deleteNodeRunFreeze(db, nodeRunID); err != nil {
+		return nil, false, err
+	}
+	AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowNodeFreezeRelease.ID,
+		Args: []interface{}{nr.WorkflowNodeName},
+	})
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return nil, false, sdk.WrapError(err, "unable to update workflow run %d after freeze release", wr.ID)
+	}
+
+	runContext := nodeRunContext{}
+	if n.Context != nil {
+		if n.Context.PipelineID != 0 {
+			runContext.Pipeline = wr.Workflow.Pipelines[n.Context.PipelineID]
+		}
+		if n.Context.ApplicationID != 0 {
+			runContext.Application = wr.Workflow.Applications[n.Context.ApplicationID]
+		}
+		if n.Context.EnvironmentID != 0 {
+			runContext.Environment = wr.Workflow.Environments[n.Context.EnvironmentID]
+		}
+		if n.Context.ProjectIntegrationID != 0 {
+			runContext.ProjectIntegration = wr.Workflow.ProjectIntegrations[n.Context.ProjectIntegrationID]
+		}
+	}
+
+	log.Debug("workflow.ReleaseFrozenNodeRun> process the node run %d because the freeze window is over", nr.ID)
+	report, err := execute(ctx, db, store, proj, nr, runContext)
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "unable to execute node run %d", nr.ID)
+	}
+	return report, true, nil
+}
@@ -0,0 +1,157 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestFreezeWindow(t *testing.T) {
+	db, cache, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	event.Initialize(event.KafkaConfig{}, cache)
+
+	u, _ := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+	}
+	require.NoError(t, pipeline.InsertPipeline(db, cache, proj, &pip, u))
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	require.NoError(t, pipeline.InsertStage(db, s))
+	require.NoError(t, pipeline.InsertJob(db, &sdk.Job{
+		Enabled:         true,
+		PipelineStageID: s.ID,
+		Action:          sdk.Action{Enabled: true, Name: "deploy"},
+	}, s.ID, &pip))
+
+	env := sdk.Environment{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "production",
+	}
+	require.NoError(t, environment.InsertEnvironment(db, &env))
+
+	proj, _ = project.LoadByID(db, cache, proj.ID, u, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments, project.LoadOptions.WithGroups)
+
+	w := sdk.Workflow{
+		Name:       "test_freeze",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: &sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "deploy",
+				Ref:  "deploy",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID:    pip.ID,
+					EnvironmentID: env.ID,
+				},
+			},
+		},
+	}
+	require.NoError(t, workflow.Insert(db, cache, &w, proj, u))
+	w1, err := workflow.Load(context.TODO(), db, cache, proj, w.Name, u, workflow.LoadOptions{DeepPipeline: true})
+	require.NoError(t, err)
+
+	run := func() *sdk.WorkflowNodeRun {
+		wr, err := workflow.CreateRun(db, w1, nil, u)
+		require.NoError(t, err)
+		wr.Workflow = *w1
+		_, err = workflow.StartWorkflowRun(context.TODO(), db, cache, proj, wr, &sdk.WorkflowRunPostHandlerOption{
+			Manual: &sdk.WorkflowNodeRunManual{User: *u},
+		}, u, nil)
+		require.NoError(t, err)
+		require.Len(t, wr.WorkflowNodeRuns[w1.WorkflowData.Node.ID], 1)
+		nr, err := workflow.LoadNodeRunByID(db, wr.WorkflowNodeRuns[w1.WorkflowData.Node.ID][0].ID, workflow.LoadRunOptions{})
+		require.NoError(t, err)
+		return nr
+	}
+	frozen := func(nodeRunID int64) bool {
+		ids, err := workflow.LoadFrozenNodeRunIDs(db)
+		require.NoError(t, err)
+		for _, id := range ids {
+			if id == nodeRunID {
+				return true
+			}
+		}
+		return false
+	}
+
+	from, to := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	f := sdk.FreezeWindow{
+		ProjectID:     proj.ID,
+		EnvironmentID: &env.ID,
+		Name:          "release",
+		Reason:        "release in progress",
+		From:          &from,
+		To:            &to,
+		Timezone:      "UTC",
+		Action:        sdk.FreezeWindowActionHold,
+	}
+	require.NoError(t, environment.InsertFreezeWindow(db, &f))
+
+	// The node run is held without job while the window is active
+	held := run()
+	assert.Equal(t, sdk.StatusWaiting.String(), held.Status)
+	assert.True(t, frozen(held.ID))
+	jobIDs, err := workflow.LoadNodeJobRunIDByNodeRunID(db, held.ID)
+	require.NoError(t, err)
+	assert.Empty(t, jobIDs)
+
+	_, released, err := workflow.ReleaseFrozenNodeRun(context.TODO(), db, cache, proj, held.ID)
+	require.NoError(t, err)
+	assert.False(t, released)
+	assert.True(t, frozen(held.ID))
+
+	// The node run is executed once the window is over
+	from, to = time.Now().Add(-2*time.Hour), time.Now().Add(-time.Minute)
+	require.NoError(t, environment.UpdateFreezeWindow(db, &f))
+	_, released, err = workflow.ReleaseFrozenNodeRun(context.TODO(), db, cache, proj, held.ID)
+	require.NoError(t, err)
+	assert.True(t, released)
+	assert.False(t, frozen(held.ID))
+	jobIDs, err = workflow.LoadNodeJobRunIDByNodeRunID(db, held.ID)
+	require.NoError(t, err)
+	assert.Len(t, jobIDs, 1)
+
+	// The node run fails during a window with the fail action
+	from, to = time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	f.Action = sdk.FreezeWindowActionFail
+	require.NoError(t, environment.UpdateFreezeWindow(db, &f))
+	failed := run()
+	assert.Equal(t, sdk.StatusFail.String(), failed.Status)
+	assert.False(t, frozen(failed.ID))
+
+	// The members of the override group can still deploy
+	g := proj.ProjectGroups[0].Group
+	require.NoError(t, group.InsertUserInGroup(db, g.ID, u.ID, false))
+	f.OverrideGroup = g.Name
+	require.NoError(t, environment.UpdateFreezeWindow(db, &f))
+	overridden := run()
+	assert.NotEqual(t, sdk.StatusFail.String(), overridden.Status)
+	assert.False(t, frozen(overridden.ID))
+	jobIDs, err = workflow.LoadNodeJobRunIDByNodeRunID(db, overridden.ID)
+	require.NoError(t, err)
+	assert.Len(t, jobIDs, 1)
+}
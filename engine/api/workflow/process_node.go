@@ -317,6 +317,12 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 		wr.Tag(tagEnvironment, wr.Workflow.Environments[n.Context.EnvironmentID].Name)
 	}
 
+	// FREEZE WINDOWS
+	frozen, err := checkFreezeWindows(ctx, db, proj, wr, n, run, manual)
+	if err != nil {
+		return nil, false, sdk.WrapError(err, "unable to check freeze windows")
+	}
+
 	for _, info := range wr.Infos {
 		if info.IsError && info.SubNumber == wr.LastSubNumber {
 			run.Status = string(sdk.StatusFail)
@@ -360,6 +366,15 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 		return nil, false, sdk.WrapError(err, "unable to update workflow run")
 	}
 
+	//The node run waits for the end of the freeze window
+	if frozen {
+		log.Debug("Noderun %s processed but not executed because of a freeze window", n.Name)
+		if err := insertNodeRunFreeze(db, run, n.Context.EnvironmentID); err != nil {
+			return nil, false, err
+		}
+		return report, false, nil
+	}
+
 	//Check the context.mutex to know if we are allowed to run it
 	if n.Context.Mutex {
 		//Check if there are builing workflownoderun with the same workflow_node_name for the same workflow
@@ -3,6 +3,7 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/go-gorp/gorp"
 
@@ -78,6 +79,17 @@ func processAllNodesTriggers(ctx context.Context, db gorp.SqlExecutor, store cac
 
 		//Trigger only if the node is over (successful or not)
 		if sdk.StatusIsTerminated(nodeRun.Status) && nodeRun.Status != sdk.StatusNeverBuilt.String() {
+			// A failed vulnerability policy can block the triggers
+			if nodeRun.VulnerabilityPolicy.BlocksTriggers() {
+				if !vulnerabilityPolicyInfoExists(wr, nodeRun.WorkflowNodeName) {
+					AddWorkflowRunInfo(wr, false, sdk.SpawnMsg{
+						ID:   sdk.MsgWorkflowNodeVulnerabilityPolicy.ID,
+						Args: []interface{}{nodeRun.WorkflowNodeName, strings.Join(nodeRun.VulnerabilityPolicy.Reasons, ", ")},
+					})
+				}
+				continue
+			}
+
 			//Find the node in the workflow
 			node := mapNodes[nodeRun.WorkflowNodeID]
 			r1, _ := processNodeTriggers(ctx, db, store, proj, wr, mapNodes, []*sdk.WorkflowNodeRun{nodeRun}, node, int(nodeRun.SubNumber))
@@ -87,6 +99,16 @@ func processAllNodesTriggers(ctx context.Context, db gorp.SqlExecutor, store cac
 	return report, nil
 }
 
+// vulnerabilityPolicyInfoExists returns true if the run already has the info about the triggers of given node blocked by the vulnerability policy.
+func vulnerabilityPolicyInfoExists(wr *sdk.WorkflowRun, nodeName string) bool {
+	for _, i := range wr.Infos {
+		if i.Message.ID == sdk.MsgWorkflowNodeVulnerabilityPolicy.ID && len(i.Message.Args) > 0 && i.Message.Args[0] == nodeName {
+			return true
+		}
+	}
+	return false
+}
+
 func processAllJoins(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, wr *sdk.WorkflowRun, mapNodes map[int64]*sdk.Node) (*ProcessorReport, error) {
 	report := new(ProcessorReport)
 	//Checks the joins
@@ -2,7 +2,10 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -10,6 +13,7 @@ import (
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
@@ -123,14 +127,6 @@ func (api *API) getWorkflowHookModelsHandler() service.Handler {
 			case sdk.RepositoryWebHookModelName:
 				if repoWebHookEnable {
 					m[i].Icon = webHookInfo.Icon
-					/*  UNCOMMENT WHEN DEVELOP EVENT SUBSCRIPTION
-					m[i].DefaultConfig[sdk.HookConfigEventFilter] = sdk.WorkflowNodeHookConfigValue{
-						Type:               sdk.HookConfigTypeMultiChoice,
-						Value:              webHookInfo.Events[0],
-						Configurable:       true,
-						MultipleChoiceList: webHookInfo.Events,
-					}
-					*/
 					models = append(models, m[i])
 				}
 			case sdk.GitPollerModelName:
@@ -327,3 +323,166 @@ func (api *API) getWorkflowJobHookDetailsHandler() service.Handler {
 		return service.WriteJSON(w, hr, http.StatusOK)
 	}
 }
+
+// checkWorkflowHook checks that the hook belongs to the workflow
+func (api *API) checkWorkflowHook(ctx context.Context, key, name, uuid string) error {
+	proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx),
+		project.LoadOptions.WithIntegrations,
+		project.LoadOptions.WithApplicationWithDeploymentStrategies,
+		project.LoadOptions.WithPipelines,
+		project.LoadOptions.WithEnvironments)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project %s", key)
+	}
+
+	wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, deprecatedGetUser(ctx), workflow.LoadOptions{})
+	if err != nil {
+		return sdk.WrapError(err, "cannot load workflow %s/%s", key, name)
+	}
+
+	if _, has := wf.WorkflowData.GetHooks()[uuid]; !has {
+		return sdk.WrapError(sdk.ErrNotFound, "cannot find hook %s on workflow %s/%s", uuid, key, name)
+	}
+	return nil
+}
+
+// hideTaskExecutionSecrets removes the configuration of the hook and the credentials received by the hook,
+// in the request headers and query string, and wherever the secrets of the configuration appear
+func hideTaskExecutionSecrets(e *sdk.TaskExecution) {
+	secrets := e.Config.SecretValues()
+	e.Config = nil
+	hide := func(s string) string {
+		for _, secret := range secrets {
+			s = strings.Replace(s, secret, sdk.PasswordPlaceholder, -1)
+		}
+		return s
+	}
+
+	if e.WebHook != nil {
+		for k, vs := range e.WebHook.RequestHeader {
+			if sdk.IsSensitiveHTTPName(k) {
+				e.WebHook.RequestHeader[k] = []string{sdk.PasswordPlaceholder}
+				continue
+			}
+			for i := range vs {
+				vs[i] = hide(vs[i])
+			}
+		}
+
+		if u, err := url.Parse(e.WebHook.RequestURL); err == nil {
+			query := u.Query()
+			var redacted bool
+			for k := range query {
+				if sdk.IsSensitiveHTTPName(k) {
+					query.Set(k, sdk.PasswordPlaceholder)
+					redacted = true
+				}
+			}
+			if redacted {
+				u.RawQuery = query.Encode()
+				e.WebHook.RequestURL = u.String()
+			}
+		}
+		e.WebHook.RequestURL = hide(e.WebHook.RequestURL)
+		if len(secrets) > 0 {
+			e.WebHook.RequestBody = []byte(hide(string(e.WebHook.RequestBody)))
+		}
+	}
+
+	for k, v := range e.Payload {
+		e.Payload[k] = hide(v)
+	}
+}
+
+func (api *API) getWorkflowHookExecutionsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		uuid := vars["uuid"]
+
+		if err := api.checkWorkflowHook(ctx, key, name, uuid); err != nil {
+			return err
+		}
+
+		srvs, err := services.FindByType(api.mustDB(), services.TypeHooks)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load hooks services")
+		}
+
+		task := sdk.Task{}
+		if _, _, err := services.DoJSONRequest(ctx, srvs, "GET", fmt.Sprintf("/task/%s/execution", uuid), nil, &task); err != nil {
+			return sdk.WrapError(err, "unable to get hook %s executions", uuid)
+		}
+
+		execs := task.Executions
+		if execs == nil {
+			execs = []sdk.TaskExecution{}
+		}
+		sort.Slice(execs, func(i, j int) bool { return execs[i].Timestamp > execs[j].Timestamp })
+		for i := range execs {
+			hideTaskExecutionSecrets(&execs[i])
+		}
+
+		return service.WriteJSON(w, execs, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowHookExecutionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		uuid := vars["uuid"]
+		timestamp := vars["timestamp"]
+
+		if err := api.checkWorkflowHook(ctx, key, name, uuid); err != nil {
+			return err
+		}
+
+		srvs, err := services.FindByType(api.mustDB(), services.TypeHooks)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load hooks services")
+		}
+
+		var e sdk.TaskExecution
+		if _, _, err := services.DoJSONRequest(ctx, srvs, "GET", fmt.Sprintf("/task/%s/execution/%s", uuid, timestamp), nil, &e); err != nil {
+			return sdk.WrapError(err, "unable to get hook %s execution %s", uuid, timestamp)
+		}
+		hideTaskExecutionSecrets(&e)
+
+		return service.WriteJSON(w, e, http.StatusOK)
+	}
+}
+
+func (api *API) postWorkflowHookExecutionReplayHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		uuid := vars["uuid"]
+		timestamp := vars["timestamp"]
+
+		var replay sdk.TaskExecutionReplay
+		if err := service.UnmarshalBody(r, &replay); err != nil {
+			return err
+		}
+
+		if err := api.checkWorkflowHook(ctx, key, name, uuid); err != nil {
+			return err
+		}
+
+		srvs, err := services.FindByType(api.mustDB(), services.TypeHooks)
+		if err != nil {
+			return sdk.WrapError(err, "unable to load hooks services")
+		}
+
+		var e sdk.TaskExecution
+		if _, _, err := services.DoJSONRequest(ctx, srvs, "POST", fmt.Sprintf("/task/%s/execution/%s/replay", uuid, timestamp), replay, &e); err != nil {
+			return sdk.WrapError(err, "unable to replay hook %s execution %s", uuid, timestamp)
+		}
+		hideTaskExecutionSecrets(&e)
+
+		return service.WriteJSON(w, e, http.StatusOK)
+	}
+}
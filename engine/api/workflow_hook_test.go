@@ -1,9 +1,13 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,6 +16,7 @@ import (
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -256,3 +261,133 @@ func Test_putWorkflowHookModelHandlerAsLambdaUser(t *testing.T) {
 	assert.Equal(t, 403, rec.Code)
 
 }
+
+func Test_postWorkflowHookExecutionReplayHandler(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	u, pass := assets.InsertAdminUser(db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+	}
+	test.NoError(t, pipeline.InsertPipeline(db, api.Cache, proj, &pip, u))
+
+	_, _ = db.Exec("DELETE FROM services")
+	mockHookService := &sdk.Service{Name: "Test_postWorkflowHookExecutionReplayHandler", Type: services.TypeHooks}
+	test.NoError(t, services.Insert(db, mockHookService))
+
+	var replayed sdk.TaskExecutionReplay
+	services.HTTPClient = mock(
+		func(r *http.Request) (*http.Response, error) {
+			body := new(bytes.Buffer)
+			w := new(http.Response)
+			enc := json.NewEncoder(body)
+			w.Body = ioutil.NopCloser(body)
+			w.StatusCode = http.StatusOK
+
+			switch {
+			case r.URL.String() == "/task/bulk":
+				var hooks map[string]sdk.NodeHook
+				if err := json.NewDecoder(r.Body).Decode(&hooks); err != nil {
+					return writeError(w, err)
+				}
+				if err := enc.Encode(hooks); err != nil {
+					return writeError(w, err)
+				}
+			case strings.HasSuffix(r.URL.String(), "/execution/42/replay"):
+				if err := json.NewDecoder(r.Body).Decode(&replayed); err != nil {
+					return writeError(w, err)
+				}
+				e := sdk.TaskExecution{
+					Timestamp: 43,
+					Type:      "Webhook",
+					Status:    "DONE",
+					Config: sdk.WorkflowNodeHookConfig{
+						sdk.HookConfigWebHookSecret: {Value: "mysecret", Type: sdk.HookConfigTypePassword},
+					},
+					WebHook: &sdk.WebHookExecution{
+						RequestURL:    "/webhook/uuid?private_token=mytoken&branch=master",
+						RequestBody:   []byte(`{"secret":"mysecret"}`),
+						RequestHeader: map[string][]string{"X-Gitlab-Token": {"mytoken"}, "X-Forwarded-For": {"mysecret"}},
+					},
+					Payload: map[string]string{"git.branch": "master", "secret": "mysecret"},
+					Replay:  &replayed,
+				}
+				if err := enc.Encode(e); err != nil {
+					return writeError(w, err)
+				}
+			default:
+				t.Fatalf("UNKNOWN ROUTE: %s", r.URL.String())
+			}
+			return w, nil
+		},
+	)
+
+	webHookModel, err := workflow.LoadHookModelByName(db, sdk.WebHookModelName)
+	test.NoError(t, err)
+	w := sdk.Workflow{
+		Name:       "test_replay",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: &sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "root",
+				Ref:  "root",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID: pip.ID,
+				},
+				Hooks: []sdk.NodeHook{{
+					HookModelID:   webHookModel.ID,
+					HookModelName: sdk.WebHookModelName,
+					Config:        sdk.WebHookModel.DefaultConfig.Clone(),
+				}},
+			},
+		},
+	}
+	proj, err = project.Load(db, api.Cache, proj.Key, u, project.LoadOptions.WithPipelines)
+	test.NoError(t, err)
+	test.NoError(t, workflow.Insert(db, api.Cache, &w, proj, u))
+
+	vars := map[string]string{
+		"key":              proj.Key,
+		"permWorkflowName": w.Name,
+		"uuid":             w.WorkflowData.Node.Hooks[0].UUID,
+		"timestamp":        "42",
+	}
+	uri := router.GetRoute("POST", api.postWorkflowHookExecutionReplayHandler, vars)
+	test.NotEmpty(t, uri)
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.TaskExecutionReplay{Payload: map[string]string{"git.branch": "feature"}})
+	rec := httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The replay is forwarded to the hooks service
+	assert.Equal(t, "feature", replayed.Payload["git.branch"])
+
+	// And the secrets of the replayed execution are hidden
+	var e sdk.TaskExecution
+	test.NoError(t, json.Unmarshal(rec.Body.Bytes(), &e))
+	assert.Equal(t, int64(43), e.Timestamp)
+	assert.Nil(t, e.Config)
+	assert.NotContains(t, e.WebHook.RequestURL, "mytoken")
+	assert.Contains(t, e.WebHook.RequestURL, "branch=master")
+	assert.Equal(t, []string{sdk.PasswordPlaceholder}, e.WebHook.RequestHeader["X-Gitlab-Token"])
+	assert.Equal(t, []string{sdk.PasswordPlaceholder}, e.WebHook.RequestHeader["X-Forwarded-For"])
+	assert.NotContains(t, string(e.WebHook.RequestBody), "mysecret")
+	assert.Equal(t, sdk.PasswordPlaceholder, e.Payload["secret"])
+	assert.Equal(t, "master", e.Payload["git.branch"])
+
+	// Without hook on the workflow, the replay is not found
+	vars["uuid"] = "unknown"
+	uri = router.GetRoute("POST", api.postWorkflowHookExecutionReplayHandler, vars)
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.TaskExecutionReplay{})
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
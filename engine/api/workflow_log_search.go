@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) postWorkflowLogSearchHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		// the default context is used when it is omitted in the body
+		req := sdk.LogSearchRequest{Context: sdk.LogSearchDefaultContext}
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "cannot unmarshal request")
+		}
+		if err := req.IsValid(); err != nil {
+			return err
+		}
+
+		proj, err := project.Load(api.mustDB(), api.Cache, key, deprecatedGetUser(ctx))
+		if err != nil {
+			return sdk.WrapError(err, "unable to load project %s", key)
+		}
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, proj, name, deprecatedGetUser(ctx), workflow.LoadOptions{Minimal: true})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow %s", name)
+		}
+
+		index := workflow.NewLogSearchIndex(api.mustDB(), api.SharedStorage, api.Config.Log.SearchIndex)
+		res, err := workflow.SearchLogs(ctx, index, wf.ID, req)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
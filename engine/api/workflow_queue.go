@@ -23,6 +23,7 @@ import (
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workermodel"
 	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/api/workflowtest"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -215,13 +216,73 @@ func (api *API) deleteBookWorkflowJobHandler() service.Handler {
 			return sdk.WrapError(errc, "Invalid id")
 		}
 
-		if err := workflow.FreeNodeJobRun(api.Cache, id); err != nil {
+		if err := workflow.ReleaseNodeJobRun(api.Cache, id, getHatchery(ctx)); err != nil {
 			return sdk.WrapError(err, "job not booked")
 		}
 		return service.WriteJSON(w, nil, http.StatusOK)
 	}
 }
 
+func (api *API) postHatcheryCapacityHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var capa sdk.HatcheryCapacity
+		if err := service.UnmarshalBody(r, &capa); err != nil {
+			return sdk.WrapError(err, "cannot unmarshal request")
+		}
+
+		h := getHatchery(ctx)
+		if h == nil {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+		capa.HatcheryID = h.ID
+		capa.HatcheryName = h.Name
+		capa.Booked = 0
+		if h.GroupID != nil {
+			capa.GroupID = *h.GroupID
+		}
+		capa.IsSharedInfra = group.SharedInfraGroup != nil && capa.GroupID == group.SharedInfraGroup.ID
+		if capa.ModelType != "" && !sdk.WorkerModelValidate(capa.ModelType) {
+			return sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("invalid given model type"))
+		}
+		if capa.MaxWorker < 0 || capa.CurrentWorkers < 0 || capa.CostWeight < 0 {
+			return sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("invalid given capacity"))
+		}
+
+		workflow.UpsertHatcheryCapacity(api.Cache, capa)
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// scheduleQueueRoutine assigns every few seconds the waiting jobs to the hatcheries that declared their capacity
+func (api *API) scheduleQueueRoutine(c context.Context) {
+	tick := time.NewTicker(5 * time.Second)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting scheduleQueueRoutine: %v", c.Err())
+			}
+			return
+		case <-tick.C:
+			if err := workflow.ScheduleQueue(c, api.mustDB(), api.Cache); err != nil {
+				log.Warning("scheduleQueueRoutine> %v", err)
+			}
+		}
+	}
+}
+
+func (api *API) getHatcheryCapacitiesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		capacities, err := workflow.LoadHatcheryCapacities(api.Cache)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, capacities, http.StatusOK)
+	}
+}
+
 func (api *API) getWorkflowJobHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		id, errc := requestVarInt(r, "id")
@@ -268,10 +329,14 @@ func (api *API) postVulnerabilityReportHandler() service.Handler {
 		}
 		defer tx.Rollback() // nolint
 
-		if err := workflow.HandleVulnerabilityReport(ctx, tx, api.Cache, p, nr, report); err != nil {
+		evaluation, err := workflow.HandleVulnerabilityReport(ctx, tx, api.Cache, p, nr, report)
+		if err != nil {
 			return sdk.WrapError(err, "Unable to handle report")
 		}
-		return tx.Commit()
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "Unable to commit transaction")
+		}
+		return service.WriteJSON(w, evaluation, http.StatusOK)
 	}
 }
 
@@ -473,7 +538,7 @@ func (api *API) postWorkflowJobLogsHandler() service.AsynchronousHandler {
 			return sdk.WrapError(err, "Unable to parse body")
 		}
 
-		if err := workflow.AddLog(api.mustDB(), pbJob, &logs, api.Config.Log.StepMaxSize); err != nil {
+		if err := workflow.AddLog(api.mustDB(), api.logStorage(), pbJob, &logs, api.Config.Log.StepMaxSize); err != nil {
 			return sdk.WithStack(err)
 		}
 
@@ -655,6 +720,10 @@ func (api *API) postWorkflowJobStepStatusHandler() service.Handler {
 			log.Warning("postWorkflowJobStepStatusHandler> Unable to load workflow for event: %v", errW)
 			return nil
 		}
+		if sdk.StatusIsTerminated(step.Status) && api.Config.Log.SearchIndex == sdk.LogSearchIndexElasticsearch {
+			go workflow.PushStepLogInElasticSearch(context.Background(), api.mustDB(), api.SharedStorage, nodeRun, *nodeJobRun, int64(step.StepOrder))
+		}
+
 		nodeRun.Translate(r.Header.Get("Accept-Language"))
 		event.PublishWorkflowNodeRun(api.mustDB(), nodeRun, work, nil)
 		return nil
@@ -807,13 +876,15 @@ func (api *API) postWorkflowJobCoverageResultsHandler() service.Handler {
 			return sdk.WrapError(errP, "Cannot load project by nodeJobRunID:%d", id)
 		}
 		if sdk.ErrorIs(errLoad, sdk.ErrNotFound) {
-			if err := workflow.ComputeNewReport(ctx, api.mustDB(), api.Cache, report, wnr, p); err != nil {
+			newReport, err := workflow.ComputeNewReport(ctx, api.mustDB(), api.Cache, report, wnr, p)
+			if err != nil {
 				return sdk.WrapError(err, "Cannot compute new coverage report")
 			}
-
+			existingReport = newReport
 		} else {
 			// update
 			existingReport.Report = report
+			existingReport.Packages = sdk.CoveragePackages(report)
 			if err := workflow.ComputeLatestDefaultBranchReport(ctx, api.mustDB(), api.Cache, p, wnr, &existingReport); err != nil {
 				return sdk.WrapError(err, "Cannot compute default branch coverage report")
 			}
@@ -823,7 +894,10 @@ func (api *API) postWorkflowJobCoverageResultsHandler() service.Handler {
 			}
 		}
 
-		return nil
+		// The worker only needs the trends to check the coverage drop
+		existingReport.Report.Files = nil
+		existingReport.Packages = nil
+		return service.WriteJSON(w, existingReport, http.StatusOK)
 	}
 }
 
@@ -857,6 +931,11 @@ func (api *API) postWorkflowJobTestsResultsHandler() service.Handler {
 			return sdk.WrapError(err, "node run not found: %d", nodeRunJob.WorkflowNodeRunID)
 		}
 
+		// Keep the history of test cases with the original test suite names
+		if err := workflowtest.InsertResults(tx, sdk.NewWorkflowTestCases(*nr, new)); err != nil {
+			return sdk.WrapError(err, "cannot insert test cases history")
+		}
+
 		if nr.Tests == nil {
 			nr.Tests = &venom.Tests{}
 		}
@@ -884,6 +963,7 @@ func (api *API) postWorkflowJobTestsResultsHandler() service.Handler {
 			nr.Tests.TotalOK += ts.Total - ts.Skipped - ts.Failures - ts.Errors
 			nr.Tests.TotalSkipped += ts.Skipped
 		}
+		nr.TestsQuarantined = sdk.CountQuarantinedTests(*nr.Tests)
 
 		if err := workflow.UpdateNodeRun(tx, nr); err != nil {
 			return sdk.WrapError(err, "Cannot update node run")
@@ -1288,3 +1288,18 @@ func TestInsertNewCodeCoverageReport(t *testing.T) {
 
 	assert.Equal(t, coverateReportDefaultBranch.Report.CoveredBranches, covDB.Trend.DefaultBranch.CoveredBranches)
 }
+
+func Test_postHatcheryCapacityHandlerWithoutHatchery(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	u, pass := assets.InsertAdminUser(db)
+	uri := router.GetRoute("POST", api.postHatcheryCapacityHandler, nil)
+	test.NotEmpty(t, uri)
+
+	// Only a hatchery can declare its capacity
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri, sdk.HatcheryCapacity{MaxWorker: 10})
+	rec := httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
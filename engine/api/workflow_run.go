@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -336,6 +338,7 @@ func (api *API) getWorkflowRunHandler() service.Handler {
 			}
 		}
 
+		run.Workflow.HideHookSecrets()
 		run.Translate(r.Header.Get("Accept-Language"))
 
 		return service.WriteJSON(w, run, http.StatusOK)
@@ -1279,15 +1282,144 @@ func (api *API) getWorkflowNodeRunJobStepHandler() service.Handler {
 		if logs != nil {
 			ls = logs
 		}
+
+		// Only the end of the logs moved to the object storage is returned when they are too large
+		parts, err := workflow.LoadStepLogParts(api.mustDB(), runJobID, stepOrder)
+		if err != nil {
+			return err
+		}
+		if len(parts.Sizes) > 0 {
+			maxSize := api.Config.Log.StepMaxSize
+			if maxSize == 0 {
+				maxSize = workflow.DefaultMaxLogSize
+			}
+			var offset int64
+			if size := parts.Size() + int64(len(ls.Val)); size > maxSize {
+				offset = size - maxSize
+			}
+			r := workflow.NewStepLogReader(api.SharedStorage, parts, ls.Val, offset)
+			defer r.Close()
+			btes, err := ioutil.ReadAll(r)
+			if err != nil {
+				return sdk.WrapError(err, "cannot read log for runJob %d on step %d", runJobID, stepOrder)
+			}
+			ls.Val = string(btes)
+			if offset > 0 {
+				ls.Val = workflow.TruncatedLogHeader + ls.Val
+			}
+		}
+
+		lines, err := workflow.LoadStepLogLines(api.mustDB(), runJobID, stepOrder)
+		if err != nil {
+			return err
+		}
+		// The markers are only used to compute the sections and the problems of the step
+		ls.Val = sdk.StripLogMarkers(ls.Val)
 		result := &sdk.BuildState{
 			Status:   sdk.StatusFromString(stepStatus),
 			StepLogs: *ls,
+			Sections: lines.Sections(),
+			Problems: lines.Problems(),
 		}
 
 		return service.WriteJSON(w, result, http.StatusOK)
 	}
 }
 
+func (api *API) getWorkflowNodeRunJobStepLogHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		workflowName := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		nodeRunID, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+		runJobID, err := requestVarInt(r, "runJobId")
+		if err != nil {
+			return err
+		}
+		stepOrder, err := requestVarInt(r, "stepOrder")
+		if err != nil {
+			return err
+		}
+
+		// Check nodeRunID is link to workflow and runJobID to nodeRun
+		nodeRun, err := workflow.LoadNodeRun(api.mustDB(), projectKey, workflowName, number, nodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+		if err != nil {
+			return sdk.WrapError(err, "cannot find nodeRun %d/%d for workflow %s in project %s", nodeRunID, number, workflowName, projectKey)
+		}
+		var found bool
+		for _, s := range nodeRun.Stages {
+			for _, rj := range s.RunJobs {
+				if rj.ID == runJobID {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return sdk.WrapError(sdk.ErrStepNotFound, "cannot find job %d in nodeRun %d/%d for workflow %s in project %s", runJobID, nodeRunID, number, workflowName, projectKey)
+		}
+
+		start, end, hasRange := parseLogRange(r.Header.Get("Range"))
+		reader, size, err := workflow.LoadStepLogReader(api.mustDB(), api.SharedStorage, runJobID, stepOrder, start)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Accept-Ranges", "bytes")
+		if !hasRange {
+			w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+			w.WriteHeader(http.StatusOK)
+			_, err := io.Copy(w, reader)
+			return sdk.WithStack(err)
+		}
+
+		if start >= size {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return nil
+		}
+		if end < 0 || end >= size {
+			end = size - 1
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+		w.Header().Set("Content-Length", strconv.FormatInt(end-start+1, 10))
+		w.WriteHeader(http.StatusPartialContent)
+		_, err = io.CopyN(w, reader, end-start+1)
+		return sdk.WithStack(err)
+	}
+}
+
+// parseLogRange parses a single range "bytes=start-end" or "bytes=start-", end is -1 when not given.
+func parseLogRange(h string) (int64, int64, bool) {
+	if !strings.HasPrefix(h, "bytes=") || strings.Contains(h, ",") {
+		return 0, -1, false
+	}
+	bounds := strings.SplitN(strings.TrimPrefix(h, "bytes="), "-", 2)
+	if len(bounds) != 2 {
+		return 0, -1, false
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(bounds[0]), 10, 64)
+	if err != nil || start < 0 {
+		return 0, -1, false
+	}
+	end := int64(-1)
+	if s := strings.TrimSpace(bounds[1]); s != "" {
+		end, err = strconv.ParseInt(s, 10, 64)
+		if err != nil || end < start {
+			return 0, -1, false
+		}
+	}
+	return start, end, true
+}
+
 func (api *API) getWorkflowRunTagsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
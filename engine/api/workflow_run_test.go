@@ -1610,13 +1610,13 @@ func initGetWorkflowNodeRunJobTest(t *testing.T, api *API, db *gorp.DbMap) (*sdk
 	test.NoError(t, errUJ)
 
 	// Add log
-	test.NoError(t, workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
+	test.NoError(t, workflow.AddLog(api.mustDB(), nil, jobRun, &sdk.Log{
 		StepOrder: 1,
 		Val:       "1234567890",
 	}, 15))
 
 	// Add truncated log
-	test.NoError(t, workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
+	test.NoError(t, workflow.AddLog(api.mustDB(), nil, jobRun, &sdk.Log{
 		StepOrder: 1,
 		Val:       "1234567890",
 	}, 15))
@@ -1664,6 +1664,52 @@ func Test_getWorkflowNodeRunJobStepHandler(t *testing.T) {
 	assert.Equal(t, sdk.StatusBuilding, stepState.Status)
 }
 
+func Test_getWorkflowNodeRunJobStepHandlerWithMarkers(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	u, pass, proj, w1, lastRun, jobRun := initGetWorkflowNodeRunJobTest(t, api, db)
+	nodeRun := &lastRun.WorkflowNodeRuns[w1.WorkflowData.Node.ID][0]
+	nodeRun.Stages[0].RunJobs[0].Job.StepStatus = append(jobRun.Job.StepStatus, sdk.StepStatus{
+		StepOrder: 2,
+		Status:    sdk.StatusFail.String(),
+	})
+	test.NoError(t, workflow.UpdateNodeRun(api.mustDB(), nodeRun))
+
+	// The lines of each chunk are appended to the stored ones
+	for _, v := range []string{"::cds-section::tests\nrunning\n", "::cds-error::tests failed\n::cds-endsection::\n"} {
+		test.NoError(t, workflow.AddLog(api.mustDB(), nil, jobRun, &sdk.Log{StepOrder: 2, Val: v}, 1000))
+	}
+
+	vars := map[string]string{
+		"key":              proj.Key,
+		"permWorkflowName": w1.Name,
+		"number":           fmt.Sprintf("%d", lastRun.Number),
+		"nodeRunID":        fmt.Sprintf("%d", nodeRun.ID),
+		"runJobId":         fmt.Sprintf("%d", jobRun.ID),
+		"stepOrder":        "2",
+	}
+	uri := router.GetRoute("GET", api.getWorkflowNodeRunJobStepHandler, vars)
+	test.NotEmpty(t, uri)
+	req := assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, vars)
+	rec := httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
+
+	stepState := &sdk.BuildState{}
+	test.NoError(t, json.Unmarshal(rec.Body.Bytes(), stepState))
+	// The markers are not displayed in the log
+	assert.Equal(t, "tests\nrunning\ntests failed\n\n", stepState.StepLogs.Val)
+	if assert.Len(t, stepState.Sections, 1) {
+		assert.Equal(t, "tests", stepState.Sections[0].Name)
+		assert.Equal(t, 4, stepState.Sections[0].LastLine)
+	}
+	if assert.Len(t, stepState.Problems, 1) {
+		assert.Equal(t, "tests failed", stepState.Problems[0].Text)
+		assert.Equal(t, 3, stepState.Problems[0].Number)
+	}
+}
+
 func Test_getWorkflowNodeRunJobServiceLogsHandler(t *testing.T) {
 	api, db, router, end := newTestAPI(t)
 	defer end()
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"mime"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/sbom"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) postWorkflowJobSBOMHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "permID")
+		if err != nil {
+			return sdk.WrapError(err, "invalid id")
+		}
+
+		nr, err := workflow.LoadNodeRunByNodeJobID(api.mustDB(), id, workflow.LoadRunOptions{
+			DisableDetailledNodeRun: true,
+		})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load node run for job %d", id)
+		}
+		if nr.ApplicationID == 0 {
+			return sdk.WrapError(sdk.ErrApplicationNotFound, "there is no application linked")
+		}
+
+		var s sdk.WorkflowNodeRunSBOM
+		if err := service.UnmarshalBody(r, &s); err != nil {
+			return sdk.WrapError(err, "unable to read body")
+		}
+
+		s.Components, err = sdk.ParseSBOM(s.Format, s.Document)
+		if err != nil {
+			return err
+		}
+		s.ApplicationID = nr.ApplicationID
+		s.WorkflowID = nr.WorkflowID
+		s.WorkflowRunID = nr.WorkflowRunID
+		s.WorkflowNodeRunID = nr.ID
+		s.Num = nr.Number
+		s.Branch = nr.VCSBranch
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WrapError(err, "unable to start transaction")
+		}
+		defer tx.Rollback() // nolint
+
+		if err := sbom.Insert(tx, &s); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "unable to commit transaction")
+		}
+
+		s.Document = nil
+		return service.WriteJSON(w, s, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowNodeRunSBOMsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		id, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+
+		if _, err := workflow.LoadNodeRun(api.mustDB(), key, name, number, id, workflow.LoadRunOptions{}); err != nil {
+			return sdk.WrapError(err, "unable to load node run %d", id)
+		}
+
+		res, err := sbom.LoadByNodeRunID(ctx, api.mustDB(), id)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowNodeRunSBOMDocumentHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		id, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+		sbomID, err := requestVarInt(r, "sbomID")
+		if err != nil {
+			return err
+		}
+
+		if _, err := workflow.LoadNodeRun(api.mustDB(), key, name, number, id, workflow.LoadRunOptions{}); err != nil {
+			return sdk.WrapError(err, "unable to load node run %d", id)
+		}
+
+		s, err := sbom.LoadDocument(ctx, api.mustDB(), id, sbomID)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		// The name comes from the uploaded document, it is escaped or dropped if it can't be encoded
+		disposition := mime.FormatMediaType("attachment", map[string]string{"filename": s.Name})
+		if disposition == "" {
+			disposition = "attachment"
+		}
+		w.Header().Add("Content-Disposition", disposition)
+		_, err = w.Write(s.Document)
+		return sdk.WithStack(err)
+	}
+}
+
+func (api *API) getSBOMComponentUsagesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		filter := sdk.SBOMComponentFilter{
+			Name:    FormString(r, "name"),
+			Version: FormString(r, "version"),
+			PURL:    FormString(r, "purl"),
+		}
+		if err := filter.IsValid(); err != nil {
+			return err
+		}
+
+		// only the projects readable by the user are searched
+		projs, err := project.LoadAll(ctx, api.mustDB(), api.Cache, deprecatedGetUser(ctx))
+		if err != nil {
+			return err
+		}
+		ids := make([]int64, len(projs))
+		for i := range projs {
+			ids[i] = projs[i].ID
+		}
+
+		res, err := sbom.LoadComponentUsages(ctx, api.mustDB(), ids, filter)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
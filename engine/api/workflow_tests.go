@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflowtest"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getWorkflowTestsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		runs, err := FormInt(r, "runs")
+		if err != nil {
+			return err
+		}
+
+		filter := sdk.WorkflowTestCaseFilter{
+			Branch:     FormString(r, "branch"),
+			Runs:       int64(runs),
+			OnlyFailed: FormBool(r, "failed"),
+			OnlyFlaky:  FormBool(r, "flaky"),
+		}
+
+		res, err := workflowtest.LoadSummary(ctx, api.mustDB(), key, name, filter)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowTestHistoryHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		testSuite := FormString(r, "suite")
+		testName := FormString(r, "name")
+		if testSuite == "" || testName == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "suite and name are mandatory")
+		}
+
+		limit, err := FormInt(r, "limit")
+		if err != nil {
+			return err
+		}
+
+		res, err := workflowtest.LoadHistory(ctx, api.mustDB(), key, name, testSuite, testName, FormString(r, "branch"), int64(limit))
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
@@ -0,0 +1,128 @@
+package workflowtest
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// DefaultRuns is the default number of last runs used to compute the summary of test cases.
+const DefaultRuns = 20
+
+// workflowIDQuery selects the id of a workflow from its project key and name.
+const workflowIDQuery = `(
+	SELECT workflow.id FROM workflow
+	JOIN project ON project.id = workflow.project_id
+	WHERE project.projectkey = $1 AND workflow.name = $2
+)`
+
+// InsertResults stores the results of test cases of a node run, then flags as flaky the
+// test cases that both succeeded and failed on the same commit of the workflow.
+func InsertResults(db gorp.SqlExecutor, tcs []sdk.WorkflowTestCase) error {
+	now := time.Now()
+	hashes := map[string]int64{}
+	for i := range tcs {
+		tcs[i].Created = now
+		if err := gorpmapping.Insert(db, &tcs[i]); err != nil {
+			return sdk.WrapError(err, "unable to insert test case %s", tcs[i].Name)
+		}
+		if tcs[i].VCSHash != "" {
+			hashes[tcs[i].VCSHash] = tcs[i].WorkflowID
+		}
+	}
+
+	for hash, workflowID := range hashes {
+		if err := markFlaky(db, workflowID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func markFlaky(db gorp.SqlExecutor, workflowID int64, hash string) error {
+	query := `
+	UPDATE workflow_test_case SET flaky = true
+	WHERE workflow_id = $1 AND vcs_hash = $2 AND flaky = false
+	AND (test_suite, name) IN (
+		SELECT test_suite, name FROM workflow_test_case
+		WHERE workflow_id = $1 AND vcs_hash = $2 AND status IN ($3, $4)
+		GROUP BY test_suite, name
+		HAVING COUNT(DISTINCT status) > 1
+	)`
+	if _, err := db.Exec(query, workflowID, hash, sdk.StatusSuccess.String(), sdk.StatusFail.String()); err != nil {
+		return sdk.WrapError(err, "unable to mark flaky test cases for commit %s", hash)
+	}
+	return nil
+}
+
+// LoadSummary returns the summary of test cases over the last runs of a workflow.
+func LoadSummary(ctx context.Context, db gorp.SqlExecutor, projectKey, workflowName string, filter sdk.WorkflowTestCaseFilter) ([]sdk.WorkflowTestCaseSummary, error) {
+	if filter.Runs <= 0 {
+		filter.Runs = DefaultRuns
+	}
+
+	query := `
+	WITH runs AS (
+		SELECT DISTINCT run_number FROM workflow_test_case
+		WHERE workflow_id = ` + workflowIDQuery + ` AND ($3 = '' OR vcs_branch = $3)
+		ORDER BY run_number DESC
+		LIMIT $4
+	)
+	SELECT test_suite, name, classname,
+		COUNT(id) AS runs,
+		COUNT(id) FILTER (WHERE status = $5) AS failures,
+		bool_or(flaky) AS flaky,
+		AVG(duration) AS avg_duration,
+		(array_agg(duration ORDER BY run_number DESC, id DESC))[1] AS last_duration,
+		(array_agg(status ORDER BY run_number DESC, id DESC))[1] AS last_status,
+		MAX(run_number) AS last_run_number
+	FROM workflow_test_case
+	WHERE workflow_id = ` + workflowIDQuery + ` AND ($3 = '' OR vcs_branch = $3)
+	AND run_number IN (SELECT run_number FROM runs)
+	GROUP BY test_suite, name, classname`
+
+	var having []string
+	if filter.OnlyFailed {
+		having = append(having, "COUNT(id) FILTER (WHERE status = $5) > 0")
+	}
+	if filter.OnlyFlaky {
+		having = append(having, "bool_or(flaky)")
+	}
+	for i, h := range having {
+		if i == 0 {
+			query += "\n\tHAVING " + h
+		} else {
+			query += " AND " + h
+		}
+	}
+	query += "\n\tORDER BY failures DESC, test_suite, name"
+
+	var res []sdk.WorkflowTestCaseSummary
+	if _, err := db.Select(&res, query, projectKey, workflowName, filter.Branch, filter.Runs, sdk.StatusFail.String()); err != nil {
+		return nil, sdk.WrapError(err, "unable to load test cases summary")
+	}
+	return res, nil
+}
+
+// LoadHistory returns the results of a test case over the last runs of a workflow, the most recent first.
+func LoadHistory(ctx context.Context, db gorp.SqlExecutor, projectKey, workflowName, testSuite, name, branch string, limit int64) ([]sdk.WorkflowTestCase, error) {
+	if limit <= 0 {
+		limit = DefaultRuns
+	}
+	query := gorpmapping.NewQuery(`
+	SELECT * FROM workflow_test_case
+	WHERE workflow_id = ` + workflowIDQuery + `
+	AND test_suite = $3 AND name = $4 AND ($5 = '' OR vcs_branch = $5)
+	ORDER BY run_number DESC, id DESC
+	LIMIT $6`).Args(projectKey, workflowName, testSuite, name, branch, limit)
+
+	var res []sdk.WorkflowTestCase
+	if err := gorpmapping.GetAll(ctx, db, query, &res); err != nil {
+		return nil, sdk.WrapError(err, "unable to load test case history")
+	}
+	return res, nil
+}
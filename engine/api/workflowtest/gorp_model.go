@@ -0,0 +1,10 @@
+package workflowtest
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(sdk.WorkflowTestCase{}, "workflow_test_case", true, "id"))
+}
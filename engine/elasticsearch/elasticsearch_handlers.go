@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -176,3 +177,128 @@ func (s *Service) mergeMetric(newMetric *sdk.Metric, oldMetricValue map[string]f
 		}
 	}
 }
+
+func (s *Service) getLogsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if s.Cfg.ElasticSearch.IndexLogs == "" {
+			return sdk.WrapError(sdk.ErrNotFound, "No logs index found")
+		}
+
+		var req sdk.LogSearchIndexRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return sdk.WrapError(err, "Unable to read request")
+		}
+
+		runs := make([]interface{}, len(req.RunNumbers))
+		for i := range req.RunNumbers {
+			runs[i] = req.RunNumbers[i]
+		}
+		boolQuery := elastic.NewBoolQuery().Filter(
+			elastic.NewTermQuery("workflow_id", req.WorkflowID),
+			elastic.NewTermsQuery("run_number", runs...),
+		)
+		// Regular expressions are applied on each line of the logs, all the logs of the runs are scrolled
+		if req.Regex {
+			docs, err := s.searchLogsWithRegex(ctx, boolQuery, req)
+			if err != nil {
+				if strings.Contains(err.Error(), indexNotFoundException) {
+					log.Warning("elasticsearch> getLogsHandler> %v", err.Error())
+					return service.WriteJSON(w, nil, http.StatusOK)
+				}
+				return sdk.WrapError(err, "Unable to get result")
+			}
+			return service.WriteJSON(w, docs, http.StatusOK)
+		}
+		boolQuery.Must(elastic.NewMatchPhraseQuery("value", req.Query))
+
+		results, errR := esClient.Search().
+			Index(s.Cfg.ElasticSearch.IndexLogs).
+			Type(fmt.Sprintf("%T", sdk.LogSearchDocument{})).
+			Query(boolQuery).
+			Sort("run_number", false).
+			Sort("node_run_id", true).
+			Sort("job_run_id", true).
+			Sort("step_order", true).
+			Size(req.Limit).
+			Do(context.Background())
+		if errR != nil {
+			if strings.Contains(errR.Error(), indexNotFoundException) {
+				log.Warning("elasticsearch> getLogsHandler> %v", errR.Error())
+				return service.WriteJSON(w, nil, http.StatusOK)
+			}
+			return sdk.WrapError(errR, "Unable to get result")
+		}
+
+		docs := make([]sdk.LogSearchDocument, 0, len(results.Hits.Hits))
+		for _, h := range results.Hits.Hits {
+			var d sdk.LogSearchDocument
+			if err := json.Unmarshal(*h.Source, &d); err != nil {
+				return sdk.WrapError(err, "Unable to unmarshal log")
+			}
+			docs = append(docs, d)
+		}
+		return service.WriteJSON(w, docs, http.StatusOK)
+	}
+}
+
+// searchLogsWithRegex scrolls the logs matching the query and returns the ones with lines matching the regular
+// expression of the request, until the limit of hits is reached.
+func (s *Service) searchLogsWithRegex(ctx context.Context, query elastic.Query, req sdk.LogSearchIndexRequest) ([]sdk.LogSearchDocument, error) {
+	scroll := esClient.Scroll(s.Cfg.ElasticSearch.IndexLogs).
+		Type(fmt.Sprintf("%T", sdk.LogSearchDocument{})).
+		Query(query).
+		Sort("run_number", false).
+		Sort("node_run_id", true).
+		Sort("job_run_id", true).
+		Sort("step_order", true).
+		Size(100)
+	defer scroll.Clear(context.Background()) // nolint
+
+	docs := []sdk.LogSearchDocument{}
+	var nbHits int
+	for nbHits < req.Limit {
+		results, err := scroll.Do(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range results.Hits.Hits {
+			var d sdk.LogSearchDocument
+			if err := json.Unmarshal(*h.Source, &d); err != nil {
+				return nil, sdk.WrapError(err, "Unable to unmarshal log")
+			}
+			hits := req.Hits(d, req.Limit-nbHits)
+			if len(hits) == 0 {
+				continue
+			}
+			docs = append(docs, d)
+			nbHits += len(hits)
+			if nbHits >= req.Limit {
+				break
+			}
+		}
+	}
+	return docs, nil
+}
+
+func (s *Service) postLogHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if s.Cfg.ElasticSearch.IndexLogs == "" {
+			return sdk.WrapError(sdk.ErrNotFound, "postLogHandler> No logs index found")
+		}
+
+		var doc sdk.LogSearchDocument
+		if err := service.UnmarshalBody(r, &doc); err != nil {
+			return sdk.WrapError(err, "Unable to read body")
+		}
+
+		id := fmt.Sprintf("%d-%d", doc.JobRunID, doc.StepOrder)
+		_, errI := esClient.Index().Index(s.Cfg.ElasticSearch.IndexLogs).Id(id).Type(fmt.Sprintf("%T", sdk.LogSearchDocument{})).BodyJson(doc).Do(context.Background())
+		if errI != nil {
+			return sdk.WrapError(errI, "Unable to insert log")
+		}
+		return nil
+	}
+}
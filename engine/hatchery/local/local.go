@@ -67,6 +67,13 @@ func (h *HatcheryLocal) Status() sdk.MonitoringStatus {
 			Value:     fmt.Sprintf("%d/%d", len(h.WorkersStarted()), h.Config.Provision.MaxWorker),
 			Status:    sdk.MonitoringStatusOK,
 		})
+		if h.Config.Sandbox.Enabled {
+			m.Lines = append(m.Lines, sdk.MonitoringStatusLine{
+				Component: "Sandbox",
+				Value:     fmt.Sprintf("cpus:%v memory:%dMo isolateNetwork:%t", h.Config.Sandbox.CPUs, h.Config.Sandbox.Memory, h.Config.Sandbox.IsolateNetwork),
+				Status:    sdk.MonitoringStatusOK,
+			})
+		}
 	}
 	return m
 }
@@ -99,6 +106,15 @@ func (h *HatcheryLocal) CheckConfiguration(cfg interface{}) error {
 	} else if err != nil {
 		return fmt.Errorf("Invalid basedir: %v", err)
 	}
+
+	if hconfig.Sandbox.Enabled {
+		if hconfig.Sandbox.CPUs < 0 || hconfig.Sandbox.Memory < 0 {
+			return fmt.Errorf("Invalid sandbox limits: cpus and memory must be positive")
+		}
+		if (hconfig.Sandbox.CPUs > 0 || hconfig.Sandbox.Memory > 0) && hconfig.Sandbox.CgroupRoot == "" {
+			return fmt.Errorf("Invalid sandbox cgroupRoot: it's mandatory to set cpus or memory limits")
+		}
+	}
 	return nil
 }
 
@@ -299,10 +315,36 @@ func (h *HatcheryLocal) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 		}
 	}
 
+	started, exited := func() {}, func() {}
+	if h.Config.Sandbox.Enabled {
+		var err error
+		started, exited, err = h.sandboxCmd(cmd, wName, basedir)
+		if err != nil {
+			log.Error("hatchery> local> unable to sandbox worker %s: %v", wName, err)
+			if err := os.RemoveAll(basedir); err != nil {
+				log.Warning("hatchery> local> unable to remove workspace %s: %v", basedir, err)
+			}
+			return "", err
+		}
+		// The workspace is disposable, it's removed with the worker
+		if !h.Config.Sandbox.KeepWorkspace {
+			removeSandbox := exited
+			exited = func() {
+				removeSandbox()
+				if err := os.RemoveAll(basedir); err != nil {
+					log.Warning("hatchery> local> unable to remove workspace %s: %v", basedir, err)
+				}
+			}
+		}
+	}
+
 	if err := cmd.Start(); err != nil {
 		log.Error("hatchery> local> %v", err)
+		started()
+		exited()
 		return "", err
 	}
+	started()
 
 	log.Debug("worker %s has been spawned by %s", wName, h.Name)
 
@@ -314,6 +356,7 @@ func (h *HatcheryLocal) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 		if err := cmd.Wait(); err != nil {
 			log.Error("hatchery> local> %v", err)
 		}
+		exited()
 	}()
 
 	return wName, nil
@@ -363,6 +406,9 @@ func (h *HatcheryLocal) WorkersStartedByModel(model *sdk.Model) int {
 // Init register local hatchery with its worker model
 func (h *HatcheryLocal) Init() error {
 	h.workers = make(map[string]workerCmd)
+	if err := h.initSandbox(); err != nil {
+		return fmt.Errorf("Cannot init sandbox: %v", err)
+	}
 	sdk.GoRoutine(context.Background(), "startKillAwolWorkerRoutine", h.startKillAwolWorkerRoutine)
 	return nil
 }
@@ -0,0 +1,335 @@
+package local
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	cgroupCPUPeriod = 100000
+	// sandboxInitArg is the name given to the hatchery binary when it's re-executed to prepare the root
+	// filesystem of a sandboxed worker, before executing the worker itself
+	sandboxInitArg = "cds-hatchery-local-sandbox-init"
+	// sandboxInitEnv is the environment variable giving the sandbox configuration to the re-executed hatchery
+	sandboxInitEnv = "HATCHERY_LOCAL_SANDBOX"
+)
+
+// flags of a mounted filesystem returned by statfs
+const (
+	stNoSUID     = 0x2
+	stNoDev      = 0x4
+	stNoExec     = 0x8
+	stNoAtime    = 0x400
+	stNoDirAtime = 0x800
+	stRelAtime   = 0x1000
+)
+
+// defaultSandboxReadOnlyPaths are the host paths visible by default, read only, in the sandbox of a worker
+var defaultSandboxReadOnlyPaths = []string{
+	"/bin", "/sbin", "/usr", "/lib", "/lib32", "/lib64",
+	"/etc/alternatives", "/etc/ssl", "/etc/ca-certificates", "/etc/pki",
+	"/etc/resolv.conf", "/etc/hosts", "/etc/nsswitch.conf", "/etc/passwd", "/etc/group", "/etc/localtime",
+}
+
+// sandboxDevices are the devices bound in the sandbox of a worker
+var sandboxDevices = []string{"/dev/null", "/dev/zero", "/dev/full", "/dev/random", "/dev/urandom", "/dev/tty"}
+
+// sandboxRootfs describes the root filesystem of a sandboxed worker
+type sandboxRootfs struct {
+	Root          string   `json:"root"`
+	Workspace     string   `json:"workspace"`
+	ReadOnlyPaths []string `json:"read_only_paths"`
+}
+
+func init() {
+	if len(os.Args) > 0 && os.Args[0] == sandboxInitArg {
+		if err := sandboxInit(); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to start sandboxed worker: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// initSandbox checks that the cgroup root exists and enables cpu and memory controllers for workers cgroups
+func (h *HatcheryLocal) initSandbox() error {
+	if !h.Config.Sandbox.Enabled || !h.sandboxNeedCgroup() {
+		return nil
+	}
+
+	if err := os.MkdirAll(h.Config.Sandbox.CgroupRoot, os.FileMode(0755)); err != nil {
+		return fmt.Errorf("unable to create cgroup root %s: %v", h.Config.Sandbox.CgroupRoot, err)
+	}
+
+	controllers := filepath.Join(h.Config.Sandbox.CgroupRoot, "cgroup.subtree_control")
+	if err := ioutil.WriteFile(controllers, []byte("+cpu +memory"), os.FileMode(0644)); err != nil {
+		return fmt.Errorf("unable to enable cpu and memory controllers on %s: %v", h.Config.Sandbox.CgroupRoot, err)
+	}
+	return nil
+}
+
+func (h *HatcheryLocal) sandboxNeedCgroup() bool {
+	return h.Config.Sandbox.CPUs > 0 || h.Config.Sandbox.Memory > 0
+}
+
+func (h *HatcheryLocal) sandboxReadOnlyPaths() []string {
+	if len(h.Config.Sandbox.ReadOnlyPaths) > 0 {
+		return h.Config.Sandbox.ReadOnlyPaths
+	}
+	return defaultSandboxReadOnlyPaths
+}
+
+// sandboxCmd configures the command to be started in new namespaces and in a dedicated cgroup.
+// The command is started through the hatchery binary, which builds a private root filesystem with the
+// workspace and read only host paths, then executes the worker inside.
+// The returned func must be called once the command is started, the second one when the process has exited.
+func (h *HatcheryLocal) sandboxCmd(cmd *exec.Cmd, wName, workspace string) (func(), func(), error) {
+	binary, err := filepath.Abs(cmd.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to resolve worker binary %s: %v", cmd.Path, err)
+	}
+
+	root := workspace + "-rootfs"
+	if err := os.Mkdir(root, os.FileMode(0700)); err != nil {
+		return nil, nil, fmt.Errorf("unable to create sandbox root %s: %v", root, err)
+	}
+	removeRoot := func() {
+		if err := os.Remove(root); err != nil {
+			log.Warning("hatchery> local> unable to remove sandbox root %s: %v", root, err)
+		}
+	}
+
+	rootfs, err := json.Marshal(sandboxRootfs{
+		Root:          root,
+		Workspace:     workspace,
+		ReadOnlyPaths: append(h.sandboxReadOnlyPaths(), binary),
+	})
+	if err != nil {
+		removeRoot()
+		return nil, nil, fmt.Errorf("unable to marshal sandbox configuration: %v", err)
+	}
+	cmd.Env = append(cmd.Env, sandboxInitEnv+"="+string(rootfs))
+	cmd.Args = append([]string{sandboxInitArg, binary}, cmd.Args[1:]...)
+	cmd.Path = "/proc/self/exe"
+	cmd.Dir = workspace
+
+	cloneFlags := syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+	if h.Config.Sandbox.IsolateNetwork {
+		cloneFlags |= syscall.CLONE_NEWNET
+	}
+
+	// The worker keeps the uid and gid of the hatchery inside its user namespace
+	// so it has no more privileges than the hatchery itself on the workspace
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: uintptr(cloneFlags),
+		Setpgid:    true,
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1},
+		},
+		GidMappingsEnableSetgroups: false,
+	}
+
+	if !h.sandboxNeedCgroup() {
+		return func() {}, removeRoot, nil
+	}
+
+	cgroupDir := filepath.Join(h.Config.Sandbox.CgroupRoot, wName)
+	if err := os.Mkdir(cgroupDir, os.FileMode(0755)); err != nil {
+		removeRoot()
+		return nil, nil, fmt.Errorf("unable to create cgroup %s: %v", cgroupDir, err)
+	}
+	cleanup := func() {
+		removeRoot()
+		if err := os.Remove(cgroupDir); err != nil {
+			log.Warning("hatchery> local> unable to remove cgroup %s: %v", cgroupDir, err)
+		}
+	}
+
+	if h.Config.Sandbox.Memory > 0 {
+		memoryMax := fmt.Sprintf("%d", h.Config.Sandbox.Memory*1024*1024)
+		if err := ioutil.WriteFile(filepath.Join(cgroupDir, "memory.max"), []byte(memoryMax), os.FileMode(0644)); err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("unable to set memory limit on cgroup %s: %v", cgroupDir, err)
+		}
+	}
+
+	if h.Config.Sandbox.CPUs > 0 {
+		cpuMax := fmt.Sprintf("%d %d", int64(h.Config.Sandbox.CPUs*cgroupCPUPeriod), cgroupCPUPeriod)
+		if err := ioutil.WriteFile(filepath.Join(cgroupDir, "cpu.max"), []byte(cpuMax), os.FileMode(0644)); err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("unable to set cpu limit on cgroup %s: %v", cgroupDir, err)
+		}
+	}
+
+	// The process is directly cloned into its cgroup, so the limits apply from its first instruction
+	fd, err := os.Open(cgroupDir)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("unable to open cgroup %s: %v", cgroupDir, err)
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(fd.Fd())
+
+	started := func() {
+		fd.Close() // nolint
+	}
+	return started, cleanup, nil
+}
+
+// sandboxInit runs in the namespaces of a sandboxed worker. It mounts a private root filesystem containing
+// only the workspace, the read only host paths and a few devices, pivots into it and executes the worker.
+func sandboxInit() error {
+	var rootfs sandboxRootfs
+	if err := json.Unmarshal([]byte(os.Getenv(sandboxInitEnv)), &rootfs); err != nil {
+		return fmt.Errorf("invalid sandbox configuration: %v", err)
+	}
+	if len(os.Args) < 2 {
+		return fmt.Errorf("missing worker binary")
+	}
+	binary, args := os.Args[1], append([]string{os.Args[1]}, os.Args[2:]...)
+
+	// The mounts of the sandbox must not propagate to the host
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("unable to make mounts private: %v", err)
+	}
+	if err := syscall.Mount("tmpfs", rootfs.Root, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=0755"); err != nil {
+		return fmt.Errorf("unable to mount sandbox root: %v", err)
+	}
+
+	// tmp and proc are mounted first, the workspace can be in the temporary directory of the host
+	tmp := filepath.Join(rootfs.Root, "tmp")
+	if err := os.MkdirAll(tmp, os.FileMode(0755)); err != nil {
+		return fmt.Errorf("unable to create %s: %v", tmp, err)
+	}
+	if err := syscall.Mount("tmpfs", tmp, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "mode=1777"); err != nil {
+		return fmt.Errorf("unable to mount /tmp: %v", err)
+	}
+	proc := filepath.Join(rootfs.Root, "proc")
+	if err := os.MkdirAll(proc, os.FileMode(0755)); err != nil {
+		return fmt.Errorf("unable to create %s: %v", proc, err)
+	}
+	if err := syscall.Mount("proc", proc, "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, ""); err != nil {
+		// proc can't be mounted when the one of the host is partly hidden, as in a container
+		if err := syscall.Mount("/proc", proc, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+			return fmt.Errorf("unable to mount /proc: %v", err)
+		}
+	}
+
+	for _, p := range rootfs.ReadOnlyPaths {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			continue
+		}
+		if err := sandboxBind(rootfs.Root, p, true); err != nil {
+			return err
+		}
+	}
+	for _, d := range sandboxDevices {
+		if _, err := os.Stat(d); err != nil {
+			continue
+		}
+		if err := sandboxBind(rootfs.Root, d, false); err != nil {
+			return err
+		}
+	}
+	if err := sandboxBind(rootfs.Root, rootfs.Workspace, false); err != nil {
+		return err
+	}
+
+	// The host filesystem is detached once the worker is in its new root
+	oldRoot := filepath.Join(rootfs.Root, ".oldroot")
+	if err := os.Mkdir(oldRoot, os.FileMode(0700)); err != nil {
+		return fmt.Errorf("unable to create %s: %v", oldRoot, err)
+	}
+	if err := syscall.PivotRoot(rootfs.Root, oldRoot); err != nil {
+		return fmt.Errorf("unable to pivot root: %v", err)
+	}
+	if err := os.Chdir("/"); err != nil {
+		return err
+	}
+	if err := syscall.Unmount("/.oldroot", syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("unable to detach host filesystem: %v", err)
+	}
+	if err := os.Remove("/.oldroot"); err != nil {
+		return err
+	}
+	if err := syscall.Mount("", "/", "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY|syscall.MS_NOSUID|syscall.MS_NODEV, ""); err != nil {
+		return fmt.Errorf("unable to remount sandbox root read only: %v", err)
+	}
+	if err := os.Chdir(rootfs.Workspace); err != nil {
+		return err
+	}
+
+	env := make([]string, 0, len(os.Environ()))
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, sandboxInitEnv+"=") || strings.HasPrefix(e, "HOME=") {
+			continue
+		}
+		env = append(env, e)
+	}
+	env = append(env, "HOME="+rootfs.Workspace)
+	return syscall.Exec(binary, args, env)
+}
+
+// sandboxBind binds a host path at the same path in the sandbox root
+func sandboxBind(root, p string, readOnly bool) error {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	// The target can already exist in a bound path, as the worker binary in /usr/bin
+	target := filepath.Join(root, p)
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		if fi.IsDir() {
+			if err := os.MkdirAll(target, os.FileMode(0755)); err != nil {
+				return fmt.Errorf("unable to create %s: %v", target, err)
+			}
+		} else {
+			if err := os.MkdirAll(filepath.Dir(target), os.FileMode(0755)); err != nil {
+				return fmt.Errorf("unable to create %s: %v", filepath.Dir(target), err)
+			}
+			if err := ioutil.WriteFile(target, nil, os.FileMode(0644)); err != nil {
+				return fmt.Errorf("unable to create %s: %v", target, err)
+			}
+		}
+	}
+
+	if err := syscall.Mount(p, target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return fmt.Errorf("unable to bind %s: %v", p, err)
+	}
+	if !readOnly {
+		return nil
+	}
+
+	// The flags of the host mount are locked in the user namespace, they must be kept on remount
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(p, &st); err != nil {
+		return fmt.Errorf("unable to stat %s: %v", p, err)
+	}
+	flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY)
+	for stFlag, msFlag := range map[int64]uintptr{
+		stNoSUID:     syscall.MS_NOSUID,
+		stNoDev:      syscall.MS_NODEV,
+		stNoExec:     syscall.MS_NOEXEC,
+		stNoAtime:    syscall.MS_NOATIME,
+		stNoDirAtime: syscall.MS_NODIRATIME,
+		stRelAtime:   syscall.MS_RELATIME,
+	} {
+		if int64(st.Flags)&stFlag != 0 {
+			flags |= msFlag
+		}
+	}
+	if err := syscall.Mount("", target, "", flags, ""); err != nil {
+		return fmt.Errorf("unable to remount %s read only: %v", p, err)
+	}
+	return nil
+}
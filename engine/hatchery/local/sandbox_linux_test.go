@@ -0,0 +1,124 @@
+package local
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxCmd(t *testing.T) {
+	root, err := ioutil.TempDir("", "cds-hatchery-local")
+	require.NoError(t, err)
+	defer os.RemoveAll(root) // nolint
+
+	h := &HatcheryLocal{}
+	h.Config.Sandbox = SandboxConfiguration{
+		Enabled:        true,
+		IsolateNetwork: true,
+		CgroupRoot:     root,
+		CPUs:           1.5,
+		Memory:         512,
+	}
+	require.NoError(t, h.initSandbox())
+	controllers, err := ioutil.ReadFile(filepath.Join(root, "cgroup.subtree_control"))
+	require.NoError(t, err)
+	assert.Equal(t, "+cpu +memory", string(controllers))
+
+	workspace := filepath.Join(root, "workspace")
+	require.NoError(t, os.Mkdir(workspace, os.FileMode(0700)))
+
+	cmd := exec.Command("/bin/true", "register")
+	started, exited, err := h.sandboxCmd(cmd, "worker-1", workspace)
+	require.NoError(t, err)
+
+	// The worker is executed by the hatchery binary once its root filesystem is ready
+	assert.Equal(t, "/proc/self/exe", cmd.Path)
+	assert.Equal(t, []string{sandboxInitArg, "/bin/true", "register"}, cmd.Args)
+	assert.Equal(t, workspace, cmd.Dir)
+	assert.DirExists(t, workspace+"-rootfs")
+
+	flags := cmd.SysProcAttr.Cloneflags
+	for _, f := range []uintptr{syscall.CLONE_NEWUSER, syscall.CLONE_NEWNS, syscall.CLONE_NEWPID, syscall.CLONE_NEWIPC, syscall.CLONE_NEWUTS, syscall.CLONE_NEWNET} {
+		assert.NotZero(t, flags&f)
+	}
+	assert.Equal(t, os.Getuid(), cmd.SysProcAttr.UidMappings[0].HostID)
+
+	cgroupDir := filepath.Join(root, "worker-1")
+	memoryMax, err := ioutil.ReadFile(filepath.Join(cgroupDir, "memory.max"))
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(512*1024*1024), string(memoryMax))
+	cpuMax, err := ioutil.ReadFile(filepath.Join(cgroupDir, "cpu.max"))
+	require.NoError(t, err)
+	assert.Equal(t, "150000 100000", string(cpuMax))
+
+	// The process is cloned into its cgroup
+	assert.True(t, cmd.SysProcAttr.UseCgroupFD)
+	assert.NotZero(t, cmd.SysProcAttr.CgroupFD)
+	started()
+	exited()
+	_, err = os.Stat(workspace + "-rootfs")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSandboxCmdWithoutCgroup(t *testing.T) {
+	h := &HatcheryLocal{}
+	h.Config.Sandbox = SandboxConfiguration{Enabled: true}
+	require.NoError(t, h.initSandbox())
+
+	workspace, err := ioutil.TempDir("", "cds-hatchery-local")
+	require.NoError(t, err)
+	defer os.RemoveAll(workspace) // nolint
+
+	cmd := exec.Command("true")
+	started, exited, err := h.sandboxCmd(cmd, "worker-1", workspace)
+	require.NoError(t, err)
+	assert.Zero(t, cmd.SysProcAttr.Cloneflags&syscall.CLONE_NEWNET)
+	assert.False(t, cmd.SysProcAttr.UseCgroupFD)
+	started()
+	exited()
+	_, err = os.Stat(workspace + "-rootfs")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSandboxCmdFilesystem(t *testing.T) {
+	if err := exec.Command("unshare", "-Urm", "true").Run(); err != nil {
+		t.Skipf("user and mount namespaces are not available: %v", err)
+	}
+
+	workspace, err := ioutil.TempDir("", "cds-hatchery-local")
+	require.NoError(t, err)
+	defer os.RemoveAll(workspace) // nolint
+	secret, err := ioutil.TempFile("", "cds-hatchery-local-secret")
+	require.NoError(t, err)
+	secret.Close()                 // nolint
+	defer os.Remove(secret.Name()) // nolint
+
+	h := &HatcheryLocal{}
+	h.Config.Sandbox = SandboxConfiguration{Enabled: true}
+	require.NoError(t, h.initSandbox())
+
+	// The worker can write in its workspace, but can't write in the read only paths nor read the other host files
+	script := fmt.Sprintf("echo ok > %s/result && ! touch /usr/cds-sandbox 2> /dev/null && ! test -e %s && test \"$HOME\" = %s && pwd", workspace, secret.Name(), workspace)
+	cmd := exec.Command("/bin/sh", "-c", script)
+	started, exited, err := h.sandboxCmd(cmd, "worker-1", workspace)
+	require.NoError(t, err)
+	out, err := cmd.CombinedOutput()
+	started()
+	exited()
+	require.NoError(t, err, string(out))
+	assert.Equal(t, workspace+"\n", string(out))
+
+	result, err := ioutil.ReadFile(filepath.Join(workspace, "result"))
+	require.NoError(t, err)
+	assert.Equal(t, "ok\n", string(result))
+	_, err = os.Stat(workspace + "-rootfs")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+// +build !linux
+
+package local
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func (h *HatcheryLocal) initSandbox() error {
+	if h.Config.Sandbox.Enabled {
+		return fmt.Errorf("sandbox mode is only available on linux")
+	}
+	return nil
+}
+
+func (h *HatcheryLocal) sandboxCmd(cmd *exec.Cmd, wName, workspace string) (func(), func(), error) {
+	return nil, nil, fmt.Errorf("sandbox mode is only available on linux")
+}
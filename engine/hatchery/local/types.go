@@ -13,8 +13,20 @@ import (
 // HatcheryConfiguration is the configuration for local hatchery
 type HatcheryConfiguration struct {
 	hatchery.CommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration" json:"commonConfiguration"`
-	Basedir                      string `mapstructure:"basedir" toml:"basedir" default:"/tmp" comment:"BaseDir for worker workspace" json:"basedir"`
-	NbProvision                  int    `mapstructure:"nbProvision" toml:"nbProvision" default:"1" comment:"Nb Workers to provision" json:"nbProvision"`
+	Basedir                      string               `mapstructure:"basedir" toml:"basedir" default:"/tmp" comment:"BaseDir for worker workspace" json:"basedir"`
+	NbProvision                  int                  `mapstructure:"nbProvision" toml:"nbProvision" default:"1" comment:"Nb Workers to provision" json:"nbProvision"`
+	Sandbox                      SandboxConfiguration `mapstructure:"sandbox" toml:"sandbox" comment:"Run each worker inside an isolated sandbox (Linux only)" json:"sandbox"`
+}
+
+// SandboxConfiguration is the configuration of the sandboxed mode of the local hatchery
+type SandboxConfiguration struct {
+	Enabled        bool     `mapstructure:"enabled" toml:"enabled" default:"false" commented:"true" comment:"if true: each worker runs in its own user, mount, pid, ipc and uts namespaces, with a private root filesystem" json:"enabled"`
+	IsolateNetwork bool     `mapstructure:"isolateNetwork" toml:"isolateNetwork" default:"false" commented:"true" comment:"if true: each worker also runs in its own network namespace. The worker must still be able to reach the CDS API" json:"isolateNetwork"`
+	CgroupRoot     string   `mapstructure:"cgroupRoot" toml:"cgroupRoot" default:"/sys/fs/cgroup/cds-hatchery-local" commented:"true" comment:"cgroup v2 directory in which a cgroup is created for each worker. Must be writable by the hatchery" json:"cgroupRoot"`
+	CPUs           float64  `mapstructure:"cpus" toml:"cpus" default:"0" commented:"true" comment:"Max CPUs usable by each worker, 0 means unlimited" json:"cpus"`
+	Memory         int64    `mapstructure:"memory" toml:"memory" default:"0" commented:"true" comment:"Max memory usable by each worker in Mo, 0 means unlimited" json:"memory"`
+	ReadOnlyPaths  []string `mapstructure:"readOnlyPaths" toml:"readOnlyPaths" commented:"true" comment:"Host paths visible read only by the workers, in addition to their workspace. Default: /bin, /sbin, /usr, /lib*, and the network, certificates and users files of /etc" json:"readOnlyPaths"`
+	KeepWorkspace  bool     `mapstructure:"keepWorkspace" toml:"keepWorkspace" default:"false" commented:"true" comment:"if true: the working directory of a sandboxed worker is not removed when the worker exits" json:"keepWorkspace"`
 }
 
 // HatcheryLocal implements HatcheryMode interface for local usage
@@ -2,6 +2,7 @@ package hooks
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
@@ -44,12 +45,89 @@ func (d *dao) SaveTask(r *sdk.Task) {
 
 func (d *dao) DeleteTask(r *sdk.Task) {
 	d.store.SetRemove(rootKey, r.UUID, r)
+	d.store.Delete(cache.Key(connectionRootKey, r.UUID))
+	d.store.Delete(cache.Key(leaseRootKey, r.UUID))
+	d.store.Delete(cache.Key(polledHeadsRootKey, r.UUID))
 	execs, _ := d.FindAllTaskExecutions(r)
 	for _, e := range execs {
 		d.DeleteTaskExecution(&e)
 	}
 }
 
+// LeaseTask acquires or renews the lease of a task for an instance. It returns false if the task is owned by
+// another instance, and true as second value if the instance already owned the task.
+func (d *dao) LeaseTask(uuid, instance string, ttl int) (bool, bool) {
+	key := cache.Key(leaseRootKey, uuid)
+	// The lease is renewed and released only by its owner, atomically: a lease which expired and was taken by
+	// another instance can't be extended or deleted by its previous owner
+	if d.store.UpdateTTLIfValue(key, instance, ttl) {
+		return true, true
+	}
+	if d.store.SetNX(key, instance, ttl) {
+		return true, false
+	}
+	return false, false
+}
+
+// ReleaseTask releases the lease of a task if it is owned by the instance
+func (d *dao) ReleaseTask(uuid, instance string) {
+	d.store.DeleteIfValue(cache.Key(leaseRootKey, uuid), instance)
+}
+
+// RevokeTaskLease removes the lease of a task, its owner will restart it
+func (d *dao) RevokeTaskLease(uuid string) {
+	d.store.Delete(cache.Key(leaseRootKey, uuid))
+}
+
+// FindTaskLease returns the instance which owns a task
+func (d *dao) FindTaskLease(uuid string) string {
+	var owner string
+	d.store.Get(cache.Key(leaseRootKey, uuid), &owner)
+	return owner
+}
+
+// SaveVCSRateLimit shares a rate limit between the pollers of all the instances, until its reset
+func (d *dao) SaveVCSRateLimit(budget string, r *sdk.VCSRateLimit) {
+	ttl := int(r.Reset - time.Now().Unix())
+	if ttl < 60 {
+		ttl = 60
+	}
+	d.store.SetWithTTL(cache.Key(rateLimitRootKey, budget), r, ttl)
+}
+
+// FindVCSRateLimit returns the last known state of a rate limit
+func (d *dao) FindVCSRateLimit(budget string) *sdk.VCSRateLimit {
+	r := &sdk.VCSRateLimit{}
+	if d.store.Get(cache.Key(rateLimitRootKey, budget), r) {
+		return r
+	}
+	return nil
+}
+
+// FindPolledHeads returns the last commit polled on each branch of the repository of a poller
+func (d *dao) FindPolledHeads(uuid string) map[string]string {
+	heads := map[string]string{}
+	d.store.Get(cache.Key(polledHeadsRootKey, uuid), &heads)
+	return heads
+}
+
+// SavePolledHeads saves the last commit polled on each branch of the repository of a poller
+func (d *dao) SavePolledHeads(uuid string, heads map[string]string) {
+	d.store.Set(cache.Key(polledHeadsRootKey, uuid), heads)
+}
+
+func (d *dao) FindTaskConnection(uuid string) *sdk.TaskConnection {
+	c := &sdk.TaskConnection{}
+	if d.store.Get(cache.Key(connectionRootKey, uuid), c) {
+		return c
+	}
+	return nil
+}
+
+func (d *dao) SaveTaskConnection(uuid string, c *sdk.TaskConnection) {
+	d.store.Set(cache.Key(connectionRootKey, uuid), c)
+}
+
 func (d *dao) SaveTaskExecution(r *sdk.TaskExecution) {
 	setKey := cache.Key(executionRootKey, r.Type, r.UUID)
 	execKey := fmt.Sprintf("%d", r.Timestamp)
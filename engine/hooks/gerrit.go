@@ -190,7 +190,8 @@ func (s *Service) ComputeGerritStreamEvent(ctx context.Context, vcsServer string
 			}
 
 			for _, h := range hooks {
-				if !sdk.IsInArray(e.Type, h.Events) {
+				// all instances listen to the stream, the event is handled by the owner of the task
+				if !sdk.IsInArray(e.Type, h.Events) || !s.ownsTask(h.UUID) {
 					continue
 				}
 
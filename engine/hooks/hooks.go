@@ -11,6 +11,7 @@ import (
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -78,6 +79,7 @@ func (s *Service) Serve(c context.Context) error {
 
 	//Init the DAO
 	s.Dao = dao{s.Cache}
+	s.instance = s.Cfg.Name + "-" + sdk.UUID()
 
 	if !s.Cfg.Disable {
 		//Start all the tasks
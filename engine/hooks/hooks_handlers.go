@@ -59,6 +59,15 @@ func (s *Service) webhookHandler() service.Handler {
 			},
 		}
 
+		//Check the signature of the request, rejected requests are kept in the executions history
+		if err := checkWebHookSignature(webHook, r.Header, req); err != nil {
+			exec.Status = TaskExecutionRejected
+			exec.LastError = err.Error()
+			exec.ProcessingTimestamp = time.Now().UnixNano()
+			s.Dao.SaveTaskExecution(exec)
+			return sdk.WrapError(err, "webhook %s rejected", webHook.UUID)
+		}
+
 		//Save the web hook execution
 		s.Dao.SaveTaskExecution(exec)
 
@@ -205,6 +214,7 @@ func (s *Service) getTasksHandler() service.Handler {
 			}
 			tasks[i].NbExecutionsTotal = len(m[t.UUID])
 			tasks[i].NbExecutionsTodo = nbTodo
+			tasks[i].Connection = s.Dao.FindTaskConnection(t.UUID)
 		}
 
 		for k, p := range sortParams {
@@ -274,6 +284,7 @@ func (s *Service) getTaskHandler() service.Handler {
 		}
 
 		t.Executions = execs
+		t.Connection = s.Dao.FindTaskConnection(t.UUID)
 
 		return service.WriteJSON(w, t, http.StatusOK)
 	}
@@ -511,11 +522,21 @@ func (s *Service) Status() sdk.MonitoringStatus {
 		log.Error("Status> Unable to find all tasks: %v", err)
 	}
 
+	// Number of tasks owned by each instance
+	nbTasksByInstance := map[string]int{s.instance: 0}
+	var nbTasksWithoutOwner int
+
 	for _, t := range tasks {
 		if t.Type == TypeKafka {
 			nbHooksKafkaTotal++
 		}
 
+		if owner := s.Dao.FindTaskLease(t.UUID); owner != "" {
+			nbTasksByInstance[owner]++
+		} else {
+			nbTasksWithoutOwner++
+		}
+
 		if t.Stopped {
 			m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Task Stopped", Value: t.UUID, Status: sdk.MonitoringStatusWarn})
 		}
@@ -557,6 +578,38 @@ func (s *Service) Status() sdk.MonitoringStatus {
 
 	m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Hook Kafka", Value: fmt.Sprintf("%d", nbHooksKafkaTotal), Status: status})
 
+	instances := make([]string, 0, len(nbTasksByInstance))
+	for instance := range nbTasksByInstance {
+		instances = append(instances, instance)
+	}
+	sort.Strings(instances)
+	for _, instance := range instances {
+		component := "Tasks " + instance
+		if instance == s.instance {
+			component += " (this instance)"
+		}
+		m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: component, Value: fmt.Sprintf("%d", nbTasksByInstance[instance]), Status: sdk.MonitoringStatusOK})
+	}
+	// Tasks are without owner until an instance takes them over, after the expiration of the lease of a dead instance
+	statusOwner := sdk.MonitoringStatusOK
+	if nbTasksWithoutOwner > 0 {
+		statusOwner = sdk.MonitoringStatusWarn
+	}
+	m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Tasks without owner", Value: fmt.Sprintf("%d", nbTasksWithoutOwner), Status: statusOwner})
+
+	// Rate limits shared by the repository pollers of each vcs server and token owner
+	nbPollers := countPollers(tasks)
+	budgets := make([]string, 0, len(nbPollers))
+	for budget := range nbPollers {
+		budgets = append(budgets, budget)
+	}
+	sort.Strings(budgets)
+	for _, budget := range budgets {
+		if rateLimit := s.Dao.FindVCSRateLimit(budget); rateLimit != nil {
+			m.Lines = append(m.Lines, rateLimitStatus(budget, rateLimit, nbPollers[budget], time.Now()))
+		}
+	}
+
 	statusConsumer := sdk.MonitoringStatusOK
 	if nbKafkaConsumers > nbHooksKafkaTotal {
 		statusConsumer = sdk.MonitoringStatusWarn
@@ -636,3 +689,45 @@ func (s *Service) postStopTaskExecutionHandler() service.Handler {
 		return nil
 	}
 }
+
+func (s *Service) postReplayTaskExecutionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		//Get the UUID of the task from the URL
+		vars := mux.Vars(r)
+		uuid := vars["uuid"]
+		timestamp := vars["timestamp"]
+
+		var replay sdk.TaskExecutionReplay
+		if err := service.UnmarshalBody(r, &replay); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		//Load the task
+		t := s.Dao.FindTask(uuid)
+		if t == nil {
+			return sdk.WrapError(sdk.ErrNotFound, "Unable to find task %s", uuid)
+		}
+
+		//Load the executions
+		execs, err := s.Dao.FindAllTaskExecutions(t)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to find task executions for %s", uuid)
+		}
+
+		for _, e := range execs {
+			if strconv.FormatInt(e.Timestamp, 10) != timestamp {
+				continue
+			}
+			replayExec, err := newReplayTaskExecution(e, replay.Payload)
+			if err != nil {
+				return err
+			}
+			s.Dao.SaveTaskExecution(replayExec)
+			s.Dao.EnqueueTaskExecution(replayExec)
+			log.Info("Hooks> postReplayTaskExecutionHandler> task execution %s:%s replayed as %d", uuid, timestamp, replayExec.Timestamp)
+			return service.WriteJSON(w, replayExec, http.StatusOK)
+		}
+
+		return sdk.WrapError(sdk.ErrNotFound, "Unable to find task execution %s:%s", uuid, timestamp)
+	}
+}
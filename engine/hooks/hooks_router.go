@@ -28,5 +28,6 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Handle("/task/{uuid}/execution", r.GET(s.getTaskExecutionsHandler), r.DELETE(s.deleteAllTaskExecutionsHandler))
 	r.Handle("/task/{uuid}/execution/{timestamp}", r.GET(s.getTaskExecutionHandler))
 	r.Handle("/task/{uuid}/execution/{timestamp}/stop", r.POST(s.postStopTaskExecutionHandler))
+	r.Handle("/task/{uuid}/execution/{timestamp}/replay", r.POST(s.postReplayTaskExecutionHandler))
 
 }
@@ -1,14 +1,12 @@
 package hooks
 
 import (
-	"encoding/json"
 	"fmt"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/Shopify/sarama"
-	"github.com/fsamin/go-dump"
 	cluster "gopkg.in/bsm/sarama-cluster.v2"
 
 	"github.com/ovh/cds/sdk"
@@ -119,46 +117,27 @@ func (s *Service) startKafkaHook(t *sdk.Task) error {
 		}
 	}()
 
+	// Closing the consumer ends the goroutines consuming its messages and errors
+	registerMessageHook(t.UUID, func() {
+		if err := consumer.Close(); err != nil {
+			log.Warning("Hooks> unable to close kafka consumer of task %s: %v", t.UUID, err)
+		}
+	})
+
 	return nil
 }
 
 func (s *Service) doKafkaTaskExecution(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
 	log.Debug("Hooks> Processing kafka %s %s", t.UUID, t.Type)
 
-	// Prepare a struct to send to CDS API
-	h := sdk.WorkflowNodeRunHookEvent{
-		WorkflowNodeHookUUID: t.UUID,
-		Payload:              map[string]string{},
-	}
-
-	var bodyJSON interface{}
-
-	//Try to parse the body as an array
-	bodyJSONArray := []interface{}{}
-	if err := json.Unmarshal(t.Kafka.Message, &bodyJSONArray); err != nil {
-		//Try to parse the body as a map
-		bodyJSONMap := map[string]interface{}{}
-		if err2 := json.Unmarshal(t.Kafka.Message, &bodyJSONMap); err2 == nil {
-			bodyJSON = bodyJSONMap
-		}
-	} else {
-		bodyJSON = bodyJSONArray
-	}
-
-	//Go Dump
-	e := dump.NewDefaultEncoder()
-	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
-	e.ExtraFields.DetailedMap = false
-	e.ExtraFields.DetailedStruct = false
-	e.ExtraFields.DeepJSON = true
-	e.ExtraFields.Len = false
-	e.ExtraFields.Type = false
-	m, err := e.ToStringMap(bodyJSON)
+	m, err := messagePayload(t.Kafka.Message)
 	if err != nil {
-		return nil, sdk.WrapError(err, "Unable to dump body %s", t.WebHook.RequestBody)
+		return nil, err
 	}
-	h.Payload = m
-	h.Payload["payload"] = string(t.Kafka.Message)
 
-	return &h, nil
+	// Prepare a struct to send to CDS API
+	return &sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: t.UUID,
+		Payload:              m,
+	}, nil
 }
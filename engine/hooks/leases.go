@@ -0,0 +1,141 @@
+package hooks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Several hooks instances can share the same cache. Each task is owned by one instance at a time, which holds a lease
+// on it: only the owner starts the task, and schedules, retries and cleans its executions. The owner renews its leases
+// every taskLeaseRenewDelay; when an instance dies, its leases expire and its tasks are taken over by the other instances.
+const (
+	taskLeaseTTL        = 30 // seconds
+	taskLeaseRenewDelay = 10 * time.Second
+)
+
+var leaseRootKey = cache.Key("hooks", "tasks", "leases")
+
+// taskLeases are the tasks owned by the instance
+type taskLeases struct {
+	sync.Mutex
+	tasks map[string]bool
+}
+
+func (l *taskLeases) has(uuid string) bool {
+	l.Lock()
+	defer l.Unlock()
+	return l.tasks[uuid]
+}
+
+func (l *taskLeases) add(uuid string) {
+	l.Lock()
+	defer l.Unlock()
+	if l.tasks == nil {
+		l.tasks = make(map[string]bool)
+	}
+	l.tasks[uuid] = true
+}
+
+func (l *taskLeases) remove(uuid string) {
+	l.Lock()
+	defer l.Unlock()
+	delete(l.tasks, uuid)
+}
+
+func (l *taskLeases) list() []string {
+	l.Lock()
+	defer l.Unlock()
+	uuids := make([]string, 0, len(l.tasks))
+	for uuid := range l.tasks {
+		uuids = append(uuids, uuid)
+	}
+	return uuids
+}
+
+// ownsTask returns true if the instance holds the lease of the task
+func (s *Service) ownsTask(uuid string) bool {
+	return s.leases.has(uuid)
+}
+
+// leaseTask acquires or renews the lease of a task, it returns false if the task is owned by another instance
+func (s *Service) leaseTask(t *sdk.Task) bool {
+	owned, _ := s.Dao.LeaseTask(t.UUID, s.instance, taskLeaseTTL)
+	if owned {
+		s.leases.add(t.UUID)
+	} else {
+		s.leases.remove(t.UUID)
+	}
+	return owned
+}
+
+// leaseTasksRoutine renews the leases of the instance and takes over the tasks without owner
+func (s *Service) leaseTasksRoutine(ctx context.Context) error {
+	tick := time.NewTicker(taskLeaseRenewDelay)
+	defer tick.Stop()
+	for {
+		s.renewTaskLeases(ctx)
+		select {
+		case <-ctx.Done():
+			// Release the leases so that the other instances take over the tasks right now
+			for _, uuid := range s.leases.list() {
+				s.Dao.ReleaseTask(uuid, s.instance)
+				s.leases.remove(uuid)
+			}
+			return ctx.Err()
+		case <-tick.C:
+		}
+	}
+}
+
+func (s *Service) renewTaskLeases(ctx context.Context) {
+	tasks, err := s.Dao.FindAllTasks()
+	if err != nil {
+		log.Error("Hooks> renewTaskLeases > Unable to find all tasks: %v", err)
+		return
+	}
+
+	exists := make(map[string]bool, len(tasks))
+	for i := range tasks {
+		t := &tasks[i]
+		exists[t.UUID] = true
+		wasOwned := s.ownsTask(t.UUID)
+
+		owned, renewed := s.Dao.LeaseTask(t.UUID, s.instance, taskLeaseTTL)
+		switch {
+		case renewed:
+			s.leases.add(t.UUID)
+		case owned:
+			// The lease has expired or has been revoked because the task was stopped or updated: restart it
+			if wasOwned {
+				s.stopMessageHook(t)
+			}
+			s.leases.add(t.UUID)
+			if t.Stopped || t.Type == TypeOutgoingWebHook || t.Type == TypeOutgoingWorkflow {
+				continue
+			}
+			log.Info("Hooks> renewTaskLeases > Task %s (%s) taken over by %s", t.UUID, t.Type, s.instance)
+			if _, err := s.startTask(ctx, t); err != nil {
+				log.Error("Hooks> renewTaskLeases > Unable to start task %s: %v", t.UUID, err)
+			}
+		case wasOwned:
+			// The task is now owned by another instance
+			log.Info("Hooks> renewTaskLeases > Task %s (%s) lost by %s", t.UUID, t.Type, s.instance)
+			s.stopMessageHook(t)
+			s.leases.remove(t.UUID)
+		}
+	}
+
+	// Forget the deleted tasks
+	for _, uuid := range s.leases.list() {
+		if !exists[uuid] {
+			s.stopMessageHook(&sdk.Task{UUID: uuid})
+			s.Dao.ReleaseTask(uuid, s.instance)
+			s.leases.remove(uuid)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package hooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// leaseStore is an in memory store for the leases, without expiration
+type leaseStore struct {
+	cache.Store
+	values map[string]string
+}
+
+func (s *leaseStore) Get(key string, value interface{}) bool {
+	v, has := s.values[key]
+	return has && json.Unmarshal([]byte(v), value) == nil
+}
+
+func (s *leaseStore) SetWithTTL(key string, value interface{}, ttl int) {
+	b, _ := json.Marshal(value)
+	s.values[key] = string(b)
+}
+
+func (s *leaseStore) Delete(key string) {
+	delete(s.values, key)
+}
+
+func (s *leaseStore) SetNX(key string, value interface{}, ttl int) bool {
+	if _, has := s.values[key]; has {
+		return false
+	}
+	s.SetWithTTL(key, value, ttl)
+	return true
+}
+
+func (s *leaseStore) UpdateTTLIfValue(key string, value interface{}, ttl int) bool {
+	b, _ := json.Marshal(value)
+	return s.values[key] == string(b)
+}
+
+func (s *leaseStore) DeleteIfValue(key string, value interface{}) bool {
+	b, _ := json.Marshal(value)
+	if s.values[key] != string(b) {
+		return false
+	}
+	delete(s.values, key)
+	return true
+}
+
+func Test_daoLeaseTask(t *testing.T) {
+	d := dao{store: &leaseStore{values: map[string]string{}}}
+
+	owned, renewed := d.LeaseTask("task", "instance-1", taskLeaseTTL)
+	assert.True(t, owned)
+	assert.False(t, renewed)
+	assert.Equal(t, "instance-1", d.FindTaskLease("task"))
+
+	owned, renewed = d.LeaseTask("task", "instance-1", taskLeaseTTL)
+	assert.True(t, owned)
+	assert.True(t, renewed)
+
+	owned, _ = d.LeaseTask("task", "instance-2", taskLeaseTTL)
+	assert.False(t, owned)
+
+	// Only the owner releases the lease
+	d.ReleaseTask("task", "instance-2")
+	assert.Equal(t, "instance-1", d.FindTaskLease("task"))
+	d.ReleaseTask("task", "instance-1")
+	assert.Equal(t, "", d.FindTaskLease("task"))
+
+	owned, renewed = d.LeaseTask("task", "instance-2", taskLeaseTTL)
+	assert.True(t, owned)
+	assert.False(t, renewed)
+
+	// A revoked lease is acquired again, the owner knows that it has to restart the task
+	d.RevokeTaskLease("task")
+	owned, renewed = d.LeaseTask("task", "instance-2", taskLeaseTTL)
+	assert.True(t, owned)
+	assert.False(t, renewed)
+}
+
+func Test_serviceLeaseTask(t *testing.T) {
+	s1 := &Service{Dao: dao{store: &leaseStore{values: map[string]string{}}}, instance: "instance-1"}
+	s2 := &Service{Dao: s1.Dao, instance: "instance-2"}
+	task := &sdk.Task{UUID: "task"}
+
+	assert.True(t, s1.leaseTask(task))
+	assert.True(t, s1.ownsTask(task.UUID))
+	assert.False(t, s2.leaseTask(task))
+	assert.False(t, s2.ownsTask(task.UUID))
+
+	s1.Dao.RevokeTaskLease(task.UUID)
+	assert.True(t, s2.leaseTask(task))
+	assert.False(t, s1.leaseTask(task))
+	assert.False(t, s1.ownsTask(task.UUID))
+}
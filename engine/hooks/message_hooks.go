@@ -0,0 +1,104 @@
+package hooks
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/fsamin/go-dump"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// messageHooks keeps the functions closing the connections of the running Kafka, RabbitMQ, NATS and MQTT hooks
+var messageHooks = struct {
+	sync.Mutex
+	closers map[string]func()
+}{closers: map[string]func(){}}
+
+// registerMessageHook saves the function closing the connection of a message hook,
+// the previous connection of the hook is closed if any
+func registerMessageHook(uuid string, close func()) {
+	messageHooks.Lock()
+	previous, has := messageHooks.closers[uuid]
+	messageHooks.closers[uuid] = close
+	messageHooks.Unlock()
+	if has {
+		previous()
+	}
+}
+
+func (s *Service) stopMessageHook(t *sdk.Task) {
+	messageHooks.Lock()
+	close, has := messageHooks.closers[t.UUID]
+	delete(messageHooks.closers, t.UUID)
+	messageHooks.Unlock()
+	if has {
+		close()
+	}
+}
+
+// saveTaskConnection updates the connection status of a message hook, a connection
+// following a disconnection is counted as a reconnection
+func (s *Service) saveTaskConnection(uuid, status string, err error) {
+	c := s.Dao.FindTaskConnection(uuid)
+	if c == nil {
+		c = &sdk.TaskConnection{}
+	}
+	if status == sdk.TaskConnectionConnected && c.Status == sdk.TaskConnectionDisconnected {
+		c.Reconnects++
+	}
+	c.Status = status
+	if err != nil {
+		c.LastError = err.Error()
+		log.Warning("Hooks> connection of task %s is %s: %v", uuid, status, err)
+	}
+	c.Timestamp = time.Now().UnixNano()
+	s.Dao.SaveTaskConnection(uuid, c)
+}
+
+// newMessageTaskExecution returns an execution of a message hook, ready to be enqueued
+func newMessageTaskExecution(t *sdk.Task) sdk.TaskExecution {
+	return sdk.TaskExecution{
+		ProcessingTimestamp: time.Now().UnixNano(),
+		Status:              TaskExecutionDoing,
+		Config:              t.Config,
+		Type:                t.Type,
+		UUID:                t.UUID,
+		Timestamp:           time.Now().UnixNano(),
+	}
+}
+
+// messagePayload maps a JSON message of a message hook (Kafka, RabbitMQ, NATS or MQTT) on workflow parameters, the
+// raw message is the parameter payload
+func messagePayload(message []byte) (map[string]string, error) {
+	var bodyJSON interface{}
+
+	//Try to parse the body as an array
+	bodyJSONArray := []interface{}{}
+	if err := json.Unmarshal(message, &bodyJSONArray); err != nil {
+		//Try to parse the body as a map
+		bodyJSONMap := map[string]interface{}{}
+		if err2 := json.Unmarshal(message, &bodyJSONMap); err2 == nil {
+			bodyJSON = bodyJSONMap
+		}
+	} else {
+		bodyJSON = bodyJSONArray
+	}
+
+	//Go Dump
+	e := dump.NewDefaultEncoder()
+	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
+	e.ExtraFields.DetailedMap = false
+	e.ExtraFields.DetailedStruct = false
+	e.ExtraFields.DeepJSON = true
+	e.ExtraFields.Len = false
+	e.ExtraFields.Type = false
+	m, err := e.ToStringMap(bodyJSON)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Unable to dump body %s", message)
+	}
+	m["payload"] = string(message)
+	return m, nil
+}
@@ -0,0 +1,63 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func Test_doNATSTaskExecution(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeNATS,
+		NATS: &sdk.NATSTaskExecution{
+			Subject: "deploy.production",
+			Message: []byte(`{"version": "1.2.3", "service": {"name": "api"}}`),
+		},
+	}
+	h, err := s.doNATSTaskExecution(task)
+	test.NoError(t, err)
+
+	assert.Equal(t, task.UUID, h.WorkflowNodeHookUUID)
+	assert.Equal(t, "1.2.3", h.Payload["version"])
+	assert.Equal(t, "api", h.Payload["service.name"])
+	assert.Equal(t, "deploy.production", h.Payload["nats.subject"])
+	assert.Equal(t, `{"version": "1.2.3", "service": {"name": "api"}}`, h.Payload["payload"])
+}
+
+func Test_doMQTTTaskExecution(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeMQTT,
+		MQTT: &sdk.MQTTTaskExecution{
+			Topic:   "devices/42/firmware",
+			Message: []byte(`not a json message`),
+		},
+	}
+	h, err := s.doMQTTTaskExecution(task)
+	test.NoError(t, err)
+
+	assert.Equal(t, "devices/42/firmware", h.Payload["mqtt.topic"])
+	assert.Equal(t, "not a json message", h.Payload["payload"])
+	assert.Equal(t, 2, len(h.Payload))
+}
+
+func Test_parseMQTTQoS(t *testing.T) {
+	for s, expected := range map[string]byte{"": 1, "0": 0, "1": 1, "2": 2} {
+		qos, err := parseMQTTQoS(s)
+		test.NoError(t, err)
+		assert.Equal(t, expected, qos, s)
+	}
+	for _, s := range []string{"3", "-1", "one"} {
+		_, err := parseMQTTQoS(s)
+		assert.Error(t, err, s)
+	}
+}
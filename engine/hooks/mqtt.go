@@ -0,0 +1,107 @@
+package hooks
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const mqttMaxReconnectInterval = time.Minute
+
+func (s *Service) startMQTTHook(t *sdk.Task) error {
+	projectKey := t.Config[sdk.HookConfigProject].Value
+	integrationName := t.Config[sdk.HookModelIntegration].Value
+	pf, err := s.Client.ProjectIntegrationGet(projectKey, integrationName, true)
+	if err != nil {
+		_ = s.stopTask(t)
+		return sdk.WrapError(err, "Cannot get MQTT configuration for %s/%s", projectKey, integrationName)
+	}
+
+	topic := t.Config[sdk.MQTTHookModelTopic].Value
+	qos, err := parseMQTTQoS(t.Config[sdk.MQTTHookModelQoS].Value)
+	if err != nil {
+		_ = s.stopTask(t)
+		return err
+	}
+
+	// The client id identifies the session of the hook on the broker
+	clientID := t.Config[sdk.MQTTHookModelClientID]
+	if clientID.Value == "" {
+		clientID.Value = "cds-hooks-" + t.UUID
+		t.Config[sdk.MQTTHookModelClientID] = clientID
+	}
+
+	handler := func(_ mqtt.Client, msg mqtt.Message) {
+		exec := newMessageTaskExecution(t)
+		exec.MQTT = &sdk.MQTTTaskExecution{Topic: msg.Topic(), Message: msg.Payload()}
+		s.Dao.SaveTaskExecution(&exec)
+		s.Dao.EnqueueTaskExecution(&exec)
+	}
+
+	// Without clean session, the broker keeps the subscription and the messages of QoS 1 and 2
+	// while the hook is disconnected. The subscription is renewed at each (re)connection.
+	broker := pf.Config["broker url"].Value
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(clientID.Value).
+		SetUsername(pf.Config["username"].Value).
+		SetPassword(pf.Config["password"].Value).
+		SetCleanSession(false).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(mqttMaxReconnectInterval).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			s.saveTaskConnection(t.UUID, sdk.TaskConnectionDisconnected, err)
+		}).
+		SetOnConnectHandler(func(c mqtt.Client) {
+			token := c.Subscribe(topic, qos, handler)
+			if token.Wait() && token.Error() != nil {
+				s.saveTaskConnection(t.UUID, sdk.TaskConnectionDisconnected, fmt.Errorf("unable to subscribe to %s: %v", topic, token.Error()))
+				return
+			}
+			s.saveTaskConnection(t.UUID, sdk.TaskConnectionConnected, nil)
+		})
+
+	client := mqtt.NewClient(opts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		_ = s.stopTask(t)
+		s.saveTaskConnection(t.UUID, sdk.TaskConnectionClosed, token.Error())
+		return fmt.Errorf("startMQTTHook> Error connecting to %s: %v", broker, token.Error())
+	}
+
+	registerMessageHook(t.UUID, func() {
+		client.Disconnect(250)
+		s.saveTaskConnection(t.UUID, sdk.TaskConnectionClosed, nil)
+	})
+	return nil
+}
+
+func parseMQTTQoS(s string) (byte, error) {
+	if s == "" {
+		return 1, nil
+	}
+	qos, err := strconv.Atoi(s)
+	if err != nil || qos < 0 || qos > 2 {
+		return 0, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid MQTT qos %q, expected 0, 1 or 2", s)
+	}
+	return byte(qos), nil
+}
+
+func (s *Service) doMQTTTaskExecution(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
+	log.Debug("Hooks> Processing MQTT %s %s", t.UUID, t.Type)
+
+	m, err := messagePayload(t.MQTT.Message)
+	if err != nil {
+		return nil, err
+	}
+	m["mqtt.topic"] = t.MQTT.Topic
+
+	return &sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: t.UUID,
+		Payload:              m,
+	}, nil
+}
@@ -0,0 +1,200 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	natsReconnectWait     = 5 * time.Second
+	natsJetStreamAPIWait  = 5 * time.Second
+	natsJetStreamAPI      = "$JS.API"
+	natsJetStreamDelivery = "cds.hooks.deliver."
+)
+
+var natsJetStreamAck = []byte("+ACK")
+
+type natsJetStreamError struct {
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+}
+
+type natsJetStreamConsumerConfig struct {
+	DurableName    string `json:"durable_name"`
+	DeliverSubject string `json:"deliver_subject"`
+	DeliverGroup   string `json:"deliver_group,omitempty"`
+	AckPolicy      string `json:"ack_policy"`
+	FilterSubject  string `json:"filter_subject,omitempty"`
+}
+
+// natsJetStreamRequest sends a request to the JetStream API, which replies with an error or the expected response.
+func natsJetStreamRequest(nc *nats.Conn, subject string, req interface{}, res interface{}) error {
+	btes, err := json.Marshal(req)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	msg, err := nc.Request(natsJetStreamAPI+"."+subject, btes, natsJetStreamAPIWait)
+	if err != nil {
+		return fmt.Errorf("JetStream is not available: %v", err)
+	}
+	var apiErr struct {
+		Error *natsJetStreamError `json:"error"`
+	}
+	if err := json.Unmarshal(msg.Data, &apiErr); err != nil {
+		return sdk.WithStack(err)
+	}
+	if apiErr.Error != nil {
+		return fmt.Errorf("%s (%d)", apiErr.Error.Description, apiErr.Error.Code)
+	}
+	if res == nil {
+		return nil
+	}
+	return sdk.WithStack(json.Unmarshal(msg.Data, res))
+}
+
+// createNATSDurableConsumer creates, or gets if it exists, a durable push consumer on the stream of the subject. The
+// stream is found from the subject when it is not given. It returns the subject where messages are delivered.
+func createNATSDurableConsumer(nc *nats.Conn, stream, subject, durable, queue string) (string, error) {
+	if stream == "" {
+		var res struct {
+			Streams []string `json:"streams"`
+		}
+		if err := natsJetStreamRequest(nc, "STREAM.NAMES", map[string]string{"subject": subject}, &res); err != nil {
+			return "", err
+		}
+		if len(res.Streams) != 1 {
+			return "", fmt.Errorf("no stream matches subject %s", subject)
+		}
+		stream = res.Streams[0]
+	}
+
+	// The delivery subject does not change so the consumer is the same when the hook is restarted
+	req := struct {
+		StreamName string                      `json:"stream_name"`
+		Config     natsJetStreamConsumerConfig `json:"config"`
+	}{
+		StreamName: stream,
+		Config: natsJetStreamConsumerConfig{
+			DurableName:    durable,
+			DeliverSubject: natsJetStreamDelivery + durable,
+			DeliverGroup:   queue,
+			AckPolicy:      "explicit",
+			FilterSubject:  subject,
+		},
+	}
+	if err := natsJetStreamRequest(nc, "CONSUMER.DURABLE.CREATE."+stream+"."+durable, req, nil); err != nil {
+		return "", err
+	}
+	return req.Config.DeliverSubject, nil
+}
+
+func (s *Service) startNATSHook(t *sdk.Task) error {
+	projectKey := t.Config[sdk.HookConfigProject].Value
+	integrationName := t.Config[sdk.HookModelIntegration].Value
+	pf, err := s.Client.ProjectIntegrationGet(projectKey, integrationName, true)
+	if err != nil {
+		_ = s.stopTask(t)
+		return sdk.WrapError(err, "Cannot get NATS configuration for %s/%s", projectKey, integrationName)
+	}
+
+	// Reconnect forever, the subscriptions are restored by the client on reconnection
+	opts := []nats.Option{
+		nats.Name("cds-hooks-" + t.UUID),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(natsReconnectWait),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			s.saveTaskConnection(t.UUID, sdk.TaskConnectionDisconnected, err)
+		}),
+		nats.ReconnectHandler(func(_ *nats.Conn) {
+			s.saveTaskConnection(t.UUID, sdk.TaskConnectionConnected, nil)
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			s.saveTaskConnection(t.UUID, sdk.TaskConnectionClosed, nil)
+		}),
+	}
+	if username := pf.Config["username"].Value; username != "" {
+		opts = append(opts, nats.UserInfo(username, pf.Config["password"].Value))
+	}
+	if token := pf.Config["token"].Value; token != "" {
+		opts = append(opts, nats.Token(token))
+	}
+
+	url := pf.Config["url"].Value
+	nc, err := nats.Connect(url, opts...)
+	if err != nil {
+		_ = s.stopTask(t)
+		s.saveTaskConnection(t.UUID, sdk.TaskConnectionClosed, err)
+		return fmt.Errorf("startNATSHook> Error connecting to %s: %v", url, err)
+	}
+	s.saveTaskConnection(t.UUID, sdk.TaskConnectionConnected, nil)
+
+	jetStream := t.Config[sdk.NATSHookModelJetStream].Value == "true"
+	handler := func(msg *nats.Msg) {
+		exec := newMessageTaskExecution(t)
+		exec.NATS = &sdk.NATSTaskExecution{Subject: msg.Subject, Message: msg.Data}
+		s.Dao.SaveTaskExecution(&exec)
+		s.Dao.EnqueueTaskExecution(&exec)
+		if jetStream {
+			if err := msg.Respond(natsJetStreamAck); err != nil {
+				log.Warning("Hooks> startNATSHook> unable to ack message on %s for task %s: %v", msg.Subject, t.UUID, err)
+			}
+		}
+	}
+
+	subject := t.Config[sdk.NATSHookModelSubject].Value
+	queue := t.Config[sdk.NATSHookModelQueueGroup].Value
+	var errSub error
+	if jetStream {
+		// The durable consumer keeps the messages published while the hook is stopped
+		durable := t.Config[sdk.NATSHookModelDurable]
+		if durable.Value == "" {
+			durable.Value = "cds-hooks-" + t.UUID
+			t.Config[sdk.NATSHookModelDurable] = durable
+		}
+		deliverSubject, err := createNATSDurableConsumer(nc, t.Config[sdk.NATSHookModelStream].Value, subject, durable.Value, queue)
+		if err != nil {
+			nc.Close()
+			_ = s.stopTask(t)
+			return fmt.Errorf("startNATSHook> Unable to create JetStream consumer %s on %s: %v", durable.Value, url, err)
+		}
+		if queue != "" {
+			_, errSub = nc.QueueSubscribe(deliverSubject, queue, handler)
+		} else {
+			_, errSub = nc.Subscribe(deliverSubject, handler)
+		}
+	} else if queue != "" {
+		_, errSub = nc.QueueSubscribe(subject, queue, handler)
+	} else {
+		_, errSub = nc.Subscribe(subject, handler)
+	}
+	if errSub != nil {
+		nc.Close()
+		_ = s.stopTask(t)
+		return fmt.Errorf("startNATSHook> Error subscribing to %s on %s: %v", subject, url, errSub)
+	}
+
+	// Closing the connection does not delete the durable consumer of a JetStream subscription
+	registerMessageHook(t.UUID, nc.Close)
+	return nil
+}
+
+func (s *Service) doNATSTaskExecution(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
+	log.Debug("Hooks> Processing NATS %s %s", t.UUID, t.Type)
+
+	m, err := messagePayload(t.NATS.Message)
+	if err != nil {
+		return nil, err
+	}
+	m["nats.subject"] = t.NATS.Subject
+
+	return &sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: t.UUID,
+		Payload:              m,
+	}, nil
+}
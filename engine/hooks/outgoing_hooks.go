@@ -11,6 +11,7 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	dump "github.com/fsamin/go-dump"
@@ -22,6 +23,18 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+// Delivery of outgoing webhooks
+const (
+	defaultOutgoingWebHookRetries    = 3
+	defaultOutgoingWebHookRetryDelay = 30 * time.Second
+	maxOutgoingWebHookRetryDelay     = time.Hour
+	maxOutgoingWebHookDumpSize       = 64 * 1024
+	maxOutgoingWebHookDeadLetters    = 10 // dead letters kept by task
+	redactedOutgoingWebHookValue     = "[redacted]"
+)
+
+var outgoingWebHookClient = &http.Client{Timeout: 60 * time.Second}
+
 func (s *Service) nodeRunToTask(nr sdk.WorkflowNodeRun) (sdk.Task, error) {
 	if nr.OutgoingHook == nil {
 		return sdk.Task{}, fmt.Errorf("Unsupported node type: %d", nr.WorkflowNodeID)
@@ -85,6 +98,9 @@ func (s *Service) startOutgoingWebHookTask(t *sdk.Task) (*sdk.TaskExecution, err
 	payload := t.Config["payload"].Value
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json")
+	for name, values := range parseOutgoingWebHookHeaders(t.Config[sdk.OutgoingWebHookHeaders].Value) {
+		headers[name] = values
+	}
 
 	//Craft a new execution
 	exec := &sdk.TaskExecution{
@@ -324,6 +340,12 @@ func (s *Service) doOutgoingWebHookExecution(t *sdk.TaskExecution) error {
 		return sdk.WrapError(handleError(err), "Unable to interpolate body")
 	}
 
+	// The secret can be a secret variable of the project
+	secret, err := interpolate.Do(t.Config[sdk.HookConfigWebHookSecret].Value, mapParams)
+	if err != nil {
+		return sdk.WrapError(handleError(err), "Unable to interpolate secret")
+	}
+
 	req, err := http.NewRequest(method, urls, bytes.NewBuffer([]byte(body)))
 	if err != nil {
 		return sdk.WrapError(handleError(err), "Unable to create request")
@@ -338,30 +360,40 @@ func (s *Service) doOutgoingWebHookExecution(t *sdk.TaskExecution) error {
 			req.Header.Add(k, val)
 		}
 	}
-
-	var logBuffer bytes.Buffer
-	logBuffer.WriteString("Request:\n")
-	dump, _ := httputil.DumpRequestOut(req, true)
-	logBuffer.Write(dump) // nolint
-
-	http.DefaultClient.Timeout = 60 * time.Second
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return sdk.WrapError(handleError(err), "Unable to send request")
+	req.Header.Set(WebHookDeliveryHeader, fmt.Sprintf("%s/%d", t.UUID, t.Timestamp))
+	req.Header.Set(WebHookAttemptHeader, strconv.Itoa(len(t.WebHook.Attempts)+1))
+	if secret != "" {
+		req.Header.Set(WebHookSignatureHeader, signHMAC(secret, []byte(body)))
 	}
 
-	// Prepare the callback
-	logBuffer.WriteString("\n\nResponse:\n")
-	dump, _ = httputil.DumpResponse(res, true)
-	logBuffer.Write(dump) // nolint
+	attempt, retryable, err := deliverOutgoingWebHook(req)
+	t.WebHook.Attempts = append(t.WebHook.Attempts, attempt)
+	if err != nil {
+		t.LastError = err.Error()
+		nbAttempts := len(t.WebHook.Attempts)
+		if retryable && nbAttempts <= outgoingWebHookRetries(t.Config) {
+			// The execution is scheduled again, the workflow run waits for the callback
+			delay := outgoingWebHookRetryDelay(t.Config, nbAttempts)
+			t.Status = TaskExecutionScheduled
+			t.WebHook.NextAttempt = time.Now().Add(delay).UnixNano()
+			log.Warning("Hooks> outgoing webhook %s/%d attempt %d failed, retry in %s: %v", t.UUID, t.Timestamp, nbAttempts, delay, err)
+			return nil
+		}
 
-	if res.StatusCode >= 400 {
-		err := fmt.Errorf("HTTP Status %d", res.StatusCode)
-		return handleError(err)
+		// No more attempts, the execution is kept as a dead letter and the node run fails
+		log.Error("Hooks> outgoing webhook %s/%d failed after %d attempts: %v", t.UUID, t.Timestamp, nbAttempts, err)
+		t.Status = TaskExecutionDeadLetter
+		callbackData.Done = time.Now()
+		callbackData.Status = sdk.StatusFail.String()
+		callbackData.Log = outgoingWebHookAttemptsLog(t.WebHook.Attempts)
+		if code, err := s.Client.(cdsclient.Raw).PostJSON(context.Background(), callbackURL, callbackData, nil); err != nil {
+			log.Error("[%d] unable to perform outgoing hook callback: %v", code, err)
+		}
+		return nil
 	}
 
 	callbackData.Done = time.Now()
-	callbackData.Log = logBuffer.String()
+	callbackData.Log = outgoingWebHookAttemptsLog(t.WebHook.Attempts)
 	callbackData.Status = sdk.StatusSuccess.String()
 
 	// Post the callback
@@ -371,3 +403,147 @@ func (s *Service) doOutgoingWebHookExecution(t *sdk.TaskExecution) error {
 
 	return nil
 }
+
+// parseOutgoingWebHookHeaders parses the headers of an outgoing webhook, one "Name: value" per line
+func parseOutgoingWebHookHeaders(s string) http.Header {
+	headers := http.Header{}
+	for _, line := range strings.Split(s, "\n") {
+		i := strings.Index(line, ":")
+		if i <= 0 {
+			continue
+		}
+		headers.Add(strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:]))
+	}
+	return headers
+}
+
+// outgoingWebHookRetries returns the number of retries of an outgoing webhook after its first attempt
+func outgoingWebHookRetries(config sdk.WorkflowNodeHookConfig) int {
+	v, ok := config[sdk.OutgoingWebHookRetries]
+	if !ok || v.Value == "" {
+		return defaultOutgoingWebHookRetries
+	}
+	retries, err := strconv.Atoi(v.Value)
+	if err != nil || retries < 0 {
+		return defaultOutgoingWebHookRetries
+	}
+	return retries
+}
+
+// outgoingWebHookRetryDelay returns the delay before the next attempt of an outgoing webhook,
+// doubled after each failed attempt
+func outgoingWebHookRetryDelay(config sdk.WorkflowNodeHookConfig, nbAttempts int) time.Duration {
+	delay, err := time.ParseDuration(config[sdk.OutgoingWebHookRetryDelay].Value)
+	if err != nil || delay <= 0 {
+		delay = defaultOutgoingWebHookRetryDelay
+	}
+	for i := 1; i < nbAttempts && delay < maxOutgoingWebHookRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxOutgoingWebHookRetryDelay {
+		delay = maxOutgoingWebHookRetryDelay
+	}
+	return delay
+}
+
+// deliverOutgoingWebHook sends the request of an outgoing webhook. It returns an error if the delivery failed,
+// and if it is worth retrying: network errors, server errors, request timeout and rate limit.
+func deliverOutgoingWebHook(req *http.Request) (sdk.WebHookAttempt, bool, error) {
+	start := time.Now()
+	attempt := sdk.WebHookAttempt{Timestamp: start.UnixNano()}
+	attempt.Request = dumpOutgoingWebHookRequest(req)
+
+	res, err := outgoingWebHookClient.Do(req)
+	attempt.Duration = time.Since(start).Nanoseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt, true, sdk.WrapError(err, "Unable to send request")
+	}
+	defer res.Body.Close() // nolint
+
+	dumpRes, _ := httputil.DumpResponse(res, true)
+	attempt.Response = truncateDump(dumpRes)
+	attempt.StatusCode = res.StatusCode
+
+	if res.StatusCode >= 400 {
+		err := fmt.Errorf("HTTP Status %d", res.StatusCode)
+		attempt.Error = err.Error()
+		retryable := res.StatusCode >= 500 || res.StatusCode == http.StatusRequestTimeout || res.StatusCode == http.StatusTooManyRequests
+		return attempt, retryable, err
+	}
+	return attempt, false, nil
+}
+
+// dumpOutgoingWebHookRequest dumps the request of an outgoing webhook attempt. The dump is stored and shown in the
+// logs of the node run, so the headers and the query parameters that can contain secret variables are redacted.
+func dumpOutgoingWebHookRequest(req *http.Request) string {
+	r := req.Clone(req.Context())
+	withBody := req.GetBody != nil
+	if withBody {
+		body, err := req.GetBody()
+		if err != nil {
+			withBody = false
+		}
+		r.Body = body
+	}
+
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		if isSensitiveName(k) {
+			v = []string{redactedOutgoingWebHookValue}
+		}
+		r.Header[k] = v
+	}
+
+	query := r.URL.Query()
+	var redacted bool
+	for k := range query {
+		if isSensitiveName(k) {
+			query.Set(k, redactedOutgoingWebHookValue)
+			redacted = true
+		}
+	}
+	if redacted {
+		r.URL.RawQuery = query.Encode()
+	}
+
+	dump, _ := httputil.DumpRequestOut(r, withBody)
+	return truncateDump(dump)
+}
+
+// isSensitiveName returns true for the names of headers and query parameters which usually hold credentials,
+// and for the signature of the outgoing webhooks
+func isSensitiveName(name string) bool {
+	return sdk.IsSensitiveHTTPName(name) || strings.EqualFold(name, WebHookSignatureHeader)
+}
+
+func truncateDump(dump []byte) string {
+	if len(dump) > maxOutgoingWebHookDumpSize {
+		return string(dump[:maxOutgoingWebHookDumpSize]) + "\n[truncated]"
+	}
+	return string(dump)
+}
+
+// outgoingWebHookAttemptsLog returns the log of the callback of an outgoing webhook
+func outgoingWebHookAttemptsLog(attempts []sdk.WebHookAttempt) string {
+	var logBuffer bytes.Buffer
+	for i, a := range attempts {
+		if len(attempts) > 1 {
+			fmt.Fprintf(&logBuffer, "Attempt %d/%d at %s:\n", i+1, len(attempts), time.Unix(0, a.Timestamp).Format(time.RFC3339))
+		}
+		logBuffer.WriteString("Request:\n")
+		logBuffer.WriteString(a.Request)
+		if a.Response != "" {
+			logBuffer.WriteString("\n\nResponse:\n")
+			logBuffer.WriteString(a.Response)
+		}
+		if a.Error != "" {
+			logBuffer.WriteString("\n\nError: ")
+			logBuffer.WriteString(a.Error)
+		}
+		if i < len(attempts)-1 {
+			logBuffer.WriteString("\n\n")
+		}
+	}
+	return logBuffer.String()
+}
@@ -0,0 +1,104 @@
+package hooks
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_parseOutgoingWebHookHeaders(t *testing.T) {
+	headers := parseOutgoingWebHookHeaders("X-Project: {{.cds.project}}\nAuthorization: Bearer {{.cds.proj.token}}\n\ninvalid line\nX-Project: other")
+	assert.Equal(t, []string{"{{.cds.project}}", "other"}, headers["X-Project"])
+	assert.Equal(t, "Bearer {{.cds.proj.token}}", headers.Get("Authorization"))
+	assert.Equal(t, 2, len(headers))
+}
+
+func Test_outgoingWebHookRetries(t *testing.T) {
+	assert.Equal(t, 3, outgoingWebHookRetries(sdk.WorkflowNodeHookConfig{}))
+	assert.Equal(t, 0, outgoingWebHookRetries(sdk.WorkflowNodeHookConfig{sdk.OutgoingWebHookRetries: {Value: "0"}}))
+	assert.Equal(t, 3, outgoingWebHookRetries(sdk.WorkflowNodeHookConfig{sdk.OutgoingWebHookRetries: {Value: "-1"}}))
+}
+
+func Test_outgoingWebHookRetryDelay(t *testing.T) {
+	config := sdk.WorkflowNodeHookConfig{sdk.OutgoingWebHookRetryDelay: {Value: "10s"}}
+	assert.Equal(t, 10*time.Second, outgoingWebHookRetryDelay(config, 1))
+	assert.Equal(t, 20*time.Second, outgoingWebHookRetryDelay(config, 2))
+	assert.Equal(t, 40*time.Second, outgoingWebHookRetryDelay(config, 3))
+	assert.Equal(t, time.Hour, outgoingWebHookRetryDelay(config, 20))
+	assert.Equal(t, 30*time.Second, outgoingWebHookRetryDelay(sdk.WorkflowNodeHookConfig{}, 1))
+}
+
+func Test_deliverOutgoingWebHook(t *testing.T) {
+	var status int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, checkHMACSignature("mysecret", r.Header.Get(WebHookSignatureHeader), []byte(`{"status":"Success"}`)))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	for code, expected := range map[int]struct {
+		err       bool
+		retryable bool
+	}{
+		http.StatusOK:                  {false, false},
+		http.StatusBadRequest:          {true, false},
+		http.StatusTooManyRequests:     {true, true},
+		http.StatusServiceUnavailable:  {true, true},
+		http.StatusInternalServerError: {true, true},
+	} {
+		status = code
+		body := []byte(`{"status":"Success"}`)
+		req, err := http.NewRequest(http.MethodPost, srv.URL, bytes.NewBuffer(body))
+		test.NoError(t, err)
+		req.Header.Set(WebHookSignatureHeader, signHMAC("mysecret", body))
+
+		attempt, retryable, err := deliverOutgoingWebHook(req)
+		assert.Equal(t, expected.err, err != nil, "status %d", code)
+		assert.Equal(t, expected.retryable, retryable, "status %d", code)
+		assert.Equal(t, code, attempt.StatusCode)
+		assert.Contains(t, attempt.Request, `{"status":"Success"}`)
+		assert.NotEmpty(t, attempt.Response)
+	}
+
+	// Network errors are retried
+	srv.Close()
+	req, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+	test.NoError(t, err)
+	attempt, retryable, err := deliverOutgoingWebHook(req)
+	assert.Error(t, err)
+	assert.True(t, retryable)
+	assert.NotEmpty(t, attempt.Error)
+	assert.Empty(t, attempt.Response)
+}
+
+func Test_dumpOutgoingWebHookRequest(t *testing.T) {
+	body := []byte(`{"status":"Success"}`)
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/hook?private_token=mytoken&status=ok", bytes.NewBuffer(body))
+	test.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer mybearer")
+	req.Header.Set("X-Gitlab-Token", "mygitlabtoken")
+	req.Header.Set("Content-Type", "application/json")
+
+	dump := dumpOutgoingWebHookRequest(req)
+	assert.NotContains(t, dump, "mytoken")
+	assert.NotContains(t, dump, "mybearer")
+	assert.NotContains(t, dump, "mygitlabtoken")
+	assert.Contains(t, dump, "status=ok")
+	assert.Contains(t, dump, "application/json")
+	assert.Contains(t, dump, `{"status":"Success"}`)
+
+	// the request itself is untouched
+	assert.Equal(t, "Bearer mybearer", req.Header.Get("Authorization"))
+	assert.Equal(t, "private_token=mytoken&status=ok", req.URL.RawQuery)
+	b, err := ioutil.ReadAll(req.Body)
+	test.NoError(t, err)
+	assert.Equal(t, body, b)
+}
@@ -8,10 +8,14 @@ import (
 	"time"
 
 	dump "github.com/fsamin/go-dump"
+
+	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
+var polledHeadsRootKey = cache.Key("hooks", "polledheads")
+
 func fillPayload(pushEvent sdk.VCSPushEvent) map[string]string {
 	payload := make(map[string]string)
 	payload["git.author"] = pushEvent.Commit.Author.Name
@@ -51,9 +55,9 @@ func (s *Service) doPollerTaskExecution(task *sdk.Task, taskExec *sdk.TaskExecut
 	}
 
 	var maxTs int64
-	// get max timestamp for previous tasks execution
+	// get max timestamp for previous tasks execution, skipped events must not be polled again
 	for _, tExec := range tExecs {
-		if tExec.Status == TaskExecutionDone && maxTs < tExec.Timestamp {
+		if (tExec.Status == TaskExecutionDone || tExec.Status == TaskExecutionSkipped) && maxTs < tExec.Timestamp {
 			maxTs = tExec.Timestamp
 		}
 	}
@@ -61,9 +65,35 @@ func (s *Service) doPollerTaskExecution(task *sdk.Task, taskExec *sdk.TaskExecut
 	if errP != nil {
 		return nil, sdk.WrapError(errP, "Hooks> doPollerTaskExecution> Cannot convert workflow id %s", taskExec.Config[sdk.HookConfigWorkflowID].Value)
 	}
-	events, interval, err := s.Client.PollVCSEvents(taskExec.UUID, workflowID, taskExec.Config["vcsServer"].Value, maxTs)
+	vcsServer := taskExec.Config["vcsServer"].Value
+
+	// The rate limit has been spent by the pollers, this execution is moved to its reset
+	budget := taskRateLimitBudget(task.Config)
+	if rateLimit := s.Dao.FindVCSRateLimit(budget); rateLimitExhausted(rateLimit, time.Now()) {
+		log.Info("Hooks> doPollerTaskExecution> Rate limit %s exhausted, %s:%d waits for its reset", budget, taskExec.UUID, taskExec.Timestamp)
+		reset := time.Unix(rateLimit.Reset, 0)
+		s.Dao.DeleteTaskExecution(taskExec)
+		taskExec.Timestamp = reset.UnixNano()
+		taskExec.Status = TaskExecutionScheduled
+		taskExec.SkipReason = fmt.Sprintf("rate limit %s exhausted, waiting for its reset at %s", budget, reset.Format(time.RFC3339))
+		taskExec.ScheduledTask.DateScheduledExecution = fmt.Sprintf("%v", reset)
+		return nil, nil
+	}
+
+	events, interval, rateLimit, err := s.Client.PollVCSEvents(taskExec.UUID, workflowID, vcsServer, maxTs)
 	if err != nil {
-		return nil, sdk.WrapError(err, "Cannot poll vcs events for workflow %s with vcsserver %s", taskExec.Config[sdk.HookConfigWorkflow].Value, taskExec.Config["vcsServer"].Value)
+		return nil, sdk.WrapError(err, "Cannot poll vcs events for workflow %s with vcsserver %s", taskExec.Config[sdk.HookConfigWorkflow].Value, vcsServer)
+	}
+	if rateLimit != nil {
+		// the owner of the token is kept to check the rate limit before the next polls
+		task.Config[configRateLimitOwner] = sdk.WorkflowNodeHookConfigValue{Value: rateLimit.Owner, Configurable: false}
+		budget = taskRateLimitBudget(task.Config)
+		s.Dao.SaveVCSRateLimit(budget, rateLimit)
+		tasks, err := s.Dao.FindAllTasks()
+		if err != nil {
+			return nil, err
+		}
+		interval = pollingInterval(interval, rateLimit, countPollers(tasks)[budget], time.Now())
 	}
 
 	//Prepare the payload
@@ -91,35 +121,51 @@ func (s *Service) doPollerTaskExecution(task *sdk.Task, taskExec *sdk.TaskExecut
 		payloadValues["payload"] = string(payload.Value)
 	}
 
+	// The repositories managers which do not send the previous commit of a branch are compared with the last polled commit
+	heads := s.Dao.FindPolledHeads(task.UUID)
 	var hookEvents []sdk.WorkflowNodeRunHookEvent
-	if len(events.PushEvents) > 0 || len(events.PullRequestEvents) > 0 {
-		i := 0
-		hookEvents = make([]sdk.WorkflowNodeRunHookEvent, len(events.PushEvents)+len(events.PullRequestEvents))
-		for _, pushEvent := range events.PushEvents {
-			payload := fillPayload(pushEvent)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
-				WorkflowNodeHookUUID: task.UUID,
-				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
-			}
-			i++
+	var skipped []string
+	for _, pushEvent := range events.PushEvents {
+		payload := fillPayload(pushEvent)
+		before := pushEvent.Before
+		if before == "" {
+			before = heads[pushEvent.Branch.DisplayID]
+		}
+		heads[pushEvent.Branch.DisplayID] = pushEvent.Commit.Hash
+		if !matchWorkflowPathFilters(taskExec.Config, s.changedFiles(taskExec, before, pushEvent.Commit.Hash)) {
+			skipped = append(skipped, fmt.Sprintf("push on %s: no changed file matches the paths of the workflow", payload["git.branch"]))
+			continue
 		}
+		hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
+			WorkflowNodeHookUUID: task.UUID,
+			Payload:              sdk.ParametersMapMerge(payloadValues, payload),
+		})
+	}
 
-		for _, pullRequestEvent := range events.PullRequestEvents {
-			payload := fillPayload(pullRequestEvent.Head)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
-				WorkflowNodeHookUUID: task.UUID,
-				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
-			}
-			i++
+	for _, pullRequestEvent := range events.PullRequestEvents {
+		payload := fillPayload(pullRequestEvent.Head)
+		if !matchWorkflowPathFilters(taskExec.Config, s.changedFiles(taskExec, pullRequestEvent.Base.Commit.Hash, pullRequestEvent.Head.Commit.Hash)) {
+			skipped = append(skipped, fmt.Sprintf("pull request on %s: no changed file matches the paths of the workflow", payload["git.branch"]))
+			continue
 		}
+		hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
+			WorkflowNodeHookUUID: task.UUID,
+			Payload:              sdk.ParametersMapMerge(payloadValues, payload),
+		})
+	}
+	skipTaskExecution(taskExec, skipped, len(hookEvents))
+	if len(events.PushEvents) > 0 {
+		s.Dao.SavePolledHeads(task.UUID, heads)
 	}
 
-	nextExec := fmt.Sprint(time.Now().Add(interval).Unix())
-	taskExec.Config["next_execution"] = sdk.WorkflowNodeHookConfigValue{
+	nextExec := sdk.WorkflowNodeHookConfigValue{
 		Configurable: false,
-		Value:        nextExec,
+		Value:        fmt.Sprint(time.Now().Add(interval).Unix()),
 	}
-	taskExec.ScheduledTask.DateScheduledExecution = nextExec
+	taskExec.Config["next_execution"] = nextExec
+	taskExec.ScheduledTask.DateScheduledExecution = nextExec.Value
+	// the next execution of the task is scheduled from its configuration
+	task.Config["next_execution"] = nextExec
 
 	return hookEvents, nil
 }
@@ -1,14 +1,13 @@
 package hooks
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
-	"github.com/fsamin/go-dump"
 	"github.com/streadway/amqp"
 
 	"github.com/ovh/cds/sdk"
@@ -62,13 +61,31 @@ func (s *Service) startRabbitMQHook(t *sdk.Task) error {
 		return fmt.Errorf("startRabbitMQHook> Queue Consume: %s", errConsume)
 	}
 
+	// The consumer is shut down once, on a signal or when the task is stopped or lost by the instance
+	var once sync.Once
+	shutdown := func() {
+		once.Do(func() {
+			if err := consumer.Shutdown(); err != nil {
+				log.Warning("Hooks> unable to shutdown rabbitMQ consumer of task %s: %v", t.UUID, err)
+			}
+		})
+	}
+	stopped := make(chan struct{})
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
-		<-sigs
-		log.Info("RabbitMQ> shutdown")
-		_ = consumer.Shutdown()
+		defer signal.Stop(sigs)
+		select {
+		case <-sigs:
+			log.Info("RabbitMQ> shutdown")
+			shutdown()
+		case <-stopped:
+		}
 	}()
+	registerMessageHook(t.UUID, func() {
+		close(stopped)
+		shutdown()
+	})
 
 	go func() {
 		for d := range deliveries {
@@ -94,42 +111,16 @@ func (s *Service) startRabbitMQHook(t *sdk.Task) error {
 func (s *Service) doRabbitMQTaskExecution(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
 	log.Debug("Hooks> Processing rabbitMQ %s %s", t.UUID, t.Type)
 
-	// Prepare a struct to send to CDS API
-	h := sdk.WorkflowNodeRunHookEvent{
-		WorkflowNodeHookUUID: t.UUID,
-		Payload:              map[string]string{},
-	}
-
-	var bodyJSON interface{}
-
-	//Try to parse the body as an array
-	bodyJSONArray := []interface{}{}
-	if err := json.Unmarshal(t.RabbitMQ.Message, &bodyJSONArray); err != nil {
-		//Try to parse the body as a map
-		bodyJSONMap := map[string]interface{}{}
-		if err2 := json.Unmarshal(t.RabbitMQ.Message, &bodyJSONMap); err2 == nil {
-			bodyJSON = bodyJSONMap
-		}
-	} else {
-		bodyJSON = bodyJSONArray
-	}
-
-	//Go Dump
-	e := dump.NewDefaultEncoder()
-	e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
-	e.ExtraFields.DetailedMap = false
-	e.ExtraFields.DetailedStruct = false
-	e.ExtraFields.DeepJSON = true
-	e.ExtraFields.Len = false
-	e.ExtraFields.Type = false
-	m, err := e.ToStringMap(bodyJSON)
+	m, err := messagePayload(t.RabbitMQ.Message)
 	if err != nil {
-		return nil, sdk.WrapError(err, "Unable to dump body %s", t.WebHook.RequestBody)
+		return nil, err
 	}
-	h.Payload = m
-	h.Payload["payload"] = string(t.RabbitMQ.Message)
 
-	return &h, nil
+	// Prepare a struct to send to CDS API
+	return &sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: t.UUID,
+		Payload:              m,
+	}, nil
 }
 
 func newConsumer(amqpURI, exchange, exchangeType, queueName, key, ctag string) (*rabbitMQConsumer, error) {
@@ -0,0 +1,94 @@
+package hooks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// The repository pollers of a vcs server which use the tokens of the same user share its rate limit: each poller gets
+// an equal part of the remaining requests until the reset, minus a reserve kept for the other calls of CDS (statuses,
+// pull requests, as code...). The interval of the pollers grows as the budget decreases, and the pollers wait for the
+// reset once it is spent.
+const pollingRateLimitReserve = 0.1
+
+// configRateLimitOwner is the key of the owner of the token of a poller in the configuration of its task,
+// it is given by the API with the rate limit
+const configRateLimitOwner = "rateLimitOwner"
+
+var rateLimitRootKey = cache.Key("hooks", "ratelimits")
+
+// rateLimitBudget returns the name of the rate limit shared by the pollers of a vcs server which use the tokens of the same user
+func rateLimitBudget(vcsServer, owner string) string {
+	if owner == "" {
+		return vcsServer
+	}
+	return vcsServer + "/" + owner
+}
+
+// taskRateLimitBudget returns the name of the rate limit used by a poller
+func taskRateLimitBudget(config sdk.WorkflowNodeHookConfig) string {
+	return rateLimitBudget(config[sdk.HookConfigVCSServer].Value, config[configRateLimitOwner].Value)
+}
+
+// pollingBudget returns the number of requests the pollers can send until the reset of the rate limit
+func pollingBudget(r *sdk.VCSRateLimit) int {
+	return r.Remaining - int(float64(r.Limit)*pollingRateLimitReserve)
+}
+
+// rateLimitExhausted returns true if the pollers have to wait for the reset of the rate limit
+func rateLimitExhausted(r *sdk.VCSRateLimit, now time.Time) bool {
+	return r != nil && r.Reset > now.Unix() && pollingBudget(r) <= 0
+}
+
+// pollingInterval adapts the interval of a poller so that the pollers of a vcs server stay in its rate limit
+func pollingInterval(interval time.Duration, r *sdk.VCSRateLimit, nbPollers int, now time.Time) time.Duration {
+	if r == nil || r.Limit <= 0 || r.Reset <= now.Unix() {
+		return interval
+	}
+	untilReset := time.Unix(r.Reset, 0).Sub(now)
+	budget := pollingBudget(r)
+	if budget <= 0 {
+		return untilReset
+	}
+	if nbPollers < 1 {
+		nbPollers = 1
+	}
+	adapted := time.Duration(int64(untilReset) * int64(nbPollers) / int64(budget))
+	if adapted > untilReset {
+		adapted = untilReset
+	}
+	if adapted > interval {
+		return adapted.Round(time.Second)
+	}
+	return interval
+}
+
+// countPollers returns the number of running repository pollers of each rate limit
+func countPollers(tasks []sdk.Task) map[string]int {
+	nbPollers := map[string]int{}
+	for _, t := range tasks {
+		if t.Type == TypeRepoPoller && !t.Stopped {
+			nbPollers[taskRateLimitBudget(t.Config)]++
+		}
+	}
+	return nbPollers
+}
+
+// rateLimitStatus returns the monitoring line of a rate limit
+func rateLimitStatus(budget string, r *sdk.VCSRateLimit, nbPollers int, now time.Time) sdk.MonitoringStatusLine {
+	status := sdk.MonitoringStatusOK
+	switch {
+	case rateLimitExhausted(r, now):
+		status = sdk.MonitoringStatusAlert
+	case r.Remaining < r.Limit/4:
+		status = sdk.MonitoringStatusWarn
+	}
+	return sdk.MonitoringStatusLine{
+		Component: "Rate limit " + budget,
+		Value:     fmt.Sprintf("%d/%d until %s, %d pollers", r.Remaining, r.Limit, time.Unix(r.Reset, 0).Format("15:04:05"), nbPollers),
+		Status:    status,
+	}
+}
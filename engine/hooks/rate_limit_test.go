@@ -0,0 +1,112 @@
+package hooks
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_pollingInterval(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	reset := now.Add(time.Hour).Unix()
+
+	// Without rate limit, or after its reset, the interval asked by the vcs server is kept
+	assert.Equal(t, time.Minute, pollingInterval(time.Minute, nil, 10, now))
+	assert.Equal(t, time.Minute, pollingInterval(time.Minute, &sdk.VCSRateLimit{Limit: 5000, Remaining: 0, Reset: now.Add(-time.Second).Unix()}, 10, now))
+
+	// Enough requests for all the pollers
+	assert.Equal(t, time.Minute, pollingInterval(time.Minute, &sdk.VCSRateLimit{Limit: 5000, Remaining: 5000, Reset: reset}, 10, now))
+
+	// 900 requests for 100 pollers during one hour: one request every 400s for each poller
+	assert.Equal(t, 400*time.Second, pollingInterval(time.Minute, &sdk.VCSRateLimit{Limit: 5000, Remaining: 1400, Reset: reset}, 100, now))
+
+	// The pollers never wait after the reset
+	assert.Equal(t, time.Hour, pollingInterval(time.Minute, &sdk.VCSRateLimit{Limit: 5000, Remaining: 510, Reset: reset}, 100, now))
+
+	// The budget is spent, the reserve is kept for the other calls
+	r := &sdk.VCSRateLimit{Limit: 5000, Remaining: 500, Reset: reset}
+	assert.Equal(t, time.Hour, pollingInterval(time.Minute, r, 100, now))
+	assert.True(t, rateLimitExhausted(r, now))
+	assert.False(t, rateLimitExhausted(r, now.Add(2*time.Hour)))
+	assert.False(t, rateLimitExhausted(&sdk.VCSRateLimit{Limit: 5000, Remaining: 501, Reset: reset}, now))
+}
+
+func Test_daoVCSRateLimit(t *testing.T) {
+	d := dao{store: &leaseStore{values: map[string]string{}}}
+	assert.Nil(t, d.FindVCSRateLimit("github/alice"))
+
+	r := &sdk.VCSRateLimit{Limit: 5000, Remaining: 4000, Reset: time.Now().Add(time.Hour).Unix(), Owner: "alice"}
+	d.SaveVCSRateLimit("github/alice", r)
+	assert.Equal(t, r, d.FindVCSRateLimit("github/alice"))
+	assert.Nil(t, d.FindVCSRateLimit("github/bob"))
+
+	// The pollers which use the tokens of the same user share the rate limit
+	poller := func(vcsServer, owner string) sdk.Task {
+		return sdk.Task{Type: TypeRepoPoller, Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigVCSServer: {Value: vcsServer},
+			configRateLimitOwner:    {Value: owner},
+		}}
+	}
+	stopped := poller("github", "alice")
+	stopped.Stopped = true
+	nbPollers := countPollers([]sdk.Task{
+		poller("github", "alice"),
+		poller("github", "alice"),
+		poller("github", "bob"),
+		poller("github", ""),
+		stopped,
+		{Type: TypeWebHook, Config: sdk.WorkflowNodeHookConfig{sdk.HookConfigVCSServer: {Value: "github"}}},
+	})
+	assert.Equal(t, map[string]int{"github/alice": 2, "github/bob": 1, "github": 1}, nbPollers)
+}
+
+// executionStore is an in memory store for the executions of a task
+type executionStore struct {
+	leaseStore
+	removed []string
+}
+
+func (s *executionStore) SetCard(key string) int { return 0 }
+
+func (s *executionStore) SetScan(key string, members ...interface{}) error { return nil }
+
+func (s *executionStore) SetRemove(rootKey string, memberKey string, member interface{}) {
+	s.removed = append(s.removed, memberKey)
+}
+
+func Test_doPollerTaskExecutionRateLimitExhausted(t *testing.T) {
+	store := &executionStore{leaseStore: leaseStore{values: map[string]string{}}}
+	s := Service{Dao: dao{store: store}}
+	reset := time.Now().Add(time.Hour).Unix()
+	s.Dao.SaveVCSRateLimit("github/alice", &sdk.VCSRateLimit{Limit: 5000, Remaining: 10, Reset: reset, Owner: "alice"})
+
+	task := &sdk.Task{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoPoller,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigVCSServer:  {Value: "github"},
+			sdk.HookConfigWorkflowID: {Value: "1"},
+			configRateLimitOwner:     {Value: "alice"},
+		},
+	}
+	exec := &sdk.TaskExecution{
+		UUID:          task.UUID,
+		Type:          TypeRepoPoller,
+		Timestamp:     time.Now().UnixNano(),
+		Config:        task.Config,
+		ScheduledTask: &sdk.ScheduledTaskExecution{},
+	}
+	previous := exec.Timestamp
+
+	// The execution is moved to the reset of the rate limit, without polling
+	hs, err := s.doPollerTaskExecution(task, exec)
+	assert.NoError(t, err)
+	assert.Empty(t, hs)
+	assert.Equal(t, TaskExecutionScheduled, exec.Status)
+	assert.Equal(t, time.Unix(reset, 0).UnixNano(), exec.Timestamp)
+	assert.Equal(t, []string{fmt.Sprintf("%d", previous)}, store.removed)
+}
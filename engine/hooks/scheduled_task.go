@@ -2,15 +2,35 @@ package hooks
 
 import (
 	"encoding/json"
+	"fmt"
+	"time"
 
 	dump "github.com/fsamin/go-dump"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/ovh/cds/sdk/log"
 )
 
 func (s *Service) doScheduledTaskExecution(t *sdk.TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
 	log.Debug("Hooks> Processing scheduled task %s", t.UUID)
 
+	loc, err := time.LoadLocation(t.Config[sdk.SchedulerModelTimezone].Value)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to parse timezone: %v", t.Config[sdk.SchedulerModelTimezone])
+	}
+	date := scheduledTime(t).In(loc)
+
+	//Do not trigger the workflow during the excluded periods
+	exclusions, err := parseExclusions(t.Config[sdk.SchedulerModelExclusions].Value, loc)
+	if err != nil {
+		return nil, err
+	}
+	if e, excluded := excludedBy(exclusions, date); excluded {
+		t.Status = TaskExecutionSkipped
+		t.SkipReason = fmt.Sprintf("%s is excluded by %s", date.Format(exclusionTimeLayout), e.raw)
+		return nil, nil
+	}
+
 	// Prepare a struct to send to CDS API
 	h := sdk.WorkflowNodeRunHookEvent{
 		WorkflowNodeHookUUID: t.UUID,
@@ -18,11 +38,16 @@ func (s *Service) doScheduledTaskExecution(t *sdk.TaskExecution) (*sdk.WorkflowN
 
 	//Prepare the payload
 	//Anything can be pushed in the configuration, just avoid sending
+	//The payload is a template that can use the date of the schedule
 
 	payloadValues := map[string]string{}
 	if payload, ok := t.Config[sdk.Payload]; ok && payload.Value != "{}" {
+		value, err := interpolate.Do(payload.Value, scheduleVariables(date))
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to interpolate payload %s", payload.Value)
+		}
 		var payloadInt interface{}
-		if err := json.Unmarshal([]byte(payload.Value), &payloadInt); err == nil {
+		if err := json.Unmarshal([]byte(value), &payloadInt); err == nil {
 			e := dump.NewDefaultEncoder()
 			e.Formatters = []dump.KeyFormatterFunc{dump.WithDefaultLowerCaseFormatter()}
 			e.ExtraFields.DetailedMap = false
@@ -36,21 +61,83 @@ func (s *Service) doScheduledTaskExecution(t *sdk.TaskExecution) (*sdk.WorkflowN
 			} else {
 				payloadValues = m1
 			}
-			payloadValues["payload"] = payload.Value
+			payloadValues["payload"] = value
 		} else {
 			log.Error("Hooks> doScheduledTaskExecution> Cannot unmarshall payload %s", err)
 		}
 	}
 	for k, v := range t.Config {
 		switch k {
-		case sdk.HookConfigProject, sdk.HookConfigWorkflow, sdk.SchedulerModelCron, sdk.SchedulerModelTimezone, sdk.Payload:
+		case sdk.HookConfigProject, sdk.HookConfigWorkflow, sdk.SchedulerModelCron, sdk.SchedulerModelTimezone, sdk.Payload,
+			sdk.SchedulerModelExclusions, sdk.SchedulerModelJitter, sdk.SchedulerModelOnlyIfChanged, sdk.HookConfigVCSServer, sdk.HookConfigRepoFullName:
 		default:
 			payloadValues[k] = v.Value
 		}
 	}
+
+	//Do not trigger the workflow if the repository has not changed since the last scheduled run
+	if t.Config[sdk.SchedulerModelOnlyIfChanged].Value == "true" {
+		changed, err := s.repositoryChangedSinceLastSchedule(t, payloadValues["git.branch"])
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			t.Status = TaskExecutionSkipped
+			t.SkipReason = fmt.Sprintf("the repository has not changed since the last scheduled run (%s)", shortHash(t.ScheduledTask.Hash))
+			return nil, nil
+		}
+	}
+
 	payloadValues["cds.triggered_by.username"] = "cds.scheduler"
 	payloadValues["cds.triggered_by.fullname"] = "CDS Scheduler"
 	h.Payload = payloadValues
 
 	return &h, nil
 }
+
+// scheduledTime returns the time computed from the cron expression for a scheduled execution, without the jitter
+func scheduledTime(t *sdk.TaskExecution) time.Time {
+	if t.ScheduledTask != nil {
+		if date, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", t.ScheduledTask.DateScheduledExecution); err == nil {
+			return date
+		}
+	}
+	return time.Unix(0, t.Timestamp)
+}
+
+// scheduleVariables returns the variables that can be used in the payload template of a scheduler
+func scheduleVariables(date time.Time) map[string]string {
+	return map[string]string{
+		"cds.schedule.date":      date.Format("2006-01-02"),
+		"cds.schedule.time":      date.Format("15:04"),
+		"cds.schedule.datetime":  date.Format(time.RFC3339),
+		"cds.schedule.weekday":   date.Weekday().String(),
+		"cds.schedule.timestamp": fmt.Sprint(date.Unix()),
+	}
+}
+
+// repositoryChangedSinceLastSchedule checks if the latest commit of the branch changed since the last scheduled execution
+// which successfully processed, the latest commit is saved in the execution.
+func (s *Service) repositoryChangedSinceLastSchedule(t *sdk.TaskExecution, branch string) (bool, error) {
+	b, err := s.Client.HookRepositoryBranch(t.UUID, branch)
+	if err != nil {
+		return false, sdk.WrapError(err, "unable to get the latest commit of branch %s", branch)
+	}
+	t.ScheduledTask.Hash = b.LatestCommit
+
+	execs, err := s.Dao.FindAllTaskExecutions(&sdk.Task{UUID: t.UUID, Type: t.Type})
+	if err != nil {
+		return false, err
+	}
+	var previous *sdk.TaskExecution
+	for i := range execs {
+		e := &execs[i]
+		if e.Timestamp >= t.Timestamp || e.LastError != "" || e.ScheduledTask == nil || e.ScheduledTask.Hash == "" {
+			continue
+		}
+		if previous == nil || e.Timestamp > previous.Timestamp {
+			previous = e
+		}
+	}
+	return previous == nil || previous.ScheduledTask.Hash != b.LatestCommit, nil
+}
@@ -67,13 +67,16 @@ func (s *Service) retryTaskExecutionsRoutine(c context.Context) error {
 				continue
 			}
 			for _, t := range tasks {
+				if !s.ownsTask(t.UUID) {
+					continue
+				}
 				execs, err := s.Dao.FindAllTaskExecutions(&t)
 				if err != nil {
 					log.Error("Hooks> retryTaskExecutionsRoutine > Unable to find all task executions (%s): %v", t.UUID, err)
 					continue
 				}
 				for _, e := range execs {
-					if e.Status == TaskExecutionDoing || e.Status == TaskExecutionScheduled {
+					if e.Status == TaskExecutionDoing || e.Status == TaskExecutionScheduled || e.Status == TaskExecutionDeadLetter {
 						continue
 					}
 
@@ -119,6 +122,9 @@ func (s *Service) enqueueScheduledTaskExecutionsRoutine(c context.Context) error
 				continue
 			}
 			for _, t := range tasks {
+				if !s.ownsTask(t.UUID) {
+					continue
+				}
 				execs, err := s.Dao.FindAllTaskExecutions(&t)
 				if err != nil {
 					log.Error("Hooks> enqueueScheduledTaskExecutionsRoutine > Unable to find all task executions (%s): %v", t.UUID, err)
@@ -126,10 +132,15 @@ func (s *Service) enqueueScheduledTaskExecutionsRoutine(c context.Context) error
 				}
 				alreadyEnqueued := false
 				for _, e := range execs {
+					// outgoing webhooks wait for their next attempt
+					if e.WebHook != nil && e.WebHook.NextAttempt > time.Now().UnixNano() {
+						continue
+					}
 					if e.Status == TaskExecutionScheduled && e.ProcessingTimestamp == 0 && e.Timestamp <= time.Now().UnixNano() {
 						// update status before enqueue
 						// this will avoid to re-enqueue the same scheduled task execution if the dequeue take more than 30s (ticker of this goroutine)
-						if alreadyEnqueued {
+						// an outgoing hook task has one execution for each node run, they are all enqueued
+						if alreadyEnqueued && e.Type != TypeOutgoingWebHook && e.Type != TypeOutgoingWorkflow {
 							log.Info("Hooks> enqueueScheduledTaskExecutionsRoutine > task execution already enqueued for this task %s of type %s- delete it", e.UUID, e.Type)
 							s.Dao.DeleteTaskExecution(&e)
 						} else {
@@ -162,6 +173,9 @@ func (s *Service) deleteTaskExecutionsRoutine(c context.Context) error {
 				continue
 			}
 			for _, t := range tasks {
+				if !s.ownsTask(t.UUID) {
+					continue
+				}
 				taskToDelete := false
 				execs, err := s.Dao.FindAllTaskExecutions(&t)
 				if err != nil {
@@ -172,6 +186,7 @@ func (s *Service) deleteTaskExecutionsRoutine(c context.Context) error {
 					return execs[i].Timestamp > execs[j].Timestamp
 				})
 
+				var nbDeadLetters int
 				for i, e := range execs {
 					switch e.Type {
 					// Delete all branch deletion task execution
@@ -181,6 +196,14 @@ func (s *Service) deleteTaskExecutionsRoutine(c context.Context) error {
 							taskToDelete = true
 						}
 					default:
+						// the last dead letters are kept for debugging
+						if e.Status == TaskExecutionDeadLetter {
+							nbDeadLetters++
+							if nbDeadLetters > maxOutgoingWebHookDeadLetters {
+								s.Dao.DeleteTaskExecution(&e)
+							}
+							continue
+						}
 						if i >= s.Cfg.ExecutionHistory && e.ProcessingTimestamp != 0 {
 							s.Dao.DeleteTaskExecution(&e)
 						}
@@ -219,6 +242,7 @@ func (s *Service) dequeueTaskExecutions(c context.Context) error {
 		}
 		t.ProcessingTimestamp = time.Now().UnixNano()
 		t.LastError = ""
+		t.SkipReason = ""
 		t.Status = TaskExecutionDoing
 		s.Dao.SaveTaskExecution(&t)
 
@@ -264,14 +288,25 @@ func (s *Service) dequeueTaskExecutions(c context.Context) error {
 
 		//Save the execution
 		if saveTaskExecution {
-			t.Status = TaskExecutionDone
-			t.ProcessingTimestamp = time.Now().UnixNano()
+			switch t.Status {
+			case TaskExecutionScheduled:
+				// the execution has to be processed again, see enqueueScheduledTaskExecutionsRoutine
+				t.ProcessingTimestamp = 0
+			case TaskExecutionSkipped, TaskExecutionDeadLetter:
+				t.ProcessingTimestamp = time.Now().UnixNano()
+			default:
+				t.Status = TaskExecutionDone
+				t.ProcessingTimestamp = time.Now().UnixNano()
+			}
 			s.Dao.SaveTaskExecution(&t)
 		}
 
-		//Start (or restart) the task
+		//Prepare the next execution of the task, whichever instance owns it
 		if restartTask {
-			s.startTask(c, task)
+			s.Dao.SaveTask(task)
+			if err := s.prepareNextScheduledTaskExecution(task); err != nil {
+				log.Error("Hooks> dequeueTaskExecutions> Unable to prepare the next execution of %s: %v", task.UUID, err)
+			}
 		}
 	}
 }
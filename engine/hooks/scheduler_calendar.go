@@ -0,0 +1,143 @@
+package hooks
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Layouts of the scheduler exclusions
+const (
+	exclusionDayLayout       = "2006-01-02"
+	exclusionYearlyDayLayout = "01-02"
+	exclusionTimeLayout      = "2006-01-02T15:04"
+)
+
+// exclusion is a period during which a scheduler does not trigger the workflow
+type exclusion struct {
+	raw     string
+	weekday *time.Weekday
+	yearly  string
+	from    time.Time
+	to      time.Time
+}
+
+// parseExclusions parses the exclusions of a scheduler, separated by ";". An exclusion is a day ("2019-12-25"), a day of every
+// year ("12-25"), a day of the week ("saturday") or a range of days or times ("2019-12-20..2020-01-03", "2019-12-20T18:00..2020-01-03T08:00").
+// Days and times are read in the timezone of the scheduler, the end of a range is included.
+func parseExclusions(value string, loc *time.Location) ([]exclusion, error) {
+	var res []exclusion
+	for _, s := range strings.Split(value, ";") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		e, err := parseExclusion(s, loc)
+		if err != nil {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid scheduler exclusion %q: %v", s, err)
+		}
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+func parseExclusion(s string, loc *time.Location) (exclusion, error) {
+	e := exclusion{raw: s}
+
+	if i := strings.Index(s, ".."); i >= 0 {
+		from, _, err := parseExclusionTime(s[:i], loc)
+		if err != nil {
+			return e, err
+		}
+		to, isDay, err := parseExclusionTime(s[i+2:], loc)
+		if err != nil {
+			return e, err
+		}
+		if isDay {
+			to = to.AddDate(0, 0, 1)
+		}
+		if !to.After(from) {
+			return e, fmt.Errorf("the end of the range is before its start")
+		}
+		e.from, e.to = from, to
+		return e, nil
+	}
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(s, d.String()) {
+			weekday := d
+			e.weekday = &weekday
+			return e, nil
+		}
+	}
+
+	if _, err := time.Parse(exclusionYearlyDayLayout, s); err == nil {
+		e.yearly = s
+		return e, nil
+	}
+
+	from, _, err := parseExclusionTime(s, loc)
+	if err != nil {
+		return e, err
+	}
+	e.from, e.to = from, from.AddDate(0, 0, 1)
+	return e, nil
+}
+
+// parseExclusionTime parses a day or a time, and returns true for a day
+func parseExclusionTime(s string, loc *time.Location) (time.Time, bool, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation(exclusionDayLayout, s, loc); err == nil {
+		return t, true, nil
+	}
+	t, err := time.ParseInLocation(exclusionTimeLayout, s, loc)
+	if err != nil {
+		return t, false, fmt.Errorf("expected a day (%s), a day of every year (%s), a day of the week or a time (%s)", exclusionDayLayout, exclusionYearlyDayLayout, exclusionTimeLayout)
+	}
+	return t, false, nil
+}
+
+// matches returns true if the time is in the period of the exclusion
+func (e exclusion) matches(t time.Time) bool {
+	switch {
+	case e.weekday != nil:
+		return t.Weekday() == *e.weekday
+	case e.yearly != "":
+		return t.Format(exclusionYearlyDayLayout) == e.yearly
+	default:
+		return !t.Before(e.from) && t.Before(e.to)
+	}
+}
+
+// excludedBy returns the exclusion that matches the time, if any
+func excludedBy(exclusions []exclusion, t time.Time) (exclusion, bool) {
+	for _, e := range exclusions {
+		if e.matches(t) {
+			return e, true
+		}
+	}
+	return exclusion{}, false
+}
+
+// parseJitter parses the jitter of a scheduler, a duration like "5m". An empty value means no jitter.
+func parseJitter(value string) (time.Duration, error) {
+	if strings.TrimSpace(value) == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || d < 0 {
+		return 0, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid scheduler jitter %q, expected a positive duration like 5m", value)
+	}
+	return d, nil
+}
+
+// randomJitter returns a random delay between 0 and the jitter
+func randomJitter(jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(jitter)))
+}
@@ -0,0 +1,99 @@
+package hooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_parseExclusions(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	test.NoError(t, err)
+
+	exclusions, err := parseExclusions("2019-12-25; 01-01 ;saturday;2019-08-01..2019-08-15;2019-11-29T18:00..2019-12-02T08:00", loc)
+	test.NoError(t, err)
+	assert.Equal(t, 5, len(exclusions))
+
+	at := func(s string) time.Time {
+		d, err := time.ParseInLocation(exclusionTimeLayout, s, loc)
+		test.NoError(t, err)
+		return d
+	}
+	tests := map[string]string{
+		"2019-12-25T23:59": "2019-12-25",
+		"2020-01-01T10:00": "01-01",
+		"2019-10-12T10:00": "saturday",
+		"2019-08-15T23:00": "2019-08-01..2019-08-15",
+		"2019-12-02T07:59": "2019-11-29T18:00..2019-12-02T08:00",
+		"2019-12-02T08:00": "",
+		"2019-12-26T00:00": "",
+	}
+	for date, expected := range tests {
+		e, excluded := excludedBy(exclusions, at(date))
+		assert.Equal(t, expected != "", excluded, date)
+		assert.Equal(t, expected, e.raw, date)
+	}
+
+	for _, invalid := range []string{"christmas", "2019-13-01", "2019-12-25..2019-12-20"} {
+		_, err := parseExclusions(invalid, loc)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func Test_parseJitter(t *testing.T) {
+	d, err := parseJitter("")
+	test.NoError(t, err)
+	assert.Equal(t, time.Duration(0), d)
+
+	d, err = parseJitter("5m")
+	test.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, d)
+	assert.True(t, randomJitter(d) < d)
+
+	_, err = parseJitter("-5m")
+	assert.Error(t, err)
+	_, err = parseJitter("five minutes")
+	assert.Error(t, err)
+}
+
+func Test_doScheduledTaskExecution(t *testing.T) {
+	s := Service{}
+	task := func(date string) *sdk.TaskExecution {
+		return &sdk.TaskExecution{
+			UUID:   sdk.RandomString(10),
+			Type:   TypeScheduler,
+			Status: TaskExecutionDoing,
+			Config: sdk.WorkflowNodeHookConfig{
+				sdk.SchedulerModelTimezone:   {Value: "UTC"},
+				sdk.SchedulerModelExclusions: {Value: "sunday"},
+				sdk.Payload:                  {Value: `{"release": "nightly-{{.cds.schedule.date}}", "day": "{{.cds.schedule.weekday | lower}}"}`},
+			},
+			ScheduledTask: &sdk.ScheduledTaskExecution{
+				DateScheduledExecution: date,
+			},
+		}
+	}
+
+	// Saturday
+	e := task("2019-10-12 01:00:00 +0000 UTC")
+	h, err := s.doScheduledTaskExecution(e)
+	test.NoError(t, err)
+	assert.Equal(t, TaskExecutionDoing, e.Status)
+	assert.Equal(t, "nightly-2019-10-12", h.Payload["release"])
+	assert.Equal(t, "saturday", h.Payload["day"])
+	assert.Equal(t, "cds.scheduler", h.Payload["cds.triggered_by.username"])
+	_, has := h.Payload[sdk.SchedulerModelExclusions]
+	assert.False(t, has)
+
+	// Sunday
+	e = task("2019-10-13 01:00:00 +0000 UTC")
+	h, err = s.doScheduledTaskExecution(e)
+	test.NoError(t, err)
+	assert.Nil(t, h)
+	assert.Equal(t, TaskExecutionSkipped, e.Status)
+	assert.Equal(t, "2019-10-13T01:00 is excluded by sunday", e.SkipReason)
+}
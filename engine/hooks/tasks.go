@@ -23,6 +23,8 @@ const (
 	TypeKafka              = "Kafka"
 	TypeGerrit             = "Gerrit"
 	TypeRabbitMQ           = "RabbitMQ"
+	TypeNATS               = "NATS"
+	TypeMQTT               = "MQTT"
 	TypeWorkflowHook       = "Workflow"
 	TypeOutgoingWebHook    = "OutgoingWebhook"
 	TypeOutgoingWorkflow   = "OutgoingWorkflow"
@@ -32,6 +34,14 @@ const (
 	BitbucketHeader      = "X-Event-Key"
 	BitbucketCloudHeader = "X-Event-Key_Cloud" // Fake header, do not use to fetch header, just to return custom header
 
+	// Fake headers for pull request and release events, do not use to fetch header
+	GithubPullRequestHeader         = "X-Github-Event_PullRequest"
+	GithubReleaseHeader             = "X-Github-Event_Release"
+	GitlabMergeRequestHeader        = "X-Gitlab-Event_MergeRequest"
+	GitlabReleaseHeader             = "X-Gitlab-Event_Release"
+	BitbucketPullRequestHeader      = "X-Event-Key_PullRequest"
+	BitbucketCloudPullRequestHeader = "X-Event-Key_Cloud_PullRequest"
+
 	ConfigNumber    = "Number"
 	ConfigSubNumber = "SubNumber"
 	ConfigHookID    = "HookID"
@@ -41,6 +51,7 @@ const (
 var (
 	rootKey           = cache.Key("hooks", "tasks")
 	executionRootKey  = cache.Key("hooks", "tasks", "executions")
+	connectionRootKey = cache.Key("hooks", "tasks", "connections")
 	schedulerQueueKey = cache.Key("hooks", "scheduler", "queue")
 	gerritRepoKey     = cache.Key("hooks", "gerrit", "repo")
 	gerritRepoHooks   = make(map[string]bool)
@@ -52,11 +63,11 @@ func (s *Service) runTasks(ctx context.Context) error {
 		log.Error("Hook> Unable to synchronize tasks: %v", err)
 	}
 
-	if err := s.startTasks(ctx); err != nil {
+	// Start the tasks without owner, and take over the tasks of the dead instances
+	if err := s.leaseTasksRoutine(ctx); err != nil && err != context.Canceled {
 		log.Error("Hook> Exit running tasks: %v", err)
 		return err
 	}
-	<-ctx.Done()
 	return ctx.Err()
 }
 
@@ -161,6 +172,18 @@ func (s *Service) hookToTask(h *sdk.NodeHook) (*sdk.Task, error) {
 			Type:   TypeRabbitMQ,
 			Config: h.Config,
 		}, nil
+	case sdk.NATSHookModelName:
+		return &sdk.Task{
+			UUID:   h.UUID,
+			Type:   TypeNATS,
+			Config: h.Config,
+		}, nil
+	case sdk.MQTTHookModelName:
+		return &sdk.Task{
+			UUID:   h.UUID,
+			Type:   TypeMQTT,
+			Config: h.Config,
+		}, nil
 	case sdk.WebHookModelName:
 		h.Config["webHookURL"] = sdk.WorkflowNodeHookConfigValue{
 			Value:        fmt.Sprintf("%s/webhook/%s", s.Cfg.URLPublic, h.UUID),
@@ -248,6 +271,16 @@ func (s *Service) startTask(ctx context.Context, t *sdk.Task) (*sdk.TaskExecutio
 	t.Stopped = false
 	s.Dao.SaveTask(t)
 
+	// The executions of outgoing hooks are created by any instance, the other tasks are run by their owner only.
+	// A task started by an instance is taken over by this instance, the previous owner stops it when it loses the lease.
+	if t.Type != TypeOutgoingWebHook && t.Type != TypeOutgoingWorkflow && !s.leaseTask(t) {
+		s.Dao.RevokeTaskLease(t.UUID)
+		if !s.leaseTask(t) {
+			log.Debug("Hooks> Task %s is owned by another instance", t.UUID)
+			return nil, nil
+		}
+	}
+
 	switch t.Type {
 	case TypeWebHook, TypeRepoManagerWebHook, TypeWorkflowHook:
 		return nil, nil
@@ -257,6 +290,10 @@ func (s *Service) startTask(ctx context.Context, t *sdk.Task) (*sdk.TaskExecutio
 		return nil, s.startKafkaHook(t)
 	case TypeRabbitMQ:
 		return nil, s.startRabbitMQHook(t)
+	case TypeNATS:
+		return nil, s.startNATSHook(t)
+	case TypeMQTT:
+		return nil, s.startMQTTHook(t)
 	case TypeOutgoingWebHook:
 		return s.startOutgoingWebHookTask(t)
 	case TypeOutgoingWorkflow:
@@ -294,6 +331,7 @@ func (s *Service) prepareNextScheduledTaskExecution(t *sdk.Task) error {
 
 	var exec *sdk.TaskExecution
 	var nextSchedule time.Time
+	var delay time.Duration
 	switch t.Type {
 	case TypeScheduler:
 		//Parse the cron expr
@@ -302,10 +340,19 @@ func (s *Service) prepareNextScheduledTaskExecution(t *sdk.Task) error {
 		if err != nil {
 			return sdk.WrapError(err, "unable to parse cron expression: %v", t.Config[sdk.SchedulerModelCron])
 		}
+		//Check the exclusions, they are applied when the execution is processed
+		if _, err := parseExclusions(t.Config[sdk.SchedulerModelExclusions].Value, loc); err != nil {
+			return err
+		}
+		jitter, err := parseJitter(t.Config[sdk.SchedulerModelJitter].Value)
+		if err != nil {
+			return err
+		}
 
-		//Compute a new date
+		//Compute a new date, the execution is delayed by a random jitter to spread the load of the schedulers
 		t0 := time.Now().In(loc)
 		nextSchedule = cronExpr.Next(t0)
+		delay = randomJitter(jitter)
 
 	case TypeRepoPoller:
 		// Default value of next scheduling
@@ -324,7 +371,7 @@ func (s *Service) prepareNextScheduledTaskExecution(t *sdk.Task) error {
 
 	//Craft a new execution
 	exec = &sdk.TaskExecution{
-		Timestamp: nextSchedule.UnixNano(),
+		Timestamp: nextSchedule.Add(delay).UnixNano(),
 		Status:    TaskExecutionScheduled,
 		Type:      t.Type,
 		UUID:      t.UUID,
@@ -346,15 +393,21 @@ func (s *Service) stopTask(t *sdk.Task) error {
 	log.Info("Hooks> Stopping task %s", t.UUID)
 	t.Stopped = true
 	s.Dao.SaveTask(t)
+	// The task may be owned by another instance, it will stop it when its lease is revoked
+	s.Dao.RevokeTaskLease(t.UUID)
 
 	switch t.Type {
-	case TypeWebHook, TypeScheduler, TypeRepoManagerWebHook, TypeRepoPoller, TypeKafka, TypeWorkflowHook:
+	case TypeWebHook, TypeScheduler, TypeRepoManagerWebHook, TypeRepoPoller, TypeWorkflowHook:
 		log.Debug("Hooks> Tasks %s has been stopped", t.UUID)
 		return nil
 	case TypeGerrit:
 		s.stopGerritHookTask(t)
 		log.Debug("Hooks> Gerrit Task %s has been stopped", t.UUID)
 		return nil
+	case TypeKafka, TypeRabbitMQ, TypeNATS, TypeMQTT:
+		s.stopMessageHook(t)
+		log.Debug("Hooks> Tasks %s has been stopped", t.UUID)
+		return nil
 	default:
 		return fmt.Errorf("Unsupported task type %s", t.Type)
 	}
@@ -372,6 +425,12 @@ func (s *Service) doTask(ctx context.Context, t *sdk.Task, e *sdk.TaskExecution)
 
 	var doRestart = false
 	switch {
+	case e.Replay != nil && e.Replay.Payload != nil:
+		// the replay of an execution triggers the workflow with the given payload
+		h = &sdk.WorkflowNodeRunHookEvent{
+			WorkflowNodeHookUUID: t.UUID,
+			Payload:              e.Replay.Payload,
+		}
 	case e.GerritEvent != nil:
 		h, err = s.doGerritExecution(e)
 	case e.WebHook != nil && e.Type == TypeOutgoingWebHook:
@@ -393,10 +452,19 @@ func (s *Service) doTask(ctx context.Context, t *sdk.Task, e *sdk.TaskExecution)
 		h, err = s.doKafkaTaskExecution(e)
 	case e.RabbitMQ != nil && e.Type == TypeRabbitMQ:
 		h, err = s.doRabbitMQTaskExecution(e)
+	case e.NATS != nil && e.Type == TypeNATS:
+		h, err = s.doNATSTaskExecution(e)
+	case e.MQTT != nil && e.Type == TypeMQTT:
+		h, err = s.doMQTTTaskExecution(e)
 	default:
 		err = fmt.Errorf("Unsupported task type %s", e.Type)
 	}
 
+	// a replay must not schedule the next executions of the task
+	if e.Replay != nil {
+		doRestart = false
+	}
+
 	if err != nil {
 		return doRestart, err
 	}
@@ -417,8 +485,9 @@ func (s *Service) doTask(ctx context.Context, t *sdk.Task, e *sdk.TaskExecution)
 			globalErr = err
 			log.Error("Hooks> Unable to run workflow %s", err)
 		} else {
-			//Save the run number
+			//Save the run number and its payload
 			e.WorkflowRun = run.Number
+			e.Payload = hEvent.Payload
 			log.Debug("Hooks> workflow %s/%s#%d has been triggered", confProj.Value, confWorkflow.Value, run.Number)
 		}
 	}
@@ -429,3 +498,30 @@ func (s *Service) doTask(ctx context.Context, t *sdk.Task, e *sdk.TaskExecution)
 
 	return doRestart, nil
 }
+
+// newReplayTaskExecution returns a new execution of a task from a past execution. Without payload, the workflow is triggered
+// with the payload of the past execution, or the data it received if its payload is unknown.
+func newReplayTaskExecution(e sdk.TaskExecution, payload map[string]string) (*sdk.TaskExecution, error) {
+	switch e.Type {
+	case TypeOutgoingWebHook, TypeOutgoingWorkflow, TypeRepoPoller, TypeBranchDeletion:
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "executions of %s hooks cannot be replayed", e.Type)
+	}
+	if payload == nil {
+		payload = e.Payload
+	}
+
+	replay := e
+	replay.Timestamp = time.Now().UnixNano()
+	replay.Status = ""
+	replay.NbErrors = 0
+	replay.LastError = ""
+	replay.SkipReason = ""
+	replay.ProcessingTimestamp = 0
+	replay.WorkflowRun = 0
+	replay.Payload = nil
+	replay.Replay = &sdk.TaskExecutionReplay{
+		Timestamp: e.Timestamp,
+		Payload:   payload,
+	}
+	return &replay, nil
+}
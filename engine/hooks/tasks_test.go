@@ -677,3 +677,39 @@ var githubTagEvent = `
   }
 }
 `
+
+func Test_newReplayTaskExecution(t *testing.T) {
+	e := sdk.TaskExecution{
+		UUID:                sdk.RandomString(10),
+		Type:                TypeKafka,
+		Timestamp:           42,
+		ProcessingTimestamp: 43,
+		Status:              TaskExecutionDone,
+		LastError:           "error",
+		NbErrors:            1,
+		WorkflowRun:         12,
+		Kafka:               &sdk.KafkaTaskExecution{Message: []byte(`{"version": "1.2.3"}`)},
+		Payload:             map[string]string{"version": "1.2.3"},
+	}
+
+	replay, err := newReplayTaskExecution(e, nil)
+	test.NoError(t, err)
+	assert.Equal(t, e.UUID, replay.UUID)
+	assert.NotEqual(t, e.Timestamp, replay.Timestamp)
+	assert.Equal(t, int64(0), replay.ProcessingTimestamp)
+	assert.Equal(t, int64(0), replay.WorkflowRun)
+	assert.Equal(t, int64(0), replay.NbErrors)
+	assert.Empty(t, replay.Status)
+	assert.Empty(t, replay.LastError)
+	assert.Nil(t, replay.Payload)
+	assert.Equal(t, e.Kafka, replay.Kafka)
+	assert.Equal(t, &sdk.TaskExecutionReplay{Timestamp: 42, Payload: map[string]string{"version": "1.2.3"}}, replay.Replay)
+
+	replay, err = newReplayTaskExecution(e, map[string]string{"version": "1.2.4"})
+	test.NoError(t, err)
+	assert.Equal(t, "1.2.4", replay.Replay.Payload["version"])
+
+	e.Type = TypeOutgoingWebHook
+	_, err = newReplayTaskExecution(e, nil)
+	assert.Error(t, err)
+}
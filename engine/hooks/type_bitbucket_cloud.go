@@ -308,3 +308,39 @@ type BitbucketCloudPushEvent struct {
 		UUID      string `json:"uuid"`
 	} `json:"repository"`
 }
+
+// BitbucketCloudPullRequestEvent represents payload send by bitbucket cloud on a pull request event
+type BitbucketCloudPullRequestEvent struct {
+	Actor struct {
+		Username    string `json:"username"`
+		DisplayName string `json:"display_name"`
+	} `json:"actor"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	PullRequest struct {
+		ID          int                          `json:"id"`
+		Title       string                       `json:"title"`
+		Description string                       `json:"description"`
+		Source      BitbucketCloudPullRequestRef `json:"source"`
+		Destination BitbucketCloudPullRequestRef `json:"destination"`
+		Links       struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+	} `json:"pullrequest"`
+}
+
+// BitbucketCloudPullRequestRef is the source or the destination of a pull request
+type BitbucketCloudPullRequestRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
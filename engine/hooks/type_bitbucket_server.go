@@ -42,3 +42,39 @@ type BitbucketServerPushEvent struct {
 		Type     string `json:"type"`
 	} `json:"changes"`
 }
+
+// BitbucketServerPullRequestEvent represents payload send by bitbucket server on a pull request event
+type BitbucketServerPullRequestEvent struct {
+	EventKey string `json:"eventKey"`
+	Date     string `json:"date"`
+	Actor    struct {
+		Name         string `json:"name"`
+		EmailAddress string `json:"emailAddress"`
+		DisplayName  string `json:"displayName"`
+	} `json:"actor"`
+	PullRequest struct {
+		ID          int                           `json:"id"`
+		Title       string                        `json:"title"`
+		Description string                        `json:"description"`
+		FromRef     BitbucketServerPullRequestRef `json:"fromRef"`
+		ToRef       BitbucketServerPullRequestRef `json:"toRef"`
+		Links       struct {
+			Self []struct {
+				Href string `json:"href"`
+			} `json:"self"`
+		} `json:"links"`
+	} `json:"pullRequest"`
+}
+
+// BitbucketServerPullRequestRef is the source or the destination of a pull request
+type BitbucketServerPullRequestRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
+		Slug    string `json:"slug"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+	} `json:"repository"`
+}
@@ -32,9 +32,9 @@ type GithubPushEvent struct {
 			Email    string `json:"email"`
 			Username string `json:"username"`
 		} `json:"committer"`
-		Added    []interface{} `json:"added"`
-		Removed  []interface{} `json:"removed"`
-		Modified []string      `json:"modified"`
+		Added    []string `json:"added"`
+		Removed  []string `json:"removed"`
+		Modified []string `json:"modified"`
 	} `json:"commits"`
 	HeadCommit struct {
 		ID        string `json:"id"`
@@ -174,3 +174,56 @@ func (g *GithubPushEvent) GetCommits() []sdk.VCSCommit {
 	}
 	return commits
 }
+
+// GithubUser represents a github user in an event
+type GithubUser struct {
+	Login string `json:"login"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// GithubPullRequestEvent represents payload send by github on a pull request event
+type GithubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		HTMLURL string               `json:"html_url"`
+		Title   string               `json:"title"`
+		Body    string               `json:"body"`
+		Merged  bool                 `json:"merged"`
+		User    GithubUser           `json:"user"`
+		Head    GithubPullRequestRef `json:"head"`
+		Base    GithubPullRequestRef `json:"base"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender GithubUser `json:"sender"`
+}
+
+// GithubPullRequestRef is the head or the base of a pull request, the repository of the head is empty when the fork has been deleted
+type GithubPullRequestRef struct {
+	Ref  string `json:"ref"`
+	Sha  string `json:"sha"`
+	Repo struct {
+		FullName string `json:"full_name"`
+	} `json:"repo"`
+}
+
+// GithubReleaseEvent represents payload send by github on a release event
+type GithubReleaseEvent struct {
+	Action  string `json:"action"`
+	Release struct {
+		HTMLURL         string     `json:"html_url"`
+		TagName         string     `json:"tag_name"`
+		TargetCommitish string     `json:"target_commitish"`
+		Name            string     `json:"name"`
+		Body            string     `json:"body"`
+		Prerelease      bool       `json:"prerelease"`
+		Author          GithubUser `json:"author"`
+	} `json:"release"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender GithubUser `json:"sender"`
+}
@@ -53,9 +53,9 @@ type GitlabPushEvent struct {
 			Name  string `json:"name"`
 			Email string `json:"email"`
 		} `json:"author"`
-		Added    []string      `json:"added"`
-		Modified []string      `json:"modified"`
-		Removed  []interface{} `json:"removed"`
+		Added    []string `json:"added"`
+		Modified []string `json:"modified"`
+		Removed  []string `json:"removed"`
 	} `json:"commits"`
 	TotalCommitsCount int `json:"total_commits_count"`
 }
@@ -78,3 +78,51 @@ func (g *GitlabPushEvent) GetCommits() []sdk.VCSCommit {
 	}
 	return commits
 }
+
+// GitlabMergeRequestEvent represents payload send by gitlab on a merge request event
+type GitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Name     string `json:"name"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID          int    `json:"iid"`
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		URL          string `json:"url"`
+		Action       string `json:"action"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+		Source       struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"source"`
+		Target struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		} `json:"target"`
+		LastCommit struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+}
+
+// GitlabReleaseEvent represents payload send by gitlab on a release event
+type GitlabReleaseEvent struct {
+	ObjectKind  string `json:"object_kind"`
+	Action      string `json:"action"`
+	Name        string `json:"name"`
+	Tag         string `json:"tag"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	Project     struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	Commit struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
@@ -8,9 +8,12 @@ import (
 
 // Task execution status
 const (
-	TaskExecutionDoing     = "DOING"
-	TaskExecutionDone      = "DONE"
-	TaskExecutionScheduled = "SCHEDULED"
+	TaskExecutionDoing      = "DOING"
+	TaskExecutionDone       = "DONE"
+	TaskExecutionScheduled  = "SCHEDULED"
+	TaskExecutionRejected   = "REJECTED"
+	TaskExecutionSkipped    = "SKIPPED"
+	TaskExecutionDeadLetter = "DEAD_LETTER" // outgoing webhook not delivered after all its attempts, kept for debugging
 )
 
 // Service is the stuct representing a hooks µService
@@ -20,6 +23,9 @@ type Service struct {
 	Router *api.Router
 	Cache  cache.Store
 	Dao    dao
+	// instance identifies this hooks instance, owner of the leases of its tasks
+	instance string
+	leases   taskLeases
 }
 
 // Configuration is the hooks configuration structure
@@ -30,13 +30,25 @@ func (s *Service) doWebHookExecution(e *sdk.TaskExecution) ([]sdk.WorkflowNodeRu
 func getRepositoryHeader(whe *sdk.WebHookExecution) string {
 	if v, ok := whe.RequestHeader[GithubHeader]; ok && v[0] == "push" {
 		return GithubHeader
-	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && v[0] == "Push Hook" {
+	} else if v, ok := whe.RequestHeader[GithubHeader]; ok && v[0] == "pull_request" {
+		return GithubPullRequestHeader
+	} else if v, ok := whe.RequestHeader[GithubHeader]; ok && v[0] == "release" {
+		return GithubReleaseHeader
+	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && (v[0] == "Push Hook" || v[0] == "Tag Push Hook") {
 		return GitlabHeader
+	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && v[0] == "Merge Request Hook" {
+		return GitlabMergeRequestHeader
+	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && v[0] == "Release Hook" {
+		return GitlabReleaseHeader
 	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && v[0] == "repo:refs_changed" {
 		return BitbucketHeader
+	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && strings.HasPrefix(v[0], "pr:") {
+		return BitbucketPullRequestHeader
 	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && v[0] == "repo:push" {
 		// We return a fake header to make a difference between server and cloud version
 		return BitbucketCloudHeader
+	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && strings.HasPrefix(v[0], "pullrequest:") {
+		return BitbucketCloudPullRequestHeader
 	}
 	return ""
 }
@@ -83,6 +95,18 @@ func (s *Service) executeRepositoryWebHook(t *sdk.TaskExecution) ([]sdk.Workflow
 		if len(pushEvent.Commits) > 0 {
 			payload["git.message"] = pushEvent.Commits[0].Message
 		}
+		var changedFiles []string
+		for _, c := range pushEvent.Commits {
+			changedFiles = append(changedFiles, c.Added...)
+			changedFiles = append(changedFiles, c.Removed...)
+			changedFiles = append(changedFiles, c.Modified...)
+		}
+		if len(pushEvent.Commits) >= githubPayloadMaxCommits {
+			if files := s.changedFiles(t, pushEvent.Before, pushEvent.After); files != nil {
+				changedFiles = files
+			}
+		}
+		payload[payloadChangedFiles] = changedFiles
 		for i := range pushEvent.Commits {
 			pushEvent.Commits[i].Added = nil
 			pushEvent.Commits[i].Removed = nil
@@ -102,6 +126,9 @@ func (s *Service) executeRepositoryWebHook(t *sdk.TaskExecution) ([]sdk.Workflow
 		}
 		// Branch deletion ( gitlab return 0000000000000000000000000000000000000000 as git hash)
 		if pushEvent.After == "0000000000000000000000000000000000000000" {
+			if strings.HasPrefix(pushEvent.Ref, "refs/tags/") {
+				return nil, nil
+			}
 			err := s.enqueueBranchDeletion(projectKey, workflowName, strings.TrimPrefix(pushEvent.Ref, "refs/heads/"))
 			return nil, sdk.WrapError(err, "cannot enqueue branch deletion")
 		}
@@ -128,6 +155,19 @@ func (s *Service) executeRepositoryWebHook(t *sdk.TaskExecution) ([]sdk.Workflow
 		if len(pushEvent.Commits) > 0 {
 			payload["git.message"] = pushEvent.Commits[0].Message
 		}
+		var changedFiles []string
+		for _, c := range pushEvent.Commits {
+			changedFiles = append(changedFiles, c.Added...)
+			changedFiles = append(changedFiles, c.Removed...)
+			changedFiles = append(changedFiles, c.Modified...)
+		}
+		// The payload does not contain all the commits of big pushes
+		if pushEvent.TotalCommitsCount > len(pushEvent.Commits) {
+			if files := s.changedFiles(t, pushEvent.Before, pushEvent.After); files != nil {
+				changedFiles = files
+			}
+		}
+		payload[payloadChangedFiles] = changedFiles
 		payloadStr, err := json.Marshal(pushEvent)
 		if err != nil {
 			log.Error("Unable to marshal payload: %v", err)
@@ -172,6 +212,7 @@ func (s *Service) executeRepositoryWebHook(t *sdk.TaskExecution) ([]sdk.Workflow
 			payload["cds.triggered_by.username"] = pushEvent.Actor.Name
 			payload["cds.triggered_by.fullname"] = pushEvent.Actor.DisplayName
 			payload["cds.triggered_by.email"] = pushEvent.Actor.EmailAddress
+			payload[payloadChangedFiles] = s.changedFiles(t, pushChange.FromHash, pushChange.ToHash)
 			payloadStr, err := json.Marshal(pushEvent)
 			if err != nil {
 				log.Error("Unable to marshal payload: %v", err)
@@ -224,6 +265,7 @@ func (s *Service) executeRepositoryWebHook(t *sdk.TaskExecution) ([]sdk.Workflow
 
 			payload["cds.triggered_by.username"] = event.Actor.Username
 			payload["cds.triggered_by.fullname"] = event.Actor.DisplayName
+			payload[payloadChangedFiles] = s.changedFiles(t, pushChange.Old.Target.Hash, pushChange.New.Target.Hash)
 			payloadStr, err := json.Marshal(pushEvent)
 			if err != nil {
 				log.Error("Unable to marshal payload: %v", err)
@@ -231,13 +273,76 @@ func (s *Service) executeRepositoryWebHook(t *sdk.TaskExecution) ([]sdk.Workflow
 			payload["payload"] = string(payloadStr)
 			payloads = append(payloads, payload)
 		}
+	case GithubPullRequestHeader:
+		payload, err := githubPullRequestPayload(t.WebHook.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		payloads = appendPayload(payloads, payload)
+	case GithubReleaseHeader:
+		payload, err := githubReleasePayload(t.WebHook.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		payloads = appendPayload(payloads, payload)
+	case GitlabMergeRequestHeader:
+		payload, err := gitlabMergeRequestPayload(t.WebHook.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		payloads = appendPayload(payloads, payload)
+	case GitlabReleaseHeader:
+		payload, err := gitlabReleasePayload(t.WebHook.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		payloads = appendPayload(payloads, payload)
+	case BitbucketPullRequestHeader:
+		payload, err := bitbucketServerPullRequestPayload(t.WebHook.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		payloads = appendPayload(payloads, payload)
+	case BitbucketCloudPullRequestHeader:
+		payload, err := bitbucketCloudPullRequestPayload(t.WebHook.RequestHeader[BitbucketHeader][0], t.WebHook.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		payloads = appendPayload(payloads, payload)
 	default:
 		log.Warning("executeRepositoryWebHook> Repository manager not found. Cannot read %s", string(t.WebHook.RequestBody))
 		return nil, fmt.Errorf("Repository manager not found. Cannot read request body")
 	}
 
 	hs := make([]sdk.WorkflowNodeRunHookEvent, 0, len(payloads))
+	var skipped []string
 	for _, payload := range payloads {
+		changedFiles, _ := payload[payloadChangedFiles].([]string)
+		delete(payload, payloadChangedFiles)
+		skipReason, _ := payload[payloadSkipReason].(string)
+		delete(payload, payloadSkipReason)
+		if _, has := payload["git.event"]; !has {
+			payload["git.event"] = sdk.RepositoryEventPush
+			if _, isTag := payload["git.tag"]; isTag {
+				payload["git.event"] = sdk.RepositoryEventTag
+			}
+		}
+		if skipReason != "" {
+			log.Debug("executeRepositoryWebHook> %s skipped on hook %s", skipReason, t.UUID)
+			skipped = append(skipped, skipReason)
+			continue
+		}
+		if !matchRepositoryEventFilters(t.Config, payload) {
+			log.Debug("executeRepositoryWebHook> event %v filtered on hook %s", payload["git.event"], t.UUID)
+			skipped = append(skipped, describeRepositoryEvent(payload)+": filtered by the hook")
+			continue
+		}
+		if !matchWorkflowPathFilters(t.Config, changedFiles) {
+			log.Debug("executeRepositoryWebHook> no changed file matches the paths of the workflow on hook %s", t.UUID)
+			skipped = append(skipped, describeRepositoryEvent(payload)+": no changed file matches the paths of the workflow")
+			continue
+		}
+
 		h := sdk.WorkflowNodeRunHookEvent{
 			WorkflowNodeHookUUID: t.UUID,
 		}
@@ -254,6 +359,7 @@ func (s *Service) executeRepositoryWebHook(t *sdk.TaskExecution) ([]sdk.Workflow
 		h.Payload = payloadValues
 		hs = append(hs, h)
 	}
+	skipTaskExecution(t, skipped, len(hs))
 
 	return hs, nil
 }
@@ -0,0 +1,331 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// payloadChangedFiles is the key of the files changed by a push in a payload, it is removed before sending the payload to the API
+const payloadChangedFiles = "__changed_files"
+
+// payloadSkipReason is the key of the reason why an event must not trigger the workflow, it is removed before sending the payload to the API
+const payloadSkipReason = "__skip_reason"
+
+// githubPayloadMaxCommits is the maximum number of commits, with their changed files, in the payload of a github push event
+const githubPayloadMaxCommits = 20
+
+// Actions of pull request events
+const (
+	pullRequestOpened  = "opened"
+	pullRequestUpdated = "updated"
+	pullRequestClosed  = "closed"
+	pullRequestMerged  = "merged"
+)
+
+// appendPayload appends a payload if it is not nil, events with ignored actions have no payload.
+func appendPayload(payloads []map[string]interface{}, payload map[string]interface{}) []map[string]interface{} {
+	if payload == nil {
+		return payloads
+	}
+	return append(payloads, payload)
+}
+
+// matchRepositoryEventFilters checks the kind of the event, its branch or tag and its changed files against the filters of the hook.
+// Hooks without event filter are triggered by push events. A push of a tag is a push, so it matches both the push and the tag events.
+func matchRepositoryEventFilters(config sdk.WorkflowNodeHookConfig, payload map[string]interface{}) bool {
+	events := config[sdk.HookConfigEventFilter].Value
+	if events == "" {
+		events = sdk.RepositoryEventsFilter
+	}
+	filter := strings.Split(events, ";")
+	event, _ := payload["git.event"].(string)
+	if !sdk.IsInArray(event, filter) && !(event == sdk.RepositoryEventTag && sdk.IsInArray(sdk.RepositoryEventPush, filter)) {
+		return false
+	}
+
+	ref, _ := payload["git.branch"].(string)
+	if tag, ok := payload["git.tag"].(string); ok {
+		ref = tag
+	}
+	return sdk.GlobsMatch(config[sdk.HookConfigRefFilter].Value, ref)
+}
+
+// hasPathFilters returns true if the workflow filter the changed files.
+func hasPathFilters(config sdk.WorkflowNodeHookConfig) bool {
+	for _, k := range []string{sdk.HookConfigIncludePaths, sdk.HookConfigExcludePaths} {
+		if strings.TrimSpace(config[k].Value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWorkflowPathFilters checks the changed files against the include and exclude paths of the workflow. A file matches if it matches
+// one of the include paths, or if there is none, and none of the exclude paths. The changed files are not filtered when they are unknown.
+func matchWorkflowPathFilters(config sdk.WorkflowNodeHookConfig, changedFiles []string) bool {
+	includes := config[sdk.HookConfigIncludePaths].Value
+	excludes := config[sdk.HookConfigExcludePaths].Value
+	if changedFiles == nil || (strings.TrimSpace(includes) == "" && strings.TrimSpace(excludes) == "") {
+		return true
+	}
+	for _, f := range changedFiles {
+		if !sdk.GlobsMatch(includes, f) {
+			continue
+		}
+		if strings.TrimSpace(excludes) != "" && sdk.GlobsMatch(excludes, f) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// changedFiles returns the files changed between two commits of the repository of the hook, they are computed by the repositories manager
+// only when the hook or the workflow filter the changed files. It returns nil when the changed files are unknown.
+func (s *Service) changedFiles(t *sdk.TaskExecution, before, after string) []string {
+	if !hasPathFilters(t.Config) || isNullHash(before) || isNullHash(after) {
+		return nil
+	}
+	files, err := s.Client.HookChangedFiles(t.UUID, before, after)
+	if err != nil {
+		log.Warning("Hooks> unable to get changed files between %s and %s for task %s: %v", before, after, t.UUID, err)
+		return nil
+	}
+	if files == nil {
+		files = []string{}
+	}
+	return files
+}
+
+// isNullHash returns true for an empty hash or for the hash sent by the repositories managers when a ref is created or deleted.
+func isNullHash(hash string) bool {
+	return strings.Trim(hash, "0") == ""
+}
+
+// describeRepositoryEvent returns the kind and the branch or the tag of a repository event, it is used in the skip reasons.
+func describeRepositoryEvent(payload map[string]interface{}) string {
+	event, _ := payload["git.event"].(string)
+	if tag, ok := payload["git.tag"].(string); ok {
+		return fmt.Sprintf("%s %s", event, tag)
+	}
+	branch, _ := payload["git.branch"].(string)
+	return fmt.Sprintf("%s on %s", event, branch)
+}
+
+// skipTaskExecution records why some events of a task execution did not trigger the workflow.
+// The execution is skipped when none of its events triggered the workflow.
+func skipTaskExecution(e *sdk.TaskExecution, reasons []string, triggered int) {
+	if len(reasons) == 0 {
+		return
+	}
+	e.SkipReason = strings.Join(reasons, "\n")
+	if triggered == 0 {
+		e.Status = TaskExecutionSkipped
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) >= 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// pullRequestPayload returns the payload of a pull request event. The pull requests opened from another repository, like a fork,
+// are skipped: their branch and their commit do not exist in the repository of the workflow and their code must not be run blindly.
+func pullRequestPayload(action string, id int, title, url, branch, baseBranch, hash, repository, headRepository string) map[string]interface{} {
+	payload := map[string]interface{}{
+		"git.event":          sdk.RepositoryEventPullRequest,
+		"git.pr.action":      action,
+		"git.pr.id":          strconv.Itoa(id),
+		"git.pr.title":       title,
+		"git.pr.url":         url,
+		"git.pr.base.branch": baseBranch,
+		"git.branch":         branch,
+		"git.hash":           hash,
+		"git.hash.short":     shortHash(hash),
+		"git.repository":     repository,
+	}
+	if headRepository != repository {
+		payload[payloadSkipReason] = fmt.Sprintf("pull request #%d from the repository %s", id, headRepository)
+	}
+	return payload
+}
+
+func githubPullRequestPayload(body []byte) (map[string]interface{}, error) {
+	var event GithubPullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read github request: %s", string(body))
+	}
+
+	var action string
+	switch event.Action {
+	case "opened", "reopened":
+		action = pullRequestOpened
+	case "synchronize":
+		action = pullRequestUpdated
+	case "closed":
+		action = pullRequestClosed
+		if event.PullRequest.Merged {
+			action = pullRequestMerged
+		}
+	default:
+		return nil, nil
+	}
+
+	pr := event.PullRequest
+	payload := pullRequestPayload(action, event.Number, pr.Title, pr.HTMLURL, pr.Head.Ref, pr.Base.Ref, pr.Head.Sha, event.Repository.FullName, pr.Head.Repo.FullName)
+	payload["git.author"] = pr.User.Login
+	payload["cds.triggered_by.username"] = event.Sender.Login
+	payload["cds.triggered_by.fullname"] = event.Sender.Name
+	payload["payload"] = string(body)
+	return payload, nil
+}
+
+func githubReleasePayload(body []byte) (map[string]interface{}, error) {
+	var event GithubReleaseEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read github request: %s", string(body))
+	}
+	if event.Action != "published" {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"git.event":                 sdk.RepositoryEventRelease,
+		"git.tag":                   event.Release.TagName,
+		"git.release.name":          event.Release.Name,
+		"git.release.url":           event.Release.HTMLURL,
+		"git.release.target":        event.Release.TargetCommitish,
+		"git.release.prerelease":    strconv.FormatBool(event.Release.Prerelease),
+		"git.repository":            event.Repository.FullName,
+		"git.author":                event.Release.Author.Login,
+		"cds.triggered_by.username": event.Sender.Login,
+		"cds.triggered_by.fullname": event.Sender.Name,
+		"payload":                   string(body),
+	}, nil
+}
+
+func gitlabMergeRequestPayload(body []byte) (map[string]interface{}, error) {
+	var event GitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read gitlab request: %s", string(body))
+	}
+
+	var action string
+	switch event.ObjectAttributes.Action {
+	case "open", "reopen":
+		action = pullRequestOpened
+	case "update":
+		action = pullRequestUpdated
+	case "close":
+		action = pullRequestClosed
+	case "merge":
+		action = pullRequestMerged
+	default:
+		return nil, nil
+	}
+
+	mr := event.ObjectAttributes
+	payload := pullRequestPayload(action, mr.IID, mr.Title, mr.URL, mr.SourceBranch, mr.TargetBranch, mr.LastCommit.ID, mr.Target.PathWithNamespace, mr.Source.PathWithNamespace)
+	payload["git.message"] = mr.LastCommit.Message
+	payload["git.author"] = event.User.Username
+	payload["git.author.email"] = event.User.Email
+	payload["cds.triggered_by.username"] = event.User.Username
+	payload["cds.triggered_by.fullname"] = event.User.Name
+	payload["cds.triggered_by.email"] = event.User.Email
+	payload["payload"] = string(body)
+	return payload, nil
+}
+
+func gitlabReleasePayload(body []byte) (map[string]interface{}, error) {
+	var event GitlabReleaseEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read gitlab request: %s", string(body))
+	}
+	if event.Action != "create" {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"git.event":        sdk.RepositoryEventRelease,
+		"git.tag":          event.Tag,
+		"git.release.name": event.Name,
+		"git.release.url":  event.URL,
+		"git.hash":         event.Commit.ID,
+		"git.hash.short":   shortHash(event.Commit.ID),
+		"git.repository":   event.Project.PathWithNamespace,
+		"payload":          string(body),
+	}, nil
+}
+
+func bitbucketServerPullRequestPayload(body []byte) (map[string]interface{}, error) {
+	var event BitbucketServerPullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read bitbucket request: %s", string(body))
+	}
+
+	var action string
+	switch event.EventKey {
+	case "pr:opened":
+		action = pullRequestOpened
+	case "pr:from_ref_updated":
+		action = pullRequestUpdated
+	case "pr:declined", "pr:deleted":
+		action = pullRequestClosed
+	case "pr:merged":
+		action = pullRequestMerged
+	default:
+		return nil, nil
+	}
+
+	pr := event.PullRequest
+	var url string
+	if len(pr.Links.Self) > 0 {
+		url = pr.Links.Self[0].Href
+	}
+	repository := fmt.Sprintf("%s/%s", pr.ToRef.Repository.Project.Key, pr.ToRef.Repository.Slug)
+	headRepository := fmt.Sprintf("%s/%s", pr.FromRef.Repository.Project.Key, pr.FromRef.Repository.Slug)
+	payload := pullRequestPayload(action, pr.ID, pr.Title, url, pr.FromRef.DisplayID, pr.ToRef.DisplayID, pr.FromRef.LatestCommit, repository, headRepository)
+	payload["git.author"] = event.Actor.Name
+	payload["git.author.email"] = event.Actor.EmailAddress
+	payload["cds.triggered_by.username"] = event.Actor.Name
+	payload["cds.triggered_by.fullname"] = event.Actor.DisplayName
+	payload["cds.triggered_by.email"] = event.Actor.EmailAddress
+	payload["payload"] = string(body)
+	return payload, nil
+}
+
+func bitbucketCloudPullRequestPayload(eventKey string, body []byte) (map[string]interface{}, error) {
+	var event BitbucketCloudPullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read bitbucket request: %s", string(body))
+	}
+
+	var action string
+	switch eventKey {
+	case "pullrequest:created":
+		action = pullRequestOpened
+	case "pullrequest:updated":
+		action = pullRequestUpdated
+	case "pullrequest:rejected":
+		action = pullRequestClosed
+	case "pullrequest:fulfilled":
+		action = pullRequestMerged
+	default:
+		return nil, nil
+	}
+
+	pr := event.PullRequest
+	payload := pullRequestPayload(action, pr.ID, pr.Title, pr.Links.HTML.Href, pr.Source.Branch.Name, pr.Destination.Branch.Name, pr.Source.Commit.Hash, pr.Destination.Repository.FullName, pr.Source.Repository.FullName)
+	payload["git.author"] = event.Actor.DisplayName
+	payload["cds.triggered_by.username"] = event.Actor.Username
+	payload["cds.triggered_by.fullname"] = event.Actor.DisplayName
+	payload["payload"] = string(body)
+	return payload, nil
+}
@@ -0,0 +1,154 @@
+package hooks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const githubPullRequestEvent = `{
+  "action": "synchronize",
+  "number": 42,
+  "pull_request": {
+    "html_url": "https://github.com/baxterthehacker/public-repo/pull/42",
+    "title": "Update the README",
+    "merged": false,
+    "user": {"login": "baxterthehacker"},
+    "head": {"ref": "my-branch", "sha": "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", "repo": {"full_name": "baxterthehacker/public-repo"}},
+    "base": {"ref": "master", "sha": "9049f1265b7d61be4a8904a9a27120d2064dab3b", "repo": {"full_name": "baxterthehacker/public-repo"}}
+  },
+  "repository": {"full_name": "baxterthehacker/public-repo"},
+  "sender": {"login": "baxterthehacker"}
+}`
+
+func Test_doWebHookExecutionPullRequestGithub(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: {Value: sdk.RepositoryEventPullRequest},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(githubPullRequestEvent),
+			RequestHeader: map[string][]string{
+				GithubHeader: {"pull_request"},
+			},
+		},
+	}
+	hs, err := s.doWebHookExecution(task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, "pull_request", hs[0].Payload["git.event"])
+	assert.Equal(t, "updated", hs[0].Payload["git.pr.action"])
+	assert.Equal(t, "42", hs[0].Payload["git.pr.id"])
+	assert.Equal(t, "master", hs[0].Payload["git.pr.base.branch"])
+	assert.Equal(t, "my-branch", hs[0].Payload["git.branch"])
+	assert.Equal(t, "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", hs[0].Payload["git.hash"])
+
+	// Pull request events are ignored by default
+	task.Config = nil
+	hs, err = s.doWebHookExecution(task)
+	test.NoError(t, err)
+	assert.Equal(t, 0, len(hs))
+
+	// Pull requests from a fork are skipped
+	task.Config = sdk.WorkflowNodeHookConfig{
+		sdk.HookConfigEventFilter: {Value: sdk.RepositoryEventPullRequest},
+	}
+	task.WebHook.RequestBody = []byte(strings.Replace(githubPullRequestEvent, `"sha": "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", "repo": {"full_name": "baxterthehacker/public-repo"}`,
+		`"sha": "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", "repo": {"full_name": "someone/public-repo"}`, 1))
+	hs, err = s.doWebHookExecution(task)
+	test.NoError(t, err)
+	assert.Equal(t, 0, len(hs))
+	assert.Equal(t, TaskExecutionSkipped, task.Status)
+	assert.Equal(t, "pull request #42 from the repository someone/public-repo", task.SkipReason)
+}
+
+func Test_matchRepositoryEventFilters(t *testing.T) {
+	push := map[string]interface{}{"git.event": sdk.RepositoryEventPush, "git.branch": "release/1.0"}
+	tag := map[string]interface{}{"git.event": sdk.RepositoryEventTag, "git.tag": "v1.0.0"}
+	pullRequest := map[string]interface{}{"git.event": sdk.RepositoryEventPullRequest, "git.branch": "my-branch"}
+	config := func(events, refs string) sdk.WorkflowNodeHookConfig {
+		return sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: {Value: events},
+			sdk.HookConfigRefFilter:   {Value: refs},
+		}
+	}
+
+	assert.True(t, matchRepositoryEventFilters(nil, push))
+	assert.True(t, matchRepositoryEventFilters(nil, tag))
+	assert.False(t, matchRepositoryEventFilters(nil, pullRequest))
+	assert.True(t, matchRepositoryEventFilters(config("push", ""), tag))
+	assert.False(t, matchRepositoryEventFilters(config("tag", ""), push))
+	assert.True(t, matchRepositoryEventFilters(config("tag;pull_request", ""), pullRequest))
+	assert.True(t, matchRepositoryEventFilters(config("", "release/*"), push))
+	assert.False(t, matchRepositoryEventFilters(config("", "master"), push))
+	assert.True(t, matchRepositoryEventFilters(config("", "v*"), tag))
+}
+
+func Test_doWebHookExecutionWorkflowPathsGithub(t *testing.T) {
+	log.SetLogger(t)
+	s := Service{}
+	newTask := func(includes, excludes string) *sdk.TaskExecution {
+		return &sdk.TaskExecution{
+			UUID: sdk.RandomString(10),
+			Type: TypeRepoManagerWebHook,
+			Config: sdk.WorkflowNodeHookConfig{
+				sdk.HookConfigIncludePaths: {Value: includes},
+				sdk.HookConfigExcludePaths: {Value: excludes},
+			},
+			WebHook: &sdk.WebHookExecution{
+				RequestBody: []byte(githubPushEvent),
+				RequestHeader: map[string][]string{
+					GithubHeader: {"push"},
+				},
+			},
+		}
+	}
+
+	// The push only changes README.md
+	task := newTask("*.md", "")
+	hs, err := s.doWebHookExecution(task)
+	test.NoError(t, err)
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, "", task.Status)
+
+	task = newTask("engine/**", "")
+	hs, err = s.doWebHookExecution(task)
+	test.NoError(t, err)
+	assert.Equal(t, 0, len(hs))
+	assert.Equal(t, TaskExecutionSkipped, task.Status)
+	assert.Equal(t, "push on my-branch: no changed file matches the paths of the workflow", task.SkipReason)
+
+	task = newTask("", "docs/**;*.md")
+	hs, err = s.doWebHookExecution(task)
+	test.NoError(t, err)
+	assert.Equal(t, 0, len(hs))
+	assert.Equal(t, TaskExecutionSkipped, task.Status)
+}
+
+func Test_matchWorkflowPathFilters(t *testing.T) {
+	config := func(includes, excludes string) sdk.WorkflowNodeHookConfig {
+		return sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigIncludePaths: {Value: includes},
+			sdk.HookConfigExcludePaths: {Value: excludes},
+		}
+	}
+	files := []string{"engine/api/api.go", "engine/api/README.md"}
+
+	assert.True(t, matchWorkflowPathFilters(nil, files))
+	assert.True(t, matchWorkflowPathFilters(config("engine/**", ""), files))
+	assert.False(t, matchWorkflowPathFilters(config("ui/**", ""), files))
+	assert.True(t, matchWorkflowPathFilters(config("", "**.md"), files))
+	assert.False(t, matchWorkflowPathFilters(config("engine/**", "**.md;**.go"), files))
+	assert.True(t, matchWorkflowPathFilters(config("ui/**", ""), nil))
+	assert.False(t, matchWorkflowPathFilters(config("ui/**", ""), []string{}))
+}
@@ -0,0 +1,104 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Headers carrying the signature or the token of a webhook request
+const (
+	GithubSignatureHeader   = "X-Hub-Signature-256"
+	HubSignatureHeader      = "X-Hub-Signature" // Bitbucket server, Bitbucket cloud and legacy Github signature
+	GitlabTokenHeader       = "X-Gitlab-Token"
+	WebHookSignatureHeader  = "X-Cds-Signature-256"
+	WebHookDeliveryHeader   = "X-Cds-Delivery" // Identifier of an outgoing webhook delivery, the same for all its attempts
+	WebHookAttemptHeader    = "X-Cds-Attempt"
+	WebHookAuthorizationKey = "Bearer "
+)
+
+// checkWebHookSignature verifies a webhook request with the secret of the task. Tasks without secret accept all requests.
+func checkWebHookSignature(t *sdk.Task, header http.Header, body []byte) error {
+	secret := t.Config[sdk.HookConfigWebHookSecret].Value
+
+	switch t.Type {
+	case TypeRepoManagerWebHook:
+		if secret == "" {
+			return nil
+		}
+		if s := header.Get(GithubSignatureHeader); s != "" {
+			return checkHMACSignature(secret, s, body)
+		}
+		if s := header.Get(GitlabTokenHeader); s != "" {
+			if subtle.ConstantTimeCompare([]byte(s), []byte(secret)) != 1 {
+				return sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid %s header", GitlabTokenHeader)
+			}
+			return nil
+		}
+		if s := header.Get(HubSignatureHeader); s != "" {
+			return checkHMACSignature(secret, s, body)
+		}
+		return sdk.NewErrorFrom(sdk.ErrUnauthorized, "missing signature of repository webhook")
+	case TypeWebHook:
+		switch t.Config[sdk.WebHookModelConfigAuth].Value {
+		case sdk.WebHookAuthHMAC:
+			s := header.Get(WebHookSignatureHeader)
+			if s == "" {
+				return sdk.NewErrorFrom(sdk.ErrUnauthorized, "missing %s header", WebHookSignatureHeader)
+			}
+			return checkHMACSignature(secret, s, body)
+		case sdk.WebHookAuthBearer:
+			if secret == "" {
+				return sdk.NewErrorFrom(sdk.ErrUnauthorized, "no secret to verify the bearer token")
+			}
+			s := header.Get("Authorization")
+			if !strings.HasPrefix(s, WebHookAuthorizationKey) ||
+				subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(s, WebHookAuthorizationKey)), []byte(secret)) != 1 {
+				return sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid bearer token")
+			}
+		}
+	}
+	return nil
+}
+
+// checkHMACSignature verifies a signature formatted as "sha256=<hex>" or "sha1=<hex>".
+func checkHMACSignature(secret, signature string, body []byte) error {
+	if secret == "" {
+		return sdk.NewErrorFrom(sdk.ErrUnauthorized, "no secret to verify the signature")
+	}
+
+	var h func() hash.Hash
+	switch {
+	case strings.HasPrefix(signature, "sha256="):
+		h = sha256.New
+	case strings.HasPrefix(signature, "sha1="):
+		h = sha1.New
+	default:
+		return sdk.NewErrorFrom(sdk.ErrUnauthorized, "unsupported signature algorithm")
+	}
+	sig, err := hex.DecodeString(signature[strings.Index(signature, "=")+1:])
+	if err != nil {
+		return sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid signature")
+	}
+
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body) // nolint
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid signature")
+	}
+	return nil
+}
+
+// signHMAC returns the signature of a body formatted as "sha256=<hex>", as verified by checkHMACSignature.
+func signHMAC(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
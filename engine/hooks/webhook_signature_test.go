@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_checkWebHookSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	mac := hmac.New(sha256.New, []byte("mysecret"))
+	mac.Write(body) // nolint
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	repoTask := &sdk.Task{
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigWebHookSecret: {Value: "mysecret"},
+		},
+	}
+	webHookTask := func(auth string) *sdk.Task {
+		return &sdk.Task{
+			Type: TypeWebHook,
+			Config: sdk.WorkflowNodeHookConfig{
+				sdk.WebHookModelConfigAuth:  {Value: auth},
+				sdk.HookConfigWebHookSecret: {Value: "mysecret"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		task   *sdk.Task
+		header http.Header
+		ok     bool
+	}{
+		{"github", repoTask, http.Header{GithubSignatureHeader: {signature}}, true},
+		{"github wrong signature", repoTask, http.Header{GithubSignatureHeader: {"sha256=0123"}}, false},
+		{"gitlab", repoTask, http.Header{GitlabTokenHeader: {"mysecret"}}, true},
+		{"gitlab wrong token", repoTask, http.Header{GitlabTokenHeader: {"wrong"}}, false},
+		{"bitbucket", repoTask, http.Header{HubSignatureHeader: {signature}}, true},
+		{"repository without signature", repoTask, http.Header{}, false},
+		{"repository without secret", &sdk.Task{Type: TypeRepoManagerWebHook, Config: sdk.WorkflowNodeHookConfig{}}, http.Header{}, true},
+		{"webhook without authentication", webHookTask(sdk.WebHookAuthNone), http.Header{}, true},
+		{"webhook hmac", webHookTask(sdk.WebHookAuthHMAC), http.Header{WebHookSignatureHeader: {signature}}, true},
+		{"webhook hmac missing", webHookTask(sdk.WebHookAuthHMAC), http.Header{}, false},
+		{"webhook bearer", webHookTask(sdk.WebHookAuthBearer), http.Header{"Authorization": {"Bearer mysecret"}}, true},
+		{"webhook bearer wrong", webHookTask(sdk.WebHookAuthBearer), http.Header{"Authorization": {"Bearer wrong"}}, false},
+		{"webhook bearer without secret", &sdk.Task{Type: TypeWebHook, Config: sdk.WorkflowNodeHookConfig{sdk.WebHookModelConfigAuth: {Value: sdk.WebHookAuthBearer}}}, http.Header{"Authorization": {"Bearer "}}, false},
+		{"webhook hmac without secret", &sdk.Task{Type: TypeWebHook, Config: sdk.WorkflowNodeHookConfig{sdk.WebHookModelConfigAuth: {Value: sdk.WebHookAuthHMAC}}}, http.Header{WebHookSignatureHeader: {"sha256=" + hex.EncodeToString(hmac.New(sha256.New, nil).Sum(nil))}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkWebHookSignature(tt.task, tt.header, body)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
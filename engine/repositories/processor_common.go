@@ -1,10 +1,16 @@
 package repositories
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
 	"github.com/fsamin/go-repo"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/vcs"
+	"github.com/ovh/cds/sdk/vcs/git"
 )
 
 func (s *Service) processGitClone(op *sdk.Operation) (repo.Repo, string, string, error) {
@@ -28,7 +34,15 @@ func (s *Service) processGitClone(op *sdk.Operation) (repo.Repo, string, string,
 	gitRepo, err := repo.New(r.Basedir, opts...)
 	if err != nil {
 		log.Info("Repositories> processGitClone> cloning %s into %s", r.URL, r.Basedir)
-		gitRepo, err = repo.Clone(r.Basedir, r.URL, opts...)
+		if cloneOpts := s.cloneOpts(op); cloneOpts != nil {
+			if err := s.cloneWithOpts(op, r.URL, r.Basedir, cloneOpts); err != nil {
+				log.Error("Repositories> processGitClone> cloneWithOpts> [%s] error %v", op.UUID, err)
+				return gitRepo, "", "", err
+			}
+			gitRepo, err = repo.New(r.Basedir, opts...)
+		} else {
+			gitRepo, err = repo.Clone(r.Basedir, r.URL, opts...)
+		}
 		if err != nil {
 			log.Error("Repositories> processGitClone> Clone> [%s] error %v", op.UUID, err)
 			return gitRepo, "", "", err
@@ -61,3 +75,47 @@ func (s *Service) processGitClone(op *sdk.Operation) (repo.Repo, string, string,
 	}
 	return gitRepo, r.Basedir, currentBranch, nil
 }
+
+// cloneOpts returns the clone options from the service configuration, nil if the default clone is used
+func (s *Service) cloneOpts(op *sdk.Operation) *git.CloneOpts {
+	if s.Cfg.Clone.Filter == "" && !s.Cfg.Clone.SparseCheckout && s.Cfg.Clone.ReferenceDir == "" {
+		return nil
+	}
+	opts := &git.CloneOpts{
+		Filter:                  s.Cfg.Clone.Filter,
+		NoStrictHostKeyChecking: true,
+		Quiet:                   true,
+	}
+	if s.Cfg.Clone.SparseCheckout {
+		opts.SparseCheckout = []string{".cds"}
+	}
+	if s.Cfg.Clone.ReferenceDir != "" && op.RepoFullName != "" {
+		opts.Reference = filepath.Join(s.Cfg.Clone.ReferenceDir, op.RepoFullName+".git")
+	}
+	return opts
+}
+
+// cloneWithOpts clones the repository with the sdk git client which supports partial clone, sparse checkout and reference repositories
+func (s *Service) cloneWithOpts(op *sdk.Operation, url, path string, opts *git.CloneOpts) error {
+	var auth *git.AuthOpts
+	if op.RepositoryStrategy.ConnectionType == "ssh" {
+		keyDir, err := ioutil.TempDir("", "cds-repositories-")
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer os.RemoveAll(keyDir) // nolint
+
+		keyFile := filepath.Join(keyDir, "key")
+		if err := ioutil.WriteFile(keyFile, []byte(op.RepositoryStrategy.SSHKeyContent), os.FileMode(0600)); err != nil {
+			return sdk.WithStack(err)
+		}
+		auth = &git.AuthOpts{PrivateKey: vcs.SSHKey{Filename: keyFile, Content: []byte(op.RepositoryStrategy.SSHKeyContent)}}
+	} else if op.RepositoryStrategy.User != "" && op.RepositoryStrategy.Password != "" {
+		auth = &git.AuthOpts{Username: op.RepositoryStrategy.User, Password: op.RepositoryStrategy.Password}
+	}
+
+	if _, err := git.Clone(url, path, auth, opts, nil); err != nil {
+		return sdk.WrapError(err, "unable to clone %s", url)
+	}
+	return nil
+}
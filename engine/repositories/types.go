@@ -38,6 +38,11 @@ type Configuration struct {
 			Password string `toml:"password" json:"-"`
 		} `toml:"redis" json:"redis"`
 	} `toml:"cache" comment:"######################\n CDS Repositories Cache Settings \n######################" json:"cache"`
+	Clone struct {
+		Filter         string `toml:"filter" default:"" commented:"true" comment:"Partial clone filter used to clone repositories, ie. blob:none or tree:0. Empty to fetch all objects" json:"filter"`
+		SparseCheckout bool   `toml:"sparseCheckout" default:"false" commented:"true" comment:"Only checkout the .cds directory of repositories" json:"sparse_checkout"`
+		ReferenceDir   string `toml:"referenceDir" default:"" commented:"true" comment:"Directory of local mirrors used as reference repositories, the mirror of a repository is expected in <referenceDir>/<repository fullname>.git" json:"reference_dir"`
+	} `toml:"clone" comment:"######################\n CDS Repositories Clone Settings \n######################" json:"clone"`
 }
 
 // Repo retiens a sdk.OperationRepo from an sdk.Operation
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run_job ADD COLUMN priority INT DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE workflow_node_run_job DROP COLUMN priority;
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "project_cache" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  integration_name VARCHAR(256) NOT NULL,
+  tag VARCHAR(256) NOT NULL,
+  digest VARCHAR(64) NOT NULL,
+  size BIGINT NOT NULL DEFAULT 0,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_access TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_CACHE_PROJECT', 'project_cache', 'project', 'project_id', 'id');
+SELECT create_unique_index('project_cache', 'IDX_PROJECT_CACHE_TAG', 'project_id,integration_name,tag');
+SELECT create_index('project_cache', 'IDX_PROJECT_CACHE_DIGEST', 'project_id,integration_name,digest');
+
+-- +migrate Down
+DROP TABLE project_cache;
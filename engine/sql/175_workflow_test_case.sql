@@ -0,0 +1,25 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_test_case" (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_id BIGINT NOT NULL,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  run_number BIGINT NOT NULL,
+  vcs_branch VARCHAR(256) NOT NULL DEFAULT '',
+  vcs_hash VARCHAR(256) NOT NULL DEFAULT '',
+  test_suite TEXT NOT NULL,
+  name TEXT NOT NULL,
+  classname TEXT NOT NULL DEFAULT '',
+  status VARCHAR(16) NOT NULL,
+  duration DOUBLE PRECISION NOT NULL DEFAULT 0,
+  flaky BOOLEAN NOT NULL DEFAULT false,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEST_CASE_WORKFLOW_RUN', 'workflow_test_case', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEST_CASE_WORKFLOW_NODE_RUN', 'workflow_test_case', 'workflow_node_run', 'workflow_node_run_id', 'id');
+SELECT create_index('workflow_test_case', 'IDX_WORKFLOW_TEST_CASE_RUN', 'workflow_id,run_number');
+SELECT create_index('workflow_test_case', 'IDX_WORKFLOW_TEST_CASE_NAME', 'workflow_id,test_suite,name');
+SELECT create_index('workflow_test_case', 'IDX_WORKFLOW_TEST_CASE_HASH', 'workflow_id,vcs_hash');
+
+-- +migrate Down
+DROP TABLE workflow_test_case;
@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "application_test_quarantine" (
+  id BIGSERIAL PRIMARY KEY,
+  application_id BIGINT NOT NULL,
+  test_suite TEXT NOT NULL DEFAULT '',
+  name TEXT NOT NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  owner VARCHAR(256) NOT NULL DEFAULT '',
+  expire TIMESTAMP WITH TIME ZONE,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_APPLICATION_TEST_QUARANTINE_APPLICATION', 'application_test_quarantine', 'application', 'application_id', 'id');
+SELECT create_unique_index('application_test_quarantine', 'IDX_APPLICATION_TEST_QUARANTINE_TEST', 'application_id,test_suite,name');
+
+ALTER TABLE workflow_node_run ADD COLUMN tests_quarantined INT DEFAULT 0;
+
+-- +migrate Down
+DROP TABLE application_test_quarantine;
+ALTER TABLE workflow_node_run DROP COLUMN tests_quarantined;
@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE application_vulnerability ALTER COLUMN title TYPE TEXT;
+ALTER TABLE application_vulnerability ADD COLUMN rule VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE application_vulnerability ADD COLUMN location TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE application_vulnerability DROP COLUMN rule;
+ALTER TABLE application_vulnerability DROP COLUMN location;
@@ -0,0 +1,33 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_sbom" (
+  id BIGSERIAL PRIMARY KEY,
+  application_id BIGINT NOT NULL,
+  workflow_id BIGINT NOT NULL,
+  workflow_run_id BIGINT NOT NULL,
+  workflow_node_run_id BIGINT NOT NULL,
+  num BIGINT NOT NULL,
+  branch VARCHAR(256) NOT NULL DEFAULT '',
+  name TEXT NOT NULL,
+  format VARCHAR(32) NOT NULL,
+  document TEXT,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_SBOM_APPLICATION', 'workflow_node_run_sbom', 'application', 'application_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_SBOM_WORKFLOW_RUN', 'workflow_node_run_sbom', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_SBOM_WORKFLOW_NODE_RUN', 'workflow_node_run_sbom', 'workflow_node_run', 'workflow_node_run_id', 'id');
+
+CREATE TABLE IF NOT EXISTS "sbom_component" (
+  id BIGSERIAL PRIMARY KEY,
+  sbom_id BIGINT NOT NULL,
+  name TEXT NOT NULL,
+  version TEXT NOT NULL DEFAULT '',
+  license TEXT NOT NULL DEFAULT '',
+  purl TEXT NOT NULL DEFAULT ''
+);
+SELECT create_foreign_key_idx_cascade('FK_SBOM_COMPONENT_SBOM', 'sbom_component', 'workflow_node_run_sbom', 'sbom_id', 'id');
+SELECT create_index('sbom_component', 'IDX_SBOM_COMPONENT_NAME', 'name,version');
+SELECT create_index('sbom_component', 'IDX_SBOM_COMPONENT_PURL', 'purl');
+
+-- +migrate Down
+DROP TABLE sbom_component;
+DROP TABLE workflow_node_run_sbom;
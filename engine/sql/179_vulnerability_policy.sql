@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "vulnerability_policy" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  application_id BIGINT,
+  action VARCHAR(32) NOT NULL,
+  only_default_branch BOOLEAN NOT NULL DEFAULT false,
+  fail_on_new VARCHAR(25) NOT NULL DEFAULT '',
+  max_by_severity JSONB,
+  ignores JSONB,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_VULNERABILITY_POLICY_PROJECT', 'vulnerability_policy', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_VULNERABILITY_POLICY_APPLICATION', 'vulnerability_policy', 'application', 'application_id', 'id');
+CREATE UNIQUE INDEX IDX_VULNERABILITY_POLICY_UNIQ ON vulnerability_policy (project_id, COALESCE(application_id, 0));
+
+ALTER TABLE workflow_node_run ADD COLUMN vulnerability_policy JSONB;
+
+-- +migrate Down
+DROP TABLE vulnerability_policy;
+ALTER TABLE workflow_node_run DROP COLUMN vulnerability_policy;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run_coverage ADD COLUMN packages JSONB;
+
+-- +migrate Down
+ALTER TABLE workflow_node_run_coverage DROP COLUMN packages;
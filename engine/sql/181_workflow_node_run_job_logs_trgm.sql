@@ -0,0 +1,15 @@
+-- +migrate Up
+-- pg_trgm can only be created by a superuser before PostgreSQL 13, run "CREATE EXTENSION pg_trgm;" as superuser
+-- if the notice is raised. The index on the step logs values is built concurrently by the API once the extension exists.
+-- +migrate StatementBegin
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS pg_trgm;
+EXCEPTION WHEN insufficient_privilege THEN
+    RAISE NOTICE 'pg_trgm extension must be created by a superuser to index the step logs';
+END
+$$;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_workflow_node_run_job_logs_value_trgm;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run_job_logs ADD COLUMN parts BIGINT[];
+ALTER TABLE workflow_node_run_job_logs ADD COLUMN archived BOOLEAN NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_workflow_node_run_job_logs_not_archived ON workflow_node_run_job_logs (workflow_node_run_id) WHERE archived = false;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_workflow_node_run_job_logs_not_archived;
+ALTER TABLE workflow_node_run_job_logs DROP COLUMN parts;
+ALTER TABLE workflow_node_run_job_logs DROP COLUMN archived;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE workflow ADD COLUMN include_paths JSONB;
+ALTER TABLE workflow ADD COLUMN exclude_paths JSONB;
+
+-- +migrate Down
+ALTER TABLE workflow DROP COLUMN include_paths;
+ALTER TABLE workflow DROP COLUMN exclude_paths;
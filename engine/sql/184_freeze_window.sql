@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "freeze_window" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  environment_id BIGINT,
+  name VARCHAR(256) NOT NULL,
+  reason TEXT NOT NULL DEFAULT '',
+  cron VARCHAR(256) NOT NULL DEFAULT '',
+  duration VARCHAR(64) NOT NULL DEFAULT '',
+  from_date TIMESTAMP WITH TIME ZONE,
+  to_date TIMESTAMP WITH TIME ZONE,
+  timezone VARCHAR(256) NOT NULL DEFAULT 'UTC',
+  action VARCHAR(64) NOT NULL DEFAULT 'hold',
+  override_group VARCHAR(256) NOT NULL DEFAULT '',
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_FREEZE_WINDOW_PROJECT', 'freeze_window', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_FREEZE_WINDOW_ENVIRONMENT', 'freeze_window', 'environment', 'environment_id', 'id');
+SELECT create_unique_index('freeze_window', 'IDX_FREEZE_WINDOW_NAME', 'project_id,name');
+
+CREATE TABLE IF NOT EXISTS "workflow_node_run_freeze" (
+  workflow_node_run_id BIGINT PRIMARY KEY,
+  workflow_run_id BIGINT NOT NULL,
+  environment_id BIGINT NOT NULL,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_FREEZE_NODE_RUN', 'workflow_node_run_freeze', 'workflow_node_run', 'workflow_node_run_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_FREEZE_RUN', 'workflow_node_run_freeze', 'workflow_run', 'workflow_run_id', 'id');
+
+-- +migrate Down
+DROP TABLE workflow_node_run_freeze;
+DROP TABLE freeze_window;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE workflow_node_run_job_logs ADD COLUMN lines JSONB;
+
+-- +migrate Down
+ALTER TABLE workflow_node_run_job_logs DROP COLUMN lines;
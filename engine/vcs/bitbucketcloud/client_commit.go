@@ -164,3 +164,42 @@ func (client *bitbucketcloudClient) CommitsBetweenRefs(ctx context.Context, repo
 
 	return commitsResult, nil
 }
+
+func (client *bitbucketcloudClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	var files []string
+	if base == "" {
+		base = "HEAD"
+	}
+	if head == "" {
+		head = "HEAD"
+	}
+	params := url.Values{}
+	path := fmt.Sprintf("/repositories/%s/diffstat/%s..%s", repo, head, base)
+	nextPage := 1
+	for {
+		if nextPage != 1 {
+			params.Set("page", fmt.Sprintf("%d", nextPage))
+		}
+
+		var response Diffstats
+		if err := client.do(ctx, "GET", "core", path, params, nil, &response); err != nil {
+			return nil, sdk.WrapError(err, "Unable to get diffstat")
+		}
+		for _, d := range response.Values {
+			if d.New != nil {
+				files = append(files, d.New.Path)
+			}
+			if d.Old != nil && (d.New == nil || d.Old.Path != d.New.Path) {
+				files = append(files, d.Old.Path)
+			}
+		}
+
+		if response.Next == "" {
+			break
+		} else {
+			nextPage++
+		}
+	}
+
+	return files, nil
+}
@@ -13,6 +13,15 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+// hookEvents returns the bitbucket events of the kinds of repository events of a hook, tags are sent with the push events.
+func hookEvents(kinds []string) []string {
+	events := []string{"repo:push"}
+	if sdk.IsInArray(sdk.RepositoryEventPullRequest, kinds) {
+		events = append(events, "pullrequest:created", "pullrequest:updated", "pullrequest:fulfilled", "pullrequest:rejected")
+	}
+	return events
+}
+
 func (client *bitbucketcloudClient) CreateHook(ctx context.Context, repo string, hook *sdk.VCSHook) error {
 	url := fmt.Sprintf("/repositories/%s/hooks", repo)
 	if client.proxyURL != "" {
@@ -26,8 +35,9 @@ func (client *bitbucketcloudClient) CreateHook(ctx context.Context, repo string,
 	r := WebhookCreate{
 		Description: "CDS webhook - " + hook.Name,
 		Active:      true,
-		Events:      []string{"repo:push"},
+		Events:      hookEvents(hook.Events),
 		URL:         hook.URL,
+		Secret:      hook.Secret,
 	}
 	b, err := json.Marshal(r)
 	if err != nil {
@@ -119,7 +129,7 @@ func (client *bitbucketcloudClient) UpdateHook(ctx context.Context, repo, id str
 	r := WebhookCreate{
 		Description: "CDS webhook - " + hook.Name,
 		Active:      true,
-		Events:      []string{"repo:push"},
+		Events:      hookEvents(hook.Events),
 		URL:         hook.URL,
 	}
 	b, err := json.Marshal(r)
@@ -19,6 +19,7 @@ type WebhookCreate struct {
 	URL         string   `json:"url"`
 	Active      bool     `json:"active"`
 	Events      []string `json:"events"`
+	Secret      string   `json:"secret,omitempty"`
 }
 
 type Webhook struct {
@@ -337,6 +338,25 @@ type Commits struct {
 	Previous string   `json:"previous,omitempty"`
 }
 
+type Diffstats struct {
+	Pagelen  int        `json:"pagelen"`
+	Page     int        `json:"page"`
+	Size     int64      `json:"size"`
+	Values   []Diffstat `json:"values"`
+	Next     string     `json:"next"`
+	Previous string     `json:"previous,omitempty"`
+}
+
+type Diffstat struct {
+	Status string        `json:"status"`
+	Old    *DiffstatFile `json:"old"`
+	New    *DiffstatFile `json:"new"`
+}
+
+type DiffstatFile struct {
+	Path string `json:"path"`
+}
+
 type Commit struct {
 	Rendered struct {
 		Message struct {
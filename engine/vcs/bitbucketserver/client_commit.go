@@ -218,3 +218,52 @@ func (b *bitbucketClient) CommitsBetweenRefs(ctx context.Context, repo, base, he
 	}
 	return commits, nil
 }
+
+func (b *bitbucketClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	var files []string
+
+	var stashChangesKey = cache.Key("vcs", "bitbucket", b.consumer.URL, repo, "compare/changes", "from@"+base, "to@"+head)
+
+	if !b.consumer.cache.Get(stashChangesKey, &files) {
+		response := ChangesResponse{}
+		path := fmt.Sprintf("/projects/%s/repos/%s/compare/changes", project, slug)
+		params := url.Values{}
+		if base != "" {
+			params.Add("from", base)
+		}
+		if head != "" {
+			params.Add("to", head)
+		}
+
+		for {
+			if response.NextPageStart != 0 {
+				params.Set("start", fmt.Sprintf("%d", response.NextPageStart))
+			}
+
+			if err := b.do(ctx, "GET", "core", path, params, nil, &response, nil); err != nil {
+				if sdk.ErrorIs(err, sdk.ErrNotFound) {
+					return nil, nil
+				}
+				return nil, sdk.WrapError(err, "Unable to get changes %s", path)
+			}
+
+			for _, c := range response.Values {
+				files = append(files, c.Path.ToString)
+				if c.SrcPath != nil && c.SrcPath.ToString != c.Path.ToString {
+					files = append(files, c.SrcPath.ToString)
+				}
+			}
+			if response.IsLastPage {
+				break
+			}
+		}
+		b.consumer.cache.SetWithTTL(stashChangesKey, files, 3*60*60) //3 hours
+	}
+
+	return files, nil
+}
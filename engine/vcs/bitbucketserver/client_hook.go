@@ -9,6 +9,15 @@ import (
 	"github.com/ovh/cds/sdk"
 )
 
+// hookEvents returns the bitbucket events of the kinds of repository events of a hook, tags are sent with the push events.
+func hookEvents(kinds []string) []string {
+	events := []string{"repo:refs_changed"}
+	if sdk.IsInArray(sdk.RepositoryEventPullRequest, kinds) {
+		events = append(events, "pr:opened", "pr:from_ref_updated", "pr:merged", "pr:declined", "pr:deleted")
+	}
+	return events
+}
+
 func (b *bitbucketClient) getHooks(ctx context.Context, repo string) ([]WebHook, error) {
 	project, slug, err := getRepo(repo)
 	if err != nil {
@@ -69,11 +78,14 @@ func (b *bitbucketClient) CreateHook(ctx context.Context, repo string, hook *sdk
 	url := fmt.Sprintf("/projects/%s/repos/%s/webhooks", project, slug)
 	request := WebHook{
 		URL:           hook.URL,
-		Events:        []string{"repo:refs_changed"},
+		Events:        hookEvents(hook.Events),
 		Active:        true,
 		Name:          repo,
 		Configuration: make(map[string]string),
 	}
+	if hook.Secret != "" {
+		request.Configuration["secret"] = hook.Secret
+	}
 
 	values, err := json.Marshal(&request)
 	if err != nil {
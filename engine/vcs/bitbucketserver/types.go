@@ -69,6 +69,23 @@ type CommitsResponse struct {
 	IsLastPage    bool     `json:"isLastPage"`
 }
 
+type ChangesResponse struct {
+	Values        []Change `json:"values"`
+	Size          int      `json:"size"`
+	NextPageStart int      `json:"nextPageStart"`
+	IsLastPage    bool     `json:"isLastPage"`
+}
+
+type Change struct {
+	Type    string      `json:"type"`
+	Path    ChangePath  `json:"path"`
+	SrcPath *ChangePath `json:"srcPath,omitempty"`
+}
+
+type ChangePath struct {
+	ToString string `json:"toString"`
+}
+
 type Commit struct {
 	Hash      string  `json:"id"`
 	Author    *Author `json:"author"`
@@ -20,3 +20,7 @@ func (c *gerritClient) Commit(ctx context.Context, repo, hash string) (sdk.VCSCo
 func (c *gerritClient) CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]sdk.VCSCommit, error) {
 	return nil, nil
 }
+
+func (c *gerritClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	return nil, nil
+}
@@ -302,3 +302,37 @@ func (g *githubClient) CommitsBetweenRefs(ctx context.Context, repo, base, head
 
 	return commits, nil
 }
+
+func (g *githubClient) ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error) {
+	var files []string
+	url := fmt.Sprintf("/repos/%s/compare/%s...%s", repo, base, head)
+	status, body, _, err := g.get(url)
+	if err != nil {
+		log.Warning("githubClient.ChangedFilesBetweenRefs> Error %s", err)
+		return files, err
+	}
+	if status >= 400 {
+		return files, sdk.NewError(sdk.ErrRepoNotFound, errorAPI(body))
+	}
+
+	//Github may return 304 status because we are using conditional request with ETag based headers
+	if status == http.StatusNotModified {
+		//If repo isn't updated, lets get them from cache
+		g.Cache.Get(cache.Key("vcs", "github", "filesdiff", g.OAuthToken, url), &files)
+		return files, nil
+	}
+
+	var diff DiffCommits
+	if err := json.Unmarshal(body, &diff); err != nil {
+		log.Warning("githubClient.ChangedFilesBetweenRefs> Unable to parse github diff: %s", err)
+		return files, err
+	}
+	files = make([]string, len(diff.Files))
+	for i, f := range diff.Files {
+		files[i] = f.Filename
+	}
+	//Put the body on cache for one hour and one minute
+	g.Cache.SetWithTTL(cache.Key("vcs", "github", "filesdiff", g.OAuthToken, url), &files, 61*60)
+
+	return files, nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/mitchellh/mapstructure"
 
+	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -27,7 +28,8 @@ func (g *githubClient) GetEvents(ctx context.Context, fullname string, dateRef t
 
 	interval := 60 * time.Second
 
-	status, body, headers, err := g.get("/repos/" + fullname + "/events")
+	// The request is conditional (If-None-Match), a not modified response is not counted in the rate limit
+	status, body, headers, err := g.get("/repos/"+fullname+"/events", withETag)
 	if err != nil {
 		log.Warning("githubClient.GetEvents> Error %s", err)
 		return nil, interval, err
@@ -39,8 +41,17 @@ func (g *githubClient) GetEvents(ctx context.Context, fullname string, dateRef t
 		return nil, interval, err
 	}
 
+	// The ETag is shared by all the pollers of the repository: the events of the last modified response are kept
+	// so that each poller filters them with its own reference date
+	eventsKey := cache.Key("vcs", "github", "events", g.OAuthToken, fullname)
 	if status == http.StatusNotModified {
-		return nil, interval, ErrNoNewEvents
+		var lastBody string
+		if !g.Cache.Get(eventsKey, &lastBody) {
+			return nil, pollInterval(headers, interval), ErrNoNewEvents
+		}
+		body = []byte(lastBody)
+	} else {
+		g.Cache.SetWithTTL(eventsKey, string(body), 15*60)
 	}
 
 	nextEvents := []Event{}
@@ -91,15 +102,18 @@ func (g *githubClient) GetEvents(ctx context.Context, fullname string, dateRef t
 		}
 	}
 
-	//Check poll interval
+	return events, pollInterval(headers, interval), nil
+}
+
+// pollInterval returns the poll interval asked by github
+func pollInterval(headers http.Header, interval time.Duration) time.Duration {
 	if headers.Get("X-Poll-Interval") != "" {
 		f, err := strconv.ParseFloat(headers.Get("X-Poll-Interval"), 64)
 		if err == nil {
-			interval = time.Duration(f) * time.Second
+			return time.Duration(f) * time.Second
 		}
 	}
-
-	return events, interval, nil
+	return interval
 }
 
 //PushEvents returns push events as commits
@@ -118,8 +132,12 @@ func (g *githubClient) PushEvents(ctx context.Context, fullname string, iEvents
 	}
 
 	lastCommitPerBranch := map[string]sdk.VCSCommit{}
+	firstEventPerBranch := map[string]Event{}
 	for _, e := range events {
 		branch := strings.Replace(e.Payload.Ref, "refs/heads/", "", 1)
+		if f, has := firstEventPerBranch[branch]; !has || e.CreatedAt.Before(f.CreatedAt.Time) {
+			firstEventPerBranch[branch] = e
+		}
 		for _, c := range e.Payload.Commits {
 			commit := sdk.VCSCommit{
 				Hash:      c.Sha,
@@ -155,6 +173,7 @@ func (g *githubClient) PushEvents(ctx context.Context, fullname string, iEvents
 		res = append(res, sdk.VCSPushEvent{
 			Branch: *branch,
 			Commit: c,
+			Before: firstEventPerBranch[b].Payload.Before,
 			Repo:   fullname,
 		})
 	}
@@ -15,6 +15,18 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+// hookEvents returns the github events of the kinds of repository events of a hook, tags are sent with the push events.
+func hookEvents(kinds []string) []string {
+	events := []string{"push"}
+	if sdk.IsInArray(sdk.RepositoryEventPullRequest, kinds) {
+		events = append(events, "pull_request")
+	}
+	if sdk.IsInArray(sdk.RepositoryEventRelease, kinds) {
+		events = append(events, "release")
+	}
+	return events
+}
+
 func (g *githubClient) CreateHook(ctx context.Context, repo string, hook *sdk.VCSHook) error {
 	url := "/repos/" + repo + "/hooks"
 	if g.proxyURL != "" {
@@ -28,10 +40,11 @@ func (g *githubClient) CreateHook(ctx context.Context, repo string, hook *sdk.VC
 	r := WebhookCreate{
 		Name:   "web",
 		Active: true,
-		Events: []string{"push"},
+		Events: hookEvents(hook.Events),
 		Config: WebHookConfig{
 			URL:         hook.URL,
 			ContentType: "json",
+			Secret:      hook.Secret,
 		},
 	}
 	b, err := json.Marshal(r)
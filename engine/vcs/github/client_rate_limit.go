@@ -18,6 +18,17 @@ func isRateLimitReached() bool {
 	return RateLimitRemaining < 100
 }
 
+// RateLimitStatus returns a copy of the rate limit given by the last call to github
+func (g *githubClient) RateLimitStatus() *sdk.VCSRateLimit {
+	g.rateLimitMutex.Lock()
+	defer g.rateLimitMutex.Unlock()
+	if g.rateLimit == nil {
+		return nil
+	}
+	r := *g.rateLimit
+	return &r
+}
+
 // RateLimit Get your current rate limit status
 // https://developer.github.com/v3/rate_limit/#get-your-current-rate-limit-status
 func (g *githubClient) RateLimit() error {
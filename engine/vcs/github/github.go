@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"sync"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
@@ -21,6 +22,8 @@ type githubClient struct {
 	proxyURL            string
 	username            string
 	token               string
+	rateLimit           *sdk.VCSRateLimit // rate limit given by the last call to github
+	rateLimitMutex      sync.Mutex
 }
 
 //GithubConsumer implements vcs.Server and it's used to instanciate a githubClient
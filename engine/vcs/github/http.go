@@ -237,6 +237,9 @@ func (c *githubClient) get(path string, opts ...getArgFunc) (int, []byte, http.H
 	}
 	defer res.Body.Close()
 
+	// Not modified responses also give the rate limit, they are not counted in it
+	c.setRateLimit(res.Header)
+
 	switch res.StatusCode {
 	case http.StatusNotModified:
 		return res.StatusCode, nil, res.Header, nil
@@ -257,17 +260,22 @@ func (c *githubClient) get(path string, opts ...getArgFunc) (int, []byte, http.H
 
 	c.setETag(path, res.Header)
 
-	rateLimitLimit := res.Header.Get("X-RateLimit-Limit")
-	rateLimitRemaining := res.Header.Get("X-RateLimit-Remaining")
-	rateLimitReset := res.Header.Get("X-RateLimit-Reset")
+	return res.StatusCode, resBody, res.Header, nil
+}
+
+func (c *githubClient) setRateLimit(headers http.Header) {
+	rateLimitLimit := headers.Get("X-RateLimit-Limit")
+	rateLimitRemaining := headers.Get("X-RateLimit-Remaining")
+	rateLimitReset := headers.Get("X-RateLimit-Reset")
 
 	if rateLimitLimit != "" && rateLimitRemaining != "" && rateLimitReset != "" {
 		RateLimitLimit, _ = strconv.Atoi(rateLimitLimit)
 		RateLimitRemaining, _ = strconv.Atoi(rateLimitRemaining)
 		RateLimitReset, _ = strconv.Atoi(rateLimitReset)
+		c.rateLimitMutex.Lock()
+		c.rateLimit = &sdk.VCSRateLimit{Limit: RateLimitLimit, Remaining: RateLimitRemaining, Reset: int64(RateLimitReset)}
+		c.rateLimitMutex.Unlock()
 	}
-
-	return res.StatusCode, resBody, res.Header, nil
 }
 
 func (c *githubClient) delete(path string) error {
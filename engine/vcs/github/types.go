@@ -72,6 +72,7 @@ type Webhook struct {
 type WebHookConfig struct {
 	URL         string `json:"url"`
 	ContentType string `json:"content_type"`
+	Secret      string `json:"secret,omitempty"`
 }
 
 // User represents a GitHub user.
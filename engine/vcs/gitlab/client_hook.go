@@ -21,6 +21,9 @@ func (c *gitlabClient) GetHook(ctx context.Context, repo, id string) (sdk.VCSHoo
 func (c *gitlabClient) CreateHook(ctx context.Context, repo string, hook *sdk.VCSHook) error {
 	t := true
 	f := false
+	// Tags and merge requests are sent only to the hooks which select them
+	tags := sdk.IsInArray(sdk.RepositoryEventTag, hook.Events)
+	mergeRequests := sdk.IsInArray(sdk.RepositoryEventPullRequest, hook.Events)
 
 	var url string
 	if !hook.Workflow {
@@ -50,10 +53,13 @@ func (c *gitlabClient) CreateHook(ctx context.Context, repo string, hook *sdk.VC
 	opt := gitlab.AddProjectHookOptions{
 		URL:                   &url,
 		PushEvents:            &t,
-		MergeRequestsEvents:   &f,
-		TagPushEvents:         &f,
+		MergeRequestsEvents:   &mergeRequests,
+		TagPushEvents:         &tags,
 		EnableSSLVerification: &f,
 	}
+	if hook.Secret != "" {
+		opt.Token = &hook.Secret
+	}
 
 	log.Debug("GitlabClient.CreateHook: %s %s\n", repo, *opt.URL)
 	ph, resp, err := c.client.Projects.AddProjectHook(repo, &opt)
@@ -555,6 +555,41 @@ func (s *Service) getCommitsBetweenRefsHandler() service.Handler {
 	}
 }
 
+func (s *Service) getChangedFilesBetweenRefsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		base := r.URL.Query().Get("base")
+		head := r.URL.Query().Get("head")
+
+		accessToken, accessTokenSecret, created, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "VCS> getChangedFilesBetweenRefsHandler> Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret, created)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+		// Check if access token has been refreshed
+		if accessToken != client.GetAccessToken(ctx) {
+			w.Header().Set(sdk.HeaderXAccessToken, client.GetAccessToken(ctx))
+		}
+
+		files, err := client.ChangedFilesBetweenRefs(ctx, fmt.Sprintf("%s/%s", owner, repo), base, head)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get changed files of %s/%s between %s and %s", owner, repo, base, head)
+		}
+		return service.WriteJSON(w, files, http.StatusOK)
+	}
+}
+
 func (s *Service) getCommitHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		name := muxVar(r, "name")
@@ -815,6 +850,12 @@ func (s *Service) getEventsHandler() service.Handler {
 		if err != nil && err != github.ErrNoNewEvents {
 			return sdk.WrapError(err, "Unable to get events on %s/%s", owner, repo)
 		}
+		// Give the rate limit of the vcs server to the pollers
+		if c, ok := client.(sdk.VCSRateLimitedClient); ok {
+			if rateLimit := c.RateLimitStatus(); rateLimit != nil {
+				rateLimit.SetHeader(w.Header())
+			}
+		}
 		res := struct {
 			Events []interface{} `json:"events"`
 			Delay  time.Duration `json:"delay"`
@@ -3,9 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
 	"github.com/ovh/cds/sdk/log"
@@ -24,6 +28,8 @@ func init() {
 	mapBuiltinActions[sdk.CheckoutApplicationAction] = runCheckoutApplication
 	mapBuiltinActions[sdk.DeployApplicationAction] = runDeployApplication
 	mapBuiltinActions[sdk.CoverageAction] = runParseCoverageResultAction
+	mapBuiltinActions[sdk.VulnerabilityReportAction] = runParseVulnerabilityReportAction
+	mapBuiltinActions[sdk.SBOMAction] = runSBOMAction
 	mapBuiltinActions[sdk.ServeStaticFiles] = runServeStaticFiles
 }
 
@@ -168,7 +174,7 @@ func (w *currentWorker) runGRPCPlugin(ctx context.Context, a *sdk.Action, buildI
 			JobID:   buildID,
 		}
 
-		result, err := actionPluginClient.Run(ctx, &query)
+		result, err := w.runActionPlugin(ctx, actionPluginClient, &query, buildID, &params, sendLog)
 		pluginDetails := fmt.Sprintf("plugin %s v%s", manifest.Name, manifest.Version)
 		if err != nil {
 			t := fmt.Sprintf("failure %s err: %v", pluginDetails, err)
@@ -200,6 +206,60 @@ func (w *currentWorker) runGRPCPlugin(ctx context.Context, a *sdk.Action, buildI
 	}
 }
 
+// runActionPlugin runs the action with RunStream, the plugin sends its logs, progress, variables and artifacts
+// in the stream. Run is called for plugins that do not implement RunStream.
+func (w *currentWorker) runActionPlugin(ctx context.Context, c actionplugin.ActionPluginClient, query *actionplugin.ActionQuery, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) (*actionplugin.ActionResult, error) {
+	stream, err := c.RunStream(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("plugin stream ended without result")
+		}
+		if status.Code(err) == codes.Unimplemented {
+			return c.Run(ctx, query)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch e := event.Event.(type) {
+		case *actionplugin.ActionEvent_Result:
+			return e.Result, nil
+		case *actionplugin.ActionEvent_Log:
+			sendLog(e.Log)
+		case *actionplugin.ActionEvent_Progress:
+			if e.Progress.Total > 0 {
+				sendLog(fmt.Sprintf("# Progress %d/%d %s", e.Progress.Current, e.Progress.Total, e.Progress.Message))
+			} else {
+				sendLog(fmt.Sprintf("# Progress %s", e.Progress.Message))
+			}
+		case *actionplugin.ActionEvent_Variable:
+			v := sdk.Variable{
+				Name:  "cds.build." + e.Variable.Name,
+				Type:  sdk.StringVariable,
+				Value: e.Variable.Value,
+			}
+			if _, err := w.addVariableInPipelineBuild(v, params); err != nil {
+				return nil, fmt.Errorf("unable to add variable %s: %v", v.Name, err)
+			}
+		case *actionplugin.ActionEvent_Artifact:
+			action := sdk.Action{
+				Parameters: []sdk.Parameter{
+					{Name: "path", Type: sdk.StringParameter, Value: e.Artifact.Path},
+					{Name: "tag", Type: sdk.StringParameter, Value: e.Artifact.Tag},
+				},
+			}
+			if res := runArtifactUpload(w)(ctx, &action, buildID, params, w.currentJob.secrets, sendLog); res.Status != sdk.StatusSuccess.String() {
+				return &actionplugin.ActionResult{Status: res.Status, Details: res.Reason}, nil
+			}
+		}
+	}
+}
+
 func actionPluginClientStop(ctx context.Context, actionPluginClient actionplugin.ActionPluginClient, stopLogs context.CancelFunc) {
 	if _, err := actionPluginClient.Stop(ctx, new(empty.Empty)); err != nil {
 		// Transport is closing is a "normal" error, as we requested plugin to stop
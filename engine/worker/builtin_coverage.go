@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strconv"
 
 	"github.com/sguiheux/go-coverage"
@@ -44,25 +45,60 @@ func runParseCoverageResultAction(w *currentWorker) BuiltInAction {
 			minReq = f
 		}
 
-		var parserMode coverage.CoverageMode
+		var maxDrop float64 = -1
+		if d := sdk.ParameterValue(a.Parameters, "max_drop"); d != "" {
+			f, errDrop := strconv.ParseFloat(d, 64)
+			if errDrop != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: wrong value for 'max_drop': %s", errDrop)
+				sendLog(res.Reason)
+				return res
+			}
+			maxDrop = f
+		}
+
+		var report coverage.Report
 		switch mode {
-		case string(coverage.COBERTURA):
-			parserMode = coverage.COBERTURA
-		case string(coverage.LCOV):
-			parserMode = coverage.LCOV
-		case string(coverage.CLOVER):
-			parserMode = coverage.CLOVER
+		case sdk.CoverageFormatGoCover, sdk.CoverageFormatJaCoCo:
+			content, errRead := ioutil.ReadFile(p)
+			if errRead != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: cannot read file %s (%s)", p, errRead)
+				sendLog(res.Reason)
+				return res
+			}
+			r, errR := sdk.ParseCoverageReport(mode, content)
+			if errR != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: unable to parse report: %v", errR)
+				sendLog(res.Reason)
+				return res
+			}
+			report = r
 		default:
-			res.Reason = fmt.Sprintf("Coverage parser: unknown format %s", mode)
-			sendLog(res.Reason)
-			return res
+			var parserMode coverage.CoverageMode
+			switch mode {
+			case string(coverage.COBERTURA):
+				parserMode = coverage.COBERTURA
+			case string(coverage.LCOV):
+				parserMode = coverage.LCOV
+			case string(coverage.CLOVER):
+				parserMode = coverage.CLOVER
+			default:
+				res.Reason = fmt.Sprintf("Coverage parser: unknown format %s", mode)
+				sendLog(res.Reason)
+				return res
+			}
+			parser := coverage.New(p, parserMode)
+			r, errR := parser.Parse()
+			if errR != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: unable to parse report: %v", errR)
+				sendLog(res.Reason)
+				return res
+			}
+			report = r
 		}
-		parser := coverage.New(p, parserMode)
-		report, errR := parser.Parse()
-		if errR != nil {
-			res.Reason = fmt.Sprintf("Coverage parser: unable to parse report: %v", errR)
-			sendLog(res.Reason)
-			return res
+
+		covPercent := sdk.CoverageLinesPercent(report)
+		if covPercent >= 0 {
+			sendLog(fmt.Sprintf("Coverage: %.2f%% of %d lines", covPercent, report.TotalLines))
 		}
 
 		data, errM := json.Marshal(report)
@@ -75,7 +111,7 @@ func runParseCoverageResultAction(w *currentWorker) BuiltInAction {
 
 		uri := fmt.Sprintf("/queue/workflows/%d/coverage", w.currentJob.wJob.ID)
 
-		_, code, err := sdk.Request("POST", uri, data)
+		body, code, err := sdk.Request("POST", uri, data)
 		if err == nil && code > 300 {
 			err = fmt.Errorf("HTTP %d", code)
 		}
@@ -87,8 +123,26 @@ func runParseCoverageResultAction(w *currentWorker) BuiltInAction {
 			return res
 		}
 
-		if minReq > 0 {
-			covPercent := (float64(report.CoveredLines) / float64(report.TotalLines)) * 100
+		if maxDrop >= 0 && covPercent < 0 {
+			sendLog("Coverage: no lines in the report, the maximum drop is not checked")
+		} else if maxDrop >= 0 {
+			var cov sdk.WorkflowNodeRunCoverage
+			if err := json.Unmarshal(body, &cov); err != nil {
+				res.Reason = fmt.Sprintf("Coverage parser: unable to read coverage trends: %v", err)
+				sendLog(res.Reason)
+				return res
+			}
+			defaultPercent := sdk.CoverageLinesPercent(cov.Trend.DefaultBranch)
+			if defaultPercent < 0 {
+				sendLog("Coverage: no report on the default branch to compare with")
+			} else if drop := defaultPercent - covPercent; drop > maxDrop {
+				res.Reason = fmt.Sprintf("Coverage: maximum drop failed: %.2f%% on default branch, %.2f%% on current branch", defaultPercent, covPercent)
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
+		if minReq > 0 && covPercent >= 0 {
 			if covPercent < minReq {
 				res.Reason = fmt.Sprintf("Coverage: minimum coverage failed: %.2f%% < %.2f%%", covPercent, minReq)
 				res.Status = sdk.StatusFail.String()
@@ -29,6 +29,12 @@ func runGitClone(w *currentWorker) BuiltInAction {
 		directory := sdk.ParameterFind(&a.Parameters, "directory")
 		depth := sdk.ParameterFind(&a.Parameters, "depth")
 		submodules := sdk.ParameterFind(&a.Parameters, "submodules")
+		sparseCheckout := sdk.ParameterValue(a.Parameters, "sparseCheckout")
+		filter := sdk.ParameterValue(a.Parameters, "filter")
+		lfs := sdk.ParameterValue(a.Parameters, "lfs")
+		lfsInclude := sdk.ParameterValue(a.Parameters, "lfsInclude")
+		lfsExclude := sdk.ParameterValue(a.Parameters, "lfsExclude")
+		reference := sdk.ParameterValue(a.Parameters, "reference")
 
 		deprecatedKey := true
 
@@ -183,6 +189,14 @@ func runGitClone(w *currentWorker) BuiltInAction {
 		if submodules != nil && submodules.Value == "false" {
 			opts.Recursive = false
 		}
+		opts.SparseCheckout = splitGitCloneList(sparseCheckout)
+		opts.Filter = filter
+		opts.Reference = reference
+		if lfs == "true" {
+			opts.LFS = true
+			opts.LFSInclude = splitGitCloneList(lfsInclude)
+			opts.LFSExclude = splitGitCloneList(lfsExclude)
+		}
 
 		// if there is no branch, check if there a defaultBranch
 		if (opts.Branch == "" || opts.Branch == "{{.git.branch}}") && defaultBranch != "" && tag == "" {
@@ -204,6 +218,17 @@ func runGitClone(w *currentWorker) BuiltInAction {
 	}
 }
 
+// splitGitCloneList returns the values of a comma or newline separated list parameter
+func splitGitCloneList(s string) []string {
+	var res []string
+	for _, v := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
 func gitClone(w *currentWorker, params *[]sdk.Parameter, url string, dir string, auth *git.AuthOpts, clone *git.CloneOpts, sendLog LoggerFunc) sdk.Result {
 	//Prepare all options - logs
 	stdErr := new(bytes.Buffer)
@@ -60,6 +60,16 @@ func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 		}
 
 		sendLog(fmt.Sprintf("%d", len(tests.TestSuites)) + " Total Testsuite(s)")
+
+		// failures of quarantined tests are reported but do not fail the step
+		quarantines, errQ := w.client.QueueJobTestQuarantines(ctx, w.currentJob.wJob.ID)
+		if errQ != nil {
+			sendLog(fmt.Sprintf("JUnit parser: unable to get quarantined tests: %v", errQ))
+		}
+		for _, r := range applyTestQuarantines(&tests, quarantines) {
+			sendLog(r)
+		}
+
 		reasons := computeStats(&res, &tests)
 		for _, r := range reasons {
 			sendLog(r)
@@ -107,6 +117,38 @@ func runParseJunitTestResultAction(w *currentWorker) BuiltInAction {
 	}
 }
 
+// applyTestQuarantines sets the quarantined status on failed test cases matching a quarantine.
+// Their failures and errors are no more counted on their testsuite.
+func applyTestQuarantines(v *venom.Tests, qs []sdk.TestQuarantine) []string {
+	reasons := []string{}
+	for i := range v.TestSuites {
+		ts := &v.TestSuites[i]
+		for k := range ts.TestCases {
+			tc := &ts.TestCases[k]
+			if len(tc.Failures) == 0 && len(tc.Errors) == 0 {
+				continue
+			}
+			for _, q := range qs {
+				if !q.Matches(ts.Name, tc.Name) {
+					continue
+				}
+				tc.Status = sdk.TestCaseStatusQuarantined
+				ts.Failures -= len(tc.Failures)
+				if ts.Failures < 0 {
+					ts.Failures = 0
+				}
+				ts.Errors -= len(tc.Errors)
+				if ts.Errors < 0 {
+					ts.Errors = 0
+				}
+				reasons = append(reasons, fmt.Sprintf("JUnit parser: testcase %s failed but is quarantined (owner: %s, reason: %s)", tc.Name, q.Owner, q.Reason))
+				break
+			}
+		}
+	}
+	return reasons
+}
+
 // computeStats computes failures / errors on testSuites,
 // set result.Status and return a list of log to send to API
 func computeStats(res *sdk.Result, v *venom.Tests) []string {
@@ -128,7 +170,7 @@ func computeStats(res *sdk.Result, v *venom.Tests) []string {
 		v.TotalSkipped += ts.Skipped
 	}
 
-	var nbOK, nbKO, nbSkipped int
+	var nbOK, nbKO, nbSkipped, nbQuarantined int
 
 	reasons := []string{}
 	reasons = append(reasons, fmt.Sprintf("JUnit parser: %d testsuite(s)", len(v.TestSuites)))
@@ -143,6 +185,11 @@ func computeStats(res *sdk.Result, v *venom.Tests) []string {
 			if tc.Name == "" {
 				tc.Name = fmt.Sprintf("TestCase.%d", k)
 			}
+			if tc.Status == sdk.TestCaseStatusQuarantined {
+				nbQuarantined++
+				v.TestSuites[i].TestCases[k] = tc
+				continue
+			}
 			if len(tc.Failures) > 0 {
 				reasons = append(reasons, fmt.Sprintf("JUnit parser: testcase %s has %d failure(s)", tc.Name, len(tc.Failures)))
 				nbFailures += len(tc.Failures)
@@ -183,6 +230,10 @@ func computeStats(res *sdk.Result, v *venom.Tests) []string {
 		v.TestSuites[i] = ts
 	}
 
+	if nbQuarantined > 0 {
+		reasons = append(reasons, fmt.Sprintf("JUnit parser: %d quarantined test(s) failed", nbQuarantined))
+	}
+
 	if nbKO > v.TotalKO {
 		v.TotalKO = nbKO
 	}
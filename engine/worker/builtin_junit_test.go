@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/venom"
 )
@@ -216,3 +218,45 @@ func Test_computeStats(t *testing.T) {
 		})
 	}
 }
+
+func Test_applyTestQuarantines(t *testing.T) {
+	v := &venom.Tests{
+		TestSuites: []venom.TestSuite{
+			{
+				Name:     "myTestSuite",
+				Failures: 2,
+				TestCases: []venom.TestCase{
+					{Name: "ok"},
+					{Name: "flaky", Failures: []venom.Failure{{Value: "boom"}}},
+					{Name: "broken", Failures: []venom.Failure{{Value: "boom"}}},
+				},
+			},
+		},
+	}
+	qs := []sdk.TestQuarantine{
+		{Name: "flaky", Owner: "foo", Reason: "timeout"},
+		{TestSuite: "otherTestSuite", Name: "broken"},
+	}
+
+	reasons := applyTestQuarantines(v, qs)
+	assert.Equal(t, []string{"JUnit parser: testcase flaky failed but is quarantined (owner: foo, reason: timeout)"}, reasons)
+	assert.Equal(t, sdk.TestCaseStatusQuarantined, v.TestSuites[0].TestCases[1].Status)
+	assert.Equal(t, "", v.TestSuites[0].TestCases[2].Status)
+	assert.Equal(t, 1, v.TestSuites[0].Failures)
+
+	res := &sdk.Result{}
+	computeStats(res, v)
+	assert.Equal(t, sdk.StatusFail.String(), res.Status)
+	assert.Equal(t, 1, v.TotalKO)
+
+	// once the broken test is quarantined too, the step succeeds
+	v.TestSuites[0].Failures = 2
+	v.TestSuites[0].TestCases[1].Status = ""
+	v.TotalKO, v.TotalOK, v.Total = 0, 0, 0
+	applyTestQuarantines(v, append(qs, sdk.TestQuarantine{TestSuite: "myTestSuite", Name: "broken"}))
+	res = &sdk.Result{}
+	computeStats(res, v)
+	assert.Equal(t, sdk.StatusSuccess.String(), res.Status)
+	assert.Equal(t, 0, v.TotalKO)
+	assert.Equal(t, 2, sdk.CountQuarantinedTests(*v))
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/grpcplugin/actionplugin"
+)
+
+type fakeActionPluginClient struct {
+	events    []*actionplugin.ActionEvent
+	streamErr error
+	runCalled bool
+}
+
+func (c *fakeActionPluginClient) Manifest(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*actionplugin.ActionPluginManifest, error) {
+	return &actionplugin.ActionPluginManifest{}, nil
+}
+
+func (c *fakeActionPluginClient) Run(ctx context.Context, in *actionplugin.ActionQuery, opts ...grpc.CallOption) (*actionplugin.ActionResult, error) {
+	c.runCalled = true
+	return &actionplugin.ActionResult{Status: sdk.StatusSuccess.String(), Details: "run"}, nil
+}
+
+func (c *fakeActionPluginClient) RunStream(ctx context.Context, in *actionplugin.ActionQuery, opts ...grpc.CallOption) (actionplugin.ActionPlugin_RunStreamClient, error) {
+	return &fakeRunStreamClient{client: c}, nil
+}
+
+func (c *fakeActionPluginClient) WorkerHTTPPort(ctx context.Context, in *actionplugin.WorkerHTTPPortQuery, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+func (c *fakeActionPluginClient) Stop(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+type fakeRunStreamClient struct {
+	grpc.ClientStream
+	client *fakeActionPluginClient
+}
+
+func (s *fakeRunStreamClient) Recv() (*actionplugin.ActionEvent, error) {
+	if s.client.streamErr != nil {
+		return nil, s.client.streamErr
+	}
+	if len(s.client.events) == 0 {
+		return nil, io.EOF
+	}
+	e := s.client.events[0]
+	s.client.events = s.client.events[1:]
+	return e, nil
+}
+
+func Test_runActionPlugin(t *testing.T) {
+	w := &currentWorker{}
+	var logs []string
+	sendLog := func(s string) { logs = append(logs, s) }
+
+	// Logs and progress are sent to the step logs until the result
+	c := &fakeActionPluginClient{
+		events: []*actionplugin.ActionEvent{
+			{Event: &actionplugin.ActionEvent_Log{Log: "starting"}},
+			{Event: &actionplugin.ActionEvent_Progress{Progress: &actionplugin.ActionProgress{Current: 1, Total: 2, Message: "uploading"}}},
+			{Event: &actionplugin.ActionEvent_Progress{Progress: &actionplugin.ActionProgress{Message: "waiting"}}},
+			{Event: &actionplugin.ActionEvent_Result{Result: &actionplugin.ActionResult{Status: sdk.StatusSuccess.String(), Details: "done"}}},
+			{Event: &actionplugin.ActionEvent_Log{Log: "after the result"}},
+		},
+	}
+	res, err := w.runActionPlugin(context.Background(), c, &actionplugin.ActionQuery{}, 1, &[]sdk.Parameter{}, sendLog)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusSuccess.String(), res.Status)
+	assert.Equal(t, "done", res.Details)
+	assert.Equal(t, []string{"starting", "# Progress 1/2 uploading", "# Progress waiting"}, logs)
+	assert.False(t, c.runCalled)
+
+	// A stream ended without result is an error
+	c = &fakeActionPluginClient{
+		events: []*actionplugin.ActionEvent{{Event: &actionplugin.ActionEvent_Log{Log: "starting"}}},
+	}
+	_, err = w.runActionPlugin(context.Background(), c, &actionplugin.ActionQuery{}, 1, &[]sdk.Parameter{}, sendLog)
+	assert.Error(t, err)
+
+	// Run is called for the plugins which do not implement RunStream
+	c = &fakeActionPluginClient{streamErr: status.Error(codes.Unimplemented, "RunStream is not implemented")}
+	res, err = w.runActionPlugin(context.Background(), c, &actionplugin.ActionQuery{}, 1, &[]sdk.Parameter{}, sendLog)
+	require.NoError(t, err)
+	assert.True(t, c.runCalled)
+	assert.Equal(t, "run", res.Details)
+}
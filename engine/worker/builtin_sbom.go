@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runSBOMAction(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		var res sdk.Result
+		res.Status = sdk.StatusFail.String()
+
+		p := sdk.ParameterValue(a.Parameters, "path")
+		if p == "" {
+			res.Reason = fmt.Sprintf("SBOM: path not provided")
+			sendLog(res.Reason)
+			return res
+		}
+
+		format := sdk.ParameterValue(a.Parameters, "format")
+		if format == "" {
+			res.Reason = fmt.Sprintf("SBOM: format not provided")
+			sendLog(res.Reason)
+			return res
+		}
+
+		files, errg := filepath.Glob(p)
+		if errg != nil {
+			res.Reason = fmt.Sprintf("SBOM: Cannot find requested files, invalid pattern")
+			sendLog(res.Reason)
+			return res
+		}
+		if len(files) == 0 {
+			res.Reason = fmt.Sprintf("SBOM: pattern '%s' matched no file", p)
+			sendLog(res.Reason)
+			return res
+		}
+
+		for _, f := range files {
+			data, errRead := ioutil.ReadFile(f)
+			if errRead != nil {
+				res.Reason = fmt.Sprintf("SBOM: cannot read file %s (%s)", f, errRead)
+				sendLog(res.Reason)
+				return res
+			}
+
+			// components are parsed before the upload to fail fast on invalid documents
+			cs, errP := sdk.ParseSBOM(format, data)
+			if errP != nil {
+				res.Reason = fmt.Sprintf("SBOM: unable to parse file %s: %v", f, errP)
+				sendLog(res.Reason)
+				return res
+			}
+
+			if err := w.client.QueueJobSendSBOM(ctx, w.currentJob.wJob.ID, sdk.WorkflowNodeRunSBOM{
+				Name:     filepath.Base(f),
+				Format:   format,
+				Document: data,
+			}); err != nil {
+				res.Reason = fmt.Sprintf("SBOM: failed to send file %s: %v", f, err)
+				sendLog(res.Reason)
+				return res
+			}
+			sendLog(fmt.Sprintf("File '%s' uploaded with %d component(s)", filepath.Base(f), len(cs)))
+		}
+
+		res.Status = sdk.StatusSuccess.String()
+		return res
+	}
+}
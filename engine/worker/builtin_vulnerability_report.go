@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runParseVulnerabilityReportAction(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, secrets []sdk.Variable, sendLog LoggerFunc) sdk.Result {
+		var res sdk.Result
+		res.Status = sdk.StatusFail.String()
+
+		p := sdk.ParameterValue(a.Parameters, "path")
+		if p == "" {
+			res.Reason = fmt.Sprintf("Vulnerability parser: path not provided")
+			sendLog(res.Reason)
+			return res
+		}
+
+		format := sdk.ParameterValue(a.Parameters, "format")
+		if format == "" {
+			res.Reason = fmt.Sprintf("Vulnerability parser: format not provided")
+			sendLog(res.Reason)
+			return res
+		}
+
+		t := sdk.ParameterValue(a.Parameters, "type")
+		if t == "" {
+			t = format
+		}
+
+		files, errg := filepath.Glob(p)
+		if errg != nil {
+			res.Reason = fmt.Sprintf("Vulnerability parser: Cannot find requested files, invalid pattern")
+			sendLog(res.Reason)
+			return res
+		}
+		sendLog(fmt.Sprintf("%d file(s) to analyze", len(files)))
+
+		var vulns []sdk.Vulnerability
+		for _, f := range files {
+			data, errRead := ioutil.ReadFile(f)
+			if errRead != nil {
+				res.Reason = fmt.Sprintf("Vulnerability parser: cannot read file %s (%s)", f, errRead)
+				sendLog(res.Reason)
+				return res
+			}
+
+			vs, errP := sdk.ParseVulnerabilityReport(format, data)
+			if errP != nil {
+				res.Reason = fmt.Sprintf("Vulnerability parser: unable to parse report %s: %v", f, errP)
+				sendLog(res.Reason)
+				return res
+			}
+			vulns = append(vulns, vs...)
+		}
+
+		report := sdk.NewVulnerabilityWorkerReport(t, vulns)
+		for s, n := range report.Summary {
+			sendLog(fmt.Sprintf("%d %s vulnerabilities", n, s))
+		}
+
+		data, errM := json.Marshal(report)
+		if errM != nil {
+			res.Reason = fmt.Sprintf("Vulnerability parser: failed to marshal report for cds api: %v", errM)
+			sendLog(res.Reason)
+			return res
+		}
+
+		uri := fmt.Sprintf("/queue/workflows/%d/vulnerability", w.currentJob.wJob.ID)
+
+		body, code, err := sdk.Request("POST", uri, data)
+		if err == nil && code > 300 {
+			err = fmt.Errorf("HTTP %d", code)
+		}
+
+		if err != nil {
+			res.Reason = fmt.Sprintf("Vulnerability parser: failed to send vulnerability report: %s", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		var evaluation *sdk.VulnerabilityPolicyEvaluation
+		if err := json.Unmarshal(body, &evaluation); err != nil {
+			res.Reason = fmt.Sprintf("Vulnerability parser: unable to read vulnerability policy evaluation: %v", err)
+			sendLog(res.Reason)
+			return res
+		}
+		if evaluation != nil {
+			for _, r := range evaluation.Reasons {
+				sendLog(fmt.Sprintf("Vulnerability policy not respected: %s", r))
+			}
+			if evaluation.BlocksTriggers() {
+				sendLog("Pipelines after the current one will not be triggered")
+			}
+			if evaluation.FailsNodeRun() {
+				res.Reason = fmt.Sprintf("Vulnerability policy not respected: %s", strings.Join(evaluation.Reasons, ", "))
+				return res
+			}
+		}
+
+		res.Status = sdk.StatusSuccess.String()
+		return res
+	}
+}
@@ -44,10 +44,9 @@ Here, an example of a script inside a CDS Job using the cache feature:
 
 	#!/bin/bash
 
-	tag=($(md5sum pom.xml))
-
 	# download the cache of .m2/
-	if worker cache pull $tag; then
+	# if there is no cache for the current pom.xml, the latest cache pushed for any pom.xml is used
+	if worker cache pull 'm2-{{ hashFiles "pom.xml" }}' --restore-key m2-; then
 		echo ".m2/ getted from cache";
 	fi
 
@@ -57,7 +56,17 @@ Here, an example of a script inside a CDS Job using the cache feature:
 	mvn install
 
 	# put in cache the updated .m2/ directory
-	worker cache push $tag .m2/
+	worker cache push 'm2-{{ hashFiles "pom.xml" }}' .m2/
+
+## Cache keys
+
+A tag can be computed from the content of files with the function hashFiles. It takes one or several patterns,
+relative to the current directory, where ** matches any number of directories:
+
+	worker cache push 'go-{{ hashFiles "go.sum" "**/go.sum" }}' vendor/
+
+Identical archives are stored once in the project storage, and the least recently used caches are deleted when
+the size of the caches of the project exceeds the limit configured on the CDS API.
 
     `,
 	}
@@ -66,7 +75,20 @@ Here, an example of a script inside a CDS Job using the cache feature:
 	return cmdCacheRoot
 }
 
-var cmdStorageIntegrationName string
+var (
+	cmdStorageIntegrationName string
+	cmdCacheRestoreKeys       []string
+)
+
+// cacheRef returns the tag sent to the API from the base64 encoded tag received by the worker.
+// Tags that are not valid names are sent encoded, like it was done for all tags before.
+func cacheRef(ref string) string {
+	tag, err := base64.RawURLEncoding.DecodeString(ref)
+	if err != nil || !sdk.NamePatternRegex.MatchString(string(tag)) {
+		return ref
+	}
+	return string(tag)
+}
 
 func cmdCachePush(w *currentWorker) *cobra.Command {
 	c := &cobra.Command{
@@ -117,8 +139,13 @@ func cachePushCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache push > Cannot find working directory : %s", err)
 		}
 
+		tag, err := sdk.ComputeCacheKey(cwd, args[0])
+		if err != nil {
+			sdk.Exit("worker cache push > %v", err)
+		}
+
 		c := sdk.Cache{
-			Tag:              args[0],
+			Tag:              tag,
 			Files:            files,
 			WorkingDirectory: cwd,
 			IntegrationName:  cmdStorageIntegrationName,
@@ -129,10 +156,10 @@ func cachePushCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache push > internal error (%s)", errMarshal)
 		}
 
-		fmt.Printf("Worker cache push in progress... (tag: %s)\n", args[0])
+		fmt.Printf("Worker cache push in progress... (tag: %s)\n", tag)
 		req, errRequest := http.NewRequest(
 			"POST",
-			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/push", port, base64.RawURLEncoding.EncodeToString([]byte(args[0]))),
+			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/push", port, base64.RawURLEncoding.EncodeToString([]byte(tag))),
 			bytes.NewReader(data),
 		)
 		if errRequest != nil {
@@ -157,7 +184,7 @@ func cachePushCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			sdk.Exit("Error: http code %d : %v", resp.StatusCode, cdsError)
 		}
 
-		fmt.Printf("Worker cache push with success (tag: %s)\n", args[0])
+		fmt.Printf("Worker cache push with success (tag: %s)\n", tag)
 	}
 }
 
@@ -186,8 +213,33 @@ func (wk *currentWorker) cachePushHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	res, size, errTar := sdk.CreateTarFromPaths(c.WorkingDirectory, c.Files, nil)
-	if errTar != nil {
+	params := wk.currentJob.wJob.Parameters
+	projectKey := sdk.ParameterValue(params, "cds.project")
+	if projectKey == "" {
+		errP := sdk.Error{
+			Message: "worker cache push > Cannot find project",
+			Status:  http.StatusInternalServerError,
+		}
+		log.Error("%v", errP)
+		writeError(w, r, errP)
+		return
+	}
+
+	// The archive is written in a temporary file instead of being kept in memory
+	tarFile, errTmp := ioutil.TempFile("", "cds-cache-")
+	if errTmp != nil {
+		errTmp = sdk.Error{
+			Message: "worker cache push > Cannot create temporary file : " + errTmp.Error(),
+			Status:  http.StatusInternalServerError,
+		}
+		log.Error("%v", errTmp)
+		writeError(w, r, errTmp)
+		return
+	}
+	defer os.Remove(tarFile.Name()) // nolint
+	defer tarFile.Close()           // nolint
+
+	if errTar := sdk.WriteTarFromPaths(tarFile, c.WorkingDirectory, c.Files, nil); errTar != nil {
 		errTar = sdk.Error{
 			Message: "worker cache push > Cannot tar : " + errTar.Error(),
 			Status:  http.StatusBadRequest,
@@ -196,21 +248,20 @@ func (wk *currentWorker) cachePushHandler(w http.ResponseWriter, r *http.Request
 		writeError(w, r, errTar)
 		return
 	}
-	params := wk.currentJob.wJob.Parameters
-	projectKey := sdk.ParameterValue(params, "cds.project")
-	if projectKey == "" {
-		errP := sdk.Error{
-			Message: "worker cache push > Cannot find project",
+	size, errSize := tarFile.Seek(0, io.SeekCurrent)
+	if errSize != nil {
+		errSize = sdk.Error{
+			Message: "worker cache push > Cannot read tar : " + errSize.Error(),
 			Status:  http.StatusInternalServerError,
 		}
-		log.Error("%v", errP)
-		writeError(w, r, errP)
+		log.Error("%v", errSize)
+		writeError(w, r, errSize)
 		return
 	}
 
 	var errPush error
 	for i := 0; i < 10; i++ {
-		if errPush = wk.client.WorkflowCachePush(projectKey, sdk.DefaultIfEmptyStorage(c.IntegrationName), vars["ref"], res, size); errPush == nil {
+		if errPush = wk.client.WorkflowCachePush(projectKey, sdk.DefaultIfEmptyStorage(c.IntegrationName), cacheRef(vars["ref"]), tarFile, int(size)); errPush == nil {
 			return
 		}
 		time.Sleep(3 * time.Second)
@@ -253,6 +304,7 @@ If you want to push a cache into a storage integration:
 		Run: cachePullCmd(w),
 	}
 	c.Flags().StringVar(&cmdStorageIntegrationName, "from", "", "optional. Your storage integration name")
+	c.Flags().StringSliceVar(&cmdCacheRestoreKeys, "restore-key", nil, "optional. If there is no cache for the tag, the latest cache which tag starts with the first matching restore key is used")
 	return c
 }
 
@@ -277,14 +329,35 @@ func cachePullCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache pull > cannot get current path: %s", err)
 		}
 
-		fmt.Printf("Worker cache pull in progress... (tag: %s)\n", args[0])
+		cwd, err := os.Getwd()
+		if err != nil {
+			sdk.Exit("worker cache pull > Cannot find working directory : %s", err)
+		}
+
+		tag, err := sdk.ComputeCacheKey(cwd, args[0])
+		if err != nil {
+			sdk.Exit("worker cache pull > %v", err)
+		}
+
+		query := url.Values{}
+		query.Set("path", dir)
+		query.Set("integration", cmdStorageIntegrationName)
+		for _, k := range cmdCacheRestoreKeys {
+			restoreKey, err := sdk.ComputeCacheKey(cwd, k)
+			if err != nil {
+				sdk.Exit("worker cache pull > %v", err)
+			}
+			query.Add("restore", restoreKey)
+		}
+
+		fmt.Printf("Worker cache pull in progress... (tag: %s)\n", tag)
 		req, errRequest := http.NewRequest(
 			"GET",
-			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?path=%s&integration=%s", port, base64.RawURLEncoding.EncodeToString([]byte(args[0])), url.QueryEscape(dir), url.QueryEscape(cmdStorageIntegrationName)),
+			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?%s", port, base64.RawURLEncoding.EncodeToString([]byte(tag)), query.Encode()),
 			nil,
 		)
 		if errRequest != nil {
-			sdk.Exit("worker cache pull > cannot post worker cache pull with tag %s (Request): %s", args[0], errRequest)
+			sdk.Exit("worker cache pull > cannot post worker cache pull with tag %s (Request): %s", tag, errRequest)
 		}
 
 		client := http.DefaultClient
@@ -304,7 +377,11 @@ func cachePullCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
 			sdk.Exit("Error: %v", cdsError)
 		}
 
-		fmt.Printf("Worker cache pull with success (tag: %s)\n", args[0])
+		if key := resp.Header.Get(sdk.CacheKeyHeader); key != "" {
+			fmt.Printf("Worker cache pull with success (tag: %s, restored from: %s)\n", tag, key)
+			return
+		}
+		fmt.Printf("Worker cache pull with success (tag: %s)\n", tag)
 	}
 }
 
@@ -314,7 +391,13 @@ func (wk *currentWorker) cachePullHandler(w http.ResponseWriter, r *http.Request
 	integrationName := sdk.DefaultIfEmptyStorage(r.FormValue("integration"))
 	params := wk.currentJob.wJob.Parameters
 	projectKey := sdk.ParameterValue(params, "cds.project")
-	bts, err := wk.client.WorkflowCachePull(projectKey, integrationName, vars["ref"])
+	ref := cacheRef(vars["ref"])
+	bts, key, err := wk.client.WorkflowCachePull(projectKey, integrationName, ref, r.Form["restore"]...)
+	if err != nil && ref != vars["ref"] {
+		// Caches pushed before the tags were sent decoded are stored with the encoded tag
+		ref = vars["ref"]
+		bts, key, err = wk.client.WorkflowCachePull(projectKey, integrationName, ref, r.Form["restore"]...)
+	}
 	if err != nil {
 		err = sdk.Error{
 			Message: "worker cache pull > Cannot pull cache: " + err.Error(),
@@ -324,6 +407,11 @@ func (wk *currentWorker) cachePullHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// the cache was found with a restore key
+	if key != "" && key != ref {
+		w.Header().Set(sdk.CacheKeyHeader, key)
+	}
+
 	tr := tar.NewReader(bts)
 	for {
 		header, errH := tr.Next()
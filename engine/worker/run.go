@@ -261,9 +261,9 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 			}
 
 			if r.Reason != "" {
-				_ = w.sendLog(buildID, fmt.Sprintf("End of step \"%s\" [%s] with reason: %s", childName, r.Status, r.Reason), w.currentJob.currentStep, true)
+				_ = w.sendLog(buildID, fmt.Sprintf("%sEnd of step \"%s\" [%s] with reason: %s", endOfStepMarker(r.Status), childName, r.Status, r.Reason), w.currentJob.currentStep, true)
 			} else {
-				_ = w.sendLog(buildID, fmt.Sprintf("End of step \"%s\" [%s]", childName, r.Status), w.currentJob.currentStep, true)
+				_ = w.sendLog(buildID, fmt.Sprintf("%sEnd of step \"%s\" [%s]", endOfStepMarker(r.Status), childName, r.Status), w.currentJob.currentStep, true)
 			}
 
 			// Update step status
@@ -287,6 +287,14 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 	return r, nbDisabledChildren
 }
 
+// endOfStepMarker returns the error marker for the end of step line of a failed step.
+func endOfStepMarker(status string) string {
+	if status == sdk.StatusFail.String() {
+		return sdk.LogMarkerError
+	}
+	return ""
+}
+
 func (w *currentWorker) updateStepStatus(ctx context.Context, buildID int64, stepOrder int, status string) error {
 	step := sdk.StepStatus{
 		StepOrder: stepOrder,
@@ -25,6 +25,8 @@ const (
 	ReleaseAction             = "Release"
 	CheckoutApplicationAction = "CheckoutApplication"
 	DeployApplicationAction   = "DeployApplication"
+	VulnerabilityReportAction = "VulnerabilityReport"
+	SBOMAction                = "SBOM"
 
 	DefaultGitCloneParameterTagValue = "{{.git.tag}}"
 )
@@ -20,8 +20,10 @@ var List = []Manifest{
 	GitTag,
 	JUnit,
 	Release,
+	SBOM,
 	Script,
 	ServeStaticFiles,
+	VulnerabilityReport,
 }
 
 // Manifest for a action.
@@ -10,16 +10,17 @@ var Coverage = Manifest{
 	Action: sdk.Action{
 		Name: sdk.CoverageAction,
 		Description: `CDS Builtin Action.
-Parse given file to extract coverage results.
+Parse given file to extract coverage results for lcov, cobertura, clover, Go cover profile (gocover) and JaCoCo XML (jacoco) formats.
 
 Coverage report will be linked to the application from the pipeline context.
-You will be able to see the coverage history in the application home page.`,
+You will be able to see the coverage history in the application home page.
+The coverage is detailed by file and by package when the report format provides it.`,
 		Parameters: []sdk.Parameter{
 			{
 				Name:        "format",
 				Description: `Coverage report format.`,
 				Type:        sdk.ListParameter,
-				Value:       "lcov;cobertura;clover",
+				Value:       "lcov;cobertura;clover;" + sdk.CoverageFormatGoCover + ";" + sdk.CoverageFormatJaCoCo,
 			},
 			{
 				Name:        "path",
@@ -32,6 +33,12 @@ You will be able to see the coverage history in the application home page.`,
 				Type:        sdk.NumberParameter,
 				Advanced:    true,
 			},
+			{
+				Name:        "max_drop",
+				Description: `Maximum drop of the percentage of coverage compared to the latest report of the default branch (-1 means no check).`,
+				Type:        sdk.NumberParameter,
+				Advanced:    true,
+			},
 		},
 	},
 	Example: exportentities.PipelineV1{
@@ -75,6 +75,48 @@ The public key have to be granted on your repository.`,
 				Type:        sdk.StringParameter,
 				Advanced:    true,
 			},
+			{
+				Name:        "sparseCheckout",
+				Description: "(optional) Comma separated list of directories to checkout, ie. `sdk,engine/api`. The whole repository is checked out by default.",
+				Value:       "",
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "filter",
+				Description: "(optional) Partial clone filter, ie. `blob:none` to fetch file contents on demand or `tree:0` to fetch trees on demand. Empty by default.",
+				Value:       "",
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "lfs",
+				Description: "(optional) Set 'true' to fetch Git LFS objects after the checkout, git-lfs must be installed on the worker.",
+				Value:       "false",
+				Type:        sdk.BooleanParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "lfsInclude",
+				Description: "(optional) Comma separated list of patterns of the Git LFS objects to fetch, ie. `*.bin,assets/**`. All objects are fetched by default.",
+				Value:       "",
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "lfsExclude",
+				Description: "(optional) Comma separated list of patterns of the Git LFS objects not to fetch.",
+				Value:       "",
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+			{
+				Name:        "reference",
+				Description: "(optional) Path of a local mirror of the repository on the worker. Its objects are reused instead of being fetched again, it is ignored if it does not exist.",
+				Value:       "",
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
 		},
 		Requirements: []sdk.Requirement{
 			sdk.Requirement{
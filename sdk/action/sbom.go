@@ -0,0 +1,48 @@
+package action
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// SBOM action definition.
+var SBOM = Manifest{
+	Action: sdk.Action{
+		Name: sdk.SBOMAction,
+		Description: `CDS Builtin Action.
+Upload software bill of materials files in CycloneDX or SPDX JSON format.
+
+The files are stored with the workflow node run and their components (name, version, license and purl) are indexed for the application.
+You will be able to search which applications and runs contain a component with 'cdsctl sbom search'.`,
+		Parameters: []sdk.Parameter{
+			{
+				Name:        "format",
+				Description: `Software bill of materials format.`,
+				Type:        sdk.ListParameter,
+				Value:       sdk.SBOMFormatCycloneDX + ";" + sdk.SBOMFormatSPDX,
+			},
+			{
+				Name:        "path",
+				Description: `Path of the software bill of materials files, ie. ./bom.json.`,
+				Type:        sdk.StringParameter,
+			},
+		},
+	},
+	Example: exportentities.PipelineV1{
+		Version: exportentities.PipelineVersion1,
+		Name:    "Pipeline1",
+		Stages:  []string{"Stage1"},
+		Jobs: []exportentities.Job{{
+			Name:  "Job1",
+			Stage: "Stage1",
+			Steps: []exportentities.Step{
+				{
+					SBOM: &exportentities.StepSBOM{
+						Format: sdk.SBOMFormatCycloneDX,
+						Path:   "./bom.json",
+					},
+				},
+			},
+		}},
+	},
+}
@@ -0,0 +1,57 @@
+package action
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// VulnerabilityReport action definition.
+var VulnerabilityReport = Manifest{
+	Action: sdk.Action{
+		Name: sdk.VulnerabilityReportAction,
+		Description: `CDS Builtin Action.
+Parse given SARIF 2.1 or CycloneDX VEX files to extract vulnerabilities.
+
+Vulnerabilities will be linked to the application from the pipeline context.
+The report is compared with the report of the default branch, on the default branch the vulnerabilities of the application are updated.
+
+If a vulnerability policy is set on the application or its project, the vulnerabilities are evaluated against it: the step fails or the next pipelines are not triggered when the policy is not respected.`,
+		Parameters: []sdk.Parameter{
+			{
+				Name:        "format",
+				Description: `Vulnerability report format.`,
+				Type:        sdk.ListParameter,
+				Value:       sdk.VulnerabilityReportFormatSARIF + ";" + sdk.VulnerabilityReportFormatCycloneDXVEX,
+			},
+			{
+				Name:        "path",
+				Description: `Path of the vulnerability report files, ie. ./reports/*.sarif.`,
+				Type:        sdk.StringParameter,
+			},
+			{
+				Name:        "type",
+				Description: `(optional) Type of the vulnerabilities, a new report replaces the vulnerabilities of the same type on the application. The format is used by default.`,
+				Type:        sdk.StringParameter,
+				Advanced:    true,
+			},
+		},
+	},
+	Example: exportentities.PipelineV1{
+		Version: exportentities.PipelineVersion1,
+		Name:    "Pipeline1",
+		Stages:  []string{"Stage1"},
+		Jobs: []exportentities.Job{{
+			Name:  "Job1",
+			Stage: "Stage1",
+			Steps: []exportentities.Step{
+				{
+					VulnerabilityReport: &exportentities.StepVulnerabilityReport{
+						Format: sdk.VulnerabilityReportFormatSARIF,
+						Path:   "./reports/*.sarif",
+						Type:   "sast",
+					},
+				},
+			},
+		}},
+	},
+}
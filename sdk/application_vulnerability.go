@@ -1,6 +1,9 @@
 package sdk
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // VulnerabilityWorkerReport represent a vulnerability report
 type VulnerabilityWorkerReport struct {
@@ -24,6 +27,13 @@ type Vulnerability struct {
 	FixIn         string `json:"fix_in" db:"fix_in"`
 	Ignored       bool   `json:"ignored" db:"ignored"`
 	Type          string `json:"type" db:"type"`
+	Rule          string `json:"rule" db:"rule"`
+	Location      string `json:"location" db:"location"`
+}
+
+// Key returns a key identifying the vulnerability across reports.
+func (v Vulnerability) Key() string {
+	return fmt.Sprintf("%s-%s-%s-%s-%s", v.Component, v.Version, v.CVE, v.Rule, v.Location)
 }
 
 const (
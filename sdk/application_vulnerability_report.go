@@ -0,0 +1,307 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Vulnerability report formats supported by the VulnerabilityReport builtin action.
+const (
+	VulnerabilityReportFormatSARIF        = "sarif"
+	VulnerabilityReportFormatCycloneDXVEX = "cyclonedx-vex"
+)
+
+// SARIFReport is a Static Analysis Results Interchange Format 2.1 log file.
+type SARIFReport struct {
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is the result of a run of an analysis tool.
+type SARIFRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string      `json:"name"`
+			Rules []SARIFRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFMessage is a message of a SARIF report.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFRule is the metadata of a rule of an analysis tool.
+type SARIFRule struct {
+	ID                   string       `json:"id"`
+	Name                 string       `json:"name"`
+	ShortDescription     SARIFMessage `json:"shortDescription"`
+	FullDescription      SARIFMessage `json:"fullDescription"`
+	HelpURI              string       `json:"helpUri"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+	Properties struct {
+		SecuritySeverity string   `json:"security-severity"`
+		Tags             []string `json:"tags"`
+	} `json:"properties"`
+}
+
+// SARIFResult is a problem detected by an analysis tool.
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex *int            `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFLocation is the location of a result.
+type SARIFLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine int `json:"startLine"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+// CycloneDXReport is a CycloneDX BOM containing Vulnerability Exploitability eXchange data.
+type CycloneDXReport struct {
+	BOMFormat       string                   `json:"bomFormat"`
+	SpecVersion     string                   `json:"specVersion"`
+	Components      []CycloneDXComponent     `json:"components"`
+	Vulnerabilities []CycloneDXVulnerability `json:"vulnerabilities"`
+}
+
+// CycloneDXComponent is a component of a CycloneDX BOM.
+type CycloneDXComponent struct {
+	BOMRef   string `json:"bom-ref"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	PURL     string `json:"purl"`
+	Licenses []struct {
+		License struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	} `json:"licenses"`
+	Components []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXVulnerability is a vulnerability of a CycloneDX BOM.
+type CycloneDXVulnerability struct {
+	ID     string `json:"id"`
+	Source struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"source"`
+	Ratings []struct {
+		Score    float64 `json:"score"`
+		Severity string  `json:"severity"`
+	} `json:"ratings"`
+	Description    string `json:"description"`
+	Detail         string `json:"detail"`
+	Recommendation string `json:"recommendation"`
+	Advisories     []struct {
+		URL string `json:"url"`
+	} `json:"advisories"`
+	Affects []struct {
+		Ref string `json:"ref"`
+	} `json:"affects"`
+	Analysis struct {
+		State string `json:"state"`
+	} `json:"analysis"`
+}
+
+// ParseVulnerabilityReport parses a report of given format and returns its vulnerabilities.
+func ParseVulnerabilityReport(format string, data []byte) ([]Vulnerability, error) {
+	switch format {
+	case VulnerabilityReportFormatSARIF:
+		var r SARIFReport
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, NewErrorFrom(ErrWrongRequest, "invalid sarif report: %v", err)
+		}
+		return r.ToVulnerabilities(), nil
+	case VulnerabilityReportFormatCycloneDXVEX:
+		var r CycloneDXReport
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, NewErrorFrom(ErrWrongRequest, "invalid cyclonedx report: %v", err)
+		}
+		return r.ToVulnerabilities(), nil
+	}
+	return nil, NewErrorFrom(ErrWrongRequest, "unknown vulnerability report format %s", format)
+}
+
+// NewVulnerabilityWorkerReport returns a report for given vulnerabilities with its summary by severity.
+func NewVulnerabilityWorkerReport(t string, vs []Vulnerability) VulnerabilityWorkerReport {
+	summary := make(map[string]int64)
+	for _, v := range vs {
+		summary[v.Severity]++
+	}
+	return VulnerabilityWorkerReport{
+		Type:            t,
+		Summary:         summary,
+		Vulnerabilities: vs,
+	}
+}
+
+// ToVulnerabilities returns the results of all runs of the report.
+func (r SARIFReport) ToVulnerabilities() []Vulnerability {
+	var vs []Vulnerability
+	for _, run := range r.Runs {
+		rules := make(map[string]SARIFRule, len(run.Tool.Driver.Rules))
+		for _, rule := range run.Tool.Driver.Rules {
+			rules[rule.ID] = rule
+		}
+		for _, res := range run.Results {
+			rule, ok := rules[res.RuleID]
+			if !ok && res.RuleIndex != nil && *res.RuleIndex < len(run.Tool.Driver.Rules) {
+				rule = run.Tool.Driver.Rules[*res.RuleIndex]
+			}
+			if res.RuleID == "" {
+				res.RuleID = rule.ID
+			}
+
+			v := Vulnerability{
+				Title:       rule.ShortDescription.Text,
+				Description: res.Message.Text,
+				CVE:         sarifCVE(res.RuleID, rule.Properties.Tags),
+				Link:        rule.HelpURI,
+				Origin:      run.Tool.Driver.Name,
+				Rule:        res.RuleID,
+				Severity:    sarifSeverity(res.Level, rule),
+			}
+			if v.Title == "" {
+				v.Title = rule.Name
+			}
+			if v.Title == "" {
+				v.Title = res.RuleID
+			}
+			if len(res.Locations) > 0 {
+				l := res.Locations[0].PhysicalLocation
+				v.Component = l.ArtifactLocation.URI
+				v.Location = l.ArtifactLocation.URI
+				if l.Region.StartLine > 0 {
+					v.Location = fmt.Sprintf("%s:%d", l.ArtifactLocation.URI, l.Region.StartLine)
+				}
+			}
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
+
+// sarifCVE returns the CVE identifier from the rule id or the rule tags.
+func sarifCVE(ruleID string, tags []string) string {
+	for _, s := range append([]string{ruleID}, tags...) {
+		if strings.HasPrefix(strings.ToUpper(s), "CVE-") {
+			return strings.ToUpper(s)
+		}
+	}
+	return ""
+}
+
+// sarifSeverity returns the severity from the rule security-severity score if set, or else from the result level.
+func sarifSeverity(level string, rule SARIFRule) string {
+	if score, err := strconv.ParseFloat(rule.Properties.SecuritySeverity, 64); err == nil {
+		return cvssSeverity(score)
+	}
+	if level == "" {
+		level = rule.DefaultConfiguration.Level
+	}
+	switch level {
+	case "error":
+		return SeverityHigh
+	case "warning", "":
+		return SeverityMedium
+	case "note":
+		return SeverityLow
+	case "none":
+		return SeverityNegligible
+	}
+	return SeverityUnknown
+}
+
+// cvssSeverity returns the severity matching a CVSS v3 score.
+func cvssSeverity(score float64) string {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityNegligible
+}
+
+// ToVulnerabilities returns the vulnerabilities of the BOM for each affected component, vulnerabilities analysed
+// as not affecting the component are ignored.
+func (r CycloneDXReport) ToVulnerabilities() []Vulnerability {
+	components := make(map[string]CycloneDXComponent, len(r.Components))
+	for _, c := range r.Components {
+		components[c.BOMRef] = c
+	}
+
+	var vs []Vulnerability
+	for _, cv := range r.Vulnerabilities {
+		v := Vulnerability{
+			Title:       cv.ID,
+			Description: cv.Description,
+			Link:        cv.Source.URL,
+			Origin:      cv.Source.Name,
+			Rule:        cv.ID,
+			FixIn:       cv.Recommendation,
+			Severity:    SeverityUnknown,
+		}
+		if strings.HasPrefix(strings.ToUpper(cv.ID), "CVE-") {
+			v.CVE = strings.ToUpper(cv.ID)
+		}
+		if v.Description == "" {
+			v.Description = cv.Detail
+		}
+		if v.Link == "" && len(cv.Advisories) > 0 {
+			v.Link = cv.Advisories[0].URL
+		}
+		var score float64
+		for _, rating := range cv.Ratings {
+			if rating.Score > score {
+				score = rating.Score
+				v.Severity = cvssSeverity(rating.Score)
+			} else if score == 0 && v.Severity == SeverityUnknown {
+				v.Severity = ToVulnerabilitySeverity(rating.Severity)
+			}
+		}
+		switch cv.Analysis.State {
+		case "not_affected", "false_positive", "resolved":
+			v.Ignored = true
+		}
+
+		if len(cv.Affects) == 0 {
+			vs = append(vs, v)
+			continue
+		}
+		for _, a := range cv.Affects {
+			av := v
+			if c, ok := components[a.Ref]; ok {
+				av.Component = c.Name
+				av.Version = c.Version
+				av.Location = c.PURL
+			} else {
+				av.Component = a.Ref
+			}
+			vs = append(vs, av)
+		}
+	}
+	return vs
+}
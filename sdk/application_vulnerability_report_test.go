@@ -0,0 +1,80 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVulnerabilityReportSARIF(t *testing.T) {
+	report := `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "trivy", "rules": [
+      {"id": "CVE-2021-44228", "shortDescription": {"text": "log4j: remote code execution"}, "helpUri": "https://avd.aquasec.com/nvd/cve-2021-44228", "properties": {"security-severity": "10.0"}},
+      {"id": "go/sql-injection", "name": "SqlInjection", "defaultConfiguration": {"level": "error"}}
+    ]}},
+    "results": [
+      {"ruleId": "CVE-2021-44228", "message": {"text": "Package log4j-core 2.14.1"}, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "pom.xml"}}}]},
+      {"ruleIndex": 1, "message": {"text": "Query built from user input"}, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 42}}}]},
+      {"ruleId": "unknown", "level": "note", "message": {"text": "Note"}}
+    ]
+  }]
+}`
+
+	vs, err := ParseVulnerabilityReport(VulnerabilityReportFormatSARIF, []byte(report))
+	assert.NoError(t, err)
+	assert.Len(t, vs, 3)
+
+	assert.Equal(t, "CVE-2021-44228", vs[0].CVE)
+	assert.Equal(t, "log4j: remote code execution", vs[0].Title)
+	assert.Equal(t, SeverityCritical, vs[0].Severity)
+	assert.Equal(t, "pom.xml", vs[0].Location)
+	assert.Equal(t, "trivy", vs[0].Origin)
+	assert.Equal(t, "https://avd.aquasec.com/nvd/cve-2021-44228", vs[0].Link)
+
+	assert.Equal(t, "go/sql-injection", vs[1].Rule)
+	assert.Equal(t, "SqlInjection", vs[1].Title)
+	assert.Equal(t, "", vs[1].CVE)
+	assert.Equal(t, SeverityHigh, vs[1].Severity)
+	assert.Equal(t, "main.go", vs[1].Component)
+	assert.Equal(t, "main.go:42", vs[1].Location)
+
+	assert.Equal(t, "unknown", vs[2].Title)
+	assert.Equal(t, SeverityLow, vs[2].Severity)
+
+	r := NewVulnerabilityWorkerReport("sarif", vs)
+	assert.Equal(t, map[string]int64{SeverityCritical: 1, SeverityHigh: 1, SeverityLow: 1}, r.Summary)
+}
+
+func TestParseVulnerabilityReportCycloneDXVEX(t *testing.T) {
+	report := `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "components": [{"bom-ref": "pkg:npm/lodash@4.17.20", "name": "lodash", "version": "4.17.20", "purl": "pkg:npm/lodash@4.17.20"}],
+  "vulnerabilities": [
+    {"id": "CVE-2021-23337", "source": {"name": "NVD", "url": "https://nvd.nist.gov/vuln/detail/CVE-2021-23337"}, "ratings": [{"score": 7.2, "severity": "high"}], "description": "Command injection", "recommendation": "4.17.21", "affects": [{"ref": "pkg:npm/lodash@4.17.20"}]},
+    {"id": "GHSA-29mw-wpgm-hmr9", "ratings": [{"severity": "medium"}], "affects": [{"ref": "pkg:npm/lodash@4.17.20"}], "analysis": {"state": "not_affected"}}
+  ]
+}`
+
+	vs, err := ParseVulnerabilityReport(VulnerabilityReportFormatCycloneDXVEX, []byte(report))
+	assert.NoError(t, err)
+	assert.Len(t, vs, 2)
+
+	assert.Equal(t, "CVE-2021-23337", vs[0].CVE)
+	assert.Equal(t, "lodash", vs[0].Component)
+	assert.Equal(t, "4.17.20", vs[0].Version)
+	assert.Equal(t, "pkg:npm/lodash@4.17.20", vs[0].Location)
+	assert.Equal(t, SeverityHigh, vs[0].Severity)
+	assert.Equal(t, "4.17.21", vs[0].FixIn)
+	assert.False(t, vs[0].Ignored)
+
+	assert.Equal(t, "", vs[1].CVE)
+	assert.Equal(t, "GHSA-29mw-wpgm-hmr9", vs[1].Rule)
+	assert.Equal(t, SeverityMedium, vs[1].Severity)
+	assert.True(t, vs[1].Ignored)
+
+	_, err = ParseVulnerabilityReport("unknown", []byte(report))
+	assert.Error(t, err)
+}
@@ -90,10 +90,12 @@ func (ss StepStatus) ToSummary() StepStatusSummary {
 
 // BuildState define struct returned when looking for build state informations
 type BuildState struct {
-	Stages   []Stage `json:"stages"`
-	Logs     []Log   `json:"logs"`
-	StepLogs Log     `json:"step_logs"`
-	Status   Status  `json:"status"`
+	Stages   []Stage      `json:"stages"`
+	Logs     []Log        `json:"logs"`
+	StepLogs Log          `json:"step_logs"`
+	Sections []LogSection `json:"sections,omitempty"`
+	Problems []LogLine    `json:"problems,omitempty"`
+	Status   Status       `json:"status"`
 }
 
 // Status represents a Build Action or Build Pipeline Status
@@ -3,14 +3,24 @@ package sdk
 import (
 	"archive/tar"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"text/template"
+	"time"
 )
 
+// CacheKeyHeader is the response header containing the key of the cache returned by the API,
+// it may differ from the requested one when a restore key was used
+const CacheKeyHeader = "X-CDS-Cache-Key"
+
 // Cache define a file needed to be save for cache
 type Cache struct {
 	ID              int64  `json:"id" cli:"id"`
@@ -23,6 +33,129 @@ type Cache struct {
 
 	Files            []string `json:"files"`
 	WorkingDirectory string   `json:"working_directory"`
+	Digest           string   `json:"digest,omitempty"`
+}
+
+// CacheEntry references a cache archive pushed with a tag in a project storage.
+// Archives are stored once by digest of their content, several tags can reference the same archive.
+type CacheEntry struct {
+	ID              int64     `json:"id" db:"id"`
+	ProjectID       int64     `json:"project_id" db:"project_id"`
+	IntegrationName string    `json:"integration_name" db:"integration_name"`
+	Tag             string    `json:"tag" db:"tag"`
+	Digest          string    `json:"digest" db:"digest"`
+	Size            int64     `json:"size" db:"size"`
+	Created         time.Time `json:"created" db:"created"`
+	LastAccess      time.Time `json:"last_access" db:"last_access"`
+}
+
+// NewCacheBlob returns the object used to store the archive with given digest in a project storage
+func NewCacheBlob(projectKey, digest string) Cache {
+	return Cache{
+		Name:    "blob.tar",
+		Project: projectKey,
+		Tag:     "sha256-" + digest,
+		Digest:  digest,
+	}
+}
+
+// ComputeCacheKey interpolates a cache key template. Available functions are:
+//  - hashFiles "pattern" ...: sha256 of the content of all files, relative to the working directory,
+//    matching the given patterns. ** matches any number of directories.
+func ComputeCacheKey(cwd, key string) (string, error) {
+	if !strings.Contains(key, "{{") {
+		return key, nil
+	}
+
+	tmpl, err := template.New("key").Funcs(template.FuncMap{
+		"hashFiles": func(patterns ...string) (string, error) {
+			return HashFiles(cwd, patterns...)
+		},
+	}).Parse(key)
+	if err != nil {
+		return "", WrapError(err, "invalid cache key %s", key)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return "", WrapError(err, "cannot compute cache key %s", key)
+	}
+	return buf.String(), nil
+}
+
+// HashFiles returns the sha256 of the content of all files in cwd matching given patterns
+func HashFiles(cwd string, patterns ...string) (string, error) {
+	var files []string
+	err := filepath.Walk(cwd, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(cwd, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, p := range patterns {
+			if matchFilePattern(p, rel) {
+				files = append(files, rel)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", WrapError(err, "cannot walk %s", cwd)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no file matching %s", strings.Join(patterns, ", "))
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, file := range files {
+		_, _ = io.WriteString(h, file)
+		f, err := os.Open(filepath.Join(cwd, file))
+		if err != nil {
+			return "", WithStack(err)
+		}
+		_, err = io.Copy(h, f)
+		_ = f.Close()
+		if err != nil {
+			return "", WithStack(err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// matchFilePattern reports whether the slash separated name matches the pattern. It
+// supports path.Match syntax for each element, and ** to match any number of elements.
+func matchFilePattern(pattern, name string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	return matchFileElements(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchFileElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchFileElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
 }
 
 //GetName returns the name the artifact
@@ -47,14 +180,21 @@ type TarOptions struct {
 func CreateTarFromPaths(cwd string, paths []string, opts *TarOptions) (io.Reader, int, error) {
 	// Create a buffer to write our archive to.
 	buf := new(bytes.Buffer)
+	if err := WriteTarFromPaths(buf, cwd, paths, opts); err != nil {
+		return nil, 0, err
+	}
+	return buf, buf.Len(), nil
+}
 
+// WriteTarFromPaths writes a tar made of several path, without keeping it in memory
+func WriteTarFromPaths(w io.Writer, cwd string, paths []string, opts *TarOptions) error {
 	// Create a new tar archive.
-	tw := tar.NewWriter(buf)
+	tw := tar.NewWriter(w)
 
 	for _, path := range paths {
 		// ensure the src actually exists before trying to tar it
 		if _, err := os.Stat(path); err != nil {
-			return nil, 0, fmt.Errorf("Unable to tar files - %v", err.Error())
+			return fmt.Errorf("Unable to tar files - %v", err.Error())
 		}
 		// walk path
 		errWalk := filepath.Walk(path, func(file string, fi os.FileInfo, err error) error {
@@ -107,6 +247,7 @@ func CreateTarFromPaths(cwd string, paths []string, opts *TarOptions) (io.Reader
 
 			// copy file data into tar writer
 			if _, err := io.Copy(tw, f); err != nil {
+				_ = f.Close()
 				return err
 			}
 
@@ -117,18 +258,9 @@ func CreateTarFromPaths(cwd string, paths []string, opts *TarOptions) (io.Reader
 
 		if errWalk != nil {
 			_ = tw.Close()
-			return nil, 0, WrapError(errWalk, "CreateTarFromPaths> Cannot walk file")
+			return WrapError(errWalk, "WriteTarFromPaths> Cannot walk file")
 		}
 	}
 
-	if err := tw.Close(); err != nil {
-		return nil, 0, err
-	}
-
-	// Open the tar archive for reading.
-	btes := buf.Bytes()
-	size := buf.Len()
-	res := bytes.NewBuffer(btes)
-
-	return res, size, nil
+	return tw.Close()
 }
@@ -0,0 +1,79 @@
+package sdk
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchFilePattern(t *testing.T) {
+	assert.True(t, matchFilePattern("go.sum", "go.sum"))
+	assert.False(t, matchFilePattern("go.sum", "vendor/go.sum"))
+	assert.True(t, matchFilePattern("**/go.sum", "go.sum"))
+	assert.True(t, matchFilePattern("**/go.sum", "a/b/go.sum"))
+	assert.True(t, matchFilePattern("./a/*.json", "a/package.json"))
+	assert.False(t, matchFilePattern("a/*.json", "a/b/package.json"))
+	assert.True(t, matchFilePattern("a/**", "a/b/package.json"))
+}
+
+func TestComputeCacheKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache-key")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), os.FileMode(0755)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte("a"), os.FileMode(0644)))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "go.sum"), []byte("b"), os.FileMode(0644)))
+
+	key, err := ComputeCacheKey(dir, "latest")
+	assert.NoError(t, err)
+	assert.Equal(t, "latest", key)
+
+	key1, err := ComputeCacheKey(dir, `go-{{ hashFiles "go.sum" }}`)
+	assert.NoError(t, err)
+	key2, err := ComputeCacheKey(dir, `go-{{ hashFiles "**/go.sum" }}`)
+	assert.NoError(t, err)
+	assert.Len(t, key1, 67)
+	assert.NotEqual(t, key1, key2)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "sub", "go.sum"), []byte("c"), os.FileMode(0644)))
+	key3, err := ComputeCacheKey(dir, `go-{{ hashFiles "**/go.sum" }}`)
+	assert.NoError(t, err)
+	assert.NotEqual(t, key2, key3)
+
+	_, err = ComputeCacheKey(dir, `go-{{ hashFiles "unknown" }}`)
+	assert.Error(t, err)
+}
+
+func TestWriteTarFromPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-tar")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "vendor", "lib"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "vendor", "lib", "a.go"), []byte("package lib"), 0644))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, WriteTarFromPaths(buf, dir, []string{filepath.Join(dir, "vendor")}, nil))
+
+	var names []string
+	tr := tar.NewReader(buf)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		names = append(names, h.Name)
+	}
+	assert.Equal(t, []string{"vendor", "vendor/lib", "vendor/lib/a.go"}, names)
+
+	_, size, err := CreateTarFromPaths(dir, []string{filepath.Join(dir, "vendor")}, nil)
+	assert.NoError(t, err)
+	assert.True(t, size > 0)
+}
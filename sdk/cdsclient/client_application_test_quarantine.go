@@ -0,0 +1,26 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ApplicationTestQuarantineList(projectKey string, appName string) ([]sdk.TestQuarantine, error) {
+	qs := []sdk.TestQuarantine{}
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/application/"+appName+"/tests/quarantine", &qs); err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+func (c *client) ApplicationTestQuarantineAdd(projectKey string, appName string, q *sdk.TestQuarantine) error {
+	_, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/application/"+appName+"/tests/quarantine", q, q)
+	return err
+}
+
+func (c *client) ApplicationTestQuarantineDelete(projectKey string, appName string, id int64) error {
+	_, _, _, err := c.Request(context.Background(), "DELETE", fmt.Sprintf("/project/%s/application/%s/tests/quarantine/%d", projectKey, appName, id), nil)
+	return err
+}
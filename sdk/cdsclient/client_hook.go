@@ -1,18 +1,20 @@
 package cdsclient
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/ovh/cds/sdk"
 )
 
-func (c *client) PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (events sdk.RepositoryEvents, interval time.Duration, err error) {
+func (c *client) PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (events sdk.RepositoryEvents, interval time.Duration, rateLimit *sdk.VCSRateLimit, err error) {
 	url := fmt.Sprintf("/hook/%s/workflow/%d/vcsevent/%s", uuid, workflowID, vcsServer)
 	header, _, errGet := c.GetJSONWithHeaders(url, &events, SetHeader("X-CDS-Last-Execution", fmt.Sprint(timestamp)))
 	if errGet != nil {
-		return events, interval, errGet
+		return events, interval, nil, errGet
 	}
 
 	//Check poll interval
@@ -23,5 +25,23 @@ func (c *client) PollVCSEvents(uuid string, workflowID int64, vcsServer string,
 		}
 	}
 
-	return events, interval, nil
+	return events, interval, sdk.VCSRateLimitFromHeader(header), nil
+}
+
+func (c *client) HookChangedFiles(uuid string, base, head string) ([]string, error) {
+	var files []string
+	path := fmt.Sprintf("/hook/%s/changes?base=%s&head=%s", uuid, url.QueryEscape(base), url.QueryEscape(head))
+	if _, err := c.GetJSON(context.Background(), path, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (c *client) HookRepositoryBranch(uuid string, branch string) (*sdk.VCSBranch, error) {
+	var b sdk.VCSBranch
+	path := fmt.Sprintf("/hook/%s/branch?branch=%s", uuid, url.QueryEscape(branch))
+	if _, err := c.GetJSON(context.Background(), path, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
 }
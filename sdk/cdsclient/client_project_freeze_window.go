@@ -0,0 +1,31 @@
+package cdsclient
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) ProjectFreezeWindowList(projectKey string) ([]sdk.FreezeWindow, error) {
+	fs := []sdk.FreezeWindow{}
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/freeze", &fs); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (c *client) ProjectFreezeWindowCreate(projectKey string, f *sdk.FreezeWindow) error {
+	_, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/freeze", f, f)
+	return err
+}
+
+func (c *client) ProjectFreezeWindowUpdate(projectKey string, name string, f *sdk.FreezeWindow) error {
+	_, err := c.PutJSON(context.Background(), "/project/"+projectKey+"/freeze/"+url.PathEscape(name), f, f)
+	return err
+}
+
+func (c *client) ProjectFreezeWindowDelete(projectKey string, name string) error {
+	_, _, _, err := c.Request(context.Background(), "DELETE", "/project/"+projectKey+"/freeze/"+url.PathEscape(name), nil)
+	return err
+}
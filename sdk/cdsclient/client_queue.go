@@ -86,10 +86,14 @@ func (c *client) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJ
 			if apiEvent.EventType == "sdk.EventRunWorkflowJob" {
 				jobRunID, ok := apiEvent.Payload["ID"].(float64)
 				status, okStatus := apiEvent.Payload["Status"].(string)
-				if ok && okStatus && status == sdk.StatusWaiting.String() {
-					// wait for the grace time before pushing the job in the channel
+				// a job assigned by the API scheduler is only pushed to the chosen hatchery, without waiting for the grace time
+				bookedBy, _ := apiEvent.Payload["BookedBy"].(float64)
+				if ok && okStatus && status == sdk.StatusWaiting.String() && (bookedBy == 0 || c.isCurrentService(int64(bookedBy))) {
 					go func() {
-						time.Sleep(time.Duration(graceTime) * time.Second)
+						// wait for the grace time before pushing the job in the channel
+						if bookedBy == 0 {
+							time.Sleep(time.Duration(graceTime) * time.Second)
+						}
 						job, err := c.QueueJobInfo(int64(jobRunID))
 
 						// Do not log the error if the job does not exist
@@ -103,7 +107,7 @@ func (c *client) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJ
 						}
 
 						// push the job in the channel
-						if job.Status == sdk.StatusWaiting.String() && job.BookedBy.Name == "" {
+						if job.Status == sdk.StatusWaiting.String() && (job.BookedBy.Name == "" || c.isBookedByCurrentService(*job)) {
 							job.Header["SSE"] = "true"
 							jobs <- *job
 						}
@@ -152,6 +156,17 @@ func (c *client) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJ
 	}
 }
 
+// isBookedByCurrentService returns true if the job was assigned to the current service by the API scheduler
+func (c *client) isBookedByCurrentService(job sdk.WorkflowNodeJobRun) bool {
+	return c.isCurrentService(job.BookedBy.ID)
+}
+
+// isCurrentService returns true if the given id is the one of the current service
+func (c *client) isCurrentService(id int64) bool {
+	srv := c.GetService()
+	return srv != nil && srv.ID != 0 && id == srv.ID
+}
+
 func (c *client) QueueWorkflowNodeJobRun(status ...sdk.Status) ([]sdk.WorkflowNodeJobRun, error) {
 	wJobs := []sdk.WorkflowNodeJobRun{}
 
@@ -227,6 +242,23 @@ func (c *client) QueueJobInfo(id int64) (*sdk.WorkflowNodeJobRun, error) {
 	return &job, nil
 }
 
+// QueueJobTestQuarantines returns the quarantined tests of the application of a job
+func (c *client) QueueJobTestQuarantines(ctx context.Context, id int64) ([]sdk.TestQuarantine, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/test/quarantine", id)
+	qs := []sdk.TestQuarantine{}
+	if _, err := c.GetJSON(ctx, path, &qs); err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+// QueueJobSendSBOM sends a software bill of materials of a job
+func (c *client) QueueJobSendSBOM(ctx context.Context, id int64, s sdk.WorkflowNodeRunSBOM) error {
+	path := fmt.Sprintf("/queue/workflows/%d/sbom", id)
+	_, err := c.PostJSON(ctx, path, &s, nil)
+	return err
+}
+
 // QueueJobSendSpawnInfo sends a spawn info on a job
 func (c *client) QueueJobSendSpawnInfo(ctx context.Context, id int64, in []sdk.SpawnInfo) error {
 	path := fmt.Sprintf("/queue/workflows/%d/spawn/infos", id)
@@ -248,6 +280,21 @@ func (c *client) QueueJobRelease(id int64) error {
 	return err
 }
 
+// QueueHatcheryCapacity declares the capacity of a Hatchery to the API scheduler
+func (c *client) QueueHatcheryCapacity(ctx context.Context, capa sdk.HatcheryCapacity) error {
+	_, err := c.PostJSON(ctx, "/queue/workflows/capacity", &capa, nil)
+	return err
+}
+
+// QueueHatcheryCapacities returns the capacities declared by all Hatcheries
+func (c *client) QueueHatcheryCapacities() ([]sdk.HatcheryCapacity, error) {
+	var capacities []sdk.HatcheryCapacity
+	if _, err := c.GetJSON(context.Background(), "/queue/workflows/capacity", &capacities); err != nil {
+		return nil, err
+	}
+	return capacities, nil
+}
+
 func (c *client) QueueSendResult(ctx context.Context, id int64, res sdk.Result) error {
 	path := fmt.Sprintf("/queue/workflows/%d/result", id)
 	_, err := c.PostJSON(ctx, path, res, nil)
@@ -0,0 +1,28 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) SBOMComponentUsages(filter sdk.SBOMComponentFilter) ([]sdk.SBOMComponentUsage, error) {
+	q := url.Values{}
+	if filter.Name != "" {
+		q.Set("name", filter.Name)
+	}
+	if filter.Version != "" {
+		q.Set("version", filter.Version)
+	}
+	if filter.PURL != "" {
+		q.Set("purl", filter.PURL)
+	}
+	path := fmt.Sprintf("/sbom/component?%s", q.Encode())
+	res := []sdk.SBOMComponentUsage{}
+	if _, err := c.GetJSON(context.Background(), path, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
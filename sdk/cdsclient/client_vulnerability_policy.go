@@ -0,0 +1,33 @@
+package cdsclient
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func vulnerabilityPolicyPath(projectKey string, appName string) string {
+	if appName == "" {
+		return "/project/" + projectKey + "/vulnerability/policy"
+	}
+	return "/project/" + projectKey + "/application/" + appName + "/vulnerability/policy"
+}
+
+func (c *client) VulnerabilityPolicyGet(projectKey string, appName string) (*sdk.VulnerabilityPolicy, error) {
+	var p sdk.VulnerabilityPolicy
+	if _, err := c.GetJSON(context.Background(), vulnerabilityPolicyPath(projectKey, appName), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (c *client) VulnerabilityPolicySet(projectKey string, appName string, p *sdk.VulnerabilityPolicy) error {
+	_, err := c.PutJSON(context.Background(), vulnerabilityPolicyPath(projectKey, appName), p, p)
+	return err
+}
+
+func (c *client) VulnerabilityPolicyDelete(projectKey string, appName string) error {
+	_, _, _, err := c.Request(context.Background(), http.MethodDelete, vulnerabilityPolicyPath(projectKey, appName), nil)
+	return err
+}
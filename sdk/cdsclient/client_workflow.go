@@ -3,6 +3,8 @@ package cdsclient
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +12,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/ovh/cds/sdk"
@@ -136,6 +139,82 @@ func (c *client) WorkflowRunArtifacts(projectKey string, workflowName string, nu
 	return arts, nil
 }
 
+func (c *client) WorkflowTests(projectKey string, workflowName string, filter sdk.WorkflowTestCaseFilter) ([]sdk.WorkflowTestCaseSummary, error) {
+	q := url.Values{}
+	if filter.Branch != "" {
+		q.Set("branch", filter.Branch)
+	}
+	if filter.Runs > 0 {
+		q.Set("runs", strconv.FormatInt(filter.Runs, 10))
+	}
+	if filter.OnlyFailed {
+		q.Set("failed", "true")
+	}
+	if filter.OnlyFlaky {
+		q.Set("flaky", "true")
+	}
+	path := fmt.Sprintf("/project/%s/workflows/%s/tests?%s", projectKey, workflowName, q.Encode())
+	res := []sdk.WorkflowTestCaseSummary{}
+	if _, err := c.GetJSON(context.Background(), path, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *client) WorkflowTestHistory(projectKey string, workflowName string, testSuite, name, branch string, limit int64) ([]sdk.WorkflowTestCase, error) {
+	q := url.Values{}
+	q.Set("suite", testSuite)
+	q.Set("name", name)
+	if branch != "" {
+		q.Set("branch", branch)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.FormatInt(limit, 10))
+	}
+	path := fmt.Sprintf("/project/%s/workflows/%s/tests/history?%s", projectKey, workflowName, q.Encode())
+	res := []sdk.WorkflowTestCase{}
+	if _, err := c.GetJSON(context.Background(), path, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *client) WorkflowLogSearch(projectKey string, workflowName string, req sdk.LogSearchRequest) (*sdk.LogSearchResult, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/logs/search", projectKey, workflowName)
+	var res sdk.LogSearchResult
+	if _, err := c.PostJSON(context.Background(), path, req, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *client) WorkflowHookExecutions(projectKey string, workflowName string, uuid string) ([]sdk.TaskExecution, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/hooks/%s/executions", projectKey, workflowName, uuid)
+	res := []sdk.TaskExecution{}
+	if _, err := c.GetJSON(context.Background(), path, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *client) WorkflowHookExecution(projectKey string, workflowName string, uuid string, timestamp int64) (*sdk.TaskExecution, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/hooks/%s/executions/%d", projectKey, workflowName, uuid, timestamp)
+	var res sdk.TaskExecution
+	if _, err := c.GetJSON(context.Background(), path, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *client) WorkflowHookExecutionReplay(projectKey string, workflowName string, uuid string, timestamp int64, payload map[string]string) (*sdk.TaskExecution, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/hooks/%s/executions/%d/replay", projectKey, workflowName, uuid, timestamp)
+	var res sdk.TaskExecution
+	if _, err := c.PostJSON(context.Background(), path, sdk.TaskExecutionReplay{Payload: payload}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
 func (c *client) WorkflowNodeRun(projectKey string, workflowName string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d", projectKey, workflowName, number, nodeRunID)
 	run := sdk.WorkflowNodeRun{}
@@ -176,6 +255,18 @@ func (c *client) WorkflowNodeRunJobStep(projectKey string, workflowName string,
 	return &buildState, nil
 }
 
+func (c *client) WorkflowNodeRunJobStepLog(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int, w io.Writer) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/job/%d/step/%d/log", projectKey, workflowName, number, nodeRunID, job, step)
+	reader, _, _, err := c.Stream(context.Background(), "GET", url, nil, true)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(w, reader)
+	return err
+}
+
 func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, workflowName string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error {
 	var url = fmt.Sprintf("/project/%s/workflows/%s/artifact/%d", projectKey, workflowName, a.ID)
 	var reader io.ReadCloser
@@ -195,6 +286,27 @@ func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, workflowName
 	return err
 }
 
+func (c *client) WorkflowNodeRunSBOMs(projectKey string, workflowName string, number int64, nodeRunID int64) ([]sdk.WorkflowNodeRunSBOM, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/sbom", projectKey, workflowName, number, nodeRunID)
+	res := []sdk.WorkflowNodeRunSBOM{}
+	if _, err := c.GetJSON(context.Background(), url, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *client) WorkflowNodeRunSBOMDownload(projectKey string, workflowName string, number int64, nodeRunID int64, sbomID int64, w io.Writer) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/sbom/%d/download", projectKey, workflowName, number, nodeRunID, sbomID)
+	reader, _, _, err := c.Stream(context.Background(), "GET", url, nil, true)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(w, reader)
+	return err
+}
+
 func (c *client) WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/release", projectKey, workflowName, runNumber, nodeRunID)
 	btes, _ := json.Marshal(release)
@@ -282,25 +394,38 @@ func (c *client) WorkflowNodeStop(projectKey string, workflowName string, number
 	return nodeRun, nil
 }
 
-func (c *client) WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
+func (c *client) WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.ReadSeeker, size int) error {
+	// The archive is stored by the API with the digest of its content, it is read twice instead of being kept in memory
+	if _, err := tarContent.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, tarContent); err != nil {
+		return err
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+	if _, err := tarContent.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
 	store := new(sdk.ArtifactsStore)
 	uri := fmt.Sprintf("/project/%s/storage/%s", projectKey, integrationName)
 	_, _ = c.GetJSON(context.Background(), uri, store)
 	if store.TemporaryURLSupported {
-		err := c.workflowCachePushIndirectUpload(projectKey, integrationName, ref, tarContent, size)
+		err := c.workflowCachePushIndirectUpload(projectKey, integrationName, ref, digest, tarContent, size)
 		return err
 	}
-	return c.workflowCachePushDirectUpload(projectKey, integrationName, ref, tarContent)
+	return c.workflowCachePushDirectUpload(projectKey, integrationName, ref, digest, tarContent)
 }
 
-func (c *client) workflowCachePushDirectUpload(projectKey, integrationName, ref string, tarContent io.Reader) error {
+func (c *client) workflowCachePushDirectUpload(projectKey, integrationName, ref, digest string, tarContent io.Reader) error {
 	mods := []RequestModifier{
 		(func(r *http.Request) {
 			r.Header.Set("Content-Type", "application/tar")
 		}),
 	}
 
-	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s", projectKey, integrationName, ref)
+	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s?digest=%s", projectKey, integrationName, ref, digest)
 	_, _, code, err := c.Stream(context.Background(), "POST", uri, tarContent, true, mods...)
 	if err != nil {
 		return err
@@ -313,8 +438,8 @@ func (c *client) workflowCachePushDirectUpload(projectKey, integrationName, ref
 	return nil
 }
 
-func (c *client) workflowCachePushIndirectUpload(projectKey, integrationName, ref string, tarContent io.Reader, size int) error {
-	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s/url", projectKey, integrationName, ref)
+func (c *client) workflowCachePushIndirectUpload(projectKey, integrationName, ref, digest string, tarContent io.ReadSeeker, size int) error {
+	uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s/url?digest=%s&size=%d", projectKey, integrationName, ref, digest, size)
 	cacheObj := sdk.Cache{}
 	code, err := c.PostJSON(context.Background(), uri, cacheObj, &cacheObj)
 	if err != nil {
@@ -325,16 +450,37 @@ func (c *client) workflowCachePushIndirectUpload(projectKey, integrationName, re
 		return fmt.Errorf("HTTP Code %d", code)
 	}
 
-	return c.workflowCachePushIndirectUploadPost(cacheObj.TmpURL, tarContent, size)
+	// An identical archive is already stored, the API only referenced it with the tag
+	if cacheObj.TmpURL == "" {
+		return nil
+	}
+
+	if err := c.workflowCachePushIndirectUploadPost(cacheObj.TmpURL, tarContent, size); err != nil {
+		return err
+	}
+
+	// The API checks the uploaded archive before referencing it with the tag
+	uri = fmt.Sprintf("/project/%s/storage/%s/cache/%s/url/callback?digest=%s&size=%d", projectKey, integrationName, ref, digest, size)
+	code, err = c.PostJSON(context.Background(), uri, nil, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 400 {
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+	return nil
 }
 
-func (c *client) workflowCachePushIndirectUploadPost(url string, tarContent io.Reader, size int) error {
+func (c *client) workflowCachePushIndirectUploadPost(url string, tarContent io.ReadSeeker, size int) error {
 	//Post the file to the temporary URL
 	var retry = 10
 	var globalErr error
 	var body []byte
 	for i := 0; i < retry; i++ {
-		req, errRequest := http.NewRequest("PUT", url, tarContent)
+		if _, err := tarContent.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		req, errRequest := http.NewRequest("PUT", url, ioutil.NopCloser(tarContent))
 		if errRequest != nil {
 			return errRequest
 		}
@@ -367,24 +513,31 @@ func (c *client) workflowCachePushIndirectUploadPost(url string, tarContent io.R
 	return globalErr
 }
 
-func (c *client) WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error) {
+func (c *client) WorkflowCachePull(projectKey, integrationName, ref string, restoreKeys ...string) (io.Reader, string, error) {
 	uri := fmt.Sprintf("/project/%s/storage/%s", projectKey, integrationName)
 	store := new(sdk.ArtifactsStore)
 	_, _ = c.GetJSON(context.Background(), uri, store)
 
-	downloadURL := fmt.Sprintf("/project/%s/storage/%s/cache/%s", projectKey, integrationName, ref)
+	query := url.Values{}
+	for _, k := range restoreKeys {
+		query.Add("restore", k)
+	}
+
+	downloadURL := fmt.Sprintf("/project/%s/storage/%s/cache/%s?%s", projectKey, integrationName, ref, query.Encode())
+	var key string
 
 	if store.TemporaryURLSupported {
-		url := fmt.Sprintf("/project/%s/storage/%s/cache/%s/url", projectKey, integrationName, ref)
+		url := fmt.Sprintf("/project/%s/storage/%s/cache/%s/url?%s", projectKey, integrationName, ref, query.Encode())
 		var cacheObj sdk.Cache
 		code, err := c.GetJSON(context.Background(), url, &cacheObj)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		if code >= 400 {
-			return nil, fmt.Errorf("HTTP Code %d", code)
+			return nil, "", fmt.Errorf("HTTP Code %d", code)
 		}
 		downloadURL = cacheObj.TmpURL
+		key = cacheObj.Tag
 	}
 
 	mods := []RequestModifier{
@@ -393,24 +546,28 @@ func (c *client) WorkflowCachePull(projectKey, integrationName, ref string) (io.
 		}),
 	}
 
-	res, _, code, err := c.Stream(context.Background(), "GET", downloadURL, nil, true, mods...)
+	res, header, code, err := c.Stream(context.Background(), "GET", downloadURL, nil, true, mods...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	if code >= 400 {
 		if code == 404 {
-			return nil, fmt.Errorf("Cache not found")
+			return nil, "", fmt.Errorf("Cache not found")
 		}
-		return nil, fmt.Errorf("HTTP Code %d", code)
+		return nil, "", fmt.Errorf("HTTP Code %d", code)
+	}
+
+	if k := header.Get(sdk.CacheKeyHeader); k != "" {
+		key = k
 	}
 
 	body, err := ioutil.ReadAll(res)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return bytes.NewBuffer(body), nil
+	return bytes.NewBuffer(body), key, nil
 }
 
 func (c *client) WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error) {
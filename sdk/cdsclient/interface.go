@@ -84,6 +84,14 @@ type ApplicationClient interface {
 	ApplicationList(projectKey string) ([]sdk.Application, error)
 	ApplicationVariableClient
 	ApplicationKeysClient
+	ApplicationTestQuarantineClient
+}
+
+// ApplicationTestQuarantineClient exposes application tests quarantine related functions
+type ApplicationTestQuarantineClient interface {
+	ApplicationTestQuarantineList(projectKey string, appName string) ([]sdk.TestQuarantine, error)
+	ApplicationTestQuarantineAdd(projectKey string, appName string, q *sdk.TestQuarantine) error
+	ApplicationTestQuarantineDelete(projectKey string, appName string, id int64) error
 }
 
 // ApplicationKeysClient exposes application keys related functions
@@ -201,6 +209,7 @@ type ProjectClient interface {
 	ProjectList(withApplications, withWorkflow bool, filters ...Filter) ([]sdk.Project, error)
 	ProjectKeysClient
 	ProjectVariablesClient
+	ProjectFreezeWindowsClient
 	ProjectGroupsImport(projectKey string, content io.Reader, format string, force bool) (sdk.Project, error)
 	ProjectIntegrationImport(projectKey string, content io.Reader, format string, force bool) (sdk.ProjectIntegration, error)
 	ProjectIntegrationGet(projectKey string, integrationName string, clearPassword bool) (sdk.ProjectIntegration, error)
@@ -217,6 +226,14 @@ type ProjectKeysClient interface {
 	ProjectKeysDelete(projectKey string, keyProjectName string) error
 }
 
+// ProjectFreezeWindowsClient exposes project freeze windows related functions
+type ProjectFreezeWindowsClient interface {
+	ProjectFreezeWindowList(projectKey string) ([]sdk.FreezeWindow, error)
+	ProjectFreezeWindowCreate(projectKey string, f *sdk.FreezeWindow) error
+	ProjectFreezeWindowUpdate(projectKey string, name string, f *sdk.FreezeWindow) error
+	ProjectFreezeWindowDelete(projectKey string, name string) error
+}
+
 // ProjectVariablesClient exposes project variables related functions
 type ProjectVariablesClient interface {
 	ProjectVariablesList(key string) ([]sdk.Variable, error)
@@ -235,7 +252,11 @@ type QueueClient interface {
 	QueueTakeJob(ctx context.Context, job sdk.WorkflowNodeJobRun, isBooked bool) (*sdk.WorkflowNodeJobRunData, error)
 	QueueJobBook(ctx context.Context, id int64) error
 	QueueJobRelease(id int64) error
+	QueueHatcheryCapacity(ctx context.Context, capa sdk.HatcheryCapacity) error
+	QueueHatcheryCapacities() ([]sdk.HatcheryCapacity, error)
 	QueueJobInfo(id int64) (*sdk.WorkflowNodeJobRun, error)
+	QueueJobTestQuarantines(ctx context.Context, id int64) ([]sdk.TestQuarantine, error)
+	QueueJobSendSBOM(ctx context.Context, id int64, s sdk.WorkflowNodeRunSBOM) error
 	QueueJobSendSpawnInfo(ctx context.Context, id int64, in []sdk.SpawnInfo) error
 	QueueSendResult(ctx context.Context, id int64, res sdk.Result) error
 	QueueArtifactUpload(ctx context.Context, projectKey, integrationName string, nodeJobRunID int64, tag, filePath string) (bool, time.Duration, error)
@@ -277,10 +298,25 @@ type WorkerClient interface {
 
 // HookClient exposes functions used for hooks services
 type HookClient interface {
-	PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (events sdk.RepositoryEvents, interval time.Duration, err error)
+	PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (events sdk.RepositoryEvents, interval time.Duration, rateLimit *sdk.VCSRateLimit, err error)
+	HookChangedFiles(uuid string, base, head string) ([]string, error)
+	HookRepositoryBranch(uuid string, branch string) (*sdk.VCSBranch, error)
 	VCSConfiguration() (map[string]sdk.VCSConfiguration, error)
 }
 
+// SBOMClient exposes software bill of materials functions
+type SBOMClient interface {
+	SBOMComponentUsages(filter sdk.SBOMComponentFilter) ([]sdk.SBOMComponentUsage, error)
+}
+
+// VulnerabilityPolicyClient exposes vulnerability policies functions, an empty application name targets
+// the policy of the whole project
+type VulnerabilityPolicyClient interface {
+	VulnerabilityPolicyGet(projectKey string, appName string) (*sdk.VulnerabilityPolicy, error)
+	VulnerabilityPolicySet(projectKey string, appName string, p *sdk.VulnerabilityPolicy) error
+	VulnerabilityPolicyDelete(projectKey string, appName string) error
+}
+
 // WorkflowClient exposes workflows functions
 type WorkflowClient interface {
 	WorkflowList(projectKey string) ([]sdk.Workflow, error)
@@ -295,6 +331,9 @@ type WorkflowClient interface {
 	WorkflowRunSearch(projectKey string, offset, limit int64, filter ...Filter) ([]sdk.WorkflowRun, error)
 	WorkflowRunList(projectKey string, workflowName string, offset, limit int64) ([]sdk.WorkflowRun, error)
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error)
+	WorkflowTests(projectKey string, workflowName string, filter sdk.WorkflowTestCaseFilter) ([]sdk.WorkflowTestCaseSummary, error)
+	WorkflowTestHistory(projectKey string, workflowName string, testSuite, name, branch string, limit int64) ([]sdk.WorkflowTestCase, error)
+	WorkflowLogSearch(projectKey string, workflowName string, req sdk.LogSearchRequest) (*sdk.LogSearchResult, error)
 	WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
 	WorkflowRunFromManual(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRun, error)
 	WorkflowRunNumberGet(projectKey string, workflowName string) (*sdk.WorkflowRunNumber, error)
@@ -304,10 +343,16 @@ type WorkflowClient interface {
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
 	WorkflowNodeRunJobStep(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int) (*sdk.BuildState, error)
+	WorkflowNodeRunJobStepLog(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int, w io.Writer) error
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
+	WorkflowNodeRunSBOMs(projectKey string, workflowName string, number int64, nodeRunID int64) ([]sdk.WorkflowNodeRunSBOM, error)
+	WorkflowNodeRunSBOMDownload(projectKey string, workflowName string, number int64, nodeRunID int64, sbomID int64, w io.Writer) error
 	WorkflowAllHooksList() ([]sdk.NodeHook, error)
-	WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error
-	WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error)
+	WorkflowHookExecutions(projectKey string, workflowName string, uuid string) ([]sdk.TaskExecution, error)
+	WorkflowHookExecution(projectKey string, workflowName string, uuid string, timestamp int64) (*sdk.TaskExecution, error)
+	WorkflowHookExecutionReplay(projectKey string, workflowName string, uuid string, timestamp int64, payload map[string]string) (*sdk.TaskExecution, error)
+	WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.ReadSeeker, size int) error
+	WorkflowCachePull(projectKey, integrationName, ref string, restoreKeys ...string) (io.Reader, string, error)
 	WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error)
 	WorkflowTransformAsCode(projectKey, workflowName string) (*sdk.Operation, error)
 	WorkflowTransformAsCodeFollow(projectKey, workflowName string, ope *sdk.Operation) error
@@ -353,9 +398,11 @@ type Interface interface {
 	Navbar() ([]sdk.NavbarProjectData, error)
 	Requirements() ([]sdk.Requirement, error)
 	RepositoriesManagerInterface
+	SBOMClient
 	GetService() *sdk.Service
 	ServiceRegister(sdk.Service) (string, error)
 	UserClient
+	VulnerabilityPolicyClient
 	WorkerClient
 	WorkflowClient
 	MonitoringClient
@@ -0,0 +1,203 @@
+package sdk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sguiheux/go-coverage"
+)
+
+// Coverage report formats parsed by CDS, other formats are parsed with github.com/sguiheux/go-coverage.
+const (
+	CoverageFormatGoCover = "gocover"
+	CoverageFormatJaCoCo  = "jacoco"
+)
+
+// CoveragePackageReport is the coverage of a package, computed from the coverage of its files.
+type CoveragePackageReport struct {
+	Name             string `json:"name"`
+	TotalLines       int    `json:"total_lines"`
+	CoveredLines     int    `json:"covered_lines"`
+	TotalFunctions   int    `json:"total_functions"`
+	CoveredFunctions int    `json:"covered_functions"`
+	TotalBranches    int    `json:"total_branches"`
+	CoveredBranches  int    `json:"covered_branches"`
+}
+
+// CoverageLinesPercent returns the percentage of covered lines of a report, -1 if the report has no lines.
+func CoverageLinesPercent(r coverage.Report) float64 {
+	if r.TotalLines == 0 {
+		return -1
+	}
+	return float64(r.CoveredLines) / float64(r.TotalLines) * 100
+}
+
+// CoveragePackages returns the coverage by package of a report, the package of a file is its directory.
+func CoveragePackages(r coverage.Report) []CoveragePackageReport {
+	pkgs := make(map[string]*CoveragePackageReport)
+	for _, f := range r.Files {
+		name := path.Dir(f.Path)
+		p, ok := pkgs[name]
+		if !ok {
+			p = &CoveragePackageReport{Name: name}
+			pkgs[name] = p
+		}
+		p.TotalLines += f.TotalLines
+		p.CoveredLines += f.CoveredLines
+		p.TotalFunctions += f.TotalFunctions
+		p.CoveredFunctions += f.CoveredFunctions
+		p.TotalBranches += f.TotalBranches
+		p.CoveredBranches += f.CoveredBranches
+	}
+
+	res := make([]CoveragePackageReport, 0, len(pkgs))
+	for _, p := range pkgs {
+		res = append(res, *p)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// ParseCoverageReport parses a Go cover profile or a JaCoCo XML report.
+func ParseCoverageReport(format string, data []byte) (coverage.Report, error) {
+	switch format {
+	case CoverageFormatGoCover:
+		return parseGoCoverProfile(data)
+	case CoverageFormatJaCoCo:
+		return parseJaCoCoReport(data)
+	}
+	return coverage.Report{}, NewErrorFrom(ErrWrongRequest, "unknown coverage format %s", format)
+}
+
+// parseGoCoverProfile parses a profile written by 'go test -coverprofile', lines are the statements of the profile.
+// A block can be present several times when profiles are merged, it is covered if one of its counts is positive.
+func parseGoCoverProfile(data []byte) (coverage.Report, error) {
+	type block struct {
+		statements int
+		covered    bool
+	}
+	files := make(map[string]map[string]*block)
+	var filenames []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var n int
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		// name.go:line.column,line.column numberOfStatements count
+		i := strings.LastIndex(line, ":")
+		fields := strings.Fields(line[i+1:])
+		if i < 0 || len(fields) != 3 {
+			return coverage.Report{}, NewErrorFrom(ErrWrongRequest, "invalid cover profile line %d: %s", n, line)
+		}
+		statements, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return coverage.Report{}, NewErrorFrom(ErrWrongRequest, "invalid number of statements at line %d: %s", n, fields[1])
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return coverage.Report{}, NewErrorFrom(ErrWrongRequest, "invalid count at line %d: %s", n, fields[2])
+		}
+
+		filename := line[:i]
+		blocks, ok := files[filename]
+		if !ok {
+			blocks = make(map[string]*block)
+			files[filename] = blocks
+			filenames = append(filenames, filename)
+		}
+		b, ok := blocks[fields[0]]
+		if !ok {
+			b = &block{statements: statements}
+			blocks[fields[0]] = b
+		}
+		b.covered = b.covered || count > 0
+	}
+	if err := scanner.Err(); err != nil {
+		return coverage.Report{}, NewErrorFrom(ErrWrongRequest, "unable to read cover profile: %v", err)
+	}
+
+	report := coverage.Report{Files: make([]coverage.FileReport, 0, len(filenames))}
+	for _, filename := range filenames {
+		f := coverage.FileReport{Path: filename}
+		for _, b := range files[filename] {
+			f.TotalLines += b.statements
+			if b.covered {
+				f.CoveredLines += b.statements
+			}
+		}
+		report.TotalLines += f.TotalLines
+		report.CoveredLines += f.CoveredLines
+		report.Files = append(report.Files, f)
+	}
+	return report, nil
+}
+
+// JaCoCoReport is a XML report generated by JaCoCo.
+type JaCoCoReport struct {
+	XMLName  xml.Name `xml:"report"`
+	Name     string   `xml:"name,attr"`
+	Packages []struct {
+		Name        string `xml:"name,attr"`
+		SourceFiles []struct {
+			Name     string          `xml:"name,attr"`
+			Counters []JaCoCoCounter `xml:"counter"`
+		} `xml:"sourcefile"`
+	} `xml:"package"`
+	Counters []JaCoCoCounter `xml:"counter"`
+}
+
+// JaCoCoCounter is a coverage counter of a JaCoCo report, types are INSTRUCTION, BRANCH, LINE, COMPLEXITY, METHOD and CLASS.
+type JaCoCoCounter struct {
+	Type    string `xml:"type,attr"`
+	Missed  int    `xml:"missed,attr"`
+	Covered int    `xml:"covered,attr"`
+}
+
+// parseJaCoCoReport parses a JaCoCo XML report, the path of a file is its package name followed by its name.
+func parseJaCoCoReport(data []byte) (coverage.Report, error) {
+	var r JaCoCoReport
+	d := xml.NewDecoder(bytes.NewReader(data))
+	// JaCoCo reports declare a DTD that is not available
+	d.Strict = false
+	if err := d.Decode(&r); err != nil {
+		return coverage.Report{}, NewErrorFrom(ErrWrongRequest, "invalid jacoco report: %v", err)
+	}
+
+	var report coverage.Report
+	jacocoCounters(r.Counters, &report.TotalLines, &report.CoveredLines, &report.TotalFunctions, &report.CoveredFunctions, &report.TotalBranches, &report.CoveredBranches)
+	for _, p := range r.Packages {
+		for _, s := range p.SourceFiles {
+			f := coverage.FileReport{Path: s.Name}
+			if p.Name != "" {
+				f.Path = fmt.Sprintf("%s/%s", p.Name, s.Name)
+			}
+			jacocoCounters(s.Counters, &f.TotalLines, &f.CoveredLines, &f.TotalFunctions, &f.CoveredFunctions, &f.TotalBranches, &f.CoveredBranches)
+			report.Files = append(report.Files, f)
+		}
+	}
+	return report, nil
+}
+
+func jacocoCounters(cs []JaCoCoCounter, totalLines, coveredLines, totalFunctions, coveredFunctions, totalBranches, coveredBranches *int) {
+	for _, c := range cs {
+		switch c.Type {
+		case "LINE":
+			*totalLines, *coveredLines = c.Missed+c.Covered, c.Covered
+		case "METHOD":
+			*totalFunctions, *coveredFunctions = c.Missed+c.Covered, c.Covered
+		case "BRANCH":
+			*totalBranches, *coveredBranches = c.Missed+c.Covered, c.Covered
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCoverageReportGoCover(t *testing.T) {
+	profile := `mode: set
+github.com/ovh/cds/sdk/foo.go:10.2,12.3 2 1
+github.com/ovh/cds/sdk/foo.go:14.2,16.3 3 0
+github.com/ovh/cds/sdk/bar/bar.go:5.2,7.3 4 0
+github.com/ovh/cds/sdk/bar/bar.go:5.2,7.3 4 1
+`
+	r, err := ParseCoverageReport(CoverageFormatGoCover, []byte(profile))
+	assert.NoError(t, err)
+	assert.Equal(t, 9, r.TotalLines)
+	assert.Equal(t, 6, r.CoveredLines)
+	if !assert.Len(t, r.Files, 2) {
+		return
+	}
+	assert.Equal(t, "github.com/ovh/cds/sdk/foo.go", r.Files[0].Path)
+	assert.Equal(t, 5, r.Files[0].TotalLines)
+	assert.Equal(t, 2, r.Files[0].CoveredLines)
+
+	pkgs := CoveragePackages(r)
+	if !assert.Len(t, pkgs, 2) {
+		return
+	}
+	assert.Equal(t, "github.com/ovh/cds/sdk", pkgs[0].Name)
+	assert.Equal(t, "github.com/ovh/cds/sdk/bar", pkgs[1].Name)
+	assert.Equal(t, 4, pkgs[1].CoveredLines)
+
+	_, err = ParseCoverageReport(CoverageFormatGoCover, []byte("foo.go:10.2,12.3 two 1"))
+	assert.Error(t, err)
+}
+
+func TestParseCoverageReportJaCoCo(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="app">
+  <package name="com/ovh/app">
+    <sourcefile name="Main.java">
+      <line nr="3" mi="0" ci="3" mb="0" cb="0"/>
+      <counter type="LINE" missed="2" covered="8"/>
+      <counter type="METHOD" missed="1" covered="3"/>
+      <counter type="BRANCH" missed="1" covered="1"/>
+    </sourcefile>
+    <counter type="LINE" missed="2" covered="8"/>
+  </package>
+  <counter type="INSTRUCTION" missed="10" covered="40"/>
+  <counter type="LINE" missed="2" covered="8"/>
+  <counter type="METHOD" missed="1" covered="3"/>
+  <counter type="BRANCH" missed="1" covered="1"/>
+</report>`
+	r, err := ParseCoverageReport(CoverageFormatJaCoCo, []byte(report))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, r.TotalLines)
+	assert.Equal(t, 8, r.CoveredLines)
+	assert.Equal(t, 4, r.TotalFunctions)
+	assert.Equal(t, 3, r.CoveredFunctions)
+	assert.Equal(t, 2, r.TotalBranches)
+	assert.Equal(t, 1, r.CoveredBranches)
+	if !assert.Len(t, r.Files, 1) {
+		return
+	}
+	assert.Equal(t, "com/ovh/app/Main.java", r.Files[0].Path)
+	assert.Equal(t, 80.0, CoverageLinesPercent(r))
+}
@@ -81,6 +81,7 @@ type EventRunWorkflowNode struct {
 	HookLog               string                    `json:"log,omitempty"`
 	NodeType              string                    `json:"node_type,omitempty"`
 	GerritChange          *GerritChangeEvent        `json:"gerrit_change,omitempty"`
+	TestsQuarantined      int                       `json:"tests_quarantined,omitempty"`
 }
 
 // GerritChangeEvent Gerrit information that are needed on event
@@ -107,10 +108,11 @@ type EventRunWorkflowOutgoingHook struct {
 
 // EventRunWorkflowJob contains event data for a workflow job node run
 type EventRunWorkflowJob struct {
-	ID     int64  `json:"id,omitempty"`
-	Status string `json:"status,omitempty"`
-	Start  int64  `json:"start,omitempty"`
-	Done   int64  `json:"done,omitempty"`
+	ID       int64  `json:"id,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Start    int64  `json:"start,omitempty"`
+	Done     int64  `json:"done,omitempty"`
+	BookedBy int64  `json:"booked_by,omitempty"` // id of the hatchery the job is assigned to
 }
 
 // EventRunWorkflow contains event data for a workflow run
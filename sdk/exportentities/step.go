@@ -46,6 +46,34 @@ func newStep(act sdk.Action) Step {
 			if minimum != nil {
 				s.Coverage.Minimum = minimum.Value
 			}
+			maxDrop := sdk.ParameterFind(&act.Parameters, "max_drop")
+			if maxDrop != nil {
+				s.Coverage.MaxDrop = maxDrop.Value
+			}
+		case sdk.VulnerabilityReportAction:
+			s.VulnerabilityReport = &StepVulnerabilityReport{}
+			format := sdk.ParameterFind(&act.Parameters, "format")
+			if format != nil {
+				s.VulnerabilityReport.Format = format.Value
+			}
+			path := sdk.ParameterFind(&act.Parameters, "path")
+			if path != nil {
+				s.VulnerabilityReport.Path = path.Value
+			}
+			t := sdk.ParameterFind(&act.Parameters, "type")
+			if t != nil {
+				s.VulnerabilityReport.Type = t.Value
+			}
+		case sdk.SBOMAction:
+			s.SBOM = &StepSBOM{}
+			format := sdk.ParameterFind(&act.Parameters, "format")
+			if format != nil {
+				s.SBOM.Format = format.Value
+			}
+			path := sdk.ParameterFind(&act.Parameters, "path")
+			if path != nil {
+				s.SBOM.Path = path.Value
+			}
 		case sdk.ArtifactDownload:
 			s.ArtifactDownload = &StepArtifactDownload{}
 			path := sdk.ParameterFind(&act.Parameters, "path")
@@ -146,6 +174,30 @@ func newStep(act sdk.Action) Step {
 			if tag != nil && tag.Value != sdk.DefaultGitCloneParameterTagValue {
 				s.GitClone.Tag = tag.Value
 			}
+			sparseCheckout := sdk.ParameterFind(&act.Parameters, "sparseCheckout")
+			if sparseCheckout != nil {
+				s.GitClone.SparseCheckout = sparseCheckout.Value
+			}
+			filter := sdk.ParameterFind(&act.Parameters, "filter")
+			if filter != nil {
+				s.GitClone.Filter = filter.Value
+			}
+			lfs := sdk.ParameterFind(&act.Parameters, "lfs")
+			if lfs != nil && lfs.Value != "false" {
+				s.GitClone.LFS = lfs.Value
+			}
+			lfsInclude := sdk.ParameterFind(&act.Parameters, "lfsInclude")
+			if lfsInclude != nil {
+				s.GitClone.LFSInclude = lfsInclude.Value
+			}
+			lfsExclude := sdk.ParameterFind(&act.Parameters, "lfsExclude")
+			if lfsExclude != nil {
+				s.GitClone.LFSExclude = lfsExclude.Value
+			}
+			reference := sdk.ParameterFind(&act.Parameters, "reference")
+			if reference != nil {
+				s.GitClone.Reference = reference.Value
+			}
 		case sdk.GitTagAction:
 			s.GitTag = &StepGitTag{}
 			path := sdk.ParameterFind(&act.Parameters, "path")
@@ -239,9 +291,23 @@ type StepCustom map[string]StepParameters
 type StepCoverage struct {
 	Format  string `json:"format,omitempty" yaml:"format,omitempty"`
 	Minimum string `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	MaxDrop string `json:"max_drop,omitempty" yaml:"max_drop,omitempty"`
 	Path    string `json:"path,omitempty" yaml:"path,omitempty"`
 }
 
+// StepVulnerabilityReport represents exported vulnerability report step.
+type StepVulnerabilityReport struct {
+	Format string `json:"format,omitempty" yaml:"format,omitempty" jsonschema:"required"`
+	Path   string `json:"path,omitempty" yaml:"path,omitempty" jsonschema:"required"`
+	Type   string `json:"type,omitempty" yaml:"type,omitempty"`
+}
+
+// StepSBOM represents exported software bill of materials step.
+type StepSBOM struct {
+	Format string `json:"format,omitempty" yaml:"format,omitempty" jsonschema:"required"`
+	Path   string `json:"path,omitempty" yaml:"path,omitempty" jsonschema:"required"`
+}
+
 // StepArtifactDownload represents exported artifact download step.
 type StepArtifactDownload struct {
 	Enabled string `json:"enabled,omitempty" yaml:"enabled,omitempty"`
@@ -269,16 +335,22 @@ type StepServeStaticFiles struct {
 
 // StepGitClone represents exported git clone step.
 type StepGitClone struct {
-	Branch     string `json:"branch,omitempty" yaml:"branch,omitempty"`
-	Commit     string `json:"commit,omitempty" yaml:"commit,omitempty"`
-	Depth      string `json:"depth,omitempty" yaml:"depth,omitempty"`
-	Directory  string `json:"directory,omitempty" yaml:"directory,omitempty"`
-	Password   string `json:"password,omitempty" yaml:"password,omitempty"`
-	PrivateKey string `json:"privateKey,omitempty" yaml:"privateKey,omitempty"`
-	SubModules string `json:"submodules,omitempty" yaml:"submodules,omitempty"`
-	Tag        string `json:"tag,omitempty" yaml:"tag,omitempty"`
-	URL        string `json:"url,omitempty" yaml:"url,omitempty" jsonschema:"required"`
-	User       string `json:"user,omitempty" yaml:"user,omitempty"`
+	Branch         string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Commit         string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Depth          string `json:"depth,omitempty" yaml:"depth,omitempty"`
+	Directory      string `json:"directory,omitempty" yaml:"directory,omitempty"`
+	Filter         string `json:"filter,omitempty" yaml:"filter,omitempty"`
+	LFS            string `json:"lfs,omitempty" yaml:"lfs,omitempty"`
+	LFSExclude     string `json:"lfsExclude,omitempty" yaml:"lfsExclude,omitempty"`
+	LFSInclude     string `json:"lfsInclude,omitempty" yaml:"lfsInclude,omitempty"`
+	Password       string `json:"password,omitempty" yaml:"password,omitempty"`
+	PrivateKey     string `json:"privateKey,omitempty" yaml:"privateKey,omitempty"`
+	Reference      string `json:"reference,omitempty" yaml:"reference,omitempty"`
+	SparseCheckout string `json:"sparseCheckout,omitempty" yaml:"sparseCheckout,omitempty"`
+	SubModules     string `json:"submodules,omitempty" yaml:"submodules,omitempty"`
+	Tag            string `json:"tag,omitempty" yaml:"tag,omitempty"`
+	URL            string `json:"url,omitempty" yaml:"url,omitempty" jsonschema:"required"`
+	User           string `json:"user,omitempty" yaml:"user,omitempty"`
 }
 
 // StepRelease represents exported release step.
@@ -316,18 +388,20 @@ type Step struct {
 	Optional       *bool  `json:"optional,omitempty" yaml:"optional,omitempty"`
 	AlwaysExecuted *bool  `json:"always_executed,omitempty" yaml:"always_executed,omitempty"`
 	// step specific data, only one option should be set
-	StepCustom       `json:"-" yaml:",inline"`
-	Script           interface{}           `json:"script,omitempty" yaml:"script,omitempty" jsonschema:"-" jsonschema_description:"Script.\nhttps://ovh.github.io/cds/docs/actions/builtin-script"`
-	Coverage         *StepCoverage         `json:"coverage,omitempty" yaml:"coverage,omitempty" jsonschema_description:"Parse coverage report.\nhttps://ovh.github.io/cds/docs/actions/builtin-coverage"`
-	ArtifactDownload *StepArtifactDownload `json:"artifactDownload,omitempty" yaml:"artifactDownload,omitempty" jsonschema_description:"Download artifacts in workspace.\nhttps://ovh.github.io/cds/docs/actions/builtin-artifact-download"`
-	ArtifactUpload   *StepArtifactUpload   `json:"artifactUpload,omitempty" yaml:"artifactUpload,omitempty" jsonschema_description:"Upload artifacts from workspace.\nhttps://ovh.github.io/cds/docs/actions/builtin-artifact-upload"`
-	ServeStaticFiles *StepServeStaticFiles `json:"serveStaticFiles,omitempty" yaml:"serveStaticFiles,omitempty" jsonschema_description:"Serve static files.\nhttps://ovh.github.io/cds/docs/actions/builtin-serve-static-files"`
-	GitClone         *StepGitClone         `json:"gitClone,omitempty" yaml:"gitClone,omitempty" jsonschema_description:"Clone a git repository.\nhttps://ovh.github.io/cds/docs/actions/builtin-gitclone"`
-	GitTag           *StepGitTag           `json:"gitTag,omitempty" yaml:"gitTag,omitempty" jsonschema_description:"Create a git tag.\nhttps://ovh.github.io/cds/docs/actions/builtin-gittag"`
-	Release          *StepRelease          `json:"release,omitempty" yaml:"release,omitempty" jsonschema_description:"Release an application.\nhttps://ovh.github.io/cds/docs/actions/builtin-release"`
-	JUnitReport      *StepJUnitReport      `json:"jUnitReport,omitempty" yaml:"jUnitReport,omitempty" jsonschema_description:"Parse JUnit report.\nhttps://ovh.github.io/cds/docs/actions/builtin-junit"`
-	Checkout         *StepCheckout         `json:"checkout,omitempty" yaml:"checkout,omitempty" jsonschema_description:"Checkout repository for an application.\nhttps://ovh.github.io/cds/docs/actions/builtin-checkoutapplication"`
-	Deploy           *StepDeploy           `json:"deploy,omitempty" yaml:"deploy,omitempty" jsonschema_description:"Deploy an application.\nhttps://ovh.github.io/cds/docs/actions/builtin-deployapplication"`
+	StepCustom          `json:"-" yaml:",inline"`
+	Script              interface{}              `json:"script,omitempty" yaml:"script,omitempty" jsonschema:"-" jsonschema_description:"Script.\nhttps://ovh.github.io/cds/docs/actions/builtin-script"`
+	Coverage            *StepCoverage            `json:"coverage,omitempty" yaml:"coverage,omitempty" jsonschema_description:"Parse coverage report.\nhttps://ovh.github.io/cds/docs/actions/builtin-coverage"`
+	VulnerabilityReport *StepVulnerabilityReport `json:"vulnerabilityReport,omitempty" yaml:"vulnerabilityReport,omitempty" jsonschema_description:"Parse SARIF or CycloneDX VEX vulnerability report.\nhttps://ovh.github.io/cds/docs/actions/builtin-vulnerabilityreport"`
+	SBOM                *StepSBOM                `json:"sbom,omitempty" yaml:"sbom,omitempty" jsonschema_description:"Upload software bill of materials.\nhttps://ovh.github.io/cds/docs/actions/builtin-sbom"`
+	ArtifactDownload    *StepArtifactDownload    `json:"artifactDownload,omitempty" yaml:"artifactDownload,omitempty" jsonschema_description:"Download artifacts in workspace.\nhttps://ovh.github.io/cds/docs/actions/builtin-artifact-download"`
+	ArtifactUpload      *StepArtifactUpload      `json:"artifactUpload,omitempty" yaml:"artifactUpload,omitempty" jsonschema_description:"Upload artifacts from workspace.\nhttps://ovh.github.io/cds/docs/actions/builtin-artifact-upload"`
+	ServeStaticFiles    *StepServeStaticFiles    `json:"serveStaticFiles,omitempty" yaml:"serveStaticFiles,omitempty" jsonschema_description:"Serve static files.\nhttps://ovh.github.io/cds/docs/actions/builtin-serve-static-files"`
+	GitClone            *StepGitClone            `json:"gitClone,omitempty" yaml:"gitClone,omitempty" jsonschema_description:"Clone a git repository.\nhttps://ovh.github.io/cds/docs/actions/builtin-gitclone"`
+	GitTag              *StepGitTag              `json:"gitTag,omitempty" yaml:"gitTag,omitempty" jsonschema_description:"Create a git tag.\nhttps://ovh.github.io/cds/docs/actions/builtin-gittag"`
+	Release             *StepRelease             `json:"release,omitempty" yaml:"release,omitempty" jsonschema_description:"Release an application.\nhttps://ovh.github.io/cds/docs/actions/builtin-release"`
+	JUnitReport         *StepJUnitReport         `json:"jUnitReport,omitempty" yaml:"jUnitReport,omitempty" jsonschema_description:"Parse JUnit report.\nhttps://ovh.github.io/cds/docs/actions/builtin-junit"`
+	Checkout            *StepCheckout            `json:"checkout,omitempty" yaml:"checkout,omitempty" jsonschema_description:"Checkout repository for an application.\nhttps://ovh.github.io/cds/docs/actions/builtin-checkoutapplication"`
+	Deploy              *StepDeploy              `json:"deploy,omitempty" yaml:"deploy,omitempty" jsonschema_description:"Deploy an application.\nhttps://ovh.github.io/cds/docs/actions/builtin-deployapplication"`
 }
 
 // MarshalJSON custom marshal json impl to inline custom step.
@@ -441,6 +515,12 @@ func (s Step) IsValid() bool {
 	if s.isCoverage() {
 		count++
 	}
+	if s.isVulnerabilityReport() {
+		count++
+	}
+	if s.isSBOM() {
+		count++
+	}
 	if s.isScript() {
 		count++
 	}
@@ -476,6 +556,10 @@ func (s Step) toAction() (*sdk.Action, error) {
 		a = s.asDeployApplication()
 	} else if s.isCoverage() {
 		a, err = s.asCoverage()
+	} else if s.isVulnerabilityReport() {
+		a, err = s.asVulnerabilityReport()
+	} else if s.isSBOM() {
+		a, err = s.asSBOM()
 	} else if s.isScript() {
 		a, err = s.asScript()
 	} else {
@@ -580,6 +664,38 @@ func (s Step) asCoverage() (sdk.Action, error) {
 	return a, nil
 }
 
+func (s Step) isVulnerabilityReport() bool { return s.VulnerabilityReport != nil }
+
+func (s Step) asVulnerabilityReport() (sdk.Action, error) {
+	var a sdk.Action
+	m, err := stepToMap(s.VulnerabilityReport)
+	if err != nil {
+		return a, err
+	}
+	a = sdk.Action{
+		Name:       sdk.VulnerabilityReportAction,
+		Type:       sdk.BuiltinAction,
+		Parameters: sdk.ParametersFromMap(m),
+	}
+	return a, nil
+}
+
+func (s Step) isSBOM() bool { return s.SBOM != nil }
+
+func (s Step) asSBOM() (sdk.Action, error) {
+	var a sdk.Action
+	m, err := stepToMap(s.SBOM)
+	if err != nil {
+		return a, err
+	}
+	a = sdk.Action{
+		Name:       sdk.SBOMAction,
+		Type:       sdk.BuiltinAction,
+		Parameters: sdk.ParametersFromMap(m),
+	}
+	return a, nil
+}
+
 func (s Step) isDeploy() bool { return s.Deploy != nil }
 
 func (s Step) asDeployApplication() sdk.Action {
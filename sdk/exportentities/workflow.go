@@ -44,6 +44,8 @@ type Workflow struct {
 	Permissions      map[string]int                 `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the workflow (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
 	Metadata         map[string]string              `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 	PurgeTags        []string                       `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty"`
+	IncludePaths     []string                       `json:"include_paths,omitempty" yaml:"include_paths,omitempty" jsonschema_description:"Glob patterns of the files that must be changed by a push to trigger the workflow from a repository webhook or a git poller."`
+	ExcludePaths     []string                       `json:"exclude_paths,omitempty" yaml:"exclude_paths,omitempty" jsonschema_description:"Glob patterns of the files ignored to trigger the workflow from a repository webhook or a git poller."`
 	Notifications    []NotificationEntry            `json:"notify,omitempty" yaml:"notify,omitempty"` // This is used when the workflow have only one pipeline
 	HistoryLength    *int64                         `json:"history_length,omitempty" yaml:"history_length,omitempty"`
 	MapNotifications map[string][]NotificationEntry `json:"notifications,omitempty" yaml:"notifications,omitempty"` // This is used when the workflow have more than one pipeline
@@ -303,6 +305,8 @@ func NewWorkflow(w sdk.Workflow, opts ...WorkflowOptions) (Workflow, error) {
 	}
 
 	exportedWorkflow.PurgeTags = w.PurgeTags
+	exportedWorkflow.IncludePaths = w.IncludePaths
+	exportedWorkflow.ExcludePaths = w.ExcludePaths
 
 	nodes := w.WorkflowData.Array()
 
@@ -510,6 +514,8 @@ func (w Workflow) GetWorkflow() (*sdk.Workflow, error) {
 		return nil, sdk.WrapError(err, "Unable to check dependencies")
 	}
 	wf.PurgeTags = w.PurgeTags
+	wf.IncludePaths = w.IncludePaths
+	wf.ExcludePaths = w.ExcludePaths
 	if len(w.Metadata) > 0 {
 		wf.Metadata = make(map[string]string, len(w.Metadata))
 		for k, v := range w.Metadata {
@@ -671,7 +677,7 @@ func (w *Workflow) processHooks(n *sdk.Node, wf *sdk.Workflow) {
 			for k, v := range h.Config {
 				var hType string
 				switch h.Model {
-				case sdk.KafkaHookModelName, sdk.RabbitMQHookModelName:
+				case sdk.KafkaHookModelName, sdk.RabbitMQHookModelName, sdk.NATSHookModelName, sdk.MQTTHookModelName:
 					if k == sdk.HookModelIntegration {
 						hType = sdk.HookConfigTypeIntegration
 					} else {
@@ -680,6 +686,9 @@ func (w *Workflow) processHooks(n *sdk.Node, wf *sdk.Workflow) {
 				default:
 					hType = sdk.HookConfigTypeString
 				}
+				if k == sdk.HookConfigWebHookSecret {
+					hType = sdk.HookConfigTypePassword
+				}
 				cfg[k] = sdk.WorkflowNodeHookConfigValue{
 					Value:        v,
 					Configurable: true,
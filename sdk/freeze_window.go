@@ -0,0 +1,98 @@
+package sdk
+
+import (
+	"time"
+
+	"github.com/gorhill/cronexpr"
+)
+
+// Actions of a freeze window on the pipelines that would start during the window
+const (
+	FreezeWindowActionHold = "hold"
+	FreezeWindowActionFail = "fail"
+)
+
+// FreezeWindow is a period during which the pipelines of a project that deploy on an environment must not start.
+// A window is either recurring, starting at each occurrence of a cron expression for a duration, or an absolute range.
+// Without environment, the window applies to all the environments of the project.
+type FreezeWindow struct {
+	ID              int64      `json:"id" db:"id" cli:"-"`
+	ProjectID       int64      `json:"project_id" db:"project_id" cli:"-"`
+	EnvironmentID   *int64     `json:"environment_id,omitempty" db:"environment_id" cli:"-"`
+	EnvironmentName string     `json:"environment,omitempty" db:"-" cli:"environment"`
+	Name            string     `json:"name" db:"name" cli:"name,key"`
+	Reason          string     `json:"reason" db:"reason" cli:"reason"`
+	Cron            string     `json:"cron,omitempty" db:"cron" cli:"cron"`
+	Duration        string     `json:"duration,omitempty" db:"duration" cli:"duration"`
+	From            *time.Time `json:"from,omitempty" db:"from_date" cli:"from"`
+	To              *time.Time `json:"to,omitempty" db:"to_date" cli:"to"`
+	Timezone        string     `json:"timezone" db:"timezone" cli:"timezone"`
+	Action          string     `json:"action" db:"action" cli:"action"`
+	OverrideGroup   string     `json:"override_group,omitempty" db:"override_group" cli:"override_group"`
+	Created         time.Time  `json:"created" db:"created" cli:"-"`
+}
+
+// IsValid returns an error if the freeze window is not a valid recurring window or range.
+func (f FreezeWindow) IsValid() error {
+	if f.Name == "" {
+		return NewErrorFrom(ErrWrongRequest, "freeze window name is mandatory")
+	}
+	switch f.Action {
+	case FreezeWindowActionHold, FreezeWindowActionFail:
+	default:
+		return NewErrorFrom(ErrWrongRequest, "invalid freeze window action %q, expected %s or %s", f.Action, FreezeWindowActionHold, FreezeWindowActionFail)
+	}
+	if _, err := time.LoadLocation(f.Timezone); err != nil {
+		return NewErrorFrom(ErrWrongRequest, "invalid freeze window timezone %q", f.Timezone)
+	}
+
+	recurring := f.Cron != "" || f.Duration != ""
+	absolute := f.From != nil || f.To != nil
+	switch {
+	case recurring && absolute:
+		return NewErrorFrom(ErrWrongRequest, "a freeze window is either recurring (cron and duration) or a range (from and to)")
+	case recurring:
+		if _, err := cronexpr.Parse(f.Cron); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid freeze window cron expression %q: %v", f.Cron, err)
+		}
+		if d, err := time.ParseDuration(f.Duration); err != nil || d <= 0 {
+			return NewErrorFrom(ErrWrongRequest, "invalid freeze window duration %q, expected a positive duration like 12h", f.Duration)
+		}
+	case absolute:
+		if f.From == nil || f.To == nil || !f.To.After(*f.From) {
+			return NewErrorFrom(ErrWrongRequest, "a freeze window range needs a start before its end")
+		}
+	default:
+		return NewErrorFrom(ErrWrongRequest, "a freeze window needs a cron expression and a duration, or a range")
+	}
+	return nil
+}
+
+// ActiveUntil returns the end of the occurrence of the window active at given time, and false if the window is not active.
+func (f FreezeWindow) ActiveUntil(t time.Time) (time.Time, bool) {
+	if f.From != nil && f.To != nil {
+		if !t.Before(*f.From) && t.Before(*f.To) {
+			return *f.To, true
+		}
+		return time.Time{}, false
+	}
+
+	expr, err := cronexpr.Parse(f.Cron)
+	if err != nil {
+		return time.Time{}, false
+	}
+	d, err := time.ParseDuration(f.Duration)
+	if err != nil {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(f.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	// The only occurrence that can be active is the first one that starts after t minus the duration
+	start := expr.Next(t.In(loc).Add(-d))
+	if start.IsZero() || start.After(t) {
+		return time.Time{}, false
+	}
+	return start.Add(d), true
+}
@@ -0,0 +1,98 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreezeWindowIsValid(t *testing.T) {
+	from := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		window FreezeWindow
+		valid  bool
+	}{
+		{
+			name:   "recurring",
+			window: FreezeWindow{Name: "friday", Action: FreezeWindowActionHold, Timezone: "Europe/Paris", Cron: "0 14 * * 5", Duration: "10h"},
+			valid:  true,
+		},
+		{
+			name:   "range",
+			window: FreezeWindow{Name: "end-of-year", Action: FreezeWindowActionFail, Timezone: "UTC", From: &from, To: &to},
+			valid:  true,
+		},
+		{
+			name:   "no name",
+			window: FreezeWindow{Action: FreezeWindowActionHold, Timezone: "UTC", From: &from, To: &to},
+		},
+		{
+			name:   "unknown action",
+			window: FreezeWindow{Name: "friday", Action: "block", Timezone: "UTC", Cron: "0 14 * * 5", Duration: "10h"},
+		},
+		{
+			name:   "invalid cron",
+			window: FreezeWindow{Name: "friday", Action: FreezeWindowActionHold, Timezone: "UTC", Cron: "every friday", Duration: "10h"},
+		},
+		{
+			name:   "no duration",
+			window: FreezeWindow{Name: "friday", Action: FreezeWindowActionHold, Timezone: "UTC", Cron: "0 14 * * 5"},
+		},
+		{
+			name:   "reversed range",
+			window: FreezeWindow{Name: "end-of-year", Action: FreezeWindowActionHold, Timezone: "UTC", From: &to, To: &from},
+		},
+		{
+			name:   "recurring and range",
+			window: FreezeWindow{Name: "friday", Action: FreezeWindowActionHold, Timezone: "UTC", Cron: "0 14 * * 5", Duration: "10h", From: &from, To: &to},
+		},
+		{
+			name:   "nothing",
+			window: FreezeWindow{Name: "friday", Action: FreezeWindowActionHold, Timezone: "UTC"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.IsValid()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestFreezeWindowActiveUntil(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	assert.NoError(t, err)
+
+	// Every Friday from 14:00 to Saturday 00:00 in Paris
+	friday := FreezeWindow{Cron: "0 14 * * 5", Duration: "10h", Timezone: "Europe/Paris"}
+
+	until, active := friday.ActiveUntil(time.Date(2019, 10, 11, 15, 0, 0, 0, paris))
+	assert.True(t, active)
+	assert.True(t, until.Equal(time.Date(2019, 10, 12, 0, 0, 0, 0, paris)))
+
+	_, active = friday.ActiveUntil(time.Date(2019, 10, 11, 13, 59, 0, 0, paris))
+	assert.False(t, active)
+	_, active = friday.ActiveUntil(time.Date(2019, 10, 12, 0, 0, 0, 0, paris))
+	assert.False(t, active)
+
+	// The cron expression is read in the timezone of the window
+	_, active = friday.ActiveUntil(time.Date(2019, 10, 11, 12, 30, 0, 0, time.UTC))
+	assert.True(t, active)
+
+	from := time.Date(2019, 12, 20, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)
+	endOfYear := FreezeWindow{From: &from, To: &to, Timezone: "UTC"}
+
+	until, active = endOfYear.ActiveUntil(time.Date(2019, 12, 25, 10, 0, 0, 0, time.UTC))
+	assert.True(t, active)
+	assert.True(t, until.Equal(to))
+	_, active = endOfYear.ActiveUntil(to)
+	assert.False(t, active)
+}
@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// GlobMatch reports whether name matches the glob pattern. A '*' matches any sequence of characters
+// except '/', a '**' matches any sequence of characters and a '?' matches any character except '/'.
+func GlobMatch(pattern, name string) bool {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" also matches no directory
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	ok, _ := regexp.MatchString(b.String(), name)
+	return ok
+}
+
+// GlobsMatch reports whether name matches one of the glob patterns separated by ';'.
+// An empty list of patterns matches all names.
+func GlobsMatch(patterns, name string) bool {
+	var hasPattern bool
+	for _, p := range strings.Split(patterns, ";") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		hasPattern = true
+		if GlobMatch(p, name) {
+			return true
+		}
+	}
+	return !hasPattern
+}
@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"master", "master", true},
+		{"release/*", "release/1.0", true},
+		{"release/*", "release/1.0/fix", false},
+		{"feat?", "feat1", true},
+		{"v*.*", "v1.2", true},
+		{"docs/**", "docs/a/b/c.md", true},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "engine/api/api.go", true},
+		{"**/*.go", "engine/api/api.md", false},
+		{"engine/**/*.sql", "engine/sql/1.sql", true},
+		{"a.b", "axb", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, GlobMatch(tt.pattern, tt.name), "%s %s", tt.pattern, tt.name)
+	}
+}
+
+func TestGlobsMatch(t *testing.T) {
+	assert.True(t, GlobsMatch("", "anything"))
+	assert.True(t, GlobsMatch("master; release/*", "release/1"))
+	assert.False(t, GlobsMatch("master;release/*", "feat/1"))
+}
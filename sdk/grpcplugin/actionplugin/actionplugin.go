@@ -10,6 +10,8 @@ import (
 	"github.com/ovh/cds/sdk/grpcplugin"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Common is the common struct of actionplugin
@@ -19,6 +21,36 @@ type Common struct {
 	HTTPPort int32
 }
 
+// RunStream is not implemented by default, the worker then calls Run.
+func (c *Common) RunStream(q *ActionQuery, stream ActionPlugin_RunStreamServer) error {
+	return status.Error(codes.Unimplemented, "RunStream is not implemented")
+}
+
+// SendLog sends a log line to the worker during RunStream.
+func SendLog(stream ActionPlugin_RunStreamServer, format string, args ...interface{}) error {
+	return stream.Send(&ActionEvent{Event: &ActionEvent_Log{Log: fmt.Sprintf(format, args...)}})
+}
+
+// SendProgress sends the progress of the action to the worker during RunStream.
+func SendProgress(stream ActionPlugin_RunStreamServer, current, total int64, message string) error {
+	return stream.Send(&ActionEvent{Event: &ActionEvent_Progress{Progress: &ActionProgress{Current: current, Total: total, Message: message}}})
+}
+
+// SendVariable sends a build variable to the worker during RunStream.
+func SendVariable(stream ActionPlugin_RunStreamServer, name, value string) error {
+	return stream.Send(&ActionEvent{Event: &ActionEvent_Variable{Variable: &ActionVariable{Name: name, Value: value}}})
+}
+
+// SendArtifact asks the worker to upload an artifact during RunStream.
+func SendArtifact(stream ActionPlugin_RunStreamServer, path, tag string) error {
+	return stream.Send(&ActionEvent{Event: &ActionEvent_Artifact{Artifact: &ActionArtifact{Path: path, Tag: tag}}})
+}
+
+// SendResult sends the result of the action to the worker, it must be the last event of RunStream.
+func SendResult(stream ActionPlugin_RunStreamServer, status, details string) error {
+	return stream.Send(&ActionEvent{Event: &ActionEvent_Result{Result: &ActionResult{Status: status, Details: details}}})
+}
+
 // Start is useful to start grpcplugin
 func Start(ctx context.Context, srv ActionPluginServer) error {
 	p, ok := srv.(grpcplugin.Plugin)
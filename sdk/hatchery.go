@@ -1,6 +1,59 @@
 package sdk
 
+import "time"
+
 // Hatchery registration model
 type Hatchery struct {
 	RatioService *int `json:"ratio_service" db:"-"`
 }
+
+// HatcheryCapacity is the capacity declared by a hatchery, used by the API to assign queued jobs to hatcheries
+type HatcheryCapacity struct {
+	HatcheryID     int64     `json:"hatchery_id" cli:"id"`
+	HatcheryName   string    `json:"hatchery_name" cli:"name,key"`
+	GroupID        int64     `json:"group_id" cli:"-"`
+	IsSharedInfra  bool      `json:"is_shared_infra" cli:"-"`
+	ModelType      string    `json:"model_type" cli:"model_type"`
+	MaxWorker      int       `json:"max_worker" cli:"max_worker"`
+	CurrentWorkers int       `json:"current_workers" cli:"current_workers"`
+	Booked         int       `json:"booked" cli:"booked"`
+	Priority       int       `json:"priority" cli:"priority"`
+	CostWeight     float64   `json:"cost_weight" cli:"cost_weight"`
+	LastUpdate     time.Time `json:"last_update" cli:"last_update"`
+}
+
+// Free returns the number of workers that the hatchery can still start
+func (c HatcheryCapacity) Free() int {
+	return c.MaxWorker - c.CurrentWorkers - c.Booked
+}
+
+// CanRun returns true if the hatchery may be able to run the given job
+func (c HatcheryCapacity) CanRun(j WorkflowNodeJobRun) bool {
+	if j.ModelType != "" && c.ModelType != "" && j.ModelType != c.ModelType {
+		return false
+	}
+	if c.IsSharedInfra || len(j.ExecGroups) == 0 {
+		return true
+	}
+	for _, g := range j.ExecGroups {
+		if g.ID == c.GroupID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBetterThan returns true if the hatchery should be preferred to the given one.
+// Hatcheries are sorted by priority, then by cost weight and by free capacity.
+func (c HatcheryCapacity) IsBetterThan(o HatcheryCapacity) bool {
+	if c.Priority != o.Priority {
+		return c.Priority > o.Priority
+	}
+	if c.CostWeight != o.CostWeight {
+		return c.CostWeight < o.CostWeight
+	}
+	if c.Free() != o.Free() {
+		return c.Free() > o.Free()
+	}
+	return c.HatcheryID < o.HatcheryID
+}
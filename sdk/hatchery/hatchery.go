@@ -96,6 +96,8 @@ func Create(ctx context.Context, h Interface) error {
 	// Create a cache with a default expiration time of 3 second, and which
 	// purges expired items every minute
 	spawnIDs := cache.New(10*time.Second, 60*time.Second)
+	// Jobs for which a worker has been requested, kept as long as the job is booked
+	startedIDs := cache.New(2*time.Minute, 60*time.Second)
 
 	// hatchery is now fully Initialized
 	h.SetInitialized()
@@ -110,6 +112,10 @@ func Create(ctx context.Context, h Interface) error {
 		PanicDump(h),
 	)
 
+	sdk.GoRoutine(ctx, "declareCapacity", func(ctx context.Context) {
+		declareCapacity(ctx, h)
+	}, PanicDump(h))
+
 	// run the starters pool
 	workersStartChan := startWorkerStarters(ctx, h)
 
@@ -201,23 +207,31 @@ func Create(ctx context.Context, h Interface) error {
 			//Before doing anything, push in cache
 			spawnIDs.SetDefault(strconv.FormatInt(j.ID, 10), j.ID)
 
-			//Check bookedBy current hatchery
-			if j.BookedBy.ID != 0 {
+			//Check bookedBy current hatchery, a job booked by the current hatchery was assigned by the API scheduler
+			if j.BookedBy.ID != 0 && j.BookedBy.ID != h.ID() {
 				log.Debug("hatchery> job %d is booked by someone (%d / %d)", j.ID, j.BookedBy.ID, h.ID())
 				endTrace("booked by someone")
 				continue
 			}
+			if _, exist := startedIDs.Get(strconv.FormatInt(j.ID, 10)); exist {
+				log.Debug("hatchery> job %d is already booked by current hatchery", j.ID)
+				endTrace("already booked")
+				continue
+			}
 
 			//Check if hatchery if able to start a new worker
 			if !checkCapacities(ctx, h) {
 				log.Info("hatchery %s is not able to provision new worker", h.Service().Name)
+				releaseScheduledJob(h, j)
 				endTrace("no capacities")
 				continue
 			}
 
+			jobID := strconv.FormatInt(j.ID, 10)
 			workerRequest := workerStarterRequest{
 				ctx:               currentCtx,
 				cancel:            endTrace,
+				failed:            func() { startedIDs.Delete(jobID) }, // the job can be started again if the worker is not spawned
 				id:                j.ID,
 				execGroups:        j.ExecGroups,
 				requirements:      j.Job.Action.Requirements,
@@ -238,6 +252,7 @@ func Create(ctx context.Context, h Interface) error {
 			// No model has been found, let's send a failing result
 			if chosenModel == nil {
 				log.Debug("hatchery> no model")
+				releaseScheduledJob(h, j)
 				endTrace("no model")
 				continue
 			}
@@ -247,6 +262,7 @@ func Create(ctx context.Context, h Interface) error {
 
 			//Ask to start
 			log.Debug("hatchery> Request a worker for job %d (%.3f seconds elapsed)", j.ID, time.Since(t0).Seconds())
+			startedIDs.SetDefault(strconv.FormatInt(j.ID, 10), j.ID)
 			workersStartChan <- workerRequest
 
 		case <-tickerProvision.C:
@@ -59,6 +59,44 @@ func checkCapacities(ctx context.Context, h Interface) bool {
 	return true
 }
 
+// declareCapacity sends periodically the capacity of the hatchery to the API scheduler
+func declareCapacity(ctx context.Context, h Interface) {
+	tick := time.NewTicker(20 * time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			if h.CDSClient().GetService() == nil || h.ID() == 0 {
+				continue
+			}
+			capa := sdk.HatcheryCapacity{
+				ModelType:      h.ModelType(),
+				MaxWorker:      h.Configuration().Provision.MaxWorker,
+				CurrentWorkers: len(h.WorkersStarted()),
+				Priority:       h.Configuration().Provision.Priority,
+				CostWeight:     h.Configuration().Provision.CostWeight,
+			}
+			ctxt, cancel := context.WithTimeout(ctx, 10*time.Second)
+			if err := h.CDSClient().QueueHatcheryCapacity(ctxt, capa); err != nil {
+				log.Warning("hatchery> declareCapacity> unable to send capacity: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// releaseScheduledJob releases a job assigned to the hatchery by the API scheduler, so it can be assigned to another hatchery
+func releaseScheduledJob(h Interface, j sdk.WorkflowNodeJobRun) {
+	if j.BookedBy.ID == 0 || j.BookedBy.ID != h.ID() {
+		return
+	}
+	if err := h.CDSClient().QueueJobRelease(j.ID); err != nil {
+		log.Warning("hatchery> releaseScheduledJob> unable to release job %d: %v", j.ID, err)
+	}
+}
+
 func provisioning(h Interface, models []sdk.Model) {
 	if h.Configuration().Provision.Disabled {
 		log.Debug("provisioning> disabled on this hatchery")
@@ -19,6 +19,7 @@ import (
 type workerStarterRequest struct {
 	ctx                 context.Context
 	cancel              func(reason string)
+	failed              func()
 	id                  int64
 	model               sdk.Model
 	execGroups          []sdk.Group
@@ -60,7 +61,9 @@ func workerStarter(ctx context.Context, h Interface, workerNum string, jobs <-ch
 	for j := range jobs {
 		// Start a worker for a job
 		if m := j.registerWorkerModel; m == nil {
-			_ = spawnWorkerForJob(h, j)
+			if !spawnWorkerForJob(h, j) && j.failed != nil {
+				j.failed()
+			}
 			j.cancel("")
 		} else { // Start a worker for registering
 			log.Debug("Spawning worker for register model %s", m.Name)
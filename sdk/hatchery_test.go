@@ -0,0 +1,31 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHatcheryCapacityIsBetterThan(t *testing.T) {
+	cheap := HatcheryCapacity{HatcheryID: 1, MaxWorker: 10, CostWeight: 0.5}
+	expensive := HatcheryCapacity{HatcheryID: 2, MaxWorker: 10, CostWeight: 2}
+	prioritized := HatcheryCapacity{HatcheryID: 3, MaxWorker: 10, CostWeight: 2, Priority: 1}
+	loaded := HatcheryCapacity{HatcheryID: 4, MaxWorker: 10, CurrentWorkers: 8, CostWeight: 0.5}
+
+	assert.True(t, cheap.IsBetterThan(expensive))
+	assert.False(t, expensive.IsBetterThan(cheap))
+	assert.True(t, prioritized.IsBetterThan(cheap))
+	assert.True(t, cheap.IsBetterThan(loaded))
+}
+
+func TestHatcheryCapacityCanRun(t *testing.T) {
+	capa := HatcheryCapacity{GroupID: 1, ModelType: Docker}
+
+	assert.True(t, capa.CanRun(WorkflowNodeJobRun{}))
+	assert.True(t, capa.CanRun(WorkflowNodeJobRun{ModelType: Docker, ExecGroups: []Group{{ID: 1}}}))
+	assert.False(t, capa.CanRun(WorkflowNodeJobRun{ModelType: Openstack}))
+	assert.False(t, capa.CanRun(WorkflowNodeJobRun{ExecGroups: []Group{{ID: 2}}}))
+
+	capa.IsSharedInfra = true
+	assert.True(t, capa.CanRun(WorkflowNodeJobRun{ExecGroups: []Group{{ID: 2}}}))
+}
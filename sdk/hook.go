@@ -9,6 +9,8 @@ const (
 	GitPollerModelName            = "Git Repository Poller"
 	KafkaHookModelName            = "Kafka hook"
 	RabbitMQHookModelName         = "RabbitMQ hook"
+	NATSHookModelName             = "NATS hook"
+	MQTTHookModelName             = "MQTT hook"
 	WorkflowModelName             = "Workflow"
 	HookConfigProject             = "project"
 	HookConfigWorkflow            = "workflow"
@@ -18,14 +20,22 @@ const (
 	HookConfigWorkflowID          = "workflow_id"
 	HookConfigVCSServer           = "vcsServer"
 	HookConfigEventFilter         = "eventFilter"
+	HookConfigRefFilter           = "refFilter"
+	HookConfigIncludePaths        = "includePaths"
+	HookConfigExcludePaths        = "excludePaths"
 	HookConfigRepoFullName        = "repoFullName"
 	HookConfigModelType           = "model_type"
 	HookConfigModelName           = "model_name"
 	HookConfigIcon                = "hookIcon"
 	WebHookModelConfigMethod      = "method"
+	WebHookModelConfigAuth        = "authentication"
+	HookConfigWebHookSecret       = "secret"
 	RepositoryWebHookModelMethod  = "method"
 	SchedulerModelCron            = "cron"
 	SchedulerModelTimezone        = "timezone"
+	SchedulerModelExclusions      = "exclusions"
+	SchedulerModelJitter          = "jitter"
+	SchedulerModelOnlyIfChanged   = "onlyIfChanged"
 	Payload                       = "payload"
 	HookModelIntegration          = "integration"
 	KafkaHookModelConsumerGroup   = "consumer group"
@@ -35,6 +45,39 @@ const (
 	RabbitMQHookModelExchangeType = "exchange_type"
 	RabbitMQHookModelExchangeName = "exchange_name"
 	RabbitMQHookModelConsumerTag  = "consumer_tag"
+	NATSHookModelSubject          = "subject"
+	NATSHookModelQueueGroup       = "queue_group"
+	NATSHookModelJetStream        = "jetstream"
+	NATSHookModelStream           = "stream"
+	NATSHookModelDurable          = "durable"
+	MQTTHookModelTopic            = "topic"
+	MQTTHookModelQoS              = "qos"
+	MQTTHookModelClientID         = "client_id"
+	OutgoingWebHookHeaders        = "headers"
+	OutgoingWebHookRetries        = "retries"
+	OutgoingWebHookRetryDelay     = "retry_delay"
+)
+
+// Kinds of events sent by repository managers
+const (
+	RepositoryEventPush        = "push"
+	RepositoryEventTag         = "tag"
+	RepositoryEventPullRequest = "pull_request"
+	RepositoryEventRelease     = "release"
+)
+
+// RepositoryEventKinds are the kinds of events that can trigger a repository webhook,
+// push events are selected by default.
+var (
+	RepositoryEventKinds   = []string{RepositoryEventPush, RepositoryEventTag, RepositoryEventPullRequest, RepositoryEventRelease}
+	RepositoryEventsFilter = RepositoryEventPush
+)
+
+// Authentications of incoming generic webhooks
+const (
+	WebHookAuthNone   = ""
+	WebHookAuthHMAC   = "hmac"
+	WebHookAuthBearer = "bearer"
 )
 
 // Here are the default hooks
@@ -46,6 +89,8 @@ var (
 		&SchedulerModel,
 		&KafkaHookModel,
 		&RabbitMQHookModel,
+		&NATSHookModel,
+		&MQTTHookModel,
 		&WorkflowModel,
 		&GerritHookModel,
 	}
@@ -120,6 +165,76 @@ var (
 		},
 	}
 
+	NATSHookModel = WorkflowHookModel{
+		Author:     "CDS",
+		Type:       WorkflowHookModelBuiltin,
+		Identifier: "github.com/ovh/cds/hook/builtin/nats",
+		Name:       NATSHookModelName,
+		Icon:       "Linkify",
+		DefaultConfig: WorkflowNodeHookConfig{
+			HookModelIntegration: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeIntegration,
+			},
+			NATSHookModelSubject: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			NATSHookModelQueueGroup: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			NATSHookModelJetStream: {
+				Value:        "false",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			NATSHookModelStream: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			NATSHookModelDurable: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+		},
+	}
+
+	MQTTHookModel = WorkflowHookModel{
+		Author:     "CDS",
+		Type:       WorkflowHookModelBuiltin,
+		Identifier: "github.com/ovh/cds/hook/builtin/mqtt",
+		Name:       MQTTHookModelName,
+		Icon:       "Linkify",
+		DefaultConfig: WorkflowNodeHookConfig{
+			HookModelIntegration: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeIntegration,
+			},
+			MQTTHookModelTopic: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			MQTTHookModelQoS: {
+				Value:        "1",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			MQTTHookModelClientID: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+		},
+	}
+
 	WebHookModel = WorkflowHookModel{
 		Author:     "CDS",
 		Type:       WorkflowHookModelBuiltin,
@@ -132,6 +247,16 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			WebHookModelConfigAuth: {
+				Value:        WebHookAuthNone,
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigWebHookSecret: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypePassword,
+			},
 		},
 	}
 
@@ -147,6 +272,17 @@ var (
 				Configurable: false,
 				Type:         HookConfigTypeString,
 			},
+			HookConfigEventFilter: {
+				Value:              RepositoryEventsFilter,
+				Configurable:       true,
+				Type:               HookConfigTypeMultiChoice,
+				MultipleChoiceList: RepositoryEventKinds,
+			},
+			HookConfigRefFilter: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -182,6 +318,21 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			SchedulerModelExclusions: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			SchedulerModelJitter: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			SchedulerModelOnlyIfChanged: {
+				Value:        "false",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 			Payload: {
 				Value:        "{}",
 				Configurable: true,
@@ -234,6 +385,26 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			OutgoingWebHookHeaders: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigWebHookSecret: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypePassword,
+			},
+			OutgoingWebHookRetries: {
+				Value:        "3",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			OutgoingWebHookRetryDelay: {
+				Value:        "30s",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
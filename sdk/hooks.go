@@ -1,5 +1,7 @@
 package sdk
 
+import "strings"
+
 // Task is a generic hook tasks such as webhook, scheduler,... which will be started and wait for execution
 type Task struct {
 	UUID              string                 `json:"uuid" cli:"UUID,key"`
@@ -9,6 +11,22 @@ type Task struct {
 	Executions        []TaskExecution        `json:"executions"`
 	NbExecutionsTotal int                    `json:"nb_executions_total" cli:"nb_executions_total"`
 	NbExecutionsTodo  int                    `json:"nb_executions_todo" cli:"nb_executions_todo"`
+	Connection        *TaskConnection        `json:"connection,omitempty" cli:"-"`
+}
+
+// Status of the connection of a message hook to its broker
+const (
+	TaskConnectionConnected    = "CONNECTED"
+	TaskConnectionDisconnected = "DISCONNECTED"
+	TaskConnectionClosed       = "CLOSED"
+)
+
+// TaskConnection is the status of the connection of a message hook (NATS, MQTT) to its broker
+type TaskConnection struct {
+	Status     string `json:"status" cli:"status"`
+	LastError  string `json:"last_error,omitempty" cli:"last_error"`
+	Reconnects int64  `json:"reconnects" cli:"reconnects"`
+	Timestamp  int64  `json:"timestamp" cli:"timestamp"`
 }
 
 // TaskExecution represents an execution instance of a task. It the task is a webhook; this represents the call of the webhook
@@ -18,15 +36,26 @@ type TaskExecution struct {
 	Timestamp           int64                   `json:"timestamp" cli:"timestamp"`
 	NbErrors            int64                   `json:"nb_errors" cli:"nb_errors"`
 	LastError           string                  `json:"last_error,omitempty" cli:"last_error"`
+	SkipReason          string                  `json:"skip_reason,omitempty" cli:"skip_reason"`
 	ProcessingTimestamp int64                   `json:"processing_timestamp" cli:"processing_timestamp"`
 	WorkflowRun         int64                   `json:"workflow_run" cli:"workflow_run"`
 	Config              WorkflowNodeHookConfig  `json:"config" cli:"-"`
 	WebHook             *WebHookExecution       `json:"webhook,omitempty" cli:"-"`
 	Kafka               *KafkaTaskExecution     `json:"kafka,omitempty" cli:"-"`
 	RabbitMQ            *RabbitMQTaskExecution  `json:"rabbitmq,omitempty" cli:"-"`
+	NATS                *NATSTaskExecution      `json:"nats,omitempty" cli:"-"`
+	MQTT                *MQTTTaskExecution      `json:"mqtt,omitempty" cli:"-"`
 	ScheduledTask       *ScheduledTaskExecution `json:"scheduled_task,omitempty" cli:"-"`
 	GerritEvent         *GerritEventExecution   `json:"gerrit,omitempty" cli:"-"`
 	Status              string                  `json:"status" cli:"status"`
+	Payload             map[string]string       `json:"payload,omitempty" cli:"-"`
+	Replay              *TaskExecutionReplay    `json:"replay,omitempty" cli:"-"`
+}
+
+// TaskExecutionReplay is a replay of a task execution, with the payload of the replayed execution or another one
+type TaskExecutionReplay struct {
+	Timestamp int64             `json:"timestamp"`
+	Payload   map[string]string `json:"payload,omitempty"`
 }
 
 // GerritEventExecution contains specific data for a gerrit event execution
@@ -40,6 +69,33 @@ type WebHookExecution struct {
 	RequestBody   []byte              `json:"request_body"`
 	RequestHeader map[string][]string `json:"request_header"`
 	RequestMethod string              `json:"request_method"`
+	Attempts      []WebHookAttempt    `json:"attempts,omitempty"`
+	NextAttempt   int64               `json:"next_attempt,omitempty"`
+}
+
+// IsSensitiveHTTPName returns true for the names of headers and query parameters which usually hold credentials
+func IsSensitiveHTTPName(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie":
+		return true
+	}
+	for _, s := range []string{"token", "secret", "password", "apikey", "api-key", "api_key"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebHookAttempt is a delivery attempt of an outgoing webhook, with the dumps of its request and response
+type WebHookAttempt struct {
+	Timestamp  int64  `json:"timestamp"`
+	Duration   int64  `json:"duration"`
+	Request    string `json:"request"`
+	Response   string `json:"response,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
 }
 
 // KafkaTaskExecution contains specific data for a kafka hook
@@ -52,7 +108,20 @@ type RabbitMQTaskExecution struct {
 	Message []byte `json:"message"`
 }
 
+// NATSTaskExecution contains specific data for a NATS hook
+type NATSTaskExecution struct {
+	Subject string `json:"subject"`
+	Message []byte `json:"message"`
+}
+
+// MQTTTaskExecution contains specific data for a MQTT hook
+type MQTTTaskExecution struct {
+	Topic   string `json:"topic"`
+	Message []byte `json:"message"`
+}
+
 // ScheduledTaskExecution contains specific data for a scheduled task execution
 type ScheduledTaskExecution struct {
 	DateScheduledExecution string `json:"date_scheduled_execution"`
+	Hash                   string `json:"hash,omitempty"` // latest commit of the repository when the execution was processed
 }
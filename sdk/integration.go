@@ -4,6 +4,8 @@ package sdk
 const (
 	KafkaIntegrationModel         = "Kafka"
 	RabbitMQIntegrationModel      = "RabbitMQ"
+	NATSIntegrationModel          = "NATS"
+	MQTTIntegrationModel          = "MQTT"
 	OpenstackIntegrationModel     = "Openstack"
 	AWSIntegrationModel           = "AWS"
 	DefaultStorageIntegrationName = "shared.infra"
@@ -14,6 +16,8 @@ var (
 	BuiltinIntegrationModels = []*IntegrationModel{
 		&KafkaIntegration,
 		&RabbitMQIntegration,
+		&NATSIntegration,
+		&MQTTIntegration,
 		&OpenstackIntegration,
 		&AWSIntegration,
 	}
@@ -57,6 +61,49 @@ var (
 		Disabled: false,
 		Hook:     true,
 	}
+	// NATSIntegration represents a NATS integration
+	NATSIntegration = IntegrationModel{
+		Name:       NATSIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/nats",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			"url": IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			"username": IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			"password": IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+			"token": IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+		},
+		Disabled: false,
+		Hook:     true,
+	}
+	// MQTTIntegration represents a MQTT integration
+	MQTTIntegration = IntegrationModel{
+		Name:       MQTTIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/mqtt",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			"broker url": IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			"username": IntegrationConfigValue{
+				Type: IntegrationConfigTypeString,
+			},
+			"password": IntegrationConfigValue{
+				Type: IntegrationConfigTypePassword,
+			},
+		},
+		Disabled: false,
+		Hook:     true,
+	}
 	// OpenstackIntegration represents an openstack integration
 	OpenstackIntegration = IntegrationModel{
 		Name:       OpenstackIntegrationModel,
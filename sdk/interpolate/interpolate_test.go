@@ -190,6 +190,15 @@ func TestDo(t *testing.T) {
 			want:   `echo '{{"conf"|uvault}}'`,
 			enable: true,
 		},
+		{
+			name: "unknown function with path arguments",
+			args: args{
+				input: `worker cache push go-{{ hashFiles "go.sum" "**/package-lock.json" }}-{{.cds.app.value}} vendor`,
+				vars:  map[string]string{"cds.app.value": "value"},
+			},
+			want:   `worker cache push go-{{ hashFiles "go.sum" "**/package-lock.json" }}-value vendor`,
+			enable: true,
+		},
 		{
 			name: "simple",
 			args: args{
@@ -0,0 +1,21 @@
+package sdk
+
+import "fmt"
+
+// StepLogPart is a compressed part of a step log moved from the database to the object storage.
+type StepLogPart struct {
+	NodeRunID int64
+	JobRunID  int64
+	StepOrder int64
+	Index     int
+}
+
+// GetName returns the name of the part object
+func (p StepLogPart) GetName() string {
+	return fmt.Sprintf("%d-%d-%d.log.gz", p.JobRunID, p.StepOrder, p.Index)
+}
+
+// GetPath returns the container of the part object, the logs of a node run are stored in the same container
+func (p StepLogPart) GetPath() string {
+	return fmt.Sprintf("logs-%d", p.NodeRunID)
+}
@@ -0,0 +1,205 @@
+package sdk
+
+import (
+	"strings"
+	"time"
+)
+
+// Markers that can be printed at the beginning of a log line by the worker or by a script.
+const (
+	LogMarkerSectionStart = "::cds-section::"
+	LogMarkerSectionEnd   = "::cds-endsection::"
+	LogMarkerWarning      = "::cds-warning::"
+	LogMarkerError        = "::cds-error::"
+)
+
+// Levels of log lines.
+const (
+	LogLevelInfo    = "info"
+	LogLevelWarning = "warning"
+	LogLevelError   = "error"
+)
+
+// Max number of lines kept in the structured data of a step log. Lines starting with a marker and lines only giving
+// the time at which the next lines were received have their own limit, so the markers are kept in long logs.
+const (
+	MaxLogLines     = 10000
+	MaxLogTimeLines = 10000
+)
+
+// LogLine is a line of a step log.
+type LogLine struct {
+	Number  int       `json:"number" cli:"line"`
+	Time    time.Time `json:"time,omitempty" cli:"-"`
+	Level   string    `json:"level" cli:"level"`
+	Section string    `json:"section,omitempty" cli:"section"`
+	Text    string    `json:"text" cli:"text"`
+}
+
+// LogSection is a part of a step log between a section start marker and a section end marker.
+type LogSection struct {
+	Name      string        `json:"name"`
+	Start     time.Time     `json:"start,omitempty"`
+	End       time.Time     `json:"end,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	FirstLine int           `json:"first_line"`
+	LastLine  int           `json:"last_line"`
+	Warnings  int           `json:"warnings"`
+	Errors    int           `json:"errors"`
+}
+
+// LogLines is the structured data of a step log, stored beside its value which is left untouched: the time at which
+// its lines were received and its lines starting with a marker. The time of a line is the time of the last kept
+// line before it.
+type LogLines struct {
+	Count     int       `json:"count"`               // number of terminated lines
+	Partial   bool      `json:"partial"`             // the last line of the log is not terminated
+	Markers   int       `json:"markers"`             // number of kept lines starting with a marker
+	TimeLines int       `json:"time_lines"`          // number of kept lines only giving a time
+	LastTime  time.Time `json:"last_time,omitempty"` // time at which the last chunk was received
+	Lines     []LogLine `json:"lines"`
+}
+
+// Append parses a chunk of a step log received at the given time. A chunk can start in the middle of a line.
+// The parsed lines are appended to Lines, Lines can be emptied between two chunks to only get the new lines.
+func (l *LogLines) Append(t time.Time, chunk string) {
+	if chunk == "" {
+		return
+	}
+	t = t.UTC()
+	first := true
+	number := l.Count + 1
+	for _, s := range strings.SplitAfter(chunk, "\n") {
+		if s == "" {
+			continue
+		}
+		// a marker is only read at the beginning of a line
+		if !(first && l.Partial) {
+			line := ParseLogLine(number, strings.TrimSuffix(s, "\n"))
+			line.Time = t
+			if line.Level != LogLevelInfo || isSectionMarker(line.Text) {
+				if l.Markers < MaxLogLines {
+					l.Markers++
+					l.add(line)
+				}
+			} else if first && !l.LastTime.Equal(t.Truncate(time.Second)) && l.TimeLines < MaxLogTimeLines {
+				l.TimeLines++
+				l.add(LogLine{Number: number, Time: t, Level: LogLevelInfo})
+			}
+		}
+		first = false
+		l.Partial = !strings.HasSuffix(s, "\n")
+		if !l.Partial {
+			l.Count++
+			number++
+		}
+	}
+	l.LastTime = t.Truncate(time.Second)
+}
+
+func (l *LogLines) add(line LogLine) {
+	line.Time = line.Time.Truncate(time.Second)
+	l.Lines = append(l.Lines, line)
+}
+
+func isSectionMarker(s string) bool {
+	return strings.HasPrefix(s, LogMarkerSectionStart) || strings.HasPrefix(s, LogMarkerSectionEnd)
+}
+
+// StripLogMarkers removes the markers at the beginning of the lines of a step log.
+func StripLogMarkers(s string) string {
+	if !strings.Contains(s, "::cds-") {
+		return s
+	}
+	lines := strings.SplitAfter(s, "\n")
+	for i, line := range lines {
+		for _, m := range []string{LogMarkerSectionStart, LogMarkerSectionEnd, LogMarkerWarning, LogMarkerError} {
+			if strings.HasPrefix(line, m) {
+				lines[i] = strings.TrimPrefix(line, m)
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// ParseLogLine parses the marker at the beginning of a line of a step log.
+func ParseLogLine(number int, s string) LogLine {
+	l := LogLine{Number: number, Level: LogLevelInfo, Text: strings.TrimSuffix(s, "\r")}
+	switch {
+	case strings.HasPrefix(l.Text, LogMarkerWarning):
+		l.Level = LogLevelWarning
+		l.Text = strings.TrimPrefix(l.Text, LogMarkerWarning)
+	case strings.HasPrefix(l.Text, LogMarkerError):
+		l.Level = LogLevelError
+		l.Text = strings.TrimPrefix(l.Text, LogMarkerError)
+	}
+	return l
+}
+
+// Sections computes the sections of a step log. Sections can be nested, a section end marker closes the last opened
+// section, sections still opened end at the last line.
+func (l LogLines) Sections() []LogSection {
+	sections, _ := l.parse()
+	return sections
+}
+
+// Problems returns the warning and error lines of a step log.
+func (l LogLines) Problems() []LogLine {
+	_, problems := l.parse()
+	return problems
+}
+
+func (l LogLines) parse() ([]LogSection, []LogLine) {
+	var sections []LogSection
+	var problems []LogLine
+	var opened []int
+	for _, line := range l.Lines {
+		switch {
+		case strings.HasPrefix(line.Text, LogMarkerSectionStart):
+			sections = append(sections, LogSection{
+				Name:      strings.TrimSpace(strings.TrimPrefix(line.Text, LogMarkerSectionStart)),
+				Start:     line.Time,
+				FirstLine: line.Number,
+			})
+			opened = append(opened, len(sections)-1)
+		case strings.HasPrefix(line.Text, LogMarkerSectionEnd):
+			if len(opened) > 0 {
+				sections[opened[len(opened)-1]].close(line.Number, line.Time)
+				opened = opened[:len(opened)-1]
+			}
+		}
+		if line.Level == LogLevelInfo {
+			continue
+		}
+		if len(opened) > 0 {
+			line.Section = sections[opened[len(opened)-1]].Name
+		}
+		for _, o := range opened {
+			switch line.Level {
+			case LogLevelWarning:
+				sections[o].Warnings++
+			case LogLevelError:
+				sections[o].Errors++
+			}
+		}
+		problems = append(problems, line)
+	}
+
+	last := l.Count
+	if l.Partial {
+		last++
+	}
+	for _, o := range opened {
+		sections[o].close(last, l.LastTime)
+	}
+	return sections, problems
+}
+
+func (s *LogSection) close(number int, t time.Time) {
+	s.End = t
+	s.LastLine = number
+	if !s.Start.IsZero() && !s.End.IsZero() {
+		s.Duration = s.End.Sub(s.Start)
+	}
+}
@@ -0,0 +1,73 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLines(t *testing.T) {
+	t0 := time.Date(2019, 3, 1, 10, 0, 0, 0, time.UTC)
+	var l LogLines
+	l.Append(t0, "legacy line\n::cds-section::build\n")
+	l.Append(t0.Add(time.Second), "compil")
+	l.Append(t0.Add(time.Second), "ing ::cds-error::not a marker\n::cds-section::tests\n")
+	l.Append(t0.Add(3*time.Second), "::cds-warning::slow test\n")
+	l.Append(t0.Add(5*time.Second), "::cds-endsection::\n")
+	l.Append(t0.Add(6*time.Second), "::cds-endsection::\n::cds-section::deploy\n")
+	l.Append(t0.Add(10*time.Second), "::cds-error::connection refused\nexit")
+
+	assert.Equal(t, 9, l.Count)
+	assert.True(t, l.Partial)
+
+	sections := l.Sections()
+	require.Len(t, sections, 3)
+	assert.Equal(t, "build", sections[0].Name)
+	assert.Equal(t, 6*time.Second, sections[0].Duration)
+	assert.Equal(t, 2, sections[0].FirstLine)
+	assert.Equal(t, 7, sections[0].LastLine)
+	assert.Equal(t, 1, sections[0].Warnings)
+	assert.Equal(t, "tests", sections[1].Name)
+	assert.Equal(t, 4, sections[1].FirstLine)
+	assert.Equal(t, 4*time.Second, sections[1].Duration)
+	assert.Equal(t, "deploy", sections[2].Name)
+	assert.Equal(t, 4*time.Second, sections[2].Duration)
+	assert.Equal(t, 10, sections[2].LastLine)
+	assert.Equal(t, 1, sections[2].Errors)
+
+	problems := l.Problems()
+	require.Len(t, problems, 2)
+	assert.Equal(t, LogLevelWarning, problems[0].Level)
+	assert.Equal(t, "slow test", problems[0].Text)
+	assert.Equal(t, 5, problems[0].Number)
+	assert.Equal(t, "tests", problems[0].Section)
+	assert.Equal(t, LogLevelError, problems[1].Level)
+	assert.Equal(t, "connection refused", problems[1].Text)
+	assert.Equal(t, 9, problems[1].Number)
+	assert.Equal(t, t0.Add(10*time.Second), problems[1].Time)
+}
+
+func TestLogLinesLimits(t *testing.T) {
+	t0 := time.Date(2019, 3, 1, 10, 0, 0, 0, time.UTC)
+	var l LogLines
+	for i := 0; i < MaxLogTimeLines+10; i++ {
+		l.Append(t0.Add(time.Duration(i)*time.Second), "line\n")
+	}
+	assert.Equal(t, MaxLogTimeLines, l.TimeLines)
+
+	// the markers are still kept once the time lines limit is reached, only the new lines are returned
+	l.Lines = nil
+	l.Append(t0.Add(time.Hour*24), "::cds-error::failure\n")
+	require.Len(t, l.Lines, 1)
+	assert.Equal(t, MaxLogTimeLines+11, l.Lines[0].Number)
+	assert.Equal(t, LogLevelError, l.Lines[0].Level)
+	assert.Equal(t, 1, l.Markers)
+	assert.Equal(t, MaxLogTimeLines+11, l.Count)
+}
+
+func TestStripLogMarkers(t *testing.T) {
+	assert.Equal(t, "build\ncompiling ::cds-error::not a marker\nslow test\n\nconnection refused",
+		StripLogMarkers("::cds-section::build\ncompiling ::cds-error::not a marker\n::cds-warning::slow test\n::cds-endsection::\n::cds-error::connection refused"))
+}
@@ -0,0 +1,162 @@
+package sdk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Indexes available to search in step logs.
+const (
+	LogSearchIndexPostgres      = "postgres"
+	LogSearchIndexElasticsearch = "elasticsearch"
+)
+
+// Default values and limits of a log search.
+const (
+	LogSearchDefaultRuns    = 10
+	LogSearchMaxRuns        = 100
+	LogSearchDefaultContext = 2
+	LogSearchMaxContext     = 10
+	LogSearchDefaultLimit   = 100
+	LogSearchMaxLimit       = 1000
+)
+
+// LogSearchRequest searches a string or a regular expression in the step logs of the latest runs of a workflow.
+type LogSearchRequest struct {
+	Query   string `json:"query"`
+	Regex   bool   `json:"regex"`
+	Runs    int64  `json:"runs"`
+	Context int    `json:"context"`
+	Limit   int    `json:"limit"`
+}
+
+// IsValid returns an error if the request is not valid and sets default values. The default context is set by the
+// caller before unmarshaling the request as zero is a valid context.
+func (r *LogSearchRequest) IsValid() error {
+	if r.Query == "" {
+		return NewErrorFrom(ErrWrongRequest, "search query is mandatory")
+	}
+	if r.Regex {
+		if _, err := regexp.Compile(r.Query); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid regular expression: %v", err)
+		}
+		// lines are matched one by one so only the flags supported by all the indexes are allowed
+		flags, _ := SplitLogSearchRegexFlags(r.Query)
+		if strings.Trim(flags, "ims-") != "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid regular expression: only i, m and s flags are supported")
+		}
+	}
+	if r.Runs <= 0 {
+		r.Runs = LogSearchDefaultRuns
+	}
+	if r.Runs > LogSearchMaxRuns {
+		r.Runs = LogSearchMaxRuns
+	}
+	if r.Context < 0 {
+		r.Context = LogSearchDefaultContext
+	}
+	if r.Context > LogSearchMaxContext {
+		r.Context = LogSearchMaxContext
+	}
+	if r.Limit <= 0 || r.Limit > LogSearchMaxLimit {
+		r.Limit = LogSearchDefaultLimit
+	}
+	return nil
+}
+
+var logSearchRegexFlags = regexp.MustCompile(`^\(\?([a-zA-Z-]*)\)`)
+
+// SplitLogSearchRegexFlags returns the flags set at the beginning of a regular expression, like "(?i)", and the
+// regular expression without them.
+func SplitLogSearchRegexFlags(expr string) (string, string) {
+	m := logSearchRegexFlags.FindStringSubmatch(expr)
+	if m == nil {
+		return "", expr
+	}
+	return m[1], expr[len(m[0]):]
+}
+
+// LogSearchIndexRequest is the search sent by the API to a log search index, restricted to some runs of a workflow.
+type LogSearchIndexRequest struct {
+	LogSearchRequest
+	WorkflowID int64   `json:"workflow_id"`
+	RunNumbers []int64 `json:"run_numbers"`
+}
+
+// LogSearchDocument is the log of a step returned by a log search index.
+type LogSearchDocument struct {
+	WorkflowID int64  `json:"workflow_id"`
+	RunNumber  int64  `json:"run_number"`
+	NodeRunID  int64  `json:"node_run_id"`
+	NodeName   string `json:"node_name"`
+	JobRunID   int64  `json:"job_run_id"`
+	JobName    string `json:"job_name"`
+	StepOrder  int64  `json:"step_order"`
+	StepName   string `json:"step_name"`
+	Value      string `json:"value"`
+}
+
+// LogSearchHit is a line of a step log that matches a search.
+type LogSearchHit struct {
+	RunNumber int64    `json:"run_number" cli:"run"`
+	NodeRunID int64    `json:"node_run_id" cli:"-"`
+	NodeName  string   `json:"node_name" cli:"pipeline"`
+	JobRunID  int64    `json:"job_run_id" cli:"-"`
+	JobName   string   `json:"job_name" cli:"job"`
+	StepOrder int64    `json:"step_order" cli:"step_order"`
+	StepName  string   `json:"step_name" cli:"step"`
+	Line      int      `json:"line" cli:"line"`
+	Content   string   `json:"content" cli:"content"`
+	Before    []string `json:"before,omitempty" cli:"-"`
+	After     []string `json:"after,omitempty" cli:"-"`
+}
+
+// LogSearchResult is the result of a log search. It is partial when some archived step logs were not searched.
+type LogSearchResult struct {
+	Hits    []LogSearchHit `json:"hits"`
+	Partial bool           `json:"partial"`
+}
+
+// Hits returns the lines of a document that match the request with their context, at most limit hits are returned.
+func (r LogSearchRequest) Hits(doc LogSearchDocument, limit int) []LogSearchHit {
+	match := func(s string) bool { return strings.Contains(s, r.Query) }
+	if r.Regex {
+		reg, err := regexp.Compile(r.Query)
+		if err != nil {
+			return nil
+		}
+		match = reg.MatchString
+	}
+
+	lines := strings.Split(doc.Value, "\n")
+	var hits []LogSearchHit
+	for i, l := range lines {
+		if len(hits) >= limit {
+			break
+		}
+		if !match(l) {
+			continue
+		}
+		from, to := i-r.Context, i+r.Context+1
+		if from < 0 {
+			from = 0
+		}
+		if to > len(lines) {
+			to = len(lines)
+		}
+		hits = append(hits, LogSearchHit{
+			RunNumber: doc.RunNumber,
+			NodeRunID: doc.NodeRunID,
+			NodeName:  doc.NodeName,
+			JobRunID:  doc.JobRunID,
+			JobName:   doc.JobName,
+			StepOrder: doc.StepOrder,
+			StepName:  doc.StepName,
+			Line:      i + 1,
+			Content:   l,
+			Before:    lines[from:i],
+			After:     lines[i+1 : to],
+		})
+	}
+	return hits
+}
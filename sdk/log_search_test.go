@@ -0,0 +1,61 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogSearchRequestIsValid(t *testing.T) {
+	assert.Error(t, (&LogSearchRequest{}).IsValid())
+	assert.Error(t, (&LogSearchRequest{Query: "(", Regex: true}).IsValid())
+	assert.Error(t, (&LogSearchRequest{Query: "(?U)a+", Regex: true}).IsValid())
+	assert.NoError(t, (&LogSearchRequest{Query: "(?is)^error", Regex: true}).IsValid())
+
+	r := LogSearchRequest{Query: "error", Runs: 1000, Context: -1, Limit: 5000}
+	assert.NoError(t, r.IsValid())
+	assert.Equal(t, int64(LogSearchMaxRuns), r.Runs)
+	assert.Equal(t, LogSearchDefaultContext, r.Context)
+	assert.Equal(t, LogSearchDefaultLimit, r.Limit)
+}
+
+func TestLogSearchRequestHits(t *testing.T) {
+	doc := LogSearchDocument{
+		RunNumber: 3,
+		NodeName:  "build",
+		JobName:   "compile",
+		StepName:  "make",
+		Value:     "line 1\nerror: first\nline 3\nline 4\nerror: second",
+	}
+
+	r := LogSearchRequest{Query: "error:", Context: 1}
+	hits := r.Hits(doc, 10)
+	if !assert.Len(t, hits, 2) {
+		return
+	}
+	assert.Equal(t, 2, hits[0].Line)
+	assert.Equal(t, "error: first", hits[0].Content)
+	assert.Equal(t, []string{"line 1"}, hits[0].Before)
+	assert.Equal(t, []string{"line 3"}, hits[0].After)
+	assert.Equal(t, 5, hits[1].Line)
+	assert.Empty(t, hits[1].After)
+	assert.Equal(t, int64(3), hits[1].RunNumber)
+
+	r = LogSearchRequest{Query: `^line \d$`, Regex: true}
+	hits = r.Hits(doc, 2)
+	if !assert.Len(t, hits, 2) {
+		return
+	}
+	assert.Equal(t, 1, hits[0].Line)
+	assert.Equal(t, 3, hits[1].Line)
+}
+
+func TestSplitLogSearchRegexFlags(t *testing.T) {
+	flags, expr := SplitLogSearchRegexFlags("(?i-s)^error$")
+	assert.Equal(t, "i-s", flags)
+	assert.Equal(t, "^error$", expr)
+
+	flags, expr = SplitLogSearchRegexFlags("(?:a|b)")
+	assert.Equal(t, "", flags)
+	assert.Equal(t, "(?:a|b)", expr)
+}
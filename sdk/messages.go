@@ -79,6 +79,10 @@ var (
 	MsgWorkflowNodeStop                    = &Message{"MsgWorkflowNodeStop", trad{FR: "Le pipeline a été arrété par %s", EN: "The pipeline has been stopped by %s"}, nil}
 	MsgWorkflowNodeMutex                   = &Message{"MsgWorkflowNodeMutex", trad{FR: "Le pipeline %s est mis en attente tant qu'il est en cours sur un autre run", EN: "The pipeline %s is waiting while it's running on another run"}, nil}
 	MsgWorkflowNodeMutexRelease            = &Message{"MsgWorkflowNodeMutexRelease", trad{FR: "Lancement du pipeline %s", EN: "Triggering pipeline %s"}, nil}
+	MsgWorkflowNodeFreezeHold              = &Message{"MsgWorkflowNodeFreezeHold", trad{FR: "Le pipeline %s est mis en attente jusqu'à %s par la période de gel %s: %s", EN: "The pipeline %s is held until %s by the freeze window %s: %s"}, nil}
+	MsgWorkflowNodeFreezeFail              = &Message{"MsgWorkflowNodeFreezeFail", trad{FR: "Le pipeline %s ne peut pas démarrer pendant la période de gel %s: %s", EN: "The pipeline %s cannot start during the freeze window %s: %s"}, nil}
+	MsgWorkflowNodeFreezeOverride          = &Message{"MsgWorkflowNodeFreezeOverride", trad{FR: "Le pipeline %s démarre pendant la période de gel %s, à la demande de %s membre du groupe %s", EN: "The pipeline %s starts during the freeze window %s, on request of %s member of group %s"}, nil}
+	MsgWorkflowNodeFreezeRelease           = &Message{"MsgWorkflowNodeFreezeRelease", trad{FR: "Fin de la période de gel, lancement du pipeline %s", EN: "End of the freeze window, triggering pipeline %s"}, nil}
 	MsgWorkflowImportedUpdated             = &Message{"MsgWorkflowImportedUpdated", trad{FR: "Le workflow %s a été mis à jour", EN: "Workflow %s has been updated"}, nil}
 	MsgWorkflowImportedInserted            = &Message{"MsgWorkflowImportedInserted", trad{FR: "Le workflow %s a été créé", EN: "Workflow %s has been created"}, nil}
 	MsgSpawnInfoHatcheryCannotStartJob     = &Message{"MsgSpawnInfoHatcheryCannotStart", trad{FR: "Aucune hatchery n'a pu démarrer de worker respectant vos pré-requis de job, merci de les vérifier.", EN: "No hatchery can spawn a worker corresponding your job's requirements. Please check your job's requirements."}, nil}
@@ -92,6 +96,7 @@ var (
 	MsgWorkflowErrorBadCdsDir              = &Message{"MsgWorkflowErrorBadCdsDir", trad{FR: "Un problème est survenu avec votre répertoire .cds", EN: "A problem occured about your .cds directory"}, nil}
 	MsgWorkflowErrorUnknownKey             = &Message{"MsgWorkflowErrorUnknownKey", trad{FR: "La clé '%s' est incorrecte ou n'existe pas", EN: "The key '%s' is incorrect or doesn't exist"}, nil}
 	MsgWorkflowErrorBadVCSStrategy         = &Message{"MsgWorkflowErrorBadVCSStrategy", trad{FR: "Vos informations vcs_* sont incorrectes", EN: "Your vcs_* fields are incorrects"}, nil}
+	MsgWorkflowNodeVulnerabilityPolicy     = &Message{"MsgWorkflowNodeVulnerabilityPolicy", trad{FR: "Les pipelines suivant %s ne sont pas lancés, la politique de vulnérabilités n'est pas respectée: %s", EN: "Pipelines after %s are not triggered, the vulnerability policy is not respected: %s"}, nil}
 )
 
 // Messages contains all sdk Messages
@@ -155,6 +160,10 @@ var Messages = map[string]*Message{
 	MsgWorkflowNodeStop.ID:                    MsgWorkflowNodeStop,
 	MsgWorkflowNodeMutex.ID:                   MsgWorkflowNodeMutex,
 	MsgWorkflowNodeMutexRelease.ID:            MsgWorkflowNodeMutexRelease,
+	MsgWorkflowNodeFreezeHold.ID:              MsgWorkflowNodeFreezeHold,
+	MsgWorkflowNodeFreezeFail.ID:              MsgWorkflowNodeFreezeFail,
+	MsgWorkflowNodeFreezeOverride.ID:          MsgWorkflowNodeFreezeOverride,
+	MsgWorkflowNodeFreezeRelease.ID:           MsgWorkflowNodeFreezeRelease,
 	MsgWorkflowImportedUpdated.ID:             MsgWorkflowImportedUpdated,
 	MsgWorkflowImportedInserted.ID:            MsgWorkflowImportedInserted,
 	MsgSpawnInfoHatcheryCannotStartJob.ID:     MsgSpawnInfoHatcheryCannotStartJob,
@@ -168,6 +177,7 @@ var Messages = map[string]*Message{
 	MsgWorkflowErrorBadCdsDir.ID:              MsgWorkflowErrorBadCdsDir,
 	MsgWorkflowErrorUnknownKey.ID:             MsgWorkflowErrorUnknownKey,
 	MsgWorkflowErrorBadVCSStrategy.ID:         MsgWorkflowErrorBadVCSStrategy,
+	MsgWorkflowNodeVulnerabilityPolicy.ID:     MsgWorkflowNodeVulnerabilityPolicy,
 }
 
 //Message represent a struc format translated messages
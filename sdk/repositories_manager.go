@@ -100,6 +100,7 @@ type VCSPushEvent struct {
 	Repo     string    `json:"repo"`
 	Branch   VCSBranch `json:"branch"`
 	Commit   VCSCommit `json:"commit"`
+	Before   string    `json:"before,omitempty"` // hash of the branch before the push
 	CloneURL string    `json:"clone_url"`
 }
 
@@ -134,6 +135,7 @@ type VCSHook struct {
 	Disable     bool     `json:"disable"`
 	InsecureSSL bool     `json:"insecure_ssl"`
 	Workflow    bool     `json:"workflow"`
+	Secret      string   `json:"secret,omitempty"`
 }
 
 // VCSCommitStatus represents a status on a VCS repository
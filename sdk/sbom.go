@@ -0,0 +1,154 @@
+package sdk
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Software bill of materials formats supported by the SBOM builtin action.
+const (
+	SBOMFormatCycloneDX = "cyclonedx"
+	SBOMFormatSPDX      = "spdx"
+)
+
+// WorkflowNodeRunSBOM is a software bill of materials uploaded by a job of a workflow node run.
+type WorkflowNodeRunSBOM struct {
+	ID                int64           `json:"id" db:"id" cli:"id,key"`
+	ApplicationID     int64           `json:"application_id" db:"application_id" cli:"-"`
+	WorkflowID        int64           `json:"workflow_id" db:"workflow_id" cli:"-"`
+	WorkflowRunID     int64           `json:"workflow_run_id" db:"workflow_run_id" cli:"-"`
+	WorkflowNodeRunID int64           `json:"workflow_node_run_id" db:"workflow_node_run_id" cli:"-"`
+	Num               int64           `json:"num" db:"num" cli:"run"`
+	Branch            string          `json:"branch" db:"branch" cli:"branch"`
+	Name              string          `json:"name" db:"name" cli:"name"`
+	Format            string          `json:"format" db:"format" cli:"format"`
+	Document          json.RawMessage `json:"document,omitempty" db:"-" cli:"-"`
+	Components        []SBOMComponent `json:"components,omitempty" db:"-" cli:"-"`
+	Created           time.Time       `json:"created" db:"created" cli:"created"`
+}
+
+// SBOMComponent is a component listed in a software bill of materials.
+type SBOMComponent struct {
+	ID      int64  `json:"id" db:"id" cli:"-"`
+	SBOMID  int64  `json:"sbom_id" db:"sbom_id" cli:"-"`
+	Name    string `json:"name" db:"name" cli:"name,key"`
+	Version string `json:"version" db:"version" cli:"version"`
+	License string `json:"license" db:"license" cli:"license"`
+	PURL    string `json:"purl" db:"purl" cli:"purl"`
+}
+
+// SBOMComponentUsage is a workflow node run that shipped a component.
+type SBOMComponentUsage struct {
+	ProjectKey      string    `json:"project_key" db:"project_key" cli:"project"`
+	ApplicationName string    `json:"application_name" db:"application_name" cli:"application"`
+	WorkflowName    string    `json:"workflow_name" db:"workflow_name" cli:"workflow"`
+	Num             int64     `json:"num" db:"num" cli:"run"`
+	Branch          string    `json:"branch" db:"branch" cli:"branch"`
+	Name            string    `json:"name" db:"name" cli:"name,key"`
+	Version         string    `json:"version" db:"version" cli:"version"`
+	License         string    `json:"license" db:"license" cli:"license"`
+	PURL            string    `json:"purl" db:"purl" cli:"purl"`
+	Created         time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// SBOMComponentFilter filters the usages of components, the name or the package url is mandatory.
+type SBOMComponentFilter struct {
+	Name    string
+	Version string
+	PURL    string
+}
+
+// IsValid returns an error if the filter doesn't identify a component.
+func (f SBOMComponentFilter) IsValid() error {
+	if f.Name == "" && f.PURL == "" {
+		return NewErrorFrom(ErrWrongRequest, "component name or purl is mandatory")
+	}
+	return nil
+}
+
+// SPDXReport is a SPDX 2 document in JSON format.
+type SPDXReport struct {
+	SPDXVersion string `json:"spdxVersion"`
+	Packages    []struct {
+		Name             string `json:"name"`
+		VersionInfo      string `json:"versionInfo"`
+		LicenseConcluded string `json:"licenseConcluded"`
+		LicenseDeclared  string `json:"licenseDeclared"`
+		ExternalRefs     []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+// ParseSBOM parses a software bill of materials in given format and returns its components.
+func ParseSBOM(format string, data []byte) ([]SBOMComponent, error) {
+	switch format {
+	case SBOMFormatCycloneDX:
+		var r CycloneDXReport
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, NewErrorFrom(ErrWrongRequest, "invalid cyclonedx document: %v", err)
+		}
+		return cycloneDXComponents(r.Components), nil
+	case SBOMFormatSPDX:
+		var r SPDXReport
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, NewErrorFrom(ErrWrongRequest, "invalid spdx document: %v", err)
+		}
+		return r.ToComponents(), nil
+	}
+	return nil, NewErrorFrom(ErrWrongRequest, "unknown sbom format %s", format)
+}
+
+// cycloneDXComponents returns given components and their nested components.
+func cycloneDXComponents(cs []CycloneDXComponent) []SBOMComponent {
+	var res []SBOMComponent
+	for _, c := range cs {
+		var licenses []string
+		for _, l := range c.Licenses {
+			switch {
+			case l.Expression != "":
+				licenses = append(licenses, l.Expression)
+			case l.License.ID != "":
+				licenses = append(licenses, l.License.ID)
+			case l.License.Name != "":
+				licenses = append(licenses, l.License.Name)
+			}
+		}
+		res = append(res, SBOMComponent{
+			Name:    c.Name,
+			Version: c.Version,
+			License: strings.Join(licenses, " OR "),
+			PURL:    c.PURL,
+		})
+		res = append(res, cycloneDXComponents(c.Components)...)
+	}
+	return res
+}
+
+// ToComponents returns the packages of the document, the concluded license is used if known.
+func (r SPDXReport) ToComponents() []SBOMComponent {
+	res := make([]SBOMComponent, 0, len(r.Packages))
+	for _, p := range r.Packages {
+		c := SBOMComponent{
+			Name:    p.Name,
+			Version: p.VersionInfo,
+			License: p.LicenseConcluded,
+		}
+		if c.License == "" || c.License == "NOASSERTION" {
+			c.License = p.LicenseDeclared
+		}
+		if c.License == "NOASSERTION" {
+			c.License = ""
+		}
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				c.PURL = ref.ReferenceLocator
+				break
+			}
+		}
+		res = append(res, c)
+	}
+	return res
+}
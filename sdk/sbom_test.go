@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSBOM(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		data    string
+		want    []SBOMComponent
+		wantErr bool
+	}{
+		{
+			name:   "cyclonedx with nested components",
+			format: SBOMFormatCycloneDX,
+			data: `{"bomFormat": "CycloneDX", "specVersion": "1.4", "components": [
+				{"name": "log4j-core", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", "licenses": [{"license": {"id": "Apache-2.0"}}],
+				 "components": [{"name": "log4j-api", "version": "2.14.1", "licenses": [{"expression": "Apache-2.0 OR MIT"}]}]}
+			]}`,
+			want: []SBOMComponent{
+				{Name: "log4j-core", Version: "2.14.1", License: "Apache-2.0", PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+				{Name: "log4j-api", Version: "2.14.1", License: "Apache-2.0 OR MIT"},
+			},
+		},
+		{
+			name:   "spdx",
+			format: SBOMFormatSPDX,
+			data: `{"spdxVersion": "SPDX-2.3", "packages": [
+				{"name": "lodash", "versionInfo": "4.17.21", "licenseConcluded": "MIT", "externalRefs": [{"referenceType": "purl", "referenceLocator": "pkg:npm/lodash@4.17.21"}]},
+				{"name": "left-pad", "versionInfo": "1.3.0", "licenseConcluded": "NOASSERTION", "licenseDeclared": "WTFPL"},
+				{"name": "unknown", "licenseConcluded": "NOASSERTION", "licenseDeclared": "NOASSERTION"}
+			]}`,
+			want: []SBOMComponent{
+				{Name: "lodash", Version: "4.17.21", License: "MIT", PURL: "pkg:npm/lodash@4.17.21"},
+				{Name: "left-pad", Version: "1.3.0", License: "WTFPL"},
+				{Name: "unknown"},
+			},
+		},
+		{
+			name:    "invalid document",
+			format:  SBOMFormatSPDX,
+			data:    `<xml/>`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			format:  "swid",
+			data:    `{}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseSBOM(tt.format, []byte(tt.data))
+		if tt.wantErr {
+			assert.Error(t, err, tt.name)
+			continue
+		}
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
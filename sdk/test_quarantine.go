@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"time"
+
+	"github.com/ovh/venom"
+)
+
+// TestCaseStatusQuarantined is the status set on failed test cases that are quarantined.
+const TestCaseStatusQuarantined = "Quarantined"
+
+// TestQuarantine identifies a test case of an application whose failures must not fail the job.
+type TestQuarantine struct {
+	ID            int64      `json:"id" db:"id" cli:"id,key"`
+	ApplicationID int64      `json:"application_id" db:"application_id" cli:"-"`
+	TestSuite     string     `json:"test_suite" db:"test_suite" cli:"test_suite"`
+	Name          string     `json:"name" db:"name" cli:"name"`
+	Reason        string     `json:"reason" db:"reason" cli:"reason"`
+	Owner         string     `json:"owner" db:"owner" cli:"owner"`
+	Expire        *time.Time `json:"expire,omitempty" db:"expire" cli:"expire"`
+	Created       time.Time  `json:"created" db:"created" cli:"created"`
+}
+
+// IsValid returns an error if the quarantine doesn't identify a test case.
+func (q TestQuarantine) IsValid() error {
+	if q.Name == "" {
+		return NewErrorFrom(ErrWrongRequest, "test name is mandatory")
+	}
+	return nil
+}
+
+// IsExpired returns true if the quarantine expiry date is past.
+func (q TestQuarantine) IsExpired() bool {
+	return q.Expire != nil && q.Expire.Before(time.Now())
+}
+
+// Matches returns true if the quarantine applies to given test case, an empty test suite matches all test suites.
+func (q TestQuarantine) Matches(testSuite, name string) bool {
+	return q.Name == name && (q.TestSuite == "" || q.TestSuite == testSuite)
+}
+
+// CountQuarantinedTests returns the number of quarantined test cases.
+func CountQuarantinedTests(tests venom.Tests) int {
+	var n int
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			if tc.Status == TestCaseStatusQuarantined {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -12,8 +14,47 @@ const (
 	HeaderXAccessToken        = "X-CDS-ACCESS-TOKEN"
 	HeaderXAccessTokenCreated = "X-CDS-ACCESS-TOKEN-CREATED"
 	HeaderXAccessTokenSecret  = "X-CDS-ACCESS-TOKEN-SECRET"
+	HeaderXRateLimitLimit     = "X-CDS-RATELIMIT-LIMIT"
+	HeaderXRateLimitRemaining = "X-CDS-RATELIMIT-REMAINING"
+	HeaderXRateLimitReset     = "X-CDS-RATELIMIT-RESET"
+	HeaderXRateLimitOwner     = "X-CDS-RATELIMIT-OWNER"
 )
 
+// VCSRateLimit is the rate limit of a repositories manager API, Reset is the unix timestamp of its next reset.
+// Owner is the user of the token the rate limit applies to.
+type VCSRateLimit struct {
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Reset     int64  `json:"reset"`
+	Owner     string `json:"owner,omitempty"`
+}
+
+// VCSRateLimitedClient is implemented by the authorized clients which know the rate limit of their repositories manager
+type VCSRateLimitedClient interface {
+	RateLimitStatus() *VCSRateLimit
+}
+
+// SetHeader writes the rate limit in http headers
+func (r VCSRateLimit) SetHeader(h http.Header) {
+	h.Set(HeaderXRateLimitLimit, strconv.Itoa(r.Limit))
+	h.Set(HeaderXRateLimitRemaining, strconv.Itoa(r.Remaining))
+	h.Set(HeaderXRateLimitReset, strconv.FormatInt(r.Reset, 10))
+	if r.Owner != "" {
+		h.Set(HeaderXRateLimitOwner, r.Owner)
+	}
+}
+
+// VCSRateLimitFromHeader reads a rate limit from http headers, it returns nil if the headers are missing
+func VCSRateLimitFromHeader(h http.Header) *VCSRateLimit {
+	limit, errL := strconv.Atoi(h.Get(HeaderXRateLimitLimit))
+	remaining, errR := strconv.Atoi(h.Get(HeaderXRateLimitRemaining))
+	reset, errT := strconv.ParseInt(h.Get(HeaderXRateLimitReset), 10, 64)
+	if errL != nil || errR != nil || errT != nil {
+		return nil
+	}
+	return &VCSRateLimit{Limit: limit, Remaining: remaining, Reset: reset, Owner: h.Get(HeaderXRateLimitOwner)}
+}
+
 // BuildNumberAndHash represents BuildNumber, Commit Hash and Branch for a Pipeline Build or Node Run
 type BuildNumberAndHash struct {
 	BuildNumber int64
@@ -57,6 +98,7 @@ type VCSAuthorizedClient interface {
 	Commits(ctx context.Context, repo, branch, since, until string) ([]VCSCommit, error)
 	Commit(ctx context.Context, repo, hash string) (VCSCommit, error)
 	CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]VCSCommit, error)
+	ChangedFilesBetweenRefs(ctx context.Context, repo, base, head string) ([]string, error)
 
 	// PullRequests
 	PullRequest(context.Context, string, int) (VCSPullRequest, error)
@@ -56,6 +56,7 @@ type cmd struct {
 	dir  string
 	cmd  string
 	args []string
+	env  []string
 }
 
 func (c cmd) String() string {
@@ -126,7 +127,7 @@ func runGitCommandRaw(cmds cmds, output *OutputOpts, envs ...string) error {
 		if c.dir != "" {
 			cmd.Dir = os.ExpandEnv(c.dir)
 		}
-		cmd.Env = osEnv
+		cmd.Env = append(osEnv, c.env...)
 
 		if verbose {
 			LogFunc("Executing Command %s - %v", c, envs)
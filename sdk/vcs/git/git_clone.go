@@ -19,6 +19,16 @@ type CloneOpts struct {
 	Quiet                   bool
 	CheckoutCommit          string
 	NoStrictHostKeyChecking bool
+	// SparseCheckout is the list of paths to checkout, the whole tree is checked out if empty
+	SparseCheckout []string
+	// Filter is the partial clone filter, ie. blob:none or tree:0
+	Filter string
+	// LFS fetches Git LFS objects matching LFSInclude and not matching LFSExclude
+	LFS        bool
+	LFSInclude []string
+	LFSExclude []string
+	// Reference is a local repository used as an alternate to avoid fetching its objects again
+	Reference string
 }
 
 // Clone make a git clone
@@ -73,6 +83,18 @@ func prepareGitCloneCommands(repo string, path string, opts *CloneOpts) (string,
 		if opts.Recursive {
 			gitcmd.args = append(gitcmd.args, "--recursive")
 		}
+
+		if opts.Filter != "" {
+			gitcmd.args = append(gitcmd.args, "--filter="+opts.Filter)
+		}
+
+		if opts.Reference != "" {
+			gitcmd.args = append(gitcmd.args, "--reference-if-able", opts.Reference)
+		}
+
+		if len(opts.SparseCheckout) > 0 {
+			gitcmd.args = append(gitcmd.args, "--no-checkout")
+		}
 	}
 
 	userLogCommand := "Executing: git " + strings.Join(gitcmd.args, " ") + " ...  "
@@ -84,6 +106,38 @@ func prepareGitCloneCommands(repo string, path string, opts *CloneOpts) (string,
 
 	allCmd = append(allCmd, gitcmd)
 
+	// the directory where the repository has been cloned
+	dir := path
+	if dir == "" {
+		t := strings.Split(repo, "/")
+		dir = strings.TrimSuffix(t[len(t)-1], ".git")
+	}
+
+	if opts != nil && len(opts.SparseCheckout) > 0 {
+		initCmd := cmd{
+			dir:  dir,
+			cmd:  "git",
+			args: []string{"sparse-checkout", "init", "--cone"},
+		}
+		setCmd := cmd{
+			dir:  dir,
+			cmd:  "git",
+			args: append([]string{"sparse-checkout", "set"}, opts.SparseCheckout...),
+		}
+		userLogCommand += "\n\rExecuting: git " + strings.Join(setCmd.args, " ")
+		allCmd = append(allCmd, initCmd, setCmd)
+
+		// the reset below will checkout the files
+		if opts.CheckoutCommit == "" || opts.Tag != "" {
+			checkoutCmd := cmd{
+				dir:  dir,
+				cmd:  "git",
+				args: []string{"read-tree", "-mu", "HEAD"},
+			}
+			allCmd = append(allCmd, checkoutCmd)
+		}
+	}
+
 	// if a specific commit hash is given, try to reset current repo to this commit
 	// when a tag is given the commit hash is ignored
 	if opts != nil && opts.CheckoutCommit != "" && opts.Tag == "" {
@@ -95,13 +149,7 @@ func prepareGitCloneCommands(repo string, path string, opts *CloneOpts) (string,
 				args: []string{"fetch", "origin", opts.CheckoutCommit},
 			}
 			userLogCommand += "\n\rExecuting: git " + strings.Join(fetchCmd.args, " ")
-			//Locate the git reset cmd to the right directory
-			if path == "" {
-				t := strings.Split(repo, "/")
-				fetchCmd.dir = strings.TrimSuffix(t[len(t)-1], ".git")
-			} else {
-				fetchCmd.dir = path
-			}
+			fetchCmd.dir = dir
 
 			allCmd = append(allCmd, fetchCmd)
 		}
@@ -112,16 +160,30 @@ func prepareGitCloneCommands(repo string, path string, opts *CloneOpts) (string,
 			args: []string{"reset", "--hard", opts.CheckoutCommit},
 		}
 		userLogCommand += "\n\rExecuting: git " + strings.Join(resetCmd.args, " ")
-		// locate the git reset cmd to the right directory
-		if path == "" {
-			t := strings.Split(repo, "/")
-			resetCmd.dir = strings.TrimSuffix(t[len(t)-1], ".git")
-		} else {
-			resetCmd.dir = path
-		}
+		resetCmd.dir = dir
 
 		allCmd = append(allCmd, resetCmd)
 	}
 
+	// LFS objects are pulled after the checkout with the given include and exclude patterns
+	if opts != nil && opts.LFS {
+		for i := range allCmd {
+			allCmd[i].env = append(allCmd[i].env, "GIT_LFS_SKIP_SMUDGE=1")
+		}
+		lfsCmd := cmd{
+			dir:  dir,
+			cmd:  "git",
+			args: []string{"lfs", "pull"},
+		}
+		if len(opts.LFSInclude) > 0 {
+			lfsCmd.args = append(lfsCmd.args, "--include="+strings.Join(opts.LFSInclude, ","))
+		}
+		if len(opts.LFSExclude) > 0 {
+			lfsCmd.args = append(lfsCmd.args, "--exclude="+strings.Join(opts.LFSExclude, ","))
+		}
+		userLogCommand += "\n\rExecuting: git " + strings.Join(lfsCmd.args, " ")
+		allCmd = append(allCmd, lfsCmd)
+	}
+
 	return userLogCommand, cmds(allCmd)
 }
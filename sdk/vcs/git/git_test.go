@@ -226,6 +226,59 @@ func Test_gitCommand(t *testing.T) {
 				"git reset --hard eb8b87a",
 			},
 		},
+		{
+			name: "Clone public repo over http with sparse checkout and partial clone",
+			args: args{
+				repo: "https://github.com/ovh/cds.git",
+				path: "/tmp/Test_gitCommand-4",
+				opts: &CloneOpts{
+					Branch:         "master",
+					Filter:         "blob:none",
+					SparseCheckout: []string{"sdk", "engine/api"},
+				},
+			},
+			want: []string{
+				"git clone --branch master --filter=blob:none --no-checkout https://github.com/ovh/cds.git /tmp/Test_gitCommand-4",
+				"git sparse-checkout init --cone",
+				"git sparse-checkout set sdk engine/api",
+				"git read-tree -mu HEAD",
+			},
+		},
+		{
+			name: "Clone public repo over http with sparse checkout and checkout commit",
+			args: args{
+				repo: "https://github.com/ovh/cds.git",
+				path: "/tmp/Test_gitCommand-5",
+				opts: &CloneOpts{
+					Branch:         "master",
+					CheckoutCommit: "eb8b87a",
+					SparseCheckout: []string{"sdk"},
+				},
+			},
+			want: []string{
+				"git clone --branch master --no-checkout https://github.com/ovh/cds.git /tmp/Test_gitCommand-5",
+				"git sparse-checkout init --cone",
+				"git sparse-checkout set sdk",
+				"git reset --hard eb8b87a",
+			},
+		},
+		{
+			name: "Clone public repo over http with lfs and reference",
+			args: args{
+				repo: "https://github.com/ovh/cds.git",
+				path: "/tmp/Test_gitCommand-6",
+				opts: &CloneOpts{
+					Reference:  "/var/cache/git/cds.git",
+					LFS:        true,
+					LFSInclude: []string{"*.bin", "assets/**"},
+					LFSExclude: []string{"*.iso"},
+				},
+			},
+			want: []string{
+				"git clone --reference-if-able /var/cache/git/cds.git https://github.com/ovh/cds.git /tmp/Test_gitCommand-6",
+				"git lfs pull --include=*.bin,assets/** --exclude=*.iso",
+			},
+		},
 	}
 	for _, tt := range tests {
 		os.RemoveAll(tt.args.path)
@@ -0,0 +1,220 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Actions of a vulnerability policy when it is not respected.
+const (
+	VulnerabilityPolicyActionFail          = "fail"
+	VulnerabilityPolicyActionBlockTriggers = "block_triggers"
+)
+
+// VulnerabilitySeverities lists the severities from the lowest to the highest.
+var VulnerabilitySeverities = []string{
+	SeverityUnknown,
+	SeverityNegligible,
+	SeverityLow,
+	SeverityMedium,
+	SeverityHigh,
+	SeverityCritical,
+	SeverityDefcon1,
+}
+
+// VulnerabilitySeverityIndex returns the rank of a severity, -1 if the severity is unknown.
+func VulnerabilitySeverityIndex(s string) int {
+	for i := range VulnerabilitySeverities {
+		if VulnerabilitySeverities[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// VulnerabilityPolicy is evaluated when a vulnerability report is stored for a node run, a policy can be set for
+// a whole project or for an application, the application's policy takes precedence.
+type VulnerabilityPolicy struct {
+	ID                int64                      `json:"id" yaml:"-" db:"id" cli:"id,key"`
+	ProjectID         int64                      `json:"project_id" yaml:"-" db:"project_id" cli:"-"`
+	ApplicationID     *int64                     `json:"application_id,omitempty" yaml:"-" db:"application_id" cli:"-"`
+	Action            string                     `json:"action" yaml:"action" db:"action" cli:"action"`
+	OnlyDefaultBranch bool                       `json:"only_default_branch" yaml:"only_default_branch,omitempty" db:"only_default_branch" cli:"only_default_branch"`
+	FailOnNew         string                     `json:"fail_on_new,omitempty" yaml:"fail_on_new,omitempty" db:"fail_on_new" cli:"fail_on_new"`
+	MaxBySeverity     VulnerabilitySeverityLimit `json:"max_by_severity,omitempty" yaml:"max_by_severity,omitempty" db:"max_by_severity" cli:"-"`
+	Ignores           VulnerabilityIgnores       `json:"ignores,omitempty" yaml:"ignores,omitempty" db:"ignores" cli:"-"`
+	Created           time.Time                  `json:"created" yaml:"-" db:"created" cli:"created"`
+}
+
+// VulnerabilitySeverityLimit is the maximum number of vulnerabilities by severity.
+type VulnerabilitySeverityLimit map[string]int64
+
+// Value returns driver.Value from severity limit.
+func (l VulnerabilitySeverityLimit) Value() (driver.Value, error) {
+	j, err := json.Marshal(l)
+	return j, WrapError(err, "cannot marshal VulnerabilitySeverityLimit")
+}
+
+// Scan severity limit.
+func (l *VulnerabilitySeverityLimit) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, l), "cannot unmarshal VulnerabilitySeverityLimit")
+}
+
+// VulnerabilityIgnore excludes vulnerabilities from the evaluation of a policy until its expiry date.
+type VulnerabilityIgnore struct {
+	// ID is a CVE or a rule identifier
+	ID            string     `json:"id" yaml:"id"`
+	Component     string     `json:"component,omitempty" yaml:"component,omitempty"`
+	Justification string     `json:"justification" yaml:"justification"`
+	Expire        *time.Time `json:"expire,omitempty" yaml:"expire,omitempty"`
+}
+
+// IsExpired returns true if the ignore expiry date is past.
+func (i VulnerabilityIgnore) IsExpired() bool {
+	return i.Expire != nil && i.Expire.Before(time.Now())
+}
+
+// Matches returns true if the ignore applies to given vulnerability, an empty component matches all components.
+func (i VulnerabilityIgnore) Matches(v Vulnerability) bool {
+	if i.ID != v.CVE && i.ID != v.Rule {
+		return false
+	}
+	return i.Component == "" || i.Component == v.Component
+}
+
+// VulnerabilityIgnores is the ignore list of a policy.
+type VulnerabilityIgnores []VulnerabilityIgnore
+
+// Value returns driver.Value from ignores.
+func (l VulnerabilityIgnores) Value() (driver.Value, error) {
+	j, err := json.Marshal(l)
+	return j, WrapError(err, "cannot marshal VulnerabilityIgnores")
+}
+
+// Scan ignores.
+func (l *VulnerabilityIgnores) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(json.Unmarshal(source, l), "cannot unmarshal VulnerabilityIgnores")
+}
+
+// IsValid returns an error if the policy is not valid.
+func (p VulnerabilityPolicy) IsValid() error {
+	switch p.Action {
+	case VulnerabilityPolicyActionFail, VulnerabilityPolicyActionBlockTriggers:
+	default:
+		return NewErrorFrom(ErrWrongRequest, "invalid action %q, must be %s or %s", p.Action, VulnerabilityPolicyActionFail, VulnerabilityPolicyActionBlockTriggers)
+	}
+	if p.FailOnNew != "" && VulnerabilitySeverityIndex(p.FailOnNew) < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid severity %q", p.FailOnNew)
+	}
+	for s, max := range p.MaxBySeverity {
+		if VulnerabilitySeverityIndex(s) < 0 {
+			return NewErrorFrom(ErrWrongRequest, "invalid severity %q", s)
+		}
+		if max < 0 {
+			return NewErrorFrom(ErrWrongRequest, "invalid maximum for severity %s", s)
+		}
+	}
+	for _, i := range p.Ignores {
+		if i.ID == "" || i.Justification == "" {
+			return NewErrorFrom(ErrWrongRequest, "ignored vulnerabilities must have an id and a justification")
+		}
+	}
+	return nil
+}
+
+// VulnerabilityPolicyEvaluation is the result of the evaluation of a policy on the vulnerabilities of a node run.
+type VulnerabilityPolicyEvaluation struct {
+	PolicyID int64    `json:"policy_id"`
+	Action   string   `json:"action"`
+	Failed   bool     `json:"failed"`
+	Reasons  []string `json:"reasons,omitempty"`
+	Ignored  int64    `json:"ignored"`
+}
+
+// FailsNodeRun returns true if the evaluation failed and the policy fails the node run.
+func (e *VulnerabilityPolicyEvaluation) FailsNodeRun() bool {
+	return e != nil && e.Failed && e.Action == VulnerabilityPolicyActionFail
+}
+
+// BlocksTriggers returns true if the evaluation failed and the policy blocks the triggers of the node run.
+func (e *VulnerabilityPolicyEvaluation) BlocksTriggers() bool {
+	return e != nil && e.Failed && e.Action == VulnerabilityPolicyActionBlockTriggers
+}
+
+// Evaluate checks given vulnerabilities against the policy. Vulnerabilities are new if they are not in the
+// reference vulnerabilities, that are the vulnerabilities of the application on its default branch.
+func (p VulnerabilityPolicy) Evaluate(vs []Vulnerability, reference []Vulnerability) VulnerabilityPolicyEvaluation {
+	e := VulnerabilityPolicyEvaluation{
+		PolicyID: p.ID,
+		Action:   p.Action,
+	}
+
+	// known vulnerabilities with their ignored flag
+	known := make(map[string]bool, len(reference))
+	for _, v := range reference {
+		known[v.Key()] = v.Ignored
+	}
+
+	var newVulns int64
+	counts := make(map[string]int64)
+	for _, v := range vs {
+		ignored, isKnown := known[v.Key()]
+		if v.Ignored || ignored || p.ignores(v) {
+			e.Ignored++
+			continue
+		}
+		counts[v.Severity]++
+		if p.FailOnNew == "" || VulnerabilitySeverityIndex(v.Severity) < VulnerabilitySeverityIndex(p.FailOnNew) {
+			continue
+		}
+		if !isKnown {
+			newVulns++
+		}
+	}
+
+	if newVulns > 0 {
+		e.Reasons = append(e.Reasons, fmt.Sprintf("%d new vulnerabilities with severity %s or higher", newVulns, p.FailOnNew))
+	}
+
+	severities := make([]string, 0, len(p.MaxBySeverity))
+	for s := range p.MaxBySeverity {
+		severities = append(severities, s)
+	}
+	sort.Strings(severities)
+	for _, s := range severities {
+		if counts[s] > p.MaxBySeverity[s] {
+			e.Reasons = append(e.Reasons, fmt.Sprintf("%d %s vulnerabilities, maximum is %d", counts[s], s, p.MaxBySeverity[s]))
+		}
+	}
+
+	e.Failed = len(e.Reasons) > 0
+	return e
+}
+
+func (p VulnerabilityPolicy) ignores(v Vulnerability) bool {
+	for _, i := range p.Ignores {
+		if !i.IsExpired() && i.Matches(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVulnerabilityPolicyIsValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy VulnerabilityPolicy
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			policy: VulnerabilityPolicy{Action: VulnerabilityPolicyActionFail, FailOnNew: SeverityHigh, MaxBySeverity: VulnerabilitySeverityLimit{SeverityCritical: 0}},
+			valid:  true,
+		},
+		{
+			name:   "invalid action",
+			policy: VulnerabilityPolicy{Action: "stop"},
+		},
+		{
+			name:   "invalid severity",
+			policy: VulnerabilityPolicy{Action: VulnerabilityPolicyActionBlockTriggers, FailOnNew: "huge"},
+		},
+		{
+			name:   "negative maximum",
+			policy: VulnerabilityPolicy{Action: VulnerabilityPolicyActionFail, MaxBySeverity: VulnerabilitySeverityLimit{SeverityLow: -1}},
+		},
+		{
+			name:   "ignore without justification",
+			policy: VulnerabilityPolicy{Action: VulnerabilityPolicyActionFail, Ignores: VulnerabilityIgnores{{ID: "CVE-2019-0001"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.IsValid()
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
+
+func TestVulnerabilityPolicyEvaluate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	known := Vulnerability{Component: "lib-a", Version: "1.0", CVE: "CVE-2019-0001", Severity: SeverityHigh}
+	newHigh := Vulnerability{Component: "lib-b", Version: "2.0", CVE: "CVE-2019-0002", Severity: SeverityHigh}
+	newLow := Vulnerability{Component: "lib-c", Version: "1.2", CVE: "CVE-2019-0003", Severity: SeverityLow}
+
+	tests := []struct {
+		name      string
+		policy    VulnerabilityPolicy
+		vulns     []Vulnerability
+		reference []Vulnerability
+		failed    bool
+		ignored   int64
+	}{
+		{
+			name:      "no new vulnerability",
+			policy:    VulnerabilityPolicy{Action: VulnerabilityPolicyActionFail, FailOnNew: SeverityHigh},
+			vulns:     []Vulnerability{known, newLow},
+			reference: []Vulnerability{known},
+		},
+		{
+			name:      "new high vulnerability",
+			policy:    VulnerabilityPolicy{Action: VulnerabilityPolicyActionFail, FailOnNew: SeverityHigh},
+			vulns:     []Vulnerability{known, newHigh},
+			reference: []Vulnerability{known},
+			failed:    true,
+		},
+		{
+			name:    "ignored new vulnerability",
+			policy:  VulnerabilityPolicy{Action: VulnerabilityPolicyActionFail, FailOnNew: SeverityHigh, Ignores: VulnerabilityIgnores{{ID: "CVE-2019-0002", Justification: "not exploitable"}}},
+			vulns:   []Vulnerability{newHigh},
+			ignored: 1,
+		},
+		{
+			name:   "expired ignore",
+			policy: VulnerabilityPolicy{Action: VulnerabilityPolicyActionFail, FailOnNew: SeverityHigh, Ignores: VulnerabilityIgnores{{ID: "CVE-2019-0002", Justification: "not exploitable", Expire: &past}}},
+			vulns:  []Vulnerability{newHigh},
+			failed: true,
+		},
+		{
+			name:      "vulnerability ignored on the application",
+			policy:    VulnerabilityPolicy{Action: VulnerabilityPolicyActionFail, MaxBySeverity: VulnerabilitySeverityLimit{SeverityHigh: 0}},
+			vulns:     []Vulnerability{known},
+			reference: []Vulnerability{{Component: "lib-a", Version: "1.0", CVE: "CVE-2019-0001", Severity: SeverityHigh, Ignored: true}},
+			ignored:   1,
+		},
+		{
+			name:      "maximum exceeded",
+			policy:    VulnerabilityPolicy{Action: VulnerabilityPolicyActionBlockTriggers, MaxBySeverity: VulnerabilitySeverityLimit{SeverityHigh: 1, SeverityLow: 1}},
+			vulns:     []Vulnerability{known, newHigh, newLow},
+			reference: []Vulnerability{known},
+			failed:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.policy.Evaluate(tt.vulns, tt.reference)
+			assert.Equal(t, tt.failed, e.Failed, "reasons: %v", e.Reasons)
+			assert.Equal(t, tt.ignored, e.Ignored)
+			assert.Equal(t, tt.failed && tt.policy.Action == VulnerabilityPolicyActionFail, e.FailsNodeRun())
+			assert.Equal(t, tt.failed && tt.policy.Action == VulnerabilityPolicyActionBlockTriggers, e.BlocksTriggers())
+		})
+	}
+}
@@ -60,6 +60,56 @@ func (w *Workflow) FilterHooksConfig(s ...string) {
 	}
 }
 
+// HideHookSecrets replaces the secret values of the configuration of the hooks and outgoing hooks by a placeholder
+func (w *Workflow) HideHookSecrets() {
+	if w.WorkflowData == nil {
+		return
+	}
+	for _, n := range w.WorkflowData.Array() {
+		for i := range n.Hooks {
+			n.Hooks[i].Config.HideSecrets()
+		}
+		if n.OutGoingHookContext != nil {
+			n.OutGoingHookContext.Config.HideSecrets()
+		}
+	}
+}
+
+// RestoreHookSecrets sets back the secret values of the hooks and outgoing hooks sent with the placeholder, from the
+// previous version of the workflow. The hooks are matched by UUID then by ref, the outgoing hooks by node name.
+func (w *Workflow) RestoreHookSecrets(previous *Workflow) {
+	if w.WorkflowData == nil {
+		return
+	}
+	var previousHooks map[string]*NodeHook
+	var previousHooksByRef map[string]NodeHook
+	previousOutgoingHooks := map[string]WorkflowNodeHookConfig{}
+	if previous != nil && previous.WorkflowData != nil {
+		previousHooks = previous.WorkflowData.GetHooks()
+		previousHooksByRef = previous.WorkflowData.GetHooksMapRef()
+		for _, n := range previous.WorkflowData.Array() {
+			if n.OutGoingHookContext != nil {
+				previousOutgoingHooks[n.Name] = n.OutGoingHookContext.Config
+			}
+		}
+	}
+	for _, n := range w.WorkflowData.Array() {
+		for i := range n.Hooks {
+			h := &n.Hooks[i]
+			var previousConfig WorkflowNodeHookConfig
+			if p, has := previousHooks[h.UUID]; has && h.UUID != "" {
+				previousConfig = p.Config
+			} else if p, has := previousHooksByRef[h.Ref]; has && h.Ref != "" {
+				previousConfig = p.Config
+			}
+			h.Config.RestoreSecrets(previousConfig)
+		}
+		if n.OutGoingHookContext != nil {
+			n.OutGoingHookContext.Config.RestoreSecrets(previousOutgoingHooks[n.Name])
+		}
+	}
+}
+
 // WorkflowHookModelBuiltin is a constant for the builtin hook models
 const WorkflowHookModelBuiltin = "builtin"
 
@@ -92,11 +142,54 @@ func (cfg WorkflowNodeHookConfig) Values(model WorkflowNodeHookConfig) map[strin
 	for k, v := range cfg {
 		if model[k].Configurable {
 			r[k] = v.Value
+			if cfg.isSecret(k) && v.Value != "" {
+				r[k] = PasswordPlaceholder
+			}
 		}
 	}
 	return r
 }
 
+func (cfg WorkflowNodeHookConfig) isSecret(k string) bool {
+	return cfg[k].Type == HookConfigTypePassword || k == HookConfigWebHookSecret
+}
+
+// SecretValues returns the secret values of the configuration
+func (cfg WorkflowNodeHookConfig) SecretValues() []string {
+	var secrets []string
+	for k, v := range cfg {
+		if cfg.isSecret(k) && v.Value != "" && v.Value != PasswordPlaceholder {
+			secrets = append(secrets, v.Value)
+		}
+	}
+	return secrets
+}
+
+// HideSecrets replaces the secret values of the configuration by a placeholder
+func (cfg WorkflowNodeHookConfig) HideSecrets() {
+	for k, v := range cfg {
+		if cfg.isSecret(k) && v.Value != "" {
+			v.Value = PasswordPlaceholder
+			cfg[k] = v
+		}
+	}
+}
+
+// RestoreSecrets sets back the secret values sent with the placeholder from the previous configuration.
+// Without previous value, the secret is emptied.
+func (cfg WorkflowNodeHookConfig) RestoreSecrets(previous WorkflowNodeHookConfig) {
+	for k, v := range cfg {
+		if !cfg.isSecret(k) {
+			continue
+		}
+		if v.Value == PasswordPlaceholder {
+			v.Value = previous[k].Value
+		}
+		v.Type = HookConfigTypePassword
+		cfg[k] = v
+	}
+}
+
 // Clone returns a copied dinstance of cfg
 func (cfg WorkflowNodeHookConfig) Clone() WorkflowNodeHookConfig {
 	m := WorkflowNodeHookConfig(make(map[string]WorkflowNodeHookConfigValue, len(cfg)))
@@ -127,6 +220,8 @@ const (
 	HookConfigTypeHook = "hook"
 	// HookConfigTypeMultiChoice type multiple
 	HookConfigTypeMultiChoice = "multiple"
+	// HookConfigTypePassword type password, its value is hidden to the users
+	HookConfigTypePassword = "password"
 )
 
 //WorkflowHookModel represents a hook which can be used in workflows.
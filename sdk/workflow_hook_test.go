@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowHideAndRestoreHookSecrets(t *testing.T) {
+	newWorkflow := func(secret, outgoingSecret string) *Workflow {
+		return &Workflow{
+			WorkflowData: &WorkflowData{
+				Node: Node{
+					Name: "root",
+					Type: NodeTypePipeline,
+					Hooks: []NodeHook{{
+						UUID: "hook-1",
+						Config: WorkflowNodeHookConfig{
+							WebHookModelConfigMethod: {Value: "POST", Type: HookConfigTypeString},
+							HookConfigWebHookSecret:  {Value: secret, Type: HookConfigTypeString},
+						},
+					}},
+					Triggers: []NodeTrigger{{
+						ChildNode: Node{
+							Name: "outgoing",
+							Type: NodeTypeOutGoingHook,
+							OutGoingHookContext: &NodeOutGoingHook{
+								Config: WorkflowNodeHookConfig{
+									HookConfigWebHookSecret: {Value: outgoingSecret, Type: HookConfigTypePassword},
+								},
+							},
+						},
+					}},
+				},
+			},
+		}
+	}
+
+	old := newWorkflow("s3cr3t", "0utg0ing")
+
+	w := newWorkflow("s3cr3t", "0utg0ing")
+	w.HideHookSecrets()
+	assert.Equal(t, PasswordPlaceholder, w.WorkflowData.Node.Hooks[0].Config[HookConfigWebHookSecret].Value)
+	assert.Equal(t, "POST", w.WorkflowData.Node.Hooks[0].Config[WebHookModelConfigMethod].Value)
+	assert.Equal(t, PasswordPlaceholder, w.WorkflowData.Node.Triggers[0].ChildNode.OutGoingHookContext.Config[HookConfigWebHookSecret].Value)
+
+	// the placeholder sent back keeps the previous secret
+	w.RestoreHookSecrets(old)
+	assert.Equal(t, "s3cr3t", w.WorkflowData.Node.Hooks[0].Config[HookConfigWebHookSecret].Value)
+	assert.Equal(t, HookConfigTypePassword, w.WorkflowData.Node.Hooks[0].Config[HookConfigWebHookSecret].Type)
+	assert.Equal(t, "0utg0ing", w.WorkflowData.Node.Triggers[0].ChildNode.OutGoingHookContext.Config[HookConfigWebHookSecret].Value)
+
+	// a new value replaces the secret
+	w = newWorkflow("n3w", PasswordPlaceholder)
+	w.RestoreHookSecrets(old)
+	assert.Equal(t, "n3w", w.WorkflowData.Node.Hooks[0].Config[HookConfigWebHookSecret].Value)
+	assert.Equal(t, "0utg0ing", w.WorkflowData.Node.Triggers[0].ChildNode.OutGoingHookContext.Config[HookConfigWebHookSecret].Value)
+
+	// without previous workflow, the placeholder is not kept as secret
+	w = newWorkflow(PasswordPlaceholder, PasswordPlaceholder)
+	w.RestoreHookSecrets(nil)
+	assert.Equal(t, "", w.WorkflowData.Node.Hooks[0].Config[HookConfigWebHookSecret].Value)
+	assert.Equal(t, "", w.WorkflowData.Node.Triggers[0].ChildNode.OutGoingHookContext.Config[HookConfigWebHookSecret].Value)
+}
@@ -228,6 +228,8 @@ type WorkflowNodeRun struct {
 	Coverage               WorkflowNodeRunCoverage              `json:"coverage,omitempty"`
 	VulnerabilitiesReport  WorkflowNodeRunVulnerabilityReport   `json:"vulnerabilities_report,omitempty"`
 	Tests                  *venom.Tests                         `json:"tests,omitempty"`
+	TestsQuarantined       int                                  `json:"tests_quarantined,omitempty"`
+	VulnerabilityPolicy    *VulnerabilityPolicyEvaluation       `json:"vulnerability_policy,omitempty"`
 	Commits                []VCSCommit                          `json:"commits,omitempty"`
 	TriggersRun            map[int64]WorkflowNodeTriggerRun     `json:"triggers_run,omitempty"`
 	VCSRepository          string                               `json:"vcs_repository"`
@@ -284,6 +286,7 @@ type WorkflowNodeRunCoverage struct {
 	Repository        string                        `json:"repository" db:"repository"`
 	Branch            string                        `json:"branch" db:"branch"`
 	Report            coverage.Report               `json:"report" db:"-"`
+	Packages          []CoveragePackageReport       `json:"packages,omitempty" db:"-"`
 	Trend             WorkflowNodeRunCoverageTrends `json:"trend" db:"-"`
 }
 
@@ -370,10 +373,19 @@ type WorkflowNodeJobRun struct {
 	IntegrationPluginBinaries []GRPCPluginBinary `json:"integration_plugin_binaries,omitempty"`
 	Header                    WorkflowRunHeaders `json:"header,omitempty"`
 	ContainsService           bool               `json:"contains_service,omitempty"`
+	Priority                  int                `json:"priority,omitempty"`
+	QueuePosition             int                `json:"queue_position,omitempty"`
+	EstimatedWaitSeconds      int64              `json:"estimated_wait_seconds,omitempty"`
 }
 
 // /!\ DONT FORGET TO REGENERATE EASYJSON FILES /!\
 
+// Bounds of a job priority. Jobs with a higher priority are taken first from the queue.
+const (
+	WorkflowNodeJobRunPriorityMin = -10
+	WorkflowNodeJobRunPriorityMax = 10
+)
+
 // WorkflowNodeJobRunSummary is a light representation of WorkflowNodeJobRun for CDS event
 type WorkflowNodeJobRunSummary struct {
 	ID                int64              `json:"id"`
@@ -435,6 +447,7 @@ type WorkflowNodeRunManual struct {
 	Payload            interface{} `json:"payload" db:"-"`
 	PipelineParameters []Parameter `json:"pipeline_parameter" db:"-"`
 	User               User        `json:"user" db:"-"`
+	Priority           *int        `json:"priority,omitempty" db:"-"`
 }
 
 //GetName returns the name the artifact
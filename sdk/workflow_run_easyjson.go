@@ -182,6 +182,12 @@ func easyjsonD7860c2dDecodeGithubComOvhCdsSdk(in *jlexer.Lexer, out *WorkflowNod
 			}
 		case "contains_service":
 			out.ContainsService = bool(in.Bool())
+		case "priority":
+			out.Priority = int(in.Int())
+		case "queue_position":
+			out.QueuePosition = int(in.Int())
+		case "estimated_wait_seconds":
+			out.EstimatedWaitSeconds = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -440,6 +446,36 @@ func easyjsonD7860c2dEncodeGithubComOvhCdsSdk(out *jwriter.Writer, in WorkflowNo
 		}
 		out.Bool(bool(in.ContainsService))
 	}
+	if in.Priority != 0 {
+		const prefix string = ",\"priority\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Priority))
+	}
+	if in.QueuePosition != 0 {
+		const prefix string = ",\"queue_position\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.QueuePosition))
+	}
+	if in.EstimatedWaitSeconds != 0 {
+		const prefix string = ",\"estimated_wait_seconds\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int64(int64(in.EstimatedWaitSeconds))
+	}
 	out.RawByte('}')
 }
 
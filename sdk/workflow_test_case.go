@@ -0,0 +1,96 @@
+package sdk
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ovh/venom"
+)
+
+// WorkflowTestCase is the result of a test case in a workflow node run, it is used to follow
+// the history of a test across runs of a workflow.
+type WorkflowTestCase struct {
+	ID                int64     `json:"id" db:"id" cli:"-"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id" cli:"-"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id" cli:"-"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id" cli:"-"`
+	RunNumber         int64     `json:"run_number" db:"run_number" cli:"run"`
+	VCSBranch         string    `json:"vcs_branch" db:"vcs_branch" cli:"branch"`
+	VCSHash           string    `json:"vcs_hash" db:"vcs_hash" cli:"hash"`
+	TestSuite         string    `json:"test_suite" db:"test_suite" cli:"test_suite"`
+	Name              string    `json:"name" db:"name" cli:"name"`
+	Classname         string    `json:"classname" db:"classname" cli:"-"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Duration          float64   `json:"duration" db:"duration" cli:"duration"`
+	Flaky             bool      `json:"flaky" db:"flaky" cli:"flaky"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// WorkflowTestCaseSummary aggregates the results of a test case over the last runs of a workflow.
+type WorkflowTestCaseSummary struct {
+	TestSuite     string  `json:"test_suite" db:"test_suite" cli:"test_suite"`
+	Name          string  `json:"name" db:"name" cli:"name,key"`
+	Classname     string  `json:"classname" db:"classname" cli:"-"`
+	Runs          int64   `json:"runs" db:"runs" cli:"runs"`
+	Failures      int64   `json:"failures" db:"failures" cli:"failures"`
+	Flaky         bool    `json:"flaky" db:"flaky" cli:"flaky"`
+	AvgDuration   float64 `json:"avg_duration" db:"avg_duration" cli:"avg_duration"`
+	LastDuration  float64 `json:"last_duration" db:"last_duration" cli:"last_duration"`
+	LastStatus    string  `json:"last_status" db:"last_status" cli:"last_status"`
+	LastRunNumber int64   `json:"last_run_number" db:"last_run_number" cli:"last_run"`
+}
+
+// WorkflowTestCaseFilter filters the test cases summary of a workflow.
+type WorkflowTestCaseFilter struct {
+	Branch string
+	// Runs is the number of last runs to look at
+	Runs int64
+	// OnlyFailed keeps the test cases that failed at least once
+	OnlyFailed bool
+	// OnlyFlaky keeps the test cases detected as flaky
+	OnlyFlaky bool
+}
+
+// NewWorkflowTestCases returns the test cases of given test results for a workflow node run.
+func NewWorkflowTestCases(nr WorkflowNodeRun, tests venom.Tests) []WorkflowTestCase {
+	var res []WorkflowTestCase
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			res = append(res, WorkflowTestCase{
+				WorkflowID:        nr.WorkflowID,
+				WorkflowRunID:     nr.WorkflowRunID,
+				WorkflowNodeRunID: nr.ID,
+				RunNumber:         nr.Number,
+				VCSBranch:         nr.VCSBranch,
+				VCSHash:           nr.VCSHash,
+				TestSuite:         ts.Name,
+				Name:              tc.Name,
+				Classname:         tc.Classname,
+				Status:            TestCaseStatus(tc),
+				Duration:          testCaseDuration(tc),
+			})
+		}
+	}
+	return res
+}
+
+// TestCaseStatus returns Fail if the test case has failures or errors, Skipped if it was skipped, or else Success.
+func TestCaseStatus(tc venom.TestCase) string {
+	switch {
+	case len(tc.Failures) > 0 || len(tc.Errors) > 0:
+		return StatusFail.String()
+	case len(tc.Skipped) > 0:
+		return StatusSkipped.String()
+	default:
+		return StatusSuccess.String()
+	}
+}
+
+// testCaseDuration returns the duration in seconds of a test case, 0 if it is not valid.
+func testCaseDuration(tc venom.TestCase) float64 {
+	d, err := strconv.ParseFloat(tc.Time, 64)
+	if err != nil {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkflowTestCases(t *testing.T) {
+	nr := WorkflowNodeRun{ID: 3, WorkflowID: 1, WorkflowRunID: 2, Number: 12, VCSBranch: "master", VCSHash: "eb8b87a"}
+	tests := venom.Tests{
+		TestSuites: []venom.TestSuite{
+			{
+				Name: "suite",
+				TestCases: []venom.TestCase{
+					{Name: "ok", Time: "1.5"},
+					{Name: "ko", Failures: []venom.Failure{{Value: "boom"}}},
+					{Name: "error", Errors: []venom.Failure{{Value: "boom"}}, Time: "invalid"},
+					{Name: "skipped", Skipped: []venom.Skipped{{Value: "skip"}}},
+				},
+			},
+		},
+	}
+
+	tcs := NewWorkflowTestCases(nr, tests)
+	assert.Len(t, tcs, 4)
+	assert.Equal(t, "suite", tcs[0].TestSuite)
+	assert.Equal(t, int64(12), tcs[0].RunNumber)
+	assert.Equal(t, "eb8b87a", tcs[0].VCSHash)
+	assert.Equal(t, int64(3), tcs[0].WorkflowNodeRunID)
+	assert.Equal(t, StatusSuccess.String(), tcs[0].Status)
+	assert.Equal(t, 1.5, tcs[0].Duration)
+	assert.Equal(t, StatusFail.String(), tcs[1].Status)
+	assert.Equal(t, StatusFail.String(), tcs[2].Status)
+	assert.Equal(t, float64(0), tcs[2].Duration)
+	assert.Equal(t, StatusSkipped.String(), tcs[3].Status)
+}
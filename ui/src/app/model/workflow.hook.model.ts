@@ -29,6 +29,14 @@ export class WorkflowHookTask {
     executions: TaskExecution[];
     nb_executions_total: number;
     nb_executions_todo: number;
+    connection?: TaskConnection;
+}
+
+export class TaskConnection {
+    status: string;
+    last_error: string;
+    reconnects: number;
+    timestamp: number;
 }
 
 export class TaskExecution {
@@ -43,14 +51,34 @@ export class TaskExecution {
     webhook: Webhook;
     rabbitmq: RabbitMQ;
     kafka: Kafka;
+    nats: NATS;
+    mqtt: MQTT;
     scheduled_task?: any;
     status: HookStatus;
+    payload: {};
+    replay: TaskExecutionReplay;
+}
+
+export class TaskExecutionReplay {
+    timestamp: number;
+    payload: {};
 }
 
 export class Webhook {
     reques_url: string;
     request_body: string;
     request_header: Map<string, string[]>;
+    attempts: Array<WebhookAttempt>;
+    next_attempt: number;
+}
+
+export class WebhookAttempt {
+    timestamp: number;
+    duration: number;
+    request: string;
+    response: string;
+    status_code: number;
+    error: string;
 }
 
 export class RabbitMQ {
@@ -61,3 +89,13 @@ export class Kafka {
     message: string;
 }
 
+export class NATS {
+    subject: string;
+    message: string;
+}
+
+export class MQTT {
+    topic: string;
+    message: string;
+}
+
@@ -81,6 +81,18 @@ export class HookTaskListComponent {
                 selector: (d: WorkflowHookTask) => d.nb_executions_total,
                 sortable: true,
                 sortKey: 'nb_executions_total'
+            },
+            <Column<WorkflowHookTask>>{
+                name: 'hook_task_connection',
+                selector: (d: WorkflowHookTask) => {
+                    if (!d.connection) {
+                        return '';
+                    }
+                    if (d.connection.last_error && d.connection.status !== 'CONNECTED') {
+                        return d.connection.status + ': ' + d.connection.last_error;
+                    }
+                    return d.connection.status;
+                }
             }
         ];
 